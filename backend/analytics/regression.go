@@ -0,0 +1,76 @@
+package analytics
+
+import "math"
+
+// WeightType represents the weighting algorithm used for the trend line
+type WeightType string
+
+const (
+	WEIGHT_NONE             WeightType = "none"             // all points equal weight
+	WEIGHT_LINEAR           WeightType = "linear"           // weight = 1 + i
+	WEIGHT_SQRT             WeightType = "sqrt"             // weight = 1 + sqrt(i)
+	WEIGHT_QUADRATIC        WeightType = "quadratic"        // weight = 1 + (i/n)²
+	WEIGHT_EXPONENTIAL_LOW  WeightType = "exponential_low"  // weight = exp(i/n)
+	WEIGHT_EXPONENTIAL_HIGH WeightType = "exponential_high" // weight = exp(2i/n)
+)
+
+// Point represents a single data point of a series
+type Point struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// Regression represents the best-fit line y = slope * x + intercept
+type Regression struct {
+	Slope     float64 `json:"slope"`
+	Intercept float64 `json:"intercept"`
+}
+
+// Weight returns the weight of the i-th point out of n for the given weighting,
+// later points get a higher weight so the trend follows recent data more closely
+func Weight(weightType WeightType, i, n int) float64 {
+	switch weightType {
+	case WEIGHT_LINEAR:
+		return 1 + float64(i)
+	case WEIGHT_SQRT:
+		return 1 + math.Sqrt(float64(i))
+	case WEIGHT_QUADRATIC:
+		return 1 + math.Pow(float64(i)/float64(n), 2)
+	case WEIGHT_EXPONENTIAL_LOW:
+		return math.Exp(float64(i) / float64(n))
+	case WEIGHT_EXPONENTIAL_HIGH:
+		return math.Exp(2 * float64(i) / float64(n))
+	default:
+		return 1
+	}
+}
+
+// LinearRegression calculates the weighted least squares fit of the points.
+// Points must be in chronological order for the weighting to make sense.
+func LinearRegression(points []Point, weightType WeightType) Regression {
+	n := len(points)
+	if n < 2 {
+		return Regression{}
+	}
+
+	var sumW, sumWX, sumWY, sumWXY, sumWX2 float64
+	for i, p := range points {
+		w := Weight(weightType, i, n)
+		sumW += w
+		sumWX += w * p.X
+		sumWY += w * p.Y
+		sumWXY += w * p.X * p.Y
+		sumWX2 += w * p.X * p.X
+	}
+
+	denominator := sumW*sumWX2 - sumWX*sumWX
+	if denominator == 0 {
+		// All points share the same x, there is no trend to speak of
+		return Regression{Slope: 0, Intercept: sumWY / sumW}
+	}
+
+	slope := (sumW*sumWXY - sumWX*sumWY) / denominator
+	intercept := (sumWY - slope*sumWX) / sumW
+
+	return Regression{Slope: slope, Intercept: intercept}
+}
@@ -0,0 +1,136 @@
+package analytics
+
+import (
+	"math"
+	"routine-tracker/models"
+	"routine-tracker/trackers/target"
+	"sort"
+	"time"
+)
+
+const day = 24 * time.Hour
+
+// Projection represents the pace, trend and projected completion of a target tracker.
+// Trend values are expressed in days since the tracker's start date.
+type Projection struct {
+	TrackerID                int        `json:"trackerId" example:"1"`
+	AddToTotal               bool       `json:"addToTotal" example:"false"`
+	TrendWeightType          WeightType `json:"trendWeightType" example:"none"`
+	Date                     string     `json:"date" example:"2024-03-01"`
+	StartValue               float64    `json:"startValue" example:"0"`
+	CurrentValue             float64    `json:"currentValue" example:"1234.56"`
+	GoalValue                float64    `json:"goalValue" example:"5000"`
+	GoalDate                 time.Time  `json:"goalDate" example:"2024-12-31T00:00:00Z"`
+	DataPoints               int        `json:"dataPoints" example:"12"`
+	Slope                    float64    `json:"slope" example:"13.7"`        // value change per day
+	Intercept                float64    `json:"intercept" example:"4.2"`     // trend value on the start date
+	ExpectedValue            float64    `json:"expectedValue" example:"830"` // value on a straight line from start to goal
+	OnPace                   bool       `json:"onPace" example:"true"`
+	RemainingValue           float64    `json:"remainingValue" example:"3765.44"`
+	DaysUntilGoal            int        `json:"daysUntilGoal" example:"305"`
+	RequiredDailyRate        *float64   `json:"requiredDailyRate" example:"12.35"`                      // null once the goal date has passed
+	ProjectedCompletionDate  *time.Time `json:"projectedCompletionDate" example:"2024-11-20T00:00:00Z"` // null when the trend never reaches the goal
+	ProjectedValueOnGoalDate float64    `json:"projectedValueOnGoalDate" example:"5521.3"`
+}
+
+// ProgressSeries turns the entries of a target tracker into chronological
+// progress points, where x is the number of days since the start date. Each
+// entry is read with the goal configuration in effect at its date.
+func ProgressSeries(t target.TargetTracker, entries []models.Entry) []Point {
+	sorted := make([]models.Entry, len(entries))
+	copy(sorted, entries)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Date.Before(sorted[j].Date)
+	})
+
+	points := make([]Point, 0, len(sorted))
+	for i, value := range t.Progress(sorted) {
+		points = append(points, Point{X: daysBetween(t.StartDate, sorted[i].Date), Y: value})
+	}
+
+	return points
+}
+
+// ProjectTarget calculates the trend line and the projected completion of a target tracker
+func ProjectTarget(t target.TargetTracker, entries []models.Entry, now time.Time) Projection {
+	weightType := WEIGHT_NONE
+	if t.TrendWeightType != nil && *t.TrendWeightType != "" {
+		weightType = WeightType(*t.TrendWeightType)
+	}
+
+	points := ProgressSeries(t, entries)
+
+	// Project towards the goal in effect now, later goal changes do not apply yet
+	t = t.At(now)
+
+	currentValue := t.StartValue
+	if len(points) > 0 {
+		currentValue = points[len(points)-1].Y
+	}
+
+	totalDays := daysBetween(t.StartDate, t.GoalDate)
+	elapsedDays := daysBetween(t.StartDate, now)
+	if elapsedDays < 0 {
+		elapsedDays = 0
+	}
+
+	projection := Projection{
+		TrackerID:       t.ID,
+		AddToTotal:      t.AddToTotal,
+		TrendWeightType: weightType,
+		Date:            now.Format("2006-01-02"),
+		StartValue:      t.StartValue,
+		CurrentValue:    currentValue,
+		GoalValue:       t.GoalValue,
+		GoalDate:        t.GoalDate,
+		DataPoints:      len(points),
+		RemainingValue:  t.GoalValue - currentValue,
+	}
+
+	// Where the value should be today when moving in a straight line from start to goal
+	projection.ExpectedValue = t.GoalValue
+	if totalDays > 0 && elapsedDays < totalDays {
+		projection.ExpectedValue = t.StartValue + (t.GoalValue-t.StartValue)*elapsedDays/totalDays
+	}
+	if t.GoalValue >= t.StartValue {
+		projection.OnPace = currentValue >= projection.ExpectedValue
+	} else {
+		projection.OnPace = currentValue <= projection.ExpectedValue
+	}
+
+	untilGoal := daysBetween(now, t.GoalDate)
+	projection.DaysUntilGoal = int(math.Ceil(untilGoal))
+	if untilGoal > 0 {
+		rate := projection.RemainingValue / untilGoal
+		projection.RequiredDailyRate = &rate
+	}
+
+	var regression Regression
+	if len(points) >= 2 {
+		regression = LinearRegression(points, weightType)
+	} else if elapsedDays > 0 {
+		// Not enough data for a trend, fall back to the average rate since the start
+		regression = Regression{Slope: (currentValue - t.StartValue) / elapsedDays, Intercept: t.StartValue}
+	} else {
+		regression = Regression{Intercept: currentValue}
+	}
+
+	projection.Slope = regression.Slope
+	projection.Intercept = regression.Intercept
+	projection.ProjectedValueOnGoalDate = regression.Slope*totalDays + regression.Intercept
+
+	// Only project a completion date when the trend is heading towards the goal
+	towardsGoal := (t.GoalValue > t.StartValue && regression.Slope > 0) ||
+		(t.GoalValue < t.StartValue && regression.Slope < 0)
+	if towardsGoal {
+		completionDays := (t.GoalValue - regression.Intercept) / regression.Slope
+		completion := t.StartDate.Add(time.Duration(completionDays * float64(day)))
+		projection.ProjectedCompletionDate = &completion
+	}
+
+	return projection
+}
+
+func daysBetween(from, to time.Time) float64 {
+	return float64(to.Sub(from)) / float64(day)
+}
@@ -0,0 +1,48 @@
+package app
+
+import (
+	"log/slog"
+	"routine-tracker/config"
+	"routine-tracker/database"
+	"routine-tracker/notify"
+	"time"
+)
+
+// Clock tells the current time, tests replace it to control "today"
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the machine's clock
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// App holds everything a running server needs. Several apps can live in
+// one process, each with its own database.
+type App struct {
+	Config   *config.Config
+	Store    database.Store
+	Notifier *notify.Dispatcher
+	Clock    Clock
+	Logger   *slog.Logger
+}
+
+// New creates an app on top of an opened store. Notifications are sent with
+// the notification settings of the configuration.
+func New(cfg *config.Config, store database.Store, logger *slog.Logger) *App {
+	return &App{
+		Config:   cfg,
+		Store:    store,
+		Notifier: notify.NewDispatcher(store, notify.SMTPConfig(cfg.Notifications.SMTP), cfg.Notifications.AllowInternalHosts, logger),
+		Clock:    SystemClock{},
+		Logger:   logger,
+	}
+}
+
+// Now is the current time according to the app's clock
+func (a *App) Now() time.Time {
+	return a.Clock.Now()
+}
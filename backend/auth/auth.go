@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+type contextKey string
+
+const (
+	userIDKey     contextKey = "userID"
+	scopesKey     contextKey = "scopes"
+	apiTokenIDKey contextKey = "apiTokenID"
+)
+
+// HashPassword hashes a plain text password for storage
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// CheckPassword reports whether the password matches the stored hash
+func CheckPassword(hash, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+// NewToken generates a random bearer token, only its hash is ever stored
+func NewToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// HashToken returns the hash of a token as it is stored in the database
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// BearerToken extracts the token from the Authorization header
+func BearerToken(r *http.Request) string {
+	header := r.Header.Get("Authorization")
+	if len(header) > 7 && strings.EqualFold(header[:7], "bearer ") {
+		return strings.TrimSpace(header[7:])
+	}
+	return ""
+}
+
+// WithUserID returns a copy of ctx carrying the authenticated user's ID
+func WithUserID(ctx context.Context, userID int) context.Context {
+	return context.WithValue(ctx, userIDKey, userID)
+}
+
+// UserID returns the authenticated user's ID, or 0 when the request is anonymous
+func UserID(ctx context.Context) int {
+	userID, _ := ctx.Value(userIDKey).(int)
+	return userID
+}
+
+// WithAPITokenID returns a copy of ctx authenticated with the API token
+func WithAPITokenID(ctx context.Context, id int) context.Context {
+	return context.WithValue(ctx, apiTokenIDKey, id)
+}
+
+// APITokenID returns the ID of the API token authenticating the request, ok
+// is false for login sessions
+func APITokenID(ctx context.Context) (id int, ok bool) {
+	id, ok = ctx.Value(apiTokenIDKey).(int)
+	return id, ok
+}
+
+// API token scopes
+const (
+	SCOPE_TRACKERS_READ  = "trackers:read"
+	SCOPE_TRACKERS_WRITE = "trackers:write"
+	SCOPE_ENTRIES_READ   = "entries:read"
+	SCOPE_ENTRIES_WRITE  = "entries:write"
+	SCOPE_ADMIN          = "admin" // grants every other scope, token and account management
+)
+
+// Scopes lists every scope an API token can be granted
+var Scopes = []string{SCOPE_TRACKERS_READ, SCOPE_TRACKERS_WRITE, SCOPE_ENTRIES_READ, SCOPE_ENTRIES_WRITE, SCOPE_ADMIN}
+
+// APITokenPrefix distinguishes personal API tokens from login session tokens
+const APITokenPrefix = "pat_"
+
+// IsValidScope reports whether scope is one of the known scopes
+func IsValidScope(scope string) bool {
+	for _, s := range Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// WithScopes returns a copy of ctx restricted to the given scopes
+func WithScopes(ctx context.Context, scopes []string) context.Context {
+	return context.WithValue(ctx, scopesKey, scopes)
+}
+
+// HasScope reports whether the request may act with the given scope.
+// Requests authenticated with a login session are not restricted.
+func HasScope(ctx context.Context, scope string) bool {
+	scopes, restricted := ctx.Value(scopesKey).([]string)
+	if !restricted {
+		return true
+	}
+	for _, s := range scopes {
+		if s == scope || s == SCOPE_ADMIN {
+			return true
+		}
+	}
+	return false
+}
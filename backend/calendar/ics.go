@@ -0,0 +1,129 @@
+// Package calendar writes iCalendar (RFC 5545) feeds of all-day events
+package calendar
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ContentType is the media type of iCalendar feeds
+const ContentType = "text/calendar; charset=utf-8"
+
+// Event is an all-day event, repeating when RRule is set
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Date        time.Time       // only the calendar date is used
+	RRule       string          // e.g. "FREQ=WEEKLY;BYDAY=MO,WE"
+	Alarms      []time.Duration // time of day of each alarm on the days of the event
+}
+
+// Calendar is a feed of events
+type Calendar struct {
+	Name    string
+	Refresh time.Duration // how often clients should fetch the feed again, 0 to leave it to them
+	Events  []Event
+}
+
+// Write writes the calendar in iCalendar format. stamp is the time the feed
+// is created at.
+func (c *Calendar) Write(w io.Writer, stamp time.Time) error {
+	out := &writer{w: bufio.NewWriter(w)}
+	dtstamp := stamp.UTC().Format("20060102T150405Z")
+
+	out.line("BEGIN:VCALENDAR")
+	out.line("VERSION:2.0")
+	out.line("PRODID:-//Progress//Routine Tracker//EN")
+	out.line("CALSCALE:GREGORIAN")
+	out.line("METHOD:PUBLISH")
+	if c.Name != "" {
+		out.line("X-WR-CALNAME:" + escape(c.Name))
+	}
+	if c.Refresh > 0 {
+		out.line("REFRESH-INTERVAL;VALUE=DURATION:" + duration(c.Refresh))
+		out.line("X-PUBLISHED-TTL:" + duration(c.Refresh))
+	}
+
+	for _, e := range c.Events {
+		out.line("BEGIN:VEVENT")
+		out.line("UID:" + e.UID)
+		out.line("DTSTAMP:" + dtstamp)
+		out.line("DTSTART;VALUE=DATE:" + e.Date.Format("20060102"))
+		out.line("DTEND;VALUE=DATE:" + e.Date.AddDate(0, 0, 1).Format("20060102"))
+		if e.RRule != "" {
+			out.line("RRULE:" + e.RRule)
+		}
+		out.line("SUMMARY:" + escape(e.Summary))
+		if e.Description != "" {
+			out.line("DESCRIPTION:" + escape(e.Description))
+		}
+		out.line("TRANSP:TRANSPARENT")
+		for _, alarm := range e.Alarms {
+			out.line("BEGIN:VALARM")
+			out.line("ACTION:DISPLAY")
+			out.line("DESCRIPTION:" + escape(e.Summary))
+			out.line("TRIGGER:" + duration(alarm))
+			out.line("END:VALARM")
+		}
+		out.line("END:VEVENT")
+	}
+
+	out.line("END:VCALENDAR")
+	if out.err != nil {
+		return out.err
+	}
+	return out.w.Flush()
+}
+
+// writer writes content lines, ending them with CRLF and folding them after
+// 75 octets without splitting UTF-8 characters
+type writer struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (w *writer) line(s string) {
+	if w.err != nil {
+		return
+	}
+
+	var b strings.Builder
+	width := 0
+	for _, r := range s {
+		size := len(string(r))
+		if width+size > 75 {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	b.WriteString("\r\n")
+
+	_, w.err = w.w.WriteString(b.String())
+}
+
+// escape escapes a TEXT value
+func escape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+// duration formats a non-negative duration like PT18H30M
+func duration(d time.Duration) string {
+	hours := int(d / time.Hour)
+	minutes := int(d % time.Hour / time.Minute)
+	if minutes == 0 {
+		return fmt.Sprintf("PT%dH", hours)
+	}
+	return fmt.Sprintf("PT%dH%dM", hours, minutes)
+}
@@ -0,0 +1,390 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the server configuration. Values are read from, in increasing
+// precedence: defaults, the config file, environment variables and flags.
+type Config struct {
+	Server        ServerConfig       `yaml:"server" toml:"server"`
+	Database      DatabaseConfig     `yaml:"database" toml:"database"`
+	CORS          CORSConfig         `yaml:"cors" toml:"cors"`
+	Log           LogConfig          `yaml:"log" toml:"log"`
+	Features      FeatureConfig      `yaml:"features" toml:"features"`
+	Trash         TrashConfig        `yaml:"trash" toml:"trash"`
+	Notifications NotificationConfig `yaml:"notifications" toml:"notifications"`
+}
+
+type ServerConfig struct {
+	Addr    string `yaml:"addr" toml:"addr"`
+	TLSCert string `yaml:"tls_cert" toml:"tls_cert"`
+	TLSKey  string `yaml:"tls_key" toml:"tls_key"`
+}
+
+type DatabaseConfig struct {
+	DSN string `yaml:"dsn" toml:"dsn"`
+}
+
+type CORSConfig struct {
+	AllowedOrigins []string `yaml:"allowed_origins" toml:"allowed_origins"`
+}
+
+type LogConfig struct {
+	Level string `yaml:"level" toml:"level"` // debug, info, warn or error
+}
+
+// FeatureConfig switches optional parts of the server on or off
+type FeatureConfig struct {
+	Registration bool `yaml:"registration" toml:"registration"`
+	Reminders    bool `yaml:"reminders" toml:"reminders"`
+	Swagger      bool `yaml:"swagger" toml:"swagger"`
+}
+
+// TrashConfig controls how long deleted trackers and entries can be restored
+type TrashConfig struct {
+	RetentionDays int `yaml:"retention_days" toml:"retention_days"`
+}
+
+// NotificationConfig holds the mail server of email channels and which
+// hosts webhook, ntfy and Gotify channels may reach
+type NotificationConfig struct {
+	SMTP SMTPConfig `yaml:"smtp" toml:"smtp"`
+	// AllowInternalHosts lets channels send to loopback, private and
+	// link-local addresses. Keep it off when users cannot be trusted with
+	// requests from inside the server's network.
+	AllowInternalHosts bool `yaml:"allow_internal_hosts" toml:"allow_internal_hosts"`
+}
+
+// SMTPConfig is the outgoing mail server, email is off without a host
+type SMTPConfig struct {
+	Host     string `yaml:"host" toml:"host"`
+	Port     int    `yaml:"port" toml:"port"`
+	Username string `yaml:"username" toml:"username"`
+	Password string `yaml:"password" toml:"password"`
+	From     string `yaml:"from" toml:"from"`
+}
+
+// EnvPrefix is the prefix of all environment variables, e.g. PROGRESS_ADDR
+const EnvPrefix = "PROGRESS_"
+
+// Default returns the configuration used when nothing is set
+func Default() *Config {
+	return &Config{
+		Server:   ServerConfig{Addr: ":8080"},
+		Database: DatabaseConfig{DSN: "./tracker.db"},
+		CORS: CORSConfig{AllowedOrigins: []string{
+			"http://localhost:5173",     // Vite dev server
+			"http://localhost:5174",     // Vite dev server
+			"http://localhost:3000",     // Docker serve
+			"http://192.168.1.101:5173", // Mobile testing
+		}},
+		Log:           LogConfig{Level: "info"},
+		Features:      FeatureConfig{Registration: true, Reminders: true, Swagger: true},
+		Trash:         TrashConfig{RetentionDays: 30},
+		Notifications: NotificationConfig{SMTP: SMTPConfig{Port: 587}},
+	}
+}
+
+// Load builds the configuration from the config file, the environment and the
+// command line arguments (without the program name) and validates it. The
+// config file is given with -config or PROGRESS_CONFIG. The arguments left
+// after the flags are returned.
+func Load(args []string, getenv func(string) string) (*Config, []string, error) {
+	cfg := Default()
+
+	flags := flag.NewFlagSet("progress", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	configFile := flags.String("config", "", "path to a YAML or TOML config file")
+	addr := flags.String("addr", "", "listen address, e.g. :8080")
+	tlsCert := flags.String("tls-cert", "", "TLS certificate file")
+	tlsKey := flags.String("tls-key", "", "TLS private key file")
+	dsn := flags.String("db", "", "database DSN")
+	origins := flags.String("cors-origins", "", "comma separated allowed CORS origins")
+	logLevel := flags.String("log-level", "", "log level: debug, info, warn or error")
+	registration := flags.Bool("registration", true, "allow new users to register")
+	reminders := flags.Bool("reminders", true, "send tracker reminders")
+	swagger := flags.Bool("swagger", true, "serve the Swagger UI")
+	retentionDays := flags.Int("trash-retention-days", 0, "days before deleted trackers and entries are purged")
+	internalHosts := flags.Bool("notify-internal-hosts", false, "allow notification channels to reach loopback, private and link-local hosts")
+	if err := flags.Parse(args); err != nil {
+		return nil, nil, err
+	}
+
+	path := *configFile
+	if path == "" {
+		path = getenv(EnvPrefix + "CONFIG")
+	}
+	if path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := cfg.loadEnv(getenv); err != nil {
+		return nil, nil, err
+	}
+
+	// Only flags given on the command line override other sources
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "addr":
+			cfg.Server.Addr = *addr
+		case "tls-cert":
+			cfg.Server.TLSCert = *tlsCert
+		case "tls-key":
+			cfg.Server.TLSKey = *tlsKey
+		case "db":
+			cfg.Database.DSN = *dsn
+		case "cors-origins":
+			cfg.CORS.AllowedOrigins = splitList(*origins)
+		case "log-level":
+			cfg.Log.Level = *logLevel
+		case "registration":
+			cfg.Features.Registration = *registration
+		case "reminders":
+			cfg.Features.Reminders = *reminders
+		case "swagger":
+			cfg.Features.Swagger = *swagger
+		case "trash-retention-days":
+			cfg.Trash.RetentionDays = *retentionDays
+		case "notify-internal-hosts":
+			cfg.Notifications.AllowInternalHosts = *internalHosts
+		}
+	})
+
+	if err := cfg.Validate(); err != nil {
+		return nil, nil, err
+	}
+	return cfg, flags.Args(), nil
+}
+
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading config file: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("parsing %s: %w", path, err)
+		}
+	case ".toml":
+		meta, err := toml.Decode(string(data), c)
+		if err != nil {
+			return fmt.Errorf("parsing %s: %w", path, err)
+		}
+		if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+			return fmt.Errorf("parsing %s: unknown field %s", path, undecoded[0])
+		}
+	default:
+		return fmt.Errorf("unsupported config file %s, use .yaml, .yml or .toml", path)
+	}
+	return nil
+}
+
+func (c *Config) loadEnv(getenv func(string) string) error {
+	values := map[string]*string{
+		"ADDR":          &c.Server.Addr,
+		"TLS_CERT":      &c.Server.TLSCert,
+		"TLS_KEY":       &c.Server.TLSKey,
+		"DATABASE_DSN":  &c.Database.DSN,
+		"LOG_LEVEL":     &c.Log.Level,
+		"SMTP_HOST":     &c.Notifications.SMTP.Host,
+		"SMTP_USERNAME": &c.Notifications.SMTP.Username,
+		"SMTP_PASSWORD": &c.Notifications.SMTP.Password,
+		"SMTP_FROM":     &c.Notifications.SMTP.From,
+	}
+	for name, field := range values {
+		if value := getenv(EnvPrefix + name); value != "" {
+			*field = value
+		}
+	}
+
+	if value := getenv(EnvPrefix + "CORS_ORIGINS"); value != "" {
+		c.CORS.AllowedOrigins = splitList(value)
+	}
+
+	bools := map[string]*bool{
+		"FEATURE_REGISTRATION":  &c.Features.Registration,
+		"FEATURE_REMINDERS":     &c.Features.Reminders,
+		"FEATURE_SWAGGER":       &c.Features.Swagger,
+		"NOTIFY_INTERNAL_HOSTS": &c.Notifications.AllowInternalHosts,
+	}
+	for name, field := range bools {
+		value := getenv(EnvPrefix + name)
+		if value == "" {
+			continue
+		}
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%s%s must be true or false, got '%s'", EnvPrefix, name, value)
+		}
+		*field = enabled
+	}
+
+	if value := getenv(EnvPrefix + "TRASH_RETENTION_DAYS"); value != "" {
+		days, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%sTRASH_RETENTION_DAYS must be a number of days, got '%s'", EnvPrefix, value)
+		}
+		c.Trash.RetentionDays = days
+	}
+
+	if value := getenv(EnvPrefix + "SMTP_PORT"); value != "" {
+		port, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%sSMTP_PORT must be a port number, got '%s'", EnvPrefix, value)
+		}
+		c.Notifications.SMTP.Port = port
+	}
+	return nil
+}
+
+// Validate checks that the configuration can be used to start the server
+func (c *Config) Validate() error {
+	if _, _, err := net.SplitHostPort(c.Server.Addr); err != nil {
+		return fmt.Errorf("invalid listen address '%s': %w", c.Server.Addr, err)
+	}
+
+	if (c.Server.TLSCert == "") != (c.Server.TLSKey == "") {
+		return errors.New("TLS needs both a certificate and a key")
+	}
+	for _, file := range []string{c.Server.TLSCert, c.Server.TLSKey} {
+		if file == "" {
+			continue
+		}
+		if _, err := os.Stat(file); err != nil {
+			return fmt.Errorf("TLS file: %w", err)
+		}
+	}
+
+	if c.Database.DSN == "" {
+		return errors.New("database DSN must not be empty")
+	}
+
+	for _, origin := range c.CORS.AllowedOrigins {
+		if origin == "*" {
+			continue
+		}
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || (u.Path != "" && u.Path != "/") {
+			return fmt.Errorf("invalid CORS origin '%s', expected e.g. https://example.com", origin)
+		}
+	}
+
+	if _, err := c.LogLevel(); err != nil {
+		return err
+	}
+
+	if c.Trash.RetentionDays < 1 {
+		return fmt.Errorf("trash retention must be at least 1 day, got %d", c.Trash.RetentionDays)
+	}
+
+	if smtp := c.Notifications.SMTP; smtp.Host != "" {
+		if smtp.Port < 1 || smtp.Port > 65535 {
+			return fmt.Errorf("invalid SMTP port %d", smtp.Port)
+		}
+		if smtp.From == "" {
+			return errors.New("SMTP needs a from address")
+		}
+	}
+	return nil
+}
+
+// LogLevel returns the configured log level
+func (c *Config) LogLevel() (slog.Level, error) {
+	switch strings.ToLower(c.Log.Level) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return 0, fmt.Errorf("invalid log level '%s', use debug, info, warn or error", c.Log.Level)
+}
+
+// TrashRetention is how long deleted trackers and entries stay in the trash
+func (c *Config) TrashRetention() time.Duration {
+	return time.Duration(c.Trash.RetentionDays) * 24 * time.Hour
+}
+
+// TLS reports whether the server should serve HTTPS
+func (c *Config) TLS() bool {
+	return c.Server.TLSCert != ""
+}
+
+// Print displays the effective configuration
+func (c *Config) Print(w io.Writer) {
+	fmt.Fprintln(w, "⚙️  Configuration:")
+	fmt.Fprintf(w, "      Listen address:  %s\n", c.Server.Addr)
+	if c.TLS() {
+		fmt.Fprintf(w, "      TLS:             %s, %s\n", c.Server.TLSCert, c.Server.TLSKey)
+	} else {
+		fmt.Fprintln(w, "      TLS:             off")
+	}
+	fmt.Fprintf(w, "      Database:        %s\n", redactDSN(c.Database.DSN))
+	fmt.Fprintf(w, "      CORS origins:    %s\n", strings.Join(c.CORS.AllowedOrigins, ", "))
+	fmt.Fprintf(w, "      Log level:       %s\n", c.Log.Level)
+	fmt.Fprintf(w, "      Registration:    %s\n", onOff(c.Features.Registration))
+	fmt.Fprintf(w, "      Reminders:       %s\n", onOff(c.Features.Reminders))
+	fmt.Fprintf(w, "      Swagger UI:      %s\n", onOff(c.Features.Swagger))
+	fmt.Fprintf(w, "      Trash retention: %d days\n", c.Trash.RetentionDays)
+	if smtp := c.Notifications.SMTP; smtp.Host != "" {
+		fmt.Fprintf(w, "      SMTP server:     %s\n", net.JoinHostPort(smtp.Host, strconv.Itoa(smtp.Port)))
+	} else {
+		fmt.Fprintln(w, "      SMTP server:     off")
+	}
+	fmt.Fprintf(w, "      Internal hosts:  %s\n", onOff(c.Notifications.AllowInternalHosts))
+	fmt.Fprintln(w)
+}
+
+func onOff(enabled bool) string {
+	if enabled {
+		return "on"
+	}
+	return "off"
+}
+
+// redactDSN hides the password of URL style DSNs
+func redactDSN(dsn string) string {
+	u, err := url.Parse(dsn)
+	if err != nil || u.User == nil {
+		return dsn
+	}
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), "xxxxx")
+	}
+	return u.String()
+}
+
+func splitList(value string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,574 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"routine-tracker/models"
+	"routine-tracker/trackers"
+	"routine-tracker/trackers/duration"
+	"routine-tracker/trackers/habit"
+	"routine-tracker/trackers/target"
+	"time"
+)
+
+// ExportArchive collects all trackers, entries, pauses, categories and tags of
+// a user, leaving out the trash
+func (s *sqlStore) ExportArchive(userID int, exportedAt time.Time) (*trackers.Archive, error) {
+	habits, err := s.GetAllHabitTrackers(userID, models.ALL_TRACKERS)
+	if err != nil {
+		return nil, err
+	}
+	targets, err := s.GetAllTargetTrackers(userID, models.ALL_TRACKERS)
+	if err != nil {
+		return nil, err
+	}
+	durations, err := s.GetAllDurationTrackers(userID, models.ALL_TRACKERS)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.GetAllEntries(userID)
+	if err != nil {
+		return nil, err
+	}
+	pauses, err := s.GetPauses(userID)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := s.GetCategories(userID)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := s.GetTags(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	archive := &trackers.Archive{
+		Version:          trackers.ArchiveVersion,
+		ExportedAt:       exportedAt.UTC(),
+		HabitTrackers:    make([]habit.HabitTracker, 0, len(habits)),
+		TargetTrackers:   make([]target.TargetTracker, 0, len(targets)),
+		DurationTrackers: make([]duration.DurationTracker, 0, len(durations)),
+		Entries:          make([]models.Entry, 0, len(entries)),
+		Pauses:           pauses,
+		Categories:       categories,
+		Tags:             tags,
+	}
+	archive.HabitTrackers = append(archive.HabitTrackers, habits...)
+	for _, t := range targets {
+		t.OriginalStartValue = t.StartValue
+		archive.TargetTrackers = append(archive.TargetTrackers, t)
+		archive.TargetSegments = append(archive.TargetSegments, t.Segments...)
+	}
+	// A running timer is not an entry yet and stays out of the archive
+	for _, d := range durations {
+		d.Timer = nil
+		archive.DurationTrackers = append(archive.DurationTrackers, d)
+	}
+	archive.Entries = append(archive.Entries, entries...)
+
+	return archive, nil
+}
+
+// trackerKey identifies a tracker across both tracker tables
+type trackerKey struct {
+	Type models.TrackerType
+	ID   int
+}
+
+// trackerName identifies a tracker by name when merging
+type trackerName struct {
+	Type models.TrackerType
+	Name string
+}
+
+// ImportArchive writes an archive into the user's data in a single
+// transaction. A dry run performs the same work and rolls it back.
+func (s *sqlStore) ImportArchive(userID int, archive trackers.Archive, mode trackers.ImportMode, dryRun bool, now time.Time) (*trackers.ImportResult, error) {
+	result := &trackers.ImportResult{DryRun: dryRun, Mode: mode}
+
+	tx, err := s.begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if mode == trackers.IMPORT_REPLACE {
+		if err := deleteUserTrackers(tx, userID, result); err != nil {
+			return nil, err
+		}
+	}
+
+	existing := make(map[trackerName]int)
+	if mode == trackers.IMPORT_MERGE {
+		if existing, err = existingTrackersByName(tx, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	categoryIDs, err := importCategories(tx, userID, archive.Categories, now, result)
+	if err != nil {
+		return nil, err
+	}
+	tagIDs, err := importTags(tx, userID, archive.Tags, now, result)
+	if err != nil {
+		return nil, err
+	}
+
+	// Old archive IDs to the IDs in this database
+	ids := make(map[trackerKey]int)
+	// Entries of merged trackers, used to skip duplicates
+	merged := make(map[trackerKey][]models.Entry)
+
+	for _, h := range archive.HabitTrackers {
+		if id, ok := existing[trackerName{models.HABIT, h.TrackerName}]; ok {
+			ids[trackerKey{models.HABIT, h.ID}] = id
+			if merged[trackerKey{models.HABIT, id}], err = trackerEntries(tx, userID, id, models.HABIT); err != nil {
+				return nil, err
+			}
+			result.TrackersMerged++
+			continue
+		}
+
+		h.CategoryID = remapID(categoryIDs, h.CategoryID)
+		id, err := insertArchivedHabit(tx, userID, h, now)
+		if err == nil {
+			err = insertArchivedTags(tx, models.HABIT, id, h.Tags, tagIDs)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("habit tracker '%s': %w", h.TrackerName, err)
+		}
+		ids[trackerKey{models.HABIT, h.ID}] = id
+		result.HabitTrackersCreated++
+	}
+
+	// Merged trackers keep their own goal changes
+	segments := make(map[int][]target.Segment)
+	for _, seg := range archive.TargetSegments {
+		segments[seg.TrackerID] = append(segments[seg.TrackerID], seg)
+	}
+	for _, t := range archive.TargetTrackers {
+		if id, ok := existing[trackerName{models.TARGET, t.TrackerName}]; ok {
+			ids[trackerKey{models.TARGET, t.ID}] = id
+			if merged[trackerKey{models.TARGET, id}], err = trackerEntries(tx, userID, id, models.TARGET); err != nil {
+				return nil, err
+			}
+			result.TrackersMerged++
+			continue
+		}
+
+		t.CategoryID = remapID(categoryIDs, t.CategoryID)
+		id, err := insertArchivedTarget(tx, userID, t, segments[t.ID], now)
+		if err == nil {
+			err = insertArchivedTags(tx, models.TARGET, id, t.Tags, tagIDs)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("target tracker '%s': %w", t.TrackerName, err)
+		}
+		ids[trackerKey{models.TARGET, t.ID}] = id
+		result.TargetTrackersCreated++
+	}
+
+	for _, d := range archive.DurationTrackers {
+		if id, ok := existing[trackerName{models.DURATION, d.TrackerName}]; ok {
+			ids[trackerKey{models.DURATION, d.ID}] = id
+			if merged[trackerKey{models.DURATION, id}], err = trackerEntries(tx, userID, id, models.DURATION); err != nil {
+				return nil, err
+			}
+			result.TrackersMerged++
+			continue
+		}
+
+		d.CategoryID = remapID(categoryIDs, d.CategoryID)
+		id, err := insertArchivedDuration(tx, userID, d, now)
+		if err == nil {
+			err = insertArchivedTags(tx, models.DURATION, id, d.Tags, tagIDs)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("duration tracker '%s': %w", d.TrackerName, err)
+		}
+		ids[trackerKey{models.DURATION, d.ID}] = id
+		result.DurationTrackersCreated++
+	}
+
+	for _, e := range archive.Entries {
+		trackerID := ids[trackerKey{e.Type, e.TrackerID}]
+		if containsEntry(merged[trackerKey{e.Type, trackerID}], e) {
+			result.EntriesSkipped++
+			continue
+		}
+
+		_, err := tx.exec(
+			"INSERT INTO entries (user_id, tracker_id, type, value, done, skipped, duration, date, note, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+			userID, trackerID, e.Type, e.Value, e.Done, e.Skipped, e.Duration, e.Date, e.Note, archivedCreatedAt(e.CreatedAt, now),
+		)
+		if err != nil {
+			return nil, err
+		}
+		result.EntriesCreated++
+	}
+
+	existingPauses, err := userPauses(tx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range archive.Pauses {
+		if p.TrackerType != nil {
+			trackerID := ids[trackerKey{*p.TrackerType, *p.TrackerID}]
+			p.TrackerID = &trackerID
+		}
+		if containsPause(existingPauses, p) {
+			continue
+		}
+
+		var trackerType sql.NullString
+		if p.TrackerType != nil {
+			trackerType = sql.NullString{String: string(*p.TrackerType), Valid: true}
+		}
+		_, err := tx.exec(
+			"INSERT INTO pauses (user_id, tracker_type, tracker_id, start_date, end_date, reason, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)",
+			userID, trackerType, p.TrackerID, p.StartDate, p.EndDate, p.Reason, archivedCreatedAt(p.CreatedAt, now),
+		)
+		if err != nil {
+			return nil, err
+		}
+		result.PausesCreated++
+	}
+
+	if dryRun {
+		return result, nil
+	}
+	return result, tx.Commit()
+}
+
+func existingTrackersByName(tx *sqlTx, userID int) (map[trackerName]int, error) {
+	existing := make(map[trackerName]int)
+	for _, table := range trackerTables {
+		rows, err := tx.query("SELECT id, tracker_name FROM "+table.name+" WHERE user_id = ? AND deleted_at IS NULL ORDER BY id", userID)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var id int
+			var name string
+			if err := rows.Scan(&id, &name); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			key := trackerName{table.trackerType, name}
+			if _, ok := existing[key]; !ok {
+				existing[key] = id
+			}
+		}
+		rows.Close()
+	}
+	return existing, nil
+}
+
+func trackerEntries(tx *sqlTx, userID int, trackerID int, trackerType models.TrackerType) ([]models.Entry, error) {
+	rows, err := tx.query(
+		"SELECT value, done, skipped, duration, date, note FROM entries WHERE user_id = ? AND tracker_id = ? AND type = ? AND deleted_at IS NULL",
+		userID, trackerID, trackerType,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []models.Entry
+	for rows.Next() {
+		var e models.Entry
+		var value sql.NullFloat64
+		var done sql.NullBool
+		var duration sql.NullInt64
+		var note sql.NullString
+		if err := rows.Scan(&value, &done, &e.Skipped, &duration, &e.Date, &note); err != nil {
+			return nil, err
+		}
+		e.Value = value.Float64
+		if done.Valid {
+			e.Done = &done.Bool
+		}
+		if duration.Valid {
+			seconds := int(duration.Int64)
+			e.Duration = &seconds
+		}
+		e.Note = note.String
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// containsEntry reports whether an identical entry is already in the list
+func containsEntry(entries []models.Entry, e models.Entry) bool {
+	for _, other := range entries {
+		if other.Date.Equal(e.Date) && other.Value == e.Value && other.Note == e.Note && other.Skipped == e.Skipped &&
+			(other.Done == nil) == (e.Done == nil) && (other.Done == nil || *other.Done == *e.Done) &&
+			(other.Duration == nil) == (e.Duration == nil) && (other.Duration == nil || *other.Duration == *e.Duration) {
+			return true
+		}
+	}
+	return false
+}
+
+func deleteUserTrackers(tx *sqlTx, userID int, result *trackers.ImportResult) error {
+	res, err := tx.exec("DELETE FROM entries WHERE user_id = ?", userID)
+	if err != nil {
+		return err
+	}
+	entries, _ := res.RowsAffected()
+	result.EntriesDeleted = int(entries)
+
+	_, err = tx.exec("DELETE FROM target_segments WHERE tracker_id IN (SELECT id FROM target_trackers WHERE user_id = ?)", userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.exec("DELETE FROM tracker_tags WHERE tag_id IN (SELECT id FROM tags WHERE user_id = ?)", userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.exec("DELETE FROM timers WHERE user_id = ?", userID)
+	if err != nil {
+		return err
+	}
+
+	for _, table := range trackerTables {
+		res, err := tx.exec("DELETE FROM "+table.name+" WHERE user_id = ?", userID)
+		if err != nil {
+			return err
+		}
+		deleted, _ := res.RowsAffected()
+		result.TrackersDeleted += int(deleted)
+	}
+
+	// Channels of deleted trackers would never be used again
+	_, err = tx.exec("DELETE FROM notification_channels WHERE user_id = ? AND tracker_id IS NOT NULL", userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.exec("DELETE FROM pauses WHERE user_id = ?", userID)
+	return err
+}
+
+func userPauses(tx *sqlTx, userID int) ([]models.Pause, error) {
+	rows, err := tx.query("SELECT "+pauseColumns+" FROM pauses WHERE user_id = ?", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var pauses []models.Pause
+	for rows.Next() {
+		pause, err := scanPause(rows)
+		if err != nil {
+			return nil, err
+		}
+		pauses = append(pauses, *pause)
+	}
+	return pauses, nil
+}
+
+// containsPause reports whether a pause of the same tracker and dates is
+// already in the list
+func containsPause(pauses []models.Pause, p models.Pause) bool {
+	for _, other := range pauses {
+		if other.StartDate.Equal(p.StartDate) && other.EndDate.Equal(p.EndDate) &&
+			(other.TrackerType == nil) == (p.TrackerType == nil) &&
+			(other.TrackerType == nil || (*other.TrackerType == *p.TrackerType && *other.TrackerID == *p.TrackerID)) {
+			return true
+		}
+	}
+	return false
+}
+
+// importCategories maps the archived categories to existing categories of the
+// same name, creating the missing ones
+func importCategories(tx *sqlTx, userID int, categories []models.Category, now time.Time, result *trackers.ImportResult) (map[int]int, error) {
+	existing, err := namedIDs(tx, "categories", userID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[int]int)
+	for _, c := range categories {
+		if id, ok := existing[c.Name]; ok {
+			ids[c.ID] = id
+			continue
+		}
+
+		id, err := tx.insert(
+			"INSERT INTO categories (user_id, name, color, icon, created_at) VALUES (?, ?, ?, ?, ?)",
+			userID, c.Name, c.Color, c.Icon, archivedCreatedAt(c.CreatedAt, now),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("category '%s': %w", c.Name, err)
+		}
+		existing[c.Name] = id
+		ids[c.ID] = id
+		result.CategoriesCreated++
+	}
+	return ids, nil
+}
+
+// importTags maps the archived tags to existing tags of the same name,
+// creating the missing ones
+func importTags(tx *sqlTx, userID int, tags []models.Tag, now time.Time, result *trackers.ImportResult) (map[int]int, error) {
+	existing, err := namedIDs(tx, "tags", userID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[int]int)
+	for _, t := range tags {
+		if id, ok := existing[t.Name]; ok {
+			ids[t.ID] = id
+			continue
+		}
+
+		id, err := tx.insert("INSERT INTO tags (user_id, name, created_at) VALUES (?, ?, ?)", userID, t.Name, archivedCreatedAt(t.CreatedAt, now))
+		if err != nil {
+			return nil, fmt.Errorf("tag '%s': %w", t.Name, err)
+		}
+		existing[t.Name] = id
+		ids[t.ID] = id
+		result.TagsCreated++
+	}
+	return ids, nil
+}
+
+// namedIDs returns the IDs of a user's categories or tags by name
+func namedIDs(tx *sqlTx, table string, userID int) (map[string]int, error) {
+	rows, err := tx.query("SELECT id, name FROM "+table+" WHERE user_id = ? ORDER BY id", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make(map[string]int)
+	for rows.Next() {
+		var id int
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			return nil, err
+		}
+		if _, ok := ids[name]; !ok {
+			ids[name] = id
+		}
+	}
+	return ids, rows.Err()
+}
+
+// remapID returns the new ID of an archived category, nil for trackers without
+// one or with one the archive does not list
+func remapID(ids map[int]int, id *int) *int {
+	if id == nil {
+		return nil
+	}
+	newID, ok := ids[*id]
+	if !ok {
+		return nil
+	}
+	return &newID
+}
+
+// insertArchivedTags tags an imported tracker with the new IDs of its tags,
+// leaving out tags the archive does not list
+func insertArchivedTags(tx *sqlTx, trackerType models.TrackerType, trackerID int, tags []models.Tag, ids map[int]int) error {
+	added := make(map[int]bool)
+	for _, tag := range tags {
+		tagID, ok := ids[tag.ID]
+		if !ok || added[tagID] {
+			continue
+		}
+		added[tagID] = true
+		if _, err := tx.exec("INSERT INTO tracker_tags (tag_id, tracker_type, tracker_id) VALUES (?, ?, ?)", tagID, trackerType, trackerID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func insertArchivedHabit(tx *sqlTx, userID int, h habit.HabitTracker, now time.Time) (int, error) {
+	dueSpecificDays, _ := json.Marshal(h.Due.SpecificDays)
+	reminderTimes, _ := json.Marshal(h.Reminders.Times)
+
+	return tx.insert(`
+        INSERT INTO habit_trackers (
+            user_id, tracker_name, goal, time_period, start_date, due_type,
+            due_specific_days, due_interval_type, due_interval_value, due_rrule,
+            reminder_times, reminder_enabled, bad_habit, goal_streak, created_at, archived_at, position, pinned, category_id
+        ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+    `,
+		userID, h.TrackerName, h.Goal, h.TimePeriod, h.StartDate, h.Due.Type,
+		string(dueSpecificDays), h.Due.IntervalType, h.Due.IntervalValue, h.Due.RRule,
+		string(reminderTimes), h.Reminders.Enabled, h.BadHabit, h.GoalStreak, archivedCreatedAt(h.CreatedAt, now), h.ArchivedAt, h.Position, h.Pinned, h.CategoryID,
+	)
+}
+
+// insertArchivedTarget inserts a target tracker with its segments, or with its
+// initial segment when the archive has none for it
+func insertArchivedTarget(tx *sqlTx, userID int, t target.TargetTracker, segments []target.Segment, now time.Time) (int, error) {
+	dueSpecificDays, _ := json.Marshal(t.Due.SpecificDays)
+	reminderTimes, _ := json.Marshal(t.Reminders.Times)
+
+	trendWeightType := "none"
+	if t.TrendWeightType != nil {
+		trendWeightType = *t.TrendWeightType
+	}
+
+	id, err := tx.insert(`
+        INSERT INTO target_trackers (
+            user_id, tracker_name, start_value, goal_value, start_date, goal_date, add_to_total, use_actual_bounds, trend_weight_type,
+            due_type, due_specific_days, due_interval_type, due_interval_value, due_rrule,
+            reminder_times, reminder_enabled, created_at, archived_at, position, pinned, category_id
+        ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+    `,
+		userID, t.TrackerName, t.StartValue, t.GoalValue, t.StartDate, t.GoalDate, t.AddToTotal, t.UseActualBounds, trendWeightType,
+		t.Due.Type, string(dueSpecificDays), t.Due.IntervalType, t.Due.IntervalValue, t.Due.RRule,
+		string(reminderTimes), t.Reminders.Enabled, archivedCreatedAt(t.CreatedAt, now), t.ArchivedAt, t.Position, t.Pinned, t.CategoryID,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(segments) == 0 {
+		segments = []target.Segment{t.InitialSegment()}
+	}
+	for _, seg := range segments {
+		_, err = tx.insert(insertSegmentQuery, id, seg.EffectiveFrom, seg.GoalValue, seg.GoalDate, seg.AddToTotal, archivedCreatedAt(seg.CreatedAt, now))
+		if err != nil {
+			return 0, err
+		}
+	}
+	return id, nil
+}
+
+func insertArchivedDuration(tx *sqlTx, userID int, d duration.DurationTracker, now time.Time) (int, error) {
+	dueSpecificDays, _ := json.Marshal(d.Due.SpecificDays)
+	reminderTimes, _ := json.Marshal(d.Reminders.Times)
+
+	return tx.insert(`
+        INSERT INTO duration_trackers (
+            user_id, tracker_name, goal, time_period, start_date, due_type,
+            due_specific_days, due_interval_type, due_interval_value, due_rrule,
+            reminder_times, reminder_enabled, created_at, archived_at, position, pinned, category_id
+        ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+    `,
+		userID, d.TrackerName, d.Goal, d.TimePeriod, d.StartDate, d.Due.Type,
+		string(dueSpecificDays), d.Due.IntervalType, d.Due.IntervalValue, d.Due.RRule,
+		string(reminderTimes), d.Reminders.Enabled, archivedCreatedAt(d.CreatedAt, now), d.ArchivedAt, d.Position, d.Pinned, d.CategoryID,
+	)
+}
+
+// archivedCreatedAt returns the creation time of an imported item, now when
+// the archive has none
+func archivedCreatedAt(createdAt time.Time, now time.Time) time.Time {
+	if createdAt.IsZero() {
+		return now
+	}
+	return createdAt
+}
@@ -0,0 +1,85 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"routine-tracker/models"
+	"strconv"
+)
+
+const auditEventColumns = `id, user_id, actor, api_token_id, action, entity, entity_id, changes, created_at`
+
+// CreateAuditEvent appends an event to the audit log
+func (s *sqlStore) CreateAuditEvent(event models.AuditEvent) (*models.AuditEvent, error) {
+	changes, err := json.Marshal(event.Changes)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := s.insert(`
+        INSERT INTO audit_events (user_id, actor, api_token_id, action, entity, entity_id, changes, created_at)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+    `, event.UserID, event.Actor, event.APITokenID, event.Action, event.Entity, event.EntityID, string(changes), event.CreatedAt.UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	event.ID = id
+	return &event, nil
+}
+
+// GetAuditEvents returns the audit events of a user matching the filter, the
+// newest first
+func (s *sqlStore) GetAuditEvents(userID int, filter models.AuditFilter) ([]models.AuditEvent, error) {
+	query := `SELECT ` + auditEventColumns + ` FROM audit_events WHERE user_id = ?`
+	args := []interface{}{userID}
+	if filter.Entity != "" {
+		query += " AND entity = ?"
+		args = append(args, filter.Entity)
+	}
+	if filter.EntityID != nil {
+		query += " AND entity_id = ?"
+		args = append(args, *filter.EntityID)
+	}
+	query += " ORDER BY created_at DESC, id DESC"
+	if filter.Limit > 0 {
+		query += " LIMIT " + strconv.Itoa(filter.Limit)
+	}
+
+	rows, err := s.query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]models.AuditEvent, 0)
+	for rows.Next() {
+		event, err := scanAuditEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, *event)
+	}
+	return events, rows.Err()
+}
+
+func scanAuditEvent(row rowScanner) (*models.AuditEvent, error) {
+	var e models.AuditEvent
+	var apiTokenID sql.NullInt64
+	var changes string
+
+	err := row.Scan(&e.ID, &e.UserID, &e.Actor, &apiTokenID, &e.Action, &e.Entity, &e.EntityID, &changes, &e.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if apiTokenID.Valid {
+		id := int(apiTokenID.Int64)
+		e.APITokenID = &id
+	}
+	if err := json.Unmarshal([]byte(changes), &e.Changes); err != nil {
+		return nil, err
+	}
+
+	return &e, nil
+}
@@ -1,135 +1,34 @@
 package database
 
 import (
-    "database/sql"
-    "log"
-    _ "github.com/mattn/go-sqlite3"
+	"log/slog"
+	"strings"
 )
 
-var DB *sql.DB
-
-func Init() error {
-    var err error
-    DB, err = sql.Open("sqlite3", "./tracker.db")
-    if err != nil {
-        return err
-    }
-    
-    if err = DB.Ping(); err != nil {
-        return err
-    }
-    
-    log.Println("📦 Database connected successfully")
-    
-    return createTables()
-}
-
-func createTables() error {
-    habitTable := `
-    CREATE TABLE IF NOT EXISTS habit_trackers (
-        id INTEGER PRIMARY KEY AUTOINCREMENT,
-        tracker_name TEXT NOT NULL,
-        goal REAL NOT NULL,
-        time_period TEXT NOT NULL,
-        start_date DATETIME NOT NULL,
-        due_type TEXT NOT NULL,
-        due_specific_days TEXT,
-        due_interval_type TEXT,
-        due_interval_value INTEGER,
-        reminder_times TEXT,
-        reminder_enabled BOOLEAN DEFAULT TRUE,
-        bad_habit BOOLEAN DEFAULT FALSE,
-        goal_streak INTEGER,
-        created_at DATETIME DEFAULT CURRENT_TIMESTAMP
-    )`
-    
-    targetTable := `
-    CREATE TABLE IF NOT EXISTS target_trackers (
-        id INTEGER PRIMARY KEY AUTOINCREMENT,
-        tracker_name TEXT NOT NULL,
-        start_value REAL NOT NULL,
-        goal_value REAL NOT NULL,
-        start_date DATETIME NOT NULL,
-        goal_date DATETIME NOT NULL,
-        add_to_total BOOLEAN DEFAULT FALSE,
-        use_actual_bounds BOOLEAN DEFAULT FALSE,
-        trend_weight_type TEXT DEFAULT 'none',
-        due_type TEXT NOT NULL,
-        due_specific_days TEXT,
-        due_interval_type TEXT,
-        due_interval_value INTEGER,
-        reminder_times TEXT,
-        reminder_enabled BOOLEAN DEFAULT TRUE,
-        created_at DATETIME DEFAULT CURRENT_TIMESTAMP
-    )`
-    
-    entriesTable := `
-    CREATE TABLE IF NOT EXISTS entries (
-        id INTEGER PRIMARY KEY AUTOINCREMENT,
-        tracker_id INTEGER NOT NULL,
-        type TEXT NOT NULL,
-        value REAL,
-        done BOOLEAN,
-        date DATETIME NOT NULL,
-        note TEXT,
-        created_at DATETIME DEFAULT CURRENT_TIMESTAMP
-    )`
-    
-    tables := []string{habitTable, targetTable, entriesTable}
-    
-    for _, table := range tables {
-        if _, err := DB.Exec(table); err != nil {
-            return err
-        }
-    }
-    
-    log.Println("📋 Database tables created/verified")
-    return runMigrations()
+// Open connects to the database without applying migrations. postgres://
+// and postgresql:// URLs use PostgreSQL, anything else is the path of a
+// SQLite database. Migrations are logged to logger.
+func Open(dsn string, logger *slog.Logger) (Store, error) {
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		return NewPostgresStore(dsn, logger)
+	}
+	return NewSQLiteStore(strings.TrimPrefix(dsn, "sqlite://"), logger)
 }
 
-func runMigrations() error {
-    // Check if use_actual_bounds column exists, if not add it
-    _, err := DB.Exec("SELECT use_actual_bounds FROM target_trackers LIMIT 1")
-    if err != nil {
-        // Column doesn't exist, add it
-        _, err := DB.Exec("ALTER TABLE target_trackers ADD COLUMN use_actual_bounds BOOLEAN DEFAULT FALSE")
-        if err != nil {
-            return err
-        }
-        log.Println("📋 Added use_actual_bounds column to target_trackers table")
-    }
+// Init connects to the database and applies pending migrations
+func Init(dsn string, logger *slog.Logger) (Store, error) {
+	store, err := Open(dsn, logger)
+	if err != nil {
+		return nil, err
+	}
 
-    // Check if trend_weight_type column exists, if not add it
-    _, err = DB.Exec("SELECT trend_weight_type FROM target_trackers LIMIT 1")
-    if err != nil {
-        // Column doesn't exist, add it
-        _, err := DB.Exec("ALTER TABLE target_trackers ADD COLUMN trend_weight_type TEXT DEFAULT 'none'")
-        if err != nil {
-            return err
-        }
-        log.Println("📋 Added trend_weight_type column to target_trackers table")
-    }
+	logger.Info("📦 Database connected successfully")
 
-    return nil
-}
-
-func Close() error {
-    if DB != nil {
-        return DB.Close()
-    }
-    return nil
-}
+	if _, err := store.MigrateUp(0); err != nil {
+		store.Close()
+		return nil, err
+	}
 
-func InitTest(dbPath string) error {
-    var err error
-    DB, err = sql.Open("sqlite3", dbPath)
-    if err != nil {
-        return err
-    }
-    
-    if err = DB.Ping(); err != nil {
-        return err
-    }
-    
-    return createTables()
+	logger.Info("📋 Database schema is up to date")
+	return store, nil
 }
@@ -0,0 +1,298 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"routine-tracker/models"
+	"routine-tracker/trackers/duration"
+	"time"
+)
+
+// ErrTimerRunning is returned when a timer is started while another one of
+// the same user is running
+var ErrTimerRunning = errors.New("a timer is already running")
+
+// ErrTrackerArchived is returned when a timer is started on an archived tracker
+var ErrTrackerArchived = errors.New("the tracker is archived")
+
+func (s *sqlStore) CreateDurationTracker(userID int, d duration.DurationTracker) (*duration.DurationTracker, error) {
+	dueSpecificDays, _ := json.Marshal(d.Due.SpecificDays)
+	reminderTimes, _ := json.Marshal(d.Reminders.Times)
+
+	id, err := s.insert(`
+        INSERT INTO duration_trackers (
+            user_id, tracker_name, goal, time_period, start_date, due_type,
+            due_specific_days, due_interval_type, due_interval_value, due_rrule,
+            reminder_times, reminder_enabled, pinned, created_at
+        ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+    `,
+		userID, d.TrackerName, d.Goal, d.TimePeriod, d.StartDate, d.Due.Type,
+		string(dueSpecificDays), d.Due.IntervalType, d.Due.IntervalValue, d.Due.RRule,
+		string(reminderTimes), d.Reminders.Enabled, d.Pinned, d.CreatedAt.UTC(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetDurationTrackerByID(userID, id)
+}
+
+func (s *sqlStore) GetAllDurationTrackers(userID int, filter models.ArchiveFilter) ([]duration.DurationTracker, error) {
+	return s.queryDurationTrackers(userID, "deleted_at IS NULL"+archivedCondition(filter))
+}
+
+// durationColumns are the columns read by scanDurationTracker
+const durationColumns = `id, tracker_name, goal, time_period, start_date, due_type,
+               due_specific_days, due_interval_type, due_interval_value, due_rrule,
+               reminder_times, reminder_enabled, created_at, archived_at, deleted_at, category_id, position, pinned`
+
+func scanDurationTracker(row rowScanner) (duration.DurationTracker, error) {
+	var d duration.DurationTracker
+	var dueSpecificDaysJSON, reminderTimesJSON string
+	var archivedAt, deletedAt sql.NullTime
+	var categoryID sql.NullInt64
+
+	err := row.Scan(
+		&d.ID, &d.TrackerName, &d.Goal, &d.TimePeriod, &d.StartDate, &d.Due.Type,
+		&dueSpecificDaysJSON, &d.Due.IntervalType, &d.Due.IntervalValue, &d.Due.RRule,
+		&reminderTimesJSON, &d.Reminders.Enabled, &d.CreatedAt, &archivedAt, &deletedAt, &categoryID, &d.Position, &d.Pinned,
+	)
+	if err != nil {
+		return d, err
+	}
+
+	json.Unmarshal([]byte(dueSpecificDaysJSON), &d.Due.SpecificDays)
+	json.Unmarshal([]byte(reminderTimesJSON), &d.Reminders.Times)
+	if archivedAt.Valid {
+		d.ArchivedAt = &archivedAt.Time
+	}
+	if deletedAt.Valid {
+		d.DeletedAt = &deletedAt.Time
+	}
+	d.CategoryID = nullableID(categoryID)
+	return d, nil
+}
+
+// queryDurationTrackers returns the duration trackers of a user matching
+// condition with their tags and running timer
+func (s *sqlStore) queryDurationTrackers(userID int, condition string) ([]duration.DurationTracker, error) {
+	rows, err := s.query(`SELECT `+durationColumns+`
+        FROM duration_trackers WHERE user_id = ? AND `+condition+` ORDER BY `+trackerOrder, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var durations []duration.DurationTracker
+	for rows.Next() {
+		d, err := scanDurationTracker(rows)
+		if err != nil {
+			return nil, err
+		}
+		d.UserID = userID
+		durations = append(durations, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return durations, s.attachTagsAndTimer(userID, durations)
+}
+
+func (s *sqlStore) GetDurationTrackerByID(userID int, id int) (*duration.DurationTracker, error) {
+	query := `SELECT ` + durationColumns + `
+        FROM duration_trackers WHERE id = ? AND user_id = ? AND deleted_at IS NULL`
+	d, err := scanDurationTracker(s.queryRow(query, id, userID))
+	if err != nil {
+		return nil, err
+	}
+	d.UserID = userID
+
+	durations := []duration.DurationTracker{d}
+	if err := s.attachTagsAndTimer(userID, durations); err != nil {
+		return nil, err
+	}
+	return &durations[0], nil
+}
+
+// attachTagsAndTimer sets the tags of duration trackers and the timer of the
+// one with a running timer
+func (s *sqlStore) attachTagsAndTimer(userID int, durations []duration.DurationTracker) error {
+	tags, err := s.trackerTags(userID, models.DURATION)
+	if err != nil {
+		return err
+	}
+	timer, err := s.GetRunningTimer(userID)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	for i := range durations {
+		durations[i].Tags = tagsOf(tags, durations[i].ID)
+		if timer != nil && timer.TrackerID == durations[i].ID {
+			durations[i].Timer = timer
+		}
+	}
+	return nil
+}
+
+func (s *sqlStore) UpdateDurationTracker(userID int, id int, d duration.UpdateDurationRequest) error {
+	current, err := s.GetDurationTrackerByID(userID, id)
+	if err != nil {
+		return err
+	}
+
+	if d.TrackerName != nil {
+		current.TrackerName = *d.TrackerName
+	}
+	if d.Goal != nil {
+		current.Goal = *d.Goal
+	}
+	if d.TimePeriod != nil {
+		current.TimePeriod = *d.TimePeriod
+	}
+	if d.StartDate != nil {
+		startDate, err := time.Parse("2006-01-02", *d.StartDate)
+		if err != nil {
+			return err
+		}
+		current.StartDate = startDate
+	}
+	if d.Due != nil {
+		current.Due = *d.Due
+	}
+	if d.Reminders != nil {
+		current.Reminders = *d.Reminders
+	}
+	if d.Pinned != nil {
+		current.Pinned = *d.Pinned
+	}
+
+	dueSpecificDays, _ := json.Marshal(current.Due.SpecificDays)
+	reminderTimes, _ := json.Marshal(current.Reminders.Times)
+
+	_, err = s.exec(`
+        UPDATE duration_trackers SET
+            tracker_name = ?, goal = ?, time_period = ?, start_date = ?,
+            due_type = ?, due_specific_days = ?, due_interval_type = ?, due_interval_value = ?, due_rrule = ?,
+            reminder_times = ?, reminder_enabled = ?, pinned = ?
+        WHERE id = ? AND user_id = ?
+    `,
+		current.TrackerName, current.Goal, current.TimePeriod, current.StartDate,
+		current.Due.Type, string(dueSpecificDays), current.Due.IntervalType, current.Due.IntervalValue, current.Due.RRule,
+		string(reminderTimes), current.Reminders.Enabled, current.Pinned, id, userID,
+	)
+	return err
+}
+
+// SetDurationTrackerArchived archives a duration tracker at archivedAt, or
+// restores it when archivedAt is nil
+func (s *sqlStore) SetDurationTrackerArchived(userID int, id int, archivedAt *time.Time) error {
+	return s.setArchived("duration_trackers", userID, id, archivedAt)
+}
+
+// DeleteDurationTracker moves a duration tracker and its entries to the trash.
+// A running timer of the tracker is discarded, it would keep the user from
+// starting another one.
+func (s *sqlStore) DeleteDurationTracker(userID int, id int, deletedAt time.Time) error {
+	if err := s.trashTracker("duration_trackers", models.DURATION, userID, id, deletedAt); err != nil {
+		return err
+	}
+	_, err := s.exec("DELETE FROM timers WHERE user_id = ? AND tracker_id = ?", userID, id)
+	return err
+}
+
+// RestoreDurationTracker takes a duration tracker and the entries deleted with
+// it out of the trash
+func (s *sqlStore) RestoreDurationTracker(userID int, id int) error {
+	return s.restoreTracker("duration_trackers", models.DURATION, userID, id)
+}
+
+// GetRunningTimer returns the running timer of a user
+func (s *sqlStore) GetRunningTimer(userID int) (*duration.Timer, error) {
+	var t duration.Timer
+	err := s.queryRow("SELECT id, tracker_id, started_at, note FROM timers WHERE user_id = ?", userID).
+		Scan(&t.ID, &t.TrackerID, &t.StartedAt, &t.Note)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// StartTimer starts a timer on a duration tracker. A user has at most one
+// running timer, starting a second one fails with ErrTimerRunning. Archived
+// trackers fail with ErrTrackerArchived and missing ones with sql.ErrNoRows.
+func (s *sqlStore) StartTimer(userID int, trackerID int, startedAt time.Time, note string) (*duration.Timer, error) {
+	tx, err := s.begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var archivedAt sql.NullTime
+	err = tx.queryRow("SELECT archived_at FROM duration_trackers WHERE id = ? AND user_id = ? AND deleted_at IS NULL", trackerID, userID).
+		Scan(&archivedAt)
+	if err != nil {
+		return nil, err
+	}
+	if archivedAt.Valid {
+		return nil, ErrTrackerArchived
+	}
+
+	timer := duration.Timer{TrackerID: trackerID, StartedAt: startedAt.UTC(), Note: note}
+	timer.ID, err = tx.insert("INSERT INTO timers (user_id, tracker_id, started_at, note) VALUES (?, ?, ?, ?)",
+		userID, trackerID, timer.StartedAt, note)
+	// The unique index on user_id allows one running timer per user
+	if isUniqueViolation(err) {
+		return nil, ErrTimerRunning
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &timer, tx.Commit()
+}
+
+// StopTimer stops the running timer of a duration tracker and turns it into
+// an entry dated at the start of the timer. It returns sql.ErrNoRows when no
+// timer of the tracker is running.
+func (s *sqlStore) StopTimer(userID int, trackerID int, stoppedAt time.Time) (*models.Entry, error) {
+	tx, err := s.begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var timer duration.Timer
+	err = tx.queryRow("SELECT id, started_at, note FROM timers WHERE user_id = ? AND tracker_id = ?", userID, trackerID).
+		Scan(&timer.ID, &timer.StartedAt, &timer.Note)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.exec("DELETE FROM timers WHERE id = ?", timer.ID); err != nil {
+		return nil, err
+	}
+
+	seconds := int(stoppedAt.Sub(timer.StartedAt).Seconds())
+	if seconds < 0 {
+		seconds = 0
+	}
+	entry := models.Entry{
+		UserID:    userID,
+		TrackerID: trackerID,
+		Type:      models.DURATION,
+		Duration:  &seconds,
+		Date:      timer.StartedAt.UTC(),
+		Note:      timer.Note,
+		CreatedAt: stoppedAt,
+	}
+	entry.ID, err = tx.insert(
+		"INSERT INTO entries (user_id, tracker_id, type, value, duration, date, note, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		userID, trackerID, entry.Type, entry.Value, entry.Duration, entry.Date, entry.Note, entry.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entry, tx.Commit()
+}
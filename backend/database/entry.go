@@ -2,47 +2,55 @@ package database
 
 import (
 	"database/sql"
+	"errors"
 	"routine-tracker/models"
+	"strings"
+	"time"
 )
 
-func CreateEntry(e models.Entry) (*models.Entry, error) {
+// ErrSkippedTarget is returned when a target entry would be marked as skipped
+var ErrSkippedTarget = errors.New("only habit entries can be skipped")
+
+// ErrNotDuration is returned when an entry of another type would get a duration
+var ErrNotDuration = errors.New("only duration entries have a duration")
+
+func (s *sqlStore) CreateEntry(userID int, e models.Entry) (*models.Entry, error) {
 	query := `
-        INSERT INTO entries (tracker_id, type, value, done, date, note)
-        VALUES (?, ?, ?, ?, ?, ?)
+        INSERT INTO entries (user_id, tracker_id, type, value, done, skipped, duration, date, note, created_at)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
     `
 
-	result, err := DB.Exec(query, e.TrackerID, e.Type, e.Value, e.Done, e.Date, e.Note)
-	if err != nil {
-		return nil, err
-	}
-
-	id, err := result.LastInsertId()
+	id, err := s.insert(query, userID, e.TrackerID, e.Type, e.Value, e.Done, e.Skipped, e.Duration, e.Date, e.Note, e.CreatedAt.UTC())
 	if err != nil {
 		return nil, err
 	}
 
-	e.ID = int(id)
+	e.ID = id
+	e.UserID = userID
 	return &e, nil
 }
 
-func UpdateEntry(entryID int, updates models.UpdateEntryRequest) (*models.Entry, error) {
+func (s *sqlStore) UpdateEntry(userID int, entryID int, updates models.UpdateEntryRequest) (*models.Entry, error) {
 	// First, get the current entry to verify it exists
-	var entry models.Entry
-	var value sql.NullFloat64
-	var done sql.NullBool
-
-	query := `SELECT id, tracker_id, type, value, done, date, note, created_at FROM entries WHERE id = ?`
-	err := DB.QueryRow(query, entryID).Scan(&entry.ID, &entry.TrackerID, &entry.Type, &value, &done, &entry.Date, &entry.Note, &entry.CreatedAt)
+	query := `SELECT ` + entryColumns + ` FROM entries WHERE id = ? AND user_id = ? AND deleted_at IS NULL`
+	entry, err := scanEntry(s.queryRow(query, entryID, userID))
 	if err != nil {
 		return nil, err
 	}
+	entry.UserID = userID
 
-	if value.Valid {
-		entry.Value = value.Float64
-	}
-	if done.Valid {
-		boolVal := done.Bool
-		entry.Done = &boolVal
+	// An entry is either done or skipped
+	if updates.Skipped != nil && *updates.Skipped {
+		if entry.Type != models.HABIT {
+			return nil, ErrSkippedTarget
+		}
+		done := false
+		updates.Done = &done
+	} else if updates.Duration != nil && entry.Type != models.DURATION {
+		return nil, ErrNotDuration
+	} else if updates.Done != nil && *updates.Done && updates.Skipped == nil {
+		skipped := false
+		updates.Skipped = &skipped
 	}
 
 	// Build dynamic update query based on provided fields
@@ -62,9 +70,26 @@ func UpdateEntry(entryID int, updates models.UpdateEntryRequest) (*models.Entry,
 		updates_made = true
 	}
 
+	if updates.Skipped != nil {
+		updateQuery += "skipped = ?, "
+		args = append(args, *updates.Skipped)
+		updates_made = true
+	}
+
+	if updates.Duration != nil {
+		updateQuery += "duration = ?, "
+		args = append(args, *updates.Duration)
+		updates_made = true
+	}
+
 	if updates.Date != nil {
+		// Dates arrive as RFC3339 in UTC, resolved against the user's time zone by the handler
+		date, err := time.Parse(time.RFC3339Nano, *updates.Date)
+		if err != nil {
+			return nil, err
+		}
 		updateQuery += "date = ?, "
-		args = append(args, *updates.Date)
+		args = append(args, date.UTC())
 		updates_made = true
 	}
 
@@ -81,170 +106,218 @@ func UpdateEntry(entryID int, updates models.UpdateEntryRequest) (*models.Entry,
 
 	// Remove trailing comma and space
 	updateQuery = updateQuery[:len(updateQuery)-2]
-	updateQuery += " WHERE id = ?"
-	args = append(args, entryID)
+	updateQuery += " WHERE id = ? AND user_id = ?"
+	args = append(args, entryID, userID)
 
-	_, err = DB.Exec(updateQuery, args...)
+	_, err = s.exec(updateQuery, args...)
 	if err != nil {
 		return nil, err
 	}
 
 	// Fetch and return the updated entry
-	err = DB.QueryRow(query, entryID).Scan(&entry.ID, &entry.TrackerID, &entry.Type, &value, &done, &entry.Date, &entry.Note, &entry.CreatedAt)
+	entry, err = scanEntry(s.queryRow(query, entryID, userID))
 	if err != nil {
 		return nil, err
 	}
-
-	if value.Valid {
-		entry.Value = value.Float64
-	}
-	if done.Valid {
-		boolVal := done.Bool
-		entry.Done = &boolVal
-	}
+	entry.UserID = userID
 
 	return &entry, nil
 }
 
-func GetEntriesByTracker(trackerID int, trackerType string) ([]models.Entry, error) {
-	// Get the tracker's start date to filter entries
-	var startDate string
-	var query string
-	
-	if trackerType == "habit" {
-		startQuery := `SELECT start_date FROM habit_trackers WHERE id = ?`
-		err := DB.QueryRow(startQuery, trackerID).Scan(&startDate)
-		if err != nil {
-			return nil, err
-		}
-	} else if trackerType == "target" {
-		startQuery := `SELECT start_date FROM target_trackers WHERE id = ?`
-		err := DB.QueryRow(startQuery, trackerID).Scan(&startDate)
-		if err != nil {
-			return nil, err
-		}
+func (s *sqlStore) GetEntriesByTracker(userID int, trackerID int, trackerType string) ([]models.Entry, error) {
+	// Get the tracker's start date and the owner's time zone to filter entries
+	var startDate time.Time
+	var timeZone string
+
+	table := trackerTable(models.TrackerType(trackerType))
+	startQuery := `SELECT t.start_date, u.time_zone FROM ` + table + ` t
+        JOIN users u ON u.id = t.user_id WHERE t.id = ? AND t.user_id = ? AND t.deleted_at IS NULL`
+	if err := s.queryRow(startQuery, trackerID, userID).Scan(&startDate, &timeZone); err != nil {
+		return nil, err
 	}
 
-	// Use JULIANDAY for proper date comparison that handles timezone differences
-	// This compares the date part only, ignoring time and timezone
-	query = `
-        SELECT id, tracker_id, type, value, done, date, note, created_at
-        FROM entries 
-        WHERE tracker_id = ? AND type = ? 
-        AND JULIANDAY(date) >= JULIANDAY(?)
+	// Entries count from the start of the start date in the user's time zone
+	since := time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0,
+		models.User{TimeZone: timeZone}.Location())
+
+	query := `
+        SELECT ` + entryColumns + `
+        FROM entries
+        WHERE tracker_id = ? AND type = ? AND user_id = ? AND deleted_at IS NULL
         ORDER BY date DESC
     `
 
-	rows, err := DB.Query(query, trackerID, trackerType, startDate)
+	rows, err := s.query(query, trackerID, trackerType, userID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-  entries := make([]models.Entry, 0)
+	entries := make([]models.Entry, 0)
 
 	for rows.Next() {
-		var e models.Entry
-		var value sql.NullFloat64
-		var done sql.NullBool
-
-		err := rows.Scan(&e.ID, &e.TrackerID, &e.Type, &value, &done, &e.Date, &e.Note, &e.CreatedAt)
+		e, err := scanEntry(rows)
 		if err != nil {
 			return nil, err
 		}
+		e.UserID = userID
 
-		if value.Valid {
-			e.Value = value.Float64
+		if e.Date.Before(since) {
+			continue
 		}
-		if done.Valid {
-			boolVal := done.Bool
-			e.Done = &boolVal
-		}
-
 		entries = append(entries, e)
 	}
 
 	return entries, nil
 }
 
-func GetAllEntries() ([]models.Entry, error) {
-	query := `
-        SELECT id, tracker_id, type, value, done, date, note, created_at
-        FROM entries ORDER BY created_at DESC
-    `
-
-	rows, err := DB.Query(query)
+func (s *sqlStore) GetEntryByID(userID int, entryID int) (*models.Entry, error) {
+	query := `SELECT ` + entryColumns + ` FROM entries WHERE id = ? AND user_id = ? AND deleted_at IS NULL`
+	entry, err := scanEntry(s.queryRow(query, entryID, userID))
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-
-	var entries []models.Entry
-
-	for rows.Next() {
-		var e models.Entry
-		var value sql.NullFloat64
-		var done sql.NullBool
+	entry.UserID = userID
+	return &entry, nil
+}
 
-		err := rows.Scan(&e.ID, &e.TrackerID, &e.Type, &value, &done, &e.Date, &e.Note, &e.CreatedAt)
-		if err != nil {
-			return nil, err
-		}
+func (s *sqlStore) GetAllEntries(userID int) ([]models.Entry, error) {
+	query := `
+        SELECT ` + entryColumns + `
+        FROM entries WHERE user_id = ? AND deleted_at IS NULL ORDER BY created_at DESC
+    `
 
-		if value.Valid {
-			e.Value = value.Float64
-		}
-		if done.Valid {
-			boolVal := done.Bool
-			e.Done = &boolVal
-		}
+	return s.queryEntries(userID, query, userID)
+}
 
-		entries = append(entries, e)
-	}
+// GetAllTrackerEntries returns every entry of a tracker, including the ones
+// before its start date
+func (s *sqlStore) GetAllTrackerEntries(userID int, trackerID int, trackerType models.TrackerType) ([]models.Entry, error) {
+	query := `
+        SELECT ` + entryColumns + `
+        FROM entries WHERE tracker_id = ? AND type = ? AND user_id = ? AND deleted_at IS NULL ORDER BY created_at DESC
+    `
 
-	return entries, nil
+	return s.queryEntries(userID, query, trackerID, trackerType, userID)
 }
 
-func DeleteEntry(entryID int) error {
-	query := `DELETE FROM entries WHERE id = ?`
-	
-	result, err := DB.Exec(query, entryID)
+// DeleteEntry moves an entry to the trash
+func (s *sqlStore) DeleteEntry(userID int, entryID int, deletedAt time.Time) error {
+	query := `UPDATE entries SET deleted_at = ? WHERE id = ? AND user_id = ? AND deleted_at IS NULL`
+
+	result, err := s.exec(query, deletedAt.UTC(), entryID, userID)
 	if err != nil {
 		return err
 	}
-	
+
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
 		return err
 	}
-	
+
 	if rowsAffected == 0 {
 		return sql.ErrNoRows
 	}
-	
+
 	return nil
 }
 
-func BulkDeleteEntries(entryIDs []int) error {
+// BulkDeleteEntries moves several entries to the trash
+func (s *sqlStore) BulkDeleteEntries(userID int, entryIDs []int, deletedAt time.Time) error {
 	if len(entryIDs) == 0 {
 		return nil
 	}
-	
-	// Build placeholders for the IN clause
-	placeholders := make([]string, len(entryIDs))
-	args := make([]interface{}, len(entryIDs))
-	for i, id := range entryIDs {
+
+	placeholders, args := inClause(entryIDs)
+	query := `UPDATE entries SET deleted_at = ? WHERE id IN (` + placeholders + `) AND user_id = ? AND deleted_at IS NULL`
+	args = append([]interface{}{deletedAt.UTC()}, args...)
+	args = append(args, userID)
+
+	_, err := s.exec(query, args...)
+	return err
+}
+
+// CreateEntries adds several entries in one transaction, either all or none are saved
+func (s *sqlStore) CreateEntries(userID int, entries []models.Entry) error {
+	tx, err := s.begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, e := range entries {
+		_, err := tx.exec(
+			"INSERT INTO entries (user_id, tracker_id, type, value, done, skipped, duration, date, note) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+			userID, e.TrackerID, e.Type, e.Value, e.Done, e.Skipped, e.Duration, e.Date, e.Note,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// entryColumns are the columns read by scanEntry
+const entryColumns = "id, tracker_id, type, value, done, skipped, duration, date, note, created_at, deleted_at"
+
+func scanEntry(row rowScanner) (models.Entry, error) {
+	var e models.Entry
+	var value sql.NullFloat64
+	var done sql.NullBool
+	var duration sql.NullInt64
+	var deletedAt sql.NullTime
+
+	err := row.Scan(&e.ID, &e.TrackerID, &e.Type, &value, &done, &e.Skipped, &duration, &e.Date, &e.Note, &e.CreatedAt, &deletedAt)
+	if err != nil {
+		return e, err
+	}
+
+	if value.Valid {
+		e.Value = value.Float64
+	}
+	if done.Valid {
+		boolVal := done.Bool
+		e.Done = &boolVal
+	}
+	if duration.Valid {
+		seconds := int(duration.Int64)
+		e.Duration = &seconds
+	}
+	if deletedAt.Valid {
+		e.DeletedAt = &deletedAt.Time
+	}
+	return e, nil
+}
+
+// queryEntries runs a query selecting entryColumns of a user's entries
+func (s *sqlStore) queryEntries(userID int, query string, args ...interface{}) ([]models.Entry, error) {
+	rows, err := s.query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []models.Entry
+	for rows.Next() {
+		e, err := scanEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		e.UserID = userID
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
+
+// inClause returns the placeholders and arguments of an IN (...) list of IDs
+func inClause(ids []int) (string, []interface{}) {
+	placeholders := make([]string, len(ids))
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
 		placeholders[i] = "?"
 		args[i] = id
 	}
-	
-	query := `DELETE FROM entries WHERE id IN (` + 
-		placeholders[0]
-	for i := 1; i < len(placeholders); i++ {
-		query += "," + placeholders[i]
-	}
-	query += ")"
-	
-	_, err := DB.Exec(query, args...)
-	return err
+	return strings.Join(placeholders, ","), args
 }
@@ -1,202 +1,230 @@
 package database
 
 import (
-    "database/sql"
-    "encoding/json"
-    "time"
-    "routine-tracker/trackers/habit"
+	"database/sql"
+	"encoding/json"
+	"routine-tracker/models"
+	"routine-tracker/trackers/habit"
+	"time"
 )
 
-func CreateHabitTracker(h habit.HabitTracker) (*habit.HabitTracker, error) {
-    // Convert Due struct to JSON string for storage
-    dueSpecificDays, _ := json.Marshal(h.Due.SpecificDays)
-    reminderTimes, _ := json.Marshal(h.Reminders.Times)
-    
-    query := `
+func (s *sqlStore) CreateHabitTracker(userID int, h habit.HabitTracker) (*habit.HabitTracker, error) {
+	// Convert Due struct to JSON string for storage
+	dueSpecificDays, _ := json.Marshal(h.Due.SpecificDays)
+	reminderTimes, _ := json.Marshal(h.Reminders.Times)
+
+	query := `
         INSERT INTO habit_trackers (
-            tracker_name, goal, time_period, start_date, due_type,
-            due_specific_days, due_interval_type, due_interval_value,
-            reminder_times, reminder_enabled, bad_habit, goal_streak
-        ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+            user_id, tracker_name, goal, time_period, start_date, due_type,
+            due_specific_days, due_interval_type, due_interval_value, due_rrule,
+            reminder_times, reminder_enabled, bad_habit, goal_streak, pinned, created_at
+        ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
     `
-    
-    result, err := DB.Exec(query,
-        h.TrackerName, h.Goal, h.TimePeriod, h.StartDate, h.Due.Type,
-        string(dueSpecificDays), h.Due.IntervalType, h.Due.IntervalValue,
-        string(reminderTimes), h.Reminders.Enabled, h.BadHabit, h.GoalStreak,
-    )
-    
-    if err != nil {
-        return nil, err
-    }
-    
-    id, err := result.LastInsertId()
-    if err != nil {
-        return nil, err
-    }
-    
-    h.ID = int(id)
-    h.CreatedAt = time.Now()
-    
-    return &h, nil
+
+	id, err := s.insert(query,
+		userID, h.TrackerName, h.Goal, h.TimePeriod, h.StartDate, h.Due.Type,
+		string(dueSpecificDays), h.Due.IntervalType, h.Due.IntervalValue, h.Due.RRule,
+		string(reminderTimes), h.Reminders.Enabled, h.BadHabit, h.GoalStreak, h.Pinned, h.CreatedAt.UTC(),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	h.ID = id
+	h.UserID = userID
+	h.Tags = []models.Tag{}
+
+	return &h, nil
 }
 
-func GetAllHabitTrackers() ([]habit.HabitTracker, error) {
-    query := `
+func (s *sqlStore) GetAllHabitTrackers(userID int, filter models.ArchiveFilter) ([]habit.HabitTracker, error) {
+	return s.queryHabitTrackers(userID, "deleted_at IS NULL"+archivedCondition(filter))
+}
+
+// queryHabitTrackers returns the habit trackers of a user matching condition
+func (s *sqlStore) queryHabitTrackers(userID int, condition string) ([]habit.HabitTracker, error) {
+	query := `
         SELECT id, tracker_name, goal, time_period, start_date, due_type,
-               due_specific_days, due_interval_type, due_interval_value,
-               reminder_times, reminder_enabled, bad_habit, goal_streak, created_at
-        FROM habit_trackers ORDER BY created_at DESC
+               due_specific_days, due_interval_type, due_interval_value, due_rrule,
+               reminder_times, reminder_enabled, bad_habit, goal_streak, created_at, archived_at, deleted_at, category_id, position, pinned
+        FROM habit_trackers WHERE user_id = ? AND ` + condition + ` ORDER BY ` + trackerOrder + `
     `
-    
-    rows, err := DB.Query(query)
-    if err != nil {
-        return nil, err
-    }
-    defer rows.Close()
-    
-    var habits []habit.HabitTracker
-    
-    for rows.Next() {
-        var h habit.HabitTracker
-        var dueSpecificDaysJSON, reminderTimesJSON string
-        var goalStreak sql.NullInt64
-        
-        err := rows.Scan(
-            &h.ID, &h.TrackerName, &h.Goal, &h.TimePeriod, &h.StartDate,
-            &h.Due.Type, &dueSpecificDaysJSON, &h.Due.IntervalType, &h.Due.IntervalValue,
-            &reminderTimesJSON, &h.Reminders.Enabled, &h.BadHabit, &goalStreak, &h.CreatedAt,
-        )
-        
-        if err != nil {
-            return nil, err
-        }
-        
-        // Convert JSON strings back to structs
-        json.Unmarshal([]byte(dueSpecificDaysJSON), &h.Due.SpecificDays)
-        json.Unmarshal([]byte(reminderTimesJSON), &h.Reminders.Times)
-        
-        if goalStreak.Valid {
-            streak := int(goalStreak.Int64)
-            h.GoalStreak = &streak
-        }
-        
-        habits = append(habits, h)
-    }
-    
-    return habits, nil
+
+	rows, err := s.query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var habits []habit.HabitTracker
+
+	for rows.Next() {
+		var h habit.HabitTracker
+		var dueSpecificDaysJSON, reminderTimesJSON string
+		var goalStreak, categoryID sql.NullInt64
+		var archivedAt, deletedAt sql.NullTime
+
+		err := rows.Scan(
+			&h.ID, &h.TrackerName, &h.Goal, &h.TimePeriod, &h.StartDate,
+			&h.Due.Type, &dueSpecificDaysJSON, &h.Due.IntervalType, &h.Due.IntervalValue, &h.Due.RRule,
+			&reminderTimesJSON, &h.Reminders.Enabled, &h.BadHabit, &goalStreak, &h.CreatedAt, &archivedAt, &deletedAt, &categoryID, &h.Position, &h.Pinned,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+		h.UserID = userID
+
+		// Convert JSON strings back to structs
+		json.Unmarshal([]byte(dueSpecificDaysJSON), &h.Due.SpecificDays)
+		json.Unmarshal([]byte(reminderTimesJSON), &h.Reminders.Times)
+
+		if goalStreak.Valid {
+			streak := int(goalStreak.Int64)
+			h.GoalStreak = &streak
+		}
+		if archivedAt.Valid {
+			h.ArchivedAt = &archivedAt.Time
+		}
+		if deletedAt.Valid {
+			h.DeletedAt = &deletedAt.Time
+		}
+		h.CategoryID = nullableID(categoryID)
+
+		habits = append(habits, h)
+	}
+
+	tags, err := s.trackerTags(userID, models.HABIT)
+	if err != nil {
+		return nil, err
+	}
+	for i := range habits {
+		habits[i].Tags = tagsOf(tags, habits[i].ID)
+	}
+
+	return habits, nil
 }
 
-func GetHabitTrackerByID(id int) (*habit.HabitTracker, error) {
-    query := `
+func (s *sqlStore) GetHabitTrackerByID(userID int, id int) (*habit.HabitTracker, error) {
+	query := `
         SELECT id, tracker_name, goal, time_period, start_date, due_type,
-               due_specific_days, due_interval_type, due_interval_value,
-               reminder_times, reminder_enabled, bad_habit, goal_streak, created_at
-        FROM habit_trackers WHERE id = ?
+               due_specific_days, due_interval_type, due_interval_value, due_rrule,
+               reminder_times, reminder_enabled, bad_habit, goal_streak, created_at, archived_at, category_id, position, pinned
+        FROM habit_trackers WHERE id = ? AND user_id = ? AND deleted_at IS NULL
     `
-    
-    var h habit.HabitTracker
-    var dueSpecificDaysJSON, reminderTimesJSON string
-    var goalStreak sql.NullInt64
-    
-    err := DB.QueryRow(query, id).Scan(
-        &h.ID, &h.TrackerName, &h.Goal, &h.TimePeriod, &h.StartDate,
-        &h.Due.Type, &dueSpecificDaysJSON, &h.Due.IntervalType, &h.Due.IntervalValue,
-        &reminderTimesJSON, &h.Reminders.Enabled, &h.BadHabit, &goalStreak, &h.CreatedAt,
-    )
-    
-    if err != nil {
-        return nil, err
-    }
-    
-    json.Unmarshal([]byte(dueSpecificDaysJSON), &h.Due.SpecificDays)
-    json.Unmarshal([]byte(reminderTimesJSON), &h.Reminders.Times)
-    
-    if goalStreak.Valid {
-        streak := int(goalStreak.Int64)
-        h.GoalStreak = &streak
-    }
-    
-    return &h, nil
+
+	var h habit.HabitTracker
+	var dueSpecificDaysJSON, reminderTimesJSON string
+	var goalStreak, categoryID sql.NullInt64
+	var archivedAt sql.NullTime
+
+	err := s.queryRow(query, id, userID).Scan(
+		&h.ID, &h.TrackerName, &h.Goal, &h.TimePeriod, &h.StartDate,
+		&h.Due.Type, &dueSpecificDaysJSON, &h.Due.IntervalType, &h.Due.IntervalValue, &h.Due.RRule,
+		&reminderTimesJSON, &h.Reminders.Enabled, &h.BadHabit, &goalStreak, &h.CreatedAt, &archivedAt, &categoryID, &h.Position, &h.Pinned,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+	h.UserID = userID
+
+	json.Unmarshal([]byte(dueSpecificDaysJSON), &h.Due.SpecificDays)
+	json.Unmarshal([]byte(reminderTimesJSON), &h.Reminders.Times)
+
+	if goalStreak.Valid {
+		streak := int(goalStreak.Int64)
+		h.GoalStreak = &streak
+	}
+	if archivedAt.Valid {
+		h.ArchivedAt = &archivedAt.Time
+	}
+	h.CategoryID = nullableID(categoryID)
+
+	tags, err := s.trackerTags(userID, models.HABIT)
+	if err != nil {
+		return nil, err
+	}
+	h.Tags = tagsOf(tags, h.ID)
+
+	return &h, nil
 }
 
-func UpdateHabitTracker(id int, h habit.UpdateHabitRequest) error {
-    // First get the current habit tracker to merge with updates
-    current, err := GetHabitTrackerByID(id)
-    if err != nil {
-        return err
-    }
-    
-    // Apply updates to current values
-    if h.TrackerName != nil {
-        current.TrackerName = *h.TrackerName
-    }
-    if h.Goal != nil {
-        current.Goal = *h.Goal
-    }
-    if h.TimePeriod != nil {
-        current.TimePeriod = *h.TimePeriod
-    }
-    if h.StartDate != nil {
-        startDate, err := time.Parse("2006-01-02", *h.StartDate)
-        if err != nil {
-            return err
-        }
-        current.StartDate = startDate
-    }
-    if h.Due != nil {
-        current.Due = *h.Due
-    }
-    if h.Reminders != nil {
-        current.Reminders = *h.Reminders
-    }
-    if h.BadHabit != nil {
-        current.BadHabit = *h.BadHabit
-    }
-    if h.GoalStreak != nil {
-        current.GoalStreak = h.GoalStreak
-    }
-    
-    // Now update with the merged values
-    dueSpecificDays, _ := json.Marshal(current.Due.SpecificDays)
-    reminderTimes, _ := json.Marshal(current.Reminders.Times)
-    
-    query := `
+func (s *sqlStore) UpdateHabitTracker(userID int, id int, h habit.UpdateHabitRequest) error {
+	// First get the current habit tracker to merge with updates
+	current, err := s.GetHabitTrackerByID(userID, id)
+	if err != nil {
+		return err
+	}
+
+	// Apply updates to current values
+	if h.TrackerName != nil {
+		current.TrackerName = *h.TrackerName
+	}
+	if h.Goal != nil {
+		current.Goal = *h.Goal
+	}
+	if h.TimePeriod != nil {
+		current.TimePeriod = *h.TimePeriod
+	}
+	if h.StartDate != nil {
+		startDate, err := time.Parse("2006-01-02", *h.StartDate)
+		if err != nil {
+			return err
+		}
+		current.StartDate = startDate
+	}
+	if h.Due != nil {
+		current.Due = *h.Due
+	}
+	if h.Reminders != nil {
+		current.Reminders = *h.Reminders
+	}
+	if h.BadHabit != nil {
+		current.BadHabit = *h.BadHabit
+	}
+	if h.GoalStreak != nil {
+		current.GoalStreak = h.GoalStreak
+	}
+	if h.Pinned != nil {
+		current.Pinned = *h.Pinned
+	}
+
+	// Now update with the merged values
+	dueSpecificDays, _ := json.Marshal(current.Due.SpecificDays)
+	reminderTimes, _ := json.Marshal(current.Reminders.Times)
+
+	query := `
         UPDATE habit_trackers SET
             tracker_name = ?, goal = ?, time_period = ?, start_date = ?,
-            due_type = ?, due_specific_days = ?, due_interval_type = ?, due_interval_value = ?,
-            reminder_times = ?, reminder_enabled = ?, bad_habit = ?, goal_streak = ?
-        WHERE id = ?
+            due_type = ?, due_specific_days = ?, due_interval_type = ?, due_interval_value = ?, due_rrule = ?,
+            reminder_times = ?, reminder_enabled = ?, bad_habit = ?, goal_streak = ?, pinned = ?
+        WHERE id = ? AND user_id = ?
     `
-    
-    _, err = DB.Exec(query,
-        current.TrackerName, current.Goal, current.TimePeriod, current.StartDate,
-        current.Due.Type, string(dueSpecificDays), current.Due.IntervalType, current.Due.IntervalValue,
-        string(reminderTimes), current.Reminders.Enabled, current.BadHabit, current.GoalStreak, id,
-    )
-    
-    return err
+
+	_, err = s.exec(query,
+		current.TrackerName, current.Goal, current.TimePeriod, current.StartDate,
+		current.Due.Type, string(dueSpecificDays), current.Due.IntervalType, current.Due.IntervalValue, current.Due.RRule,
+		string(reminderTimes), current.Reminders.Enabled, current.BadHabit, current.GoalStreak, current.Pinned, id, userID,
+	)
+
+	return err
+}
+
+// SetHabitTrackerArchived archives a habit tracker at archivedAt, or restores
+// it when archivedAt is nil
+func (s *sqlStore) SetHabitTrackerArchived(userID int, id int, archivedAt *time.Time) error {
+	return s.setArchived("habit_trackers", userID, id, archivedAt)
+}
+
+// DeleteHabitTracker moves a habit tracker and its entries to the trash
+func (s *sqlStore) DeleteHabitTracker(userID int, id int, deletedAt time.Time) error {
+	return s.trashTracker("habit_trackers", models.HABIT, userID, id, deletedAt)
 }
 
-func DeleteHabitTracker(id int) error {
-    // Delete tracker and its entries in a transaction
-    tx, err := DB.Begin()
-    if err != nil {
-        return err
-    }
-    defer tx.Rollback()
-    
-    // Delete entries first
-    _, err = tx.Exec("DELETE FROM entries WHERE tracker_id = ? AND type = 'habit'", id)
-    if err != nil {
-        return err
-    }
-    
-    // Delete tracker
-    _, err = tx.Exec("DELETE FROM habit_trackers WHERE id = ?", id)
-    if err != nil {
-        return err
-    }
-    
-    return tx.Commit()
+// RestoreHabitTracker takes a habit tracker and the entries deleted with it
+// out of the trash
+func (s *sqlStore) RestoreHabitTracker(userID int, id int) error {
+	return s.restoreTracker("habit_trackers", models.HABIT, userID, id)
 }
@@ -0,0 +1,293 @@
+package database
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Migration is a versioned schema change. Up and Down run in a transaction
+// together with the update of the schema_migrations table.
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(tx *sql.Tx) error
+	Down    func(tx *sql.Tx) error
+}
+
+// MigrationStatus tells whether a migration has been applied
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+}
+
+// SQL migrations of each dialect are in migrations/<dialect> and named
+// <version>_<name>.up.sql and <version>_<name>.down.sql
+//
+//go:embed migrations
+var migrationFiles embed.FS
+
+// migrations returns all migrations of the dialect, SQL files and Go
+// migrations, ordered by version
+func (d dialect) migrations() ([]Migration, error) {
+	byVersion := make(map[int]*Migration)
+	for i := range d.goMigrations {
+		m := d.goMigrations[i]
+		byVersion[m.Version] = &m
+	}
+
+	files, err := fs.Glob(migrationFiles, "migrations/"+d.name+"/*.sql")
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		base := path.Base(file)
+		direction := "up"
+		if strings.HasSuffix(base, ".down.sql") {
+			direction = "down"
+		}
+		stem := strings.TrimSuffix(strings.TrimSuffix(base, ".up.sql"), ".down.sql")
+
+		parts := strings.SplitN(stem, "_", 2)
+		version, err := strconv.Atoi(parts[0])
+		if err != nil || len(parts) != 2 || stem == strings.TrimSuffix(base, ".sql") {
+			return nil, fmt.Errorf("invalid migration file name %s", base)
+		}
+
+		content, err := migrationFiles.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: parts[1]}
+			byVersion[version] = m
+		} else if m.Name != parts[1] {
+			return nil, fmt.Errorf("migration %d is defined twice: %s and %s", version, m.Name, parts[1])
+		}
+
+		run := execSQL(string(content))
+		if direction == "up" {
+			m.Up = run
+		} else {
+			m.Down = run
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == nil || m.Down == nil {
+			return nil, fmt.Errorf("migration %04d_%s needs both an up and a down step", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+func execSQL(statements string) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		_, err := tx.Exec(statements)
+		return err
+	}
+}
+
+func (s *sqlStore) ensureMigrationsTable() error {
+	_, err := s.exec(`
+    CREATE TABLE IF NOT EXISTS schema_migrations (
+        version INTEGER PRIMARY KEY,
+        name TEXT NOT NULL,
+        applied_at TIMESTAMP NOT NULL
+    )`)
+	return err
+}
+
+func (s *sqlStore) appliedMigrations() (map[int]time.Time, error) {
+	if err := s.ensureMigrationsTable(); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.query("SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+	return applied, rows.Err()
+}
+
+// GetMigrationStatus lists all migrations and when they were applied
+func (s *sqlStore) GetMigrationStatus() ([]MigrationStatus, error) {
+	migrations, err := s.dialect.migrations()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := s.appliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		status := MigrationStatus{Version: m.Version, Name: m.Name}
+		if appliedAt, ok := applied[m.Version]; ok {
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// MigrateUp applies all pending migrations up to and including version,
+// or all of them when version is 0. It returns the number applied.
+func (s *sqlStore) MigrateUp(version int) (int, error) {
+	migrations, err := s.dialect.migrations()
+	if err != nil {
+		return 0, err
+	}
+	applied, err := s.appliedMigrations()
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, m := range migrations {
+		if version > 0 && m.Version > version {
+			break
+		}
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+		if err := s.runMigration(m, true); err != nil {
+			return count, err
+		}
+		s.logger.Info(fmt.Sprintf("📋 Applied migration %04d_%s", m.Version, m.Name))
+		count++
+	}
+	return count, nil
+}
+
+// MigrateDown reverts the given number of most recently applied migrations
+// and returns the number reverted
+func (s *sqlStore) MigrateDown(steps int) (int, error) {
+	migrations, err := s.dialect.migrations()
+	if err != nil {
+		return 0, err
+	}
+	applied, err := s.appliedMigrations()
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for i := len(migrations) - 1; i >= 0 && count < steps; i-- {
+		m := migrations[i]
+		if _, ok := applied[m.Version]; !ok {
+			continue
+		}
+		if err := s.runMigration(m, false); err != nil {
+			return count, err
+		}
+		s.logger.Info(fmt.Sprintf("📋 Reverted migration %04d_%s", m.Version, m.Name))
+		count++
+	}
+	return count, nil
+}
+
+func (s *sqlStore) runMigration(m Migration, up bool) error {
+	tx, err := s.begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if up {
+		err = m.Up(tx.Tx)
+		if err == nil {
+			_, err = tx.exec("INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)", m.Version, m.Name, time.Now().UTC())
+		}
+	} else {
+		err = m.Down(tx.Tx)
+		if err == nil {
+			_, err = tx.exec("DELETE FROM schema_migrations WHERE version = ?", m.Version)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("migration %04d_%s: %w", m.Version, m.Name, err)
+	}
+
+	return tx.Commit()
+}
+
+// MigrateCommand runs the migrate command line: status, up [version] or
+// down [steps]
+func MigrateCommand(store Store, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: migrate status | up [version] | down [steps]")
+	}
+
+	number := func(fallback int) (int, error) {
+		if len(args) < 2 {
+			return fallback, nil
+		}
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid number '%s'", args[1])
+		}
+		return n, nil
+	}
+
+	switch args[0] {
+	case "status":
+		statuses, err := store.GetMigrationStatus()
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = "applied " + s.AppliedAt.Local().Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(out, "%04d  %-28s %s\n", s.Version, s.Name, applied)
+		}
+		return nil
+	case "up":
+		version, err := number(0)
+		if err != nil {
+			return err
+		}
+		count, err := store.MigrateUp(version)
+		fmt.Fprintf(out, "Applied %d migration(s)\n", count)
+		return err
+	case "down":
+		steps, err := number(1)
+		if err != nil {
+			return err
+		}
+		count, err := store.MigrateDown(steps)
+		fmt.Fprintf(out, "Reverted %d migration(s)\n", count)
+		return err
+	default:
+		return fmt.Errorf("unknown migrate command '%s', use status, up or down", args[0])
+	}
+}
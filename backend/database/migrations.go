@@ -0,0 +1,90 @@
+package database
+
+import (
+	"database/sql"
+)
+
+// sqliteGoMigrations are migrations that cannot be written as plain SQL. Databases
+// created before schema_migrations existed may already contain the columns
+// added here, so they are only added when missing.
+var sqliteGoMigrations = []Migration{
+	{
+		Version: 2,
+		Name:    "add_use_actual_bounds",
+		Up: func(tx *sql.Tx) error {
+			return addColumn(tx, "target_trackers", "use_actual_bounds", "BOOLEAN DEFAULT FALSE")
+		},
+		Down: func(tx *sql.Tx) error {
+			return dropColumn(tx, "target_trackers", "use_actual_bounds")
+		},
+	},
+	{
+		Version: 3,
+		Name:    "add_trend_weight_type",
+		Up: func(tx *sql.Tx) error {
+			return addColumn(tx, "target_trackers", "trend_weight_type", "TEXT DEFAULT 'none'")
+		},
+		Down: func(tx *sql.Tx) error {
+			return dropColumn(tx, "target_trackers", "trend_weight_type")
+		},
+	},
+	{
+		// Trackers and entries created before multi-user support have no owner
+		// yet, they are claimed by the first user that registers
+		Version: 5,
+		Name:    "add_tracker_owners",
+		Up: func(tx *sql.Tx) error {
+			for _, table := range []string{"habit_trackers", "target_trackers", "entries"} {
+				if err := addColumn(tx, table, "user_id", "INTEGER REFERENCES users(id)"); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(tx *sql.Tx) error {
+			for _, table := range []string{"habit_trackers", "target_trackers", "entries"} {
+				if err := dropColumn(tx, table, "user_id"); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+}
+
+func hasColumn(tx *sql.Tx, table, column string) (bool, error) {
+	rows, err := tx.Query("SELECT name FROM pragma_table_info(?)", table)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return false, err
+		}
+		if name == column {
+			return true, nil
+		}
+	}
+	return false, rows.Err()
+}
+
+func addColumn(tx *sql.Tx, table, column, definition string) error {
+	exists, err := hasColumn(tx, table, column)
+	if err != nil || exists {
+		return err
+	}
+	_, err = tx.Exec("ALTER TABLE " + table + " ADD COLUMN " + column + " " + definition)
+	return err
+}
+
+func dropColumn(tx *sql.Tx, table, column string) error {
+	exists, err := hasColumn(tx, table, column)
+	if err != nil || !exists {
+		return err
+	}
+	_, err = tx.Exec("ALTER TABLE " + table + " DROP COLUMN " + column)
+	return err
+}
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS entries;
+DROP TABLE IF EXISTS target_trackers;
+DROP TABLE IF EXISTS habit_trackers;
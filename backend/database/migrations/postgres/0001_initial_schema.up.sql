@@ -0,0 +1,44 @@
+CREATE TABLE IF NOT EXISTS habit_trackers (
+    id SERIAL PRIMARY KEY,
+    tracker_name TEXT NOT NULL,
+    goal DOUBLE PRECISION NOT NULL,
+    time_period TEXT NOT NULL,
+    start_date TIMESTAMPTZ NOT NULL,
+    due_type TEXT NOT NULL,
+    due_specific_days TEXT,
+    due_interval_type TEXT,
+    due_interval_value INTEGER,
+    reminder_times TEXT,
+    reminder_enabled BOOLEAN DEFAULT TRUE,
+    bad_habit BOOLEAN DEFAULT FALSE,
+    goal_streak INTEGER,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS target_trackers (
+    id SERIAL PRIMARY KEY,
+    tracker_name TEXT NOT NULL,
+    start_value DOUBLE PRECISION NOT NULL,
+    goal_value DOUBLE PRECISION NOT NULL,
+    start_date TIMESTAMPTZ NOT NULL,
+    goal_date TIMESTAMPTZ NOT NULL,
+    add_to_total BOOLEAN DEFAULT FALSE,
+    due_type TEXT NOT NULL,
+    due_specific_days TEXT,
+    due_interval_type TEXT,
+    due_interval_value INTEGER,
+    reminder_times TEXT,
+    reminder_enabled BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS entries (
+    id SERIAL PRIMARY KEY,
+    tracker_id INTEGER NOT NULL,
+    type TEXT NOT NULL,
+    value DOUBLE PRECISION,
+    done BOOLEAN,
+    date TIMESTAMPTZ NOT NULL,
+    note TEXT,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
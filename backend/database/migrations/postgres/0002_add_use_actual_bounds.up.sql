@@ -0,0 +1 @@
+ALTER TABLE target_trackers ADD COLUMN IF NOT EXISTS use_actual_bounds BOOLEAN DEFAULT FALSE;
@@ -0,0 +1 @@
+ALTER TABLE target_trackers DROP COLUMN IF EXISTS trend_weight_type;
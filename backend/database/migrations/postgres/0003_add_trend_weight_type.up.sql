@@ -0,0 +1 @@
+ALTER TABLE target_trackers ADD COLUMN IF NOT EXISTS trend_weight_type TEXT DEFAULT 'none';
@@ -0,0 +1,3 @@
+ALTER TABLE entries DROP COLUMN IF EXISTS user_id;
+ALTER TABLE target_trackers DROP COLUMN IF EXISTS user_id;
+ALTER TABLE habit_trackers DROP COLUMN IF EXISTS user_id;
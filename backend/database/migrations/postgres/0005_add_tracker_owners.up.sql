@@ -0,0 +1,5 @@
+-- Trackers and entries created before multi-user support have no owner yet,
+-- they are claimed by the first user that registers
+ALTER TABLE habit_trackers ADD COLUMN IF NOT EXISTS user_id INTEGER REFERENCES users(id);
+ALTER TABLE target_trackers ADD COLUMN IF NOT EXISTS user_id INTEGER REFERENCES users(id);
+ALTER TABLE entries ADD COLUMN IF NOT EXISTS user_id INTEGER REFERENCES users(id);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS reminder_deliveries (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    tracker_type TEXT NOT NULL,
+    tracker_id INTEGER NOT NULL,
+    scheduled_for TIMESTAMPTZ NOT NULL,
+    sent_at TIMESTAMPTZ NOT NULL,
+    UNIQUE (tracker_type, tracker_id, scheduled_for)
+);
@@ -0,0 +1,2 @@
+ALTER TABLE entries DROP COLUMN skipped;
+DROP TABLE IF EXISTS pauses;
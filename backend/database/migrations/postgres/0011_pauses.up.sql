@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS pauses (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    tracker_type TEXT,
+    tracker_id INTEGER,
+    start_date TIMESTAMPTZ NOT NULL,
+    end_date TIMESTAMPTZ NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+ALTER TABLE entries ADD COLUMN skipped BOOLEAN NOT NULL DEFAULT FALSE;
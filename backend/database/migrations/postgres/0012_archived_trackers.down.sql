@@ -0,0 +1,2 @@
+ALTER TABLE target_trackers DROP COLUMN archived_at;
+ALTER TABLE habit_trackers DROP COLUMN archived_at;
@@ -0,0 +1,9 @@
+-- Without the column trashed rows would come back, purge them instead
+DELETE FROM entries WHERE deleted_at IS NOT NULL
+    OR (type = 'habit' AND tracker_id IN (SELECT id FROM habit_trackers WHERE deleted_at IS NOT NULL))
+    OR (type = 'target' AND tracker_id IN (SELECT id FROM target_trackers WHERE deleted_at IS NOT NULL));
+DELETE FROM habit_trackers WHERE deleted_at IS NOT NULL;
+DELETE FROM target_trackers WHERE deleted_at IS NOT NULL;
+ALTER TABLE target_trackers DROP COLUMN deleted_at;
+ALTER TABLE habit_trackers DROP COLUMN deleted_at;
+ALTER TABLE entries DROP COLUMN deleted_at;
@@ -0,0 +1,3 @@
+ALTER TABLE entries ADD COLUMN deleted_at TIMESTAMPTZ;
+ALTER TABLE habit_trackers ADD COLUMN deleted_at TIMESTAMPTZ;
+ALTER TABLE target_trackers ADD COLUMN deleted_at TIMESTAMPTZ;
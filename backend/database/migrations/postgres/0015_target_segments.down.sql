@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_target_segments_tracker;
+DROP TABLE IF EXISTS target_segments;
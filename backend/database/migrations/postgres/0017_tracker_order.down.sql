@@ -0,0 +1,4 @@
+ALTER TABLE target_trackers DROP COLUMN pinned;
+ALTER TABLE target_trackers DROP COLUMN position;
+ALTER TABLE habit_trackers DROP COLUMN pinned;
+ALTER TABLE habit_trackers DROP COLUMN position;
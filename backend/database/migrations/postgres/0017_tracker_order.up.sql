@@ -0,0 +1,4 @@
+ALTER TABLE habit_trackers ADD COLUMN position INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE habit_trackers ADD COLUMN pinned BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE target_trackers ADD COLUMN position INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE target_trackers ADD COLUMN pinned BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS reminder_deliveries (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    tracker_type TEXT NOT NULL,
+    tracker_id INTEGER NOT NULL,
+    scheduled_for DATETIME NOT NULL,
+    sent_at DATETIME NOT NULL,
+    UNIQUE (tracker_type, tracker_id, scheduled_for)
+);
@@ -0,0 +1,2 @@
+ALTER TABLE target_trackers DROP COLUMN due_rrule;
+ALTER TABLE habit_trackers DROP COLUMN due_rrule;
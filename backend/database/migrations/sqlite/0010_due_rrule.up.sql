@@ -0,0 +1,2 @@
+ALTER TABLE habit_trackers ADD COLUMN due_rrule TEXT NOT NULL DEFAULT '';
+ALTER TABLE target_trackers ADD COLUMN due_rrule TEXT NOT NULL DEFAULT '';
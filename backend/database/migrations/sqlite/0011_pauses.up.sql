@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS pauses (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    tracker_type TEXT,
+    tracker_id INTEGER,
+    start_date DATETIME NOT NULL,
+    end_date DATETIME NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+ALTER TABLE entries ADD COLUMN skipped BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,2 @@
+ALTER TABLE habit_trackers ADD COLUMN archived_at DATETIME;
+ALTER TABLE target_trackers ADD COLUMN archived_at DATETIME;
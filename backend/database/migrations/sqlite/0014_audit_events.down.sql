@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_audit_events_entity;
+DROP TABLE IF EXISTS audit_events;
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS target_segments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    tracker_id INTEGER NOT NULL REFERENCES target_trackers(id),
+    effective_from DATETIME NOT NULL,
+    goal_value REAL NOT NULL,
+    goal_date DATETIME NOT NULL,
+    add_to_total BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_target_segments_tracker ON target_segments (tracker_id, effective_from);
+INSERT INTO target_segments (tracker_id, effective_from, goal_value, goal_date, add_to_total, created_at)
+SELECT id, start_date, goal_value, goal_date, add_to_total, COALESCE(created_at, CURRENT_TIMESTAMP) FROM target_trackers;
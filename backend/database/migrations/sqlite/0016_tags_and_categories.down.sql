@@ -0,0 +1,5 @@
+ALTER TABLE target_trackers DROP COLUMN category_id;
+ALTER TABLE habit_trackers DROP COLUMN category_id;
+DROP TABLE IF EXISTS tracker_tags;
+DROP TABLE IF EXISTS tags;
+DROP TABLE IF EXISTS categories;
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS categories (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    name TEXT NOT NULL,
+    color TEXT NOT NULL DEFAULT '',
+    icon TEXT NOT NULL DEFAULT '',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+CREATE TABLE IF NOT EXISTS tags (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    name TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+CREATE TABLE IF NOT EXISTS tracker_tags (
+    tag_id INTEGER NOT NULL REFERENCES tags(id),
+    tracker_type TEXT NOT NULL,
+    tracker_id INTEGER NOT NULL,
+    PRIMARY KEY (tag_id, tracker_type, tracker_id)
+);
+ALTER TABLE habit_trackers ADD COLUMN category_id INTEGER REFERENCES categories(id);
+ALTER TABLE target_trackers ADD COLUMN category_id INTEGER REFERENCES categories(id);
@@ -0,0 +1,9 @@
+-- Rows of duration trackers would be left without their tracker, delete them
+DELETE FROM entries WHERE type = 'duration';
+ALTER TABLE entries DROP COLUMN duration;
+DELETE FROM pauses WHERE tracker_type = 'duration';
+DELETE FROM notification_channels WHERE tracker_type = 'duration';
+DELETE FROM tracker_tags WHERE tracker_type = 'duration';
+DELETE FROM reminder_deliveries WHERE tracker_type = 'duration';
+DROP TABLE IF EXISTS timers;
+DROP TABLE IF EXISTS duration_trackers;
@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS duration_trackers (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    tracker_name TEXT NOT NULL,
+    goal INTEGER NOT NULL,
+    time_period TEXT NOT NULL,
+    start_date DATETIME NOT NULL,
+    due_type TEXT NOT NULL,
+    due_specific_days TEXT,
+    due_interval_type TEXT,
+    due_interval_value INTEGER,
+    due_rrule TEXT NOT NULL DEFAULT '',
+    reminder_times TEXT,
+    reminder_enabled BOOLEAN DEFAULT TRUE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    archived_at DATETIME,
+    deleted_at DATETIME,
+    category_id INTEGER REFERENCES categories(id),
+    position INTEGER NOT NULL DEFAULT 0,
+    pinned BOOLEAN NOT NULL DEFAULT FALSE
+);
+CREATE TABLE IF NOT EXISTS timers (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    tracker_id INTEGER NOT NULL REFERENCES duration_trackers(id),
+    started_at DATETIME NOT NULL,
+    note TEXT NOT NULL DEFAULT ''
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_timers_user ON timers (user_id);
+ALTER TABLE entries ADD COLUMN duration INTEGER;
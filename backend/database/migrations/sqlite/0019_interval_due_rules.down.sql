@@ -0,0 +1,8 @@
+UPDATE habit_trackers
+SET due_type = 'interval', due_rrule = ''
+WHERE due_type = 'rrule' AND due_interval_type IN ('week', 'month', 'year')
+    AND due_rrule LIKE 'FREQ=%;INTERVAL=%;BYDAY=MO,TU,WE,TH,FR,SA,SU%';
+UPDATE target_trackers
+SET due_type = 'interval', due_rrule = ''
+WHERE due_type = 'rrule' AND due_interval_type IN ('week', 'month', 'year')
+    AND due_rrule LIKE 'FREQ=%;INTERVAL=%;BYDAY=MO,TU,WE,TH,FR,SA,SU%';
@@ -0,0 +1,25 @@
+-- Interval trackers used to be due on every day of each Nth week, month or
+-- year. Rules on every weekday keep them that way, weeks start on the
+-- weekday of the start date like the 7 day blocks they were counted in.
+UPDATE habit_trackers
+SET due_type = 'rrule',
+    due_rrule = 'FREQ=WEEKLY;INTERVAL=' || due_interval_value || ';BYDAY=MO,TU,WE,TH,FR,SA,SU;WKST=' ||
+        CASE strftime('%w', substr(start_date, 1, 10)) WHEN '0' THEN 'SU' WHEN '1' THEN 'MO' WHEN '2' THEN 'TU' WHEN '3' THEN 'WE' WHEN '4' THEN 'TH' WHEN '5' THEN 'FR' WHEN '6' THEN 'SA' END
+WHERE due_type = 'interval' AND due_interval_type = 'week' AND due_interval_value >= 1;
+UPDATE habit_trackers
+SET due_type = 'rrule', due_rrule = 'FREQ=MONTHLY;INTERVAL=' || due_interval_value || ';BYDAY=MO,TU,WE,TH,FR,SA,SU'
+WHERE due_type = 'interval' AND due_interval_type = 'month' AND due_interval_value >= 1;
+UPDATE habit_trackers
+SET due_type = 'rrule', due_rrule = 'FREQ=YEARLY;INTERVAL=' || due_interval_value || ';BYDAY=MO,TU,WE,TH,FR,SA,SU'
+WHERE due_type = 'interval' AND due_interval_type = 'year' AND due_interval_value >= 1;
+UPDATE target_trackers
+SET due_type = 'rrule',
+    due_rrule = 'FREQ=WEEKLY;INTERVAL=' || due_interval_value || ';BYDAY=MO,TU,WE,TH,FR,SA,SU;WKST=' ||
+        CASE strftime('%w', substr(start_date, 1, 10)) WHEN '0' THEN 'SU' WHEN '1' THEN 'MO' WHEN '2' THEN 'TU' WHEN '3' THEN 'WE' WHEN '4' THEN 'TH' WHEN '5' THEN 'FR' WHEN '6' THEN 'SA' END
+WHERE due_type = 'interval' AND due_interval_type = 'week' AND due_interval_value >= 1;
+UPDATE target_trackers
+SET due_type = 'rrule', due_rrule = 'FREQ=MONTHLY;INTERVAL=' || due_interval_value || ';BYDAY=MO,TU,WE,TH,FR,SA,SU'
+WHERE due_type = 'interval' AND due_interval_type = 'month' AND due_interval_value >= 1;
+UPDATE target_trackers
+SET due_type = 'rrule', due_rrule = 'FREQ=YEARLY;INTERVAL=' || due_interval_value || ';BYDAY=MO,TU,WE,TH,FR,SA,SU'
+WHERE due_type = 'interval' AND due_interval_type = 'year' AND due_interval_value >= 1;
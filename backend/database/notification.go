@@ -0,0 +1,142 @@
+package database
+
+import (
+	"database/sql"
+	"routine-tracker/models"
+)
+
+const notificationChannelColumns = `id, user_id, name, type, target, token, tracker_type, tracker_id, enabled, created_at`
+
+func (s *sqlStore) CreateNotificationChannel(userID int, channel models.NotificationChannel) (*models.NotificationChannel, error) {
+	var trackerType sql.NullString
+	if channel.TrackerType != nil {
+		trackerType = sql.NullString{String: string(*channel.TrackerType), Valid: true}
+	}
+
+	id, err := s.insert(`
+        INSERT INTO notification_channels (user_id, name, type, target, token, tracker_type, tracker_id, enabled)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+    `, userID, channel.Name, channel.Type, channel.Target, channel.Token, trackerType, channel.TrackerID, channel.Enabled)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetNotificationChannelByID(userID, id)
+}
+
+func (s *sqlStore) GetNotificationChannelByID(userID int, id int) (*models.NotificationChannel, error) {
+	query := `SELECT ` + notificationChannelColumns + ` FROM notification_channels WHERE id = ? AND user_id = ?`
+	return scanNotificationChannel(s.queryRow(query, id, userID))
+}
+
+func (s *sqlStore) GetNotificationChannels(userID int) ([]models.NotificationChannel, error) {
+	query := `SELECT ` + notificationChannelColumns + ` FROM notification_channels WHERE user_id = ? ORDER BY id`
+	return s.queryNotificationChannels(query, userID)
+}
+
+// GetTrackerNotificationChannels returns the enabled channels a tracker's
+// notifications go to. A tracker's own channels take precedence over the
+// channels configured for all trackers.
+func (s *sqlStore) GetTrackerNotificationChannels(userID int, trackerType string, trackerID int) ([]models.NotificationChannel, error) {
+	own, err := s.queryNotificationChannels(
+		`SELECT `+notificationChannelColumns+` FROM notification_channels
+         WHERE user_id = ? AND tracker_type = ? AND tracker_id = ? AND enabled = TRUE ORDER BY id`,
+		userID, trackerType, trackerID,
+	)
+	if err != nil || len(own) > 0 {
+		return own, err
+	}
+
+	return s.queryNotificationChannels(
+		`SELECT `+notificationChannelColumns+` FROM notification_channels
+         WHERE user_id = ? AND tracker_id IS NULL AND enabled = TRUE ORDER BY id`,
+		userID,
+	)
+}
+
+func (s *sqlStore) UpdateNotificationChannel(userID int, id int, req models.UpdateNotificationChannelRequest) (*models.NotificationChannel, error) {
+	channel, err := s.GetNotificationChannelByID(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		channel.Name = *req.Name
+	}
+	if req.Target != nil {
+		channel.Target = *req.Target
+	}
+	if req.Token != nil {
+		channel.Token = *req.Token
+	}
+	if req.Enabled != nil {
+		channel.Enabled = *req.Enabled
+	}
+
+	_, err = s.exec(
+		"UPDATE notification_channels SET name = ?, target = ?, token = ?, enabled = ? WHERE id = ? AND user_id = ?",
+		channel.Name, channel.Target, channel.Token, channel.Enabled, id, userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return channel, nil
+}
+
+func (s *sqlStore) DeleteNotificationChannel(userID int, id int) error {
+	result, err := s.exec("DELETE FROM notification_channels WHERE id = ? AND user_id = ?", id, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (s *sqlStore) queryNotificationChannels(query string, args ...interface{}) ([]models.NotificationChannel, error) {
+	rows, err := s.query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	channels := make([]models.NotificationChannel, 0)
+	for rows.Next() {
+		channel, err := scanNotificationChannel(rows)
+		if err != nil {
+			return nil, err
+		}
+		channels = append(channels, *channel)
+	}
+
+	return channels, nil
+}
+
+func scanNotificationChannel(row rowScanner) (*models.NotificationChannel, error) {
+	var c models.NotificationChannel
+	var token, trackerType sql.NullString
+	var trackerID sql.NullInt64
+
+	err := row.Scan(&c.ID, &c.UserID, &c.Name, &c.Type, &c.Target, &token, &trackerType, &trackerID, &c.Enabled, &c.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Token = token.String
+	if trackerType.Valid && trackerID.Valid {
+		tt := models.TrackerType(trackerType.String)
+		id := int(trackerID.Int64)
+		c.TrackerType = &tt
+		c.TrackerID = &id
+	}
+
+	return &c, nil
+}
@@ -0,0 +1,35 @@
+package database
+
+import (
+	"routine-tracker/models"
+)
+
+// trackerOrder sorts tracker queries in the user-defined order: pinned
+// trackers first, then by position, newest first on equal positions
+const trackerOrder = `pinned DESC, position, created_at DESC, id DESC`
+
+// UpdateTrackerOrder sets the positions and pinned flags of a batch of
+// trackers in one transaction. Nothing changes when one of the trackers does
+// not exist.
+func (s *sqlStore) UpdateTrackerOrder(userID int, positions []models.TrackerPosition) error {
+	tx, err := s.begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, p := range positions {
+		result, err := tx.exec(
+			"UPDATE "+trackerTable(p.Type)+" SET position = ?, pinned = COALESCE(?, pinned) WHERE id = ? AND user_id = ? AND deleted_at IS NULL",
+			p.Position, p.Pinned, p.ID, userID,
+		)
+		if err != nil {
+			return err
+		}
+		if err := requireAffected(result); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,109 @@
+package database
+
+import (
+	"database/sql"
+	"routine-tracker/models"
+)
+
+const pauseColumns = `id, user_id, tracker_type, tracker_id, start_date, end_date, reason, created_at`
+
+func (s *sqlStore) CreatePause(userID int, pause models.Pause) (*models.Pause, error) {
+	var trackerType sql.NullString
+	if pause.TrackerType != nil {
+		trackerType = sql.NullString{String: string(*pause.TrackerType), Valid: true}
+	}
+
+	id, err := s.insert(`
+        INSERT INTO pauses (user_id, tracker_type, tracker_id, start_date, end_date, reason)
+        VALUES (?, ?, ?, ?, ?, ?)
+    `, userID, trackerType, pause.TrackerID, pause.StartDate, pause.EndDate, pause.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetPauseByID(userID, id)
+}
+
+func (s *sqlStore) GetPauseByID(userID int, id int) (*models.Pause, error) {
+	query := `SELECT ` + pauseColumns + ` FROM pauses WHERE id = ? AND user_id = ? AND ` + notOfTrashedTracker("tracker_type")
+	return scanPause(s.queryRow(query, id, userID))
+}
+
+// GetPauses returns all pauses of a user, the ones for all trackers and the
+// tracker specific ones, ordered by start date. Pauses of trackers in the
+// trash are left out.
+func (s *sqlStore) GetPauses(userID int) ([]models.Pause, error) {
+	rows, err := s.query(`SELECT `+pauseColumns+` FROM pauses WHERE user_id = ? AND `+notOfTrashedTracker("tracker_type")+` ORDER BY start_date, id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pauses := make([]models.Pause, 0)
+	for rows.Next() {
+		pause, err := scanPause(rows)
+		if err != nil {
+			return nil, err
+		}
+		pauses = append(pauses, *pause)
+	}
+
+	return pauses, nil
+}
+
+func (s *sqlStore) UpdatePause(userID int, id int, pause models.Pause) (*models.Pause, error) {
+	result, err := s.exec(
+		"UPDATE pauses SET start_date = ?, end_date = ?, reason = ? WHERE id = ? AND user_id = ?",
+		pause.StartDate, pause.EndDate, pause.Reason, id, userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if rowsAffected == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return s.GetPauseByID(userID, id)
+}
+
+func (s *sqlStore) DeletePause(userID int, id int) error {
+	result, err := s.exec("DELETE FROM pauses WHERE id = ? AND user_id = ? AND "+notOfTrashedTracker("tracker_type"), id, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func scanPause(row rowScanner) (*models.Pause, error) {
+	var p models.Pause
+	var trackerType sql.NullString
+	var trackerID sql.NullInt64
+
+	err := row.Scan(&p.ID, &p.UserID, &trackerType, &trackerID, &p.StartDate, &p.EndDate, &p.Reason, &p.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if trackerType.Valid && trackerID.Valid {
+		tt := models.TrackerType(trackerType.String)
+		id := int(trackerID.Int64)
+		p.TrackerType = &tt
+		p.TrackerID = &id
+	}
+
+	return &p, nil
+}
@@ -0,0 +1,54 @@
+package database
+
+import (
+	"time"
+)
+
+// GetUserIDs returns the IDs of all users, background jobs use it to visit every account
+func (s *sqlStore) GetUserIDs() ([]int, error) {
+	rows, err := s.query("SELECT id FROM users ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make([]int, 0)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// ClaimReminderDelivery records that a reminder is about to be sent. It returns
+// false when the same reminder was already delivered, e.g. before a restart.
+func (s *sqlStore) ClaimReminderDelivery(userID int, trackerType string, trackerID int, scheduledFor time.Time, sentAt time.Time) (bool, error) {
+	result, err := s.exec(`
+        INSERT INTO reminder_deliveries (user_id, tracker_type, tracker_id, scheduled_for, sent_at)
+        VALUES (?, ?, ?, ?, ?)
+        ON CONFLICT (tracker_type, tracker_id, scheduled_for) DO NOTHING
+    `, userID, trackerType, trackerID, scheduledFor.UTC(), sentAt.UTC())
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected == 1, nil
+}
+
+// ReleaseReminderDelivery forgets a claimed reminder so that it is retried
+func (s *sqlStore) ReleaseReminderDelivery(trackerType string, trackerID int, scheduledFor time.Time) error {
+	_, err := s.exec(
+		"DELETE FROM reminder_deliveries WHERE tracker_type = ? AND tracker_id = ? AND scheduled_for = ?",
+		trackerType, trackerID, scheduledFor.UTC(),
+	)
+	return err
+}
@@ -0,0 +1,105 @@
+package database
+
+import (
+	"errors"
+	"routine-tracker/trackers/target"
+	"time"
+)
+
+// ErrSegmentOrder is returned when a goal change would start before the
+// latest one of the tracker
+var ErrSegmentOrder = errors.New("goal changes cannot start before the latest one")
+
+const segmentColumns = `id, tracker_id, effective_from, goal_value, goal_date, add_to_total, created_at`
+
+const insertSegmentQuery = `
+        INSERT INTO target_segments (tracker_id, effective_from, goal_value, goal_date, add_to_total, created_at)
+        VALUES (?, ?, ?, ?, ?, ?)
+    `
+
+// GetTargetSegments returns the goal configurations of a target tracker
+// ordered by the time they start to apply
+func (s *sqlStore) GetTargetSegments(userID int, trackerID int) ([]target.Segment, error) {
+	tracker, err := s.GetTargetTrackerByID(userID, trackerID)
+	if err != nil {
+		return nil, err
+	}
+	if len(tracker.Segments) == 0 {
+		return []target.Segment{tracker.InitialSegment()}, nil
+	}
+	return tracker.Segments, nil
+}
+
+// attachSegments loads the segments of the given target trackers of a user
+func (s *sqlStore) attachSegments(userID int, targets []target.TargetTracker) error {
+	if len(targets) == 0 {
+		return nil
+	}
+
+	rows, err := s.query(`
+        SELECT `+segmentColumns+` FROM target_segments
+        WHERE tracker_id IN (SELECT id FROM target_trackers WHERE user_id = ?)
+        ORDER BY tracker_id, effective_from, id
+    `, userID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	segments := make(map[int][]target.Segment)
+	for rows.Next() {
+		var seg target.Segment
+		err := rows.Scan(&seg.ID, &seg.TrackerID, &seg.EffectiveFrom, &seg.GoalValue, &seg.GoalDate, &seg.AddToTotal, &seg.CreatedAt)
+		if err != nil {
+			return err
+		}
+		segments[seg.TrackerID] = append(segments[seg.TrackerID], seg)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for i := range targets {
+		targets[i].Segments = segments[targets[i].ID]
+	}
+	return nil
+}
+
+// saveSegment records the goal configuration of an updated tracker from
+// effectiveFrom on, which may be in the future. A change starting together
+// with the latest segment replaces it, as does any change of a tracker that
+// was never reconfigured.
+func saveSegment(tx *sqlTx, previous *target.TargetTracker, updated target.TargetTracker, effectiveFrom time.Time, updatedAt time.Time) error {
+	now := updatedAt.UTC()
+	latest := previous.InitialSegment()
+	if n := len(previous.Segments); n > 0 {
+		latest = previous.Segments[n-1]
+	} else {
+		// Trackers without segments get their first one before the change
+		id, err := tx.insert(insertSegmentQuery, latest.TrackerID, latest.EffectiveFrom, latest.GoalValue, latest.GoalDate, latest.AddToTotal, now)
+		if err != nil {
+			return err
+		}
+		latest.ID = id
+	}
+
+	// Nothing changes when the goal equals the one that would apply anyway
+	applying := previous.SegmentAt(effectiveFrom)
+	if updated.GoalValue == applying.GoalValue && updated.GoalDate.Equal(applying.GoalDate) && updated.AddToTotal == applying.AddToTotal {
+		return nil
+	}
+
+	if effectiveFrom.After(latest.EffectiveFrom) {
+		_, err := tx.insert(insertSegmentQuery, updated.ID, effectiveFrom.UTC(), updated.GoalValue, updated.GoalDate, updated.AddToTotal, now)
+		return err
+	}
+	if len(previous.Segments) > 1 && effectiveFrom.Before(latest.EffectiveFrom) {
+		return ErrSegmentOrder
+	}
+
+	_, err := tx.exec(
+		"UPDATE target_segments SET goal_value = ?, goal_date = ?, add_to_total = ? WHERE id = ?",
+		updated.GoalValue, updated.GoalDate, updated.AddToTotal, latest.ID,
+	)
+	return err
+}
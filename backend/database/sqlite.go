@@ -0,0 +1,39 @@
+package database
+
+import (
+	"database/sql"
+	"log/slog"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// SQLiteStore keeps the data in a SQLite database file
+type SQLiteStore struct {
+	*sqlStore
+}
+
+var sqliteDialect = dialect{
+	name:         "sqlite",
+	goMigrations: sqliteGoMigrations,
+}
+
+// NewSQLiteStore opens the SQLite database at path without applying
+// migrations, logging them to logger
+func NewSQLiteStore(path string, logger *slog.Logger) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every connection to :memory: opens another empty database
+	if path == ":memory:" {
+		db.SetMaxOpenConns(1)
+	}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &SQLiteStore{&sqlStore{db: db, dialect: sqliteDialect, logger: logger}}, nil
+}
@@ -0,0 +1,274 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+	"routine-tracker/models"
+	"routine-tracker/trackers"
+	"routine-tracker/trackers/duration"
+	"routine-tracker/trackers/habit"
+	"routine-tracker/trackers/target"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/mattn/go-sqlite3"
+)
+
+// Store keeps all data of the application. SQLiteStore and PostgresStore
+// implement it, handlers and background jobs get a Store injected.
+type Store interface {
+	// Users and sessions
+	CreateUser(username, passwordHash string) (*models.User, error)
+	GetUserByID(id int) (*models.User, error)
+	GetUserByUsername(username string) (*models.User, error)
+	GetUserIDs() ([]int, error)
+	UpdateUserTimeZone(id int, timeZone string) error
+	CreateSession(userID int, tokenHash string, expiresAt time.Time) error
+	GetSessionUserID(tokenHash string, now time.Time) (int, error)
+	DeleteSession(tokenHash string) error
+
+	// Personal API tokens
+	CreateAPIToken(userID int, name string, scopes []string, tokenHash string) (*models.APIToken, error)
+	GetAPITokenByID(userID int, id int) (*models.APIToken, error)
+	GetAPITokens(userID int) ([]models.APIToken, error)
+	AuthenticateAPIToken(tokenHash string, usedAt time.Time) (int, int, []string, error)
+	DeleteAPIToken(userID int, id int) error
+
+	// Habit trackers
+	CreateHabitTracker(userID int, h habit.HabitTracker) (*habit.HabitTracker, error)
+	GetAllHabitTrackers(userID int, filter models.ArchiveFilter) ([]habit.HabitTracker, error)
+	GetHabitTrackerByID(userID int, id int) (*habit.HabitTracker, error)
+	UpdateHabitTracker(userID int, id int, h habit.UpdateHabitRequest) error
+	SetHabitTrackerArchived(userID int, id int, archivedAt *time.Time) error
+	DeleteHabitTracker(userID int, id int, deletedAt time.Time) error
+	RestoreHabitTracker(userID int, id int) error
+
+	// Target trackers
+	CreateTargetTracker(userID int, t target.TargetTracker) (*target.TargetTracker, error)
+	GetAllTargetTrackers(userID int, filter models.ArchiveFilter) ([]target.TargetTracker, error)
+	GetTargetTrackerByID(userID int, id int) (*target.TargetTracker, error)
+	UpdateTargetTracker(userID int, id int, t target.UpdateTargetRequest, effectiveFrom time.Time, updatedAt time.Time) error
+	SetTargetTrackerArchived(userID int, id int, archivedAt *time.Time) error
+	DeleteTargetTracker(userID int, id int, deletedAt time.Time) error
+	RestoreTargetTracker(userID int, id int) error
+	CalculateCurrentValue(tracker *target.TargetTracker) (float64, error)
+	GetAdjustedStartValue(tracker *target.TargetTracker) (float64, error)
+	GetTargetSegments(userID int, trackerID int) ([]target.Segment, error)
+
+	// Duration trackers and their timers
+	CreateDurationTracker(userID int, d duration.DurationTracker) (*duration.DurationTracker, error)
+	GetAllDurationTrackers(userID int, filter models.ArchiveFilter) ([]duration.DurationTracker, error)
+	GetDurationTrackerByID(userID int, id int) (*duration.DurationTracker, error)
+	UpdateDurationTracker(userID int, id int, d duration.UpdateDurationRequest) error
+	SetDurationTrackerArchived(userID int, id int, archivedAt *time.Time) error
+	DeleteDurationTracker(userID int, id int, deletedAt time.Time) error
+	RestoreDurationTracker(userID int, id int) error
+	GetRunningTimer(userID int) (*duration.Timer, error)
+	StartTimer(userID int, trackerID int, startedAt time.Time, note string) (*duration.Timer, error)
+	StopTimer(userID int, trackerID int, stoppedAt time.Time) (*models.Entry, error)
+
+	// Entries
+	CreateEntry(userID int, e models.Entry) (*models.Entry, error)
+	CreateEntries(userID int, entries []models.Entry) error
+	UpdateEntry(userID int, entryID int, updates models.UpdateEntryRequest) (*models.Entry, error)
+	GetEntriesByTracker(userID int, trackerID int, trackerType string) ([]models.Entry, error)
+	GetAllTrackerEntries(userID int, trackerID int, trackerType models.TrackerType) ([]models.Entry, error)
+	GetEntryByID(userID int, entryID int) (*models.Entry, error)
+	GetAllEntries(userID int) ([]models.Entry, error)
+	DeleteEntry(userID int, entryID int, deletedAt time.Time) error
+	BulkDeleteEntries(userID int, entryIDs []int, deletedAt time.Time) error
+	RestoreEntries(userID int, entryIDs []int) ([]int, error)
+
+	// Notification channels and reminders
+	CreateNotificationChannel(userID int, channel models.NotificationChannel) (*models.NotificationChannel, error)
+	GetNotificationChannelByID(userID int, id int) (*models.NotificationChannel, error)
+	GetNotificationChannels(userID int) ([]models.NotificationChannel, error)
+	GetTrackerNotificationChannels(userID int, trackerType string, trackerID int) ([]models.NotificationChannel, error)
+	UpdateNotificationChannel(userID int, id int, req models.UpdateNotificationChannelRequest) (*models.NotificationChannel, error)
+	DeleteNotificationChannel(userID int, id int) error
+	ClaimReminderDelivery(userID int, trackerType string, trackerID int, scheduledFor time.Time, sentAt time.Time) (bool, error)
+	ReleaseReminderDelivery(trackerType string, trackerID int, scheduledFor time.Time) error
+
+	// Pauses
+	CreatePause(userID int, pause models.Pause) (*models.Pause, error)
+	GetPauseByID(userID int, id int) (*models.Pause, error)
+	GetPauses(userID int) ([]models.Pause, error)
+	UpdatePause(userID int, id int, pause models.Pause) (*models.Pause, error)
+	DeletePause(userID int, id int) error
+
+	// Tags and categories
+	CreateTag(userID int, name string) (*models.Tag, error)
+	GetTagByID(userID int, id int) (*models.Tag, error)
+	GetTags(userID int) ([]models.Tag, error)
+	UpdateTag(userID int, id int, name string) (*models.Tag, error)
+	DeleteTag(userID int, id int) error
+	SetTrackerTags(userID int, trackerType models.TrackerType, trackerID int, tagIDs []int) error
+	CreateCategory(userID int, category models.Category) (*models.Category, error)
+	GetCategoryByID(userID int, id int) (*models.Category, error)
+	GetCategories(userID int) ([]models.Category, error)
+	UpdateCategory(userID int, id int, category models.Category) (*models.Category, error)
+	DeleteCategory(userID int, id int) error
+	SetTrackerCategory(userID int, trackerType models.TrackerType, trackerID int, categoryID *int) error
+	UpdateTrackerOrder(userID int, positions []models.TrackerPosition) error
+
+	// Trash
+	GetTrash(userID int) (*trackers.TrashResponse, error)
+	EmptyTrash(userID int) (int, error)
+	PurgeTrash(before time.Time) (int, error)
+
+	// Audit log
+	CreateAuditEvent(event models.AuditEvent) (*models.AuditEvent, error)
+	GetAuditEvents(userID int, filter models.AuditFilter) ([]models.AuditEvent, error)
+
+	// Import and export
+	ExportArchive(userID int, exportedAt time.Time) (*trackers.Archive, error)
+	ImportArchive(userID int, archive trackers.Archive, mode trackers.ImportMode, dryRun bool, now time.Time) (*trackers.ImportResult, error)
+
+	// Schema
+	GetMigrationStatus() ([]MigrationStatus, error)
+	MigrateUp(version int) (int, error)
+	MigrateDown(steps int) (int, error)
+
+	Close() error
+}
+
+// dialect holds what differs between the SQL databases
+type dialect struct {
+	name string
+	// numbered placeholders ($1, $2, ...) instead of ?
+	numbered bool
+	// goMigrations complement the SQL files in migrations/<name>
+	goMigrations []Migration
+}
+
+// sqlStore implements Store with database/sql. Queries are written with ?
+// placeholders and rewritten for the dialect.
+type sqlStore struct {
+	db      *sql.DB
+	dialect dialect
+	logger  *slog.Logger
+}
+
+// rowScanner is a *sql.Row or *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// rebind rewrites ? placeholders for the dialect
+func (d dialect) rebind(query string) string {
+	if !d.numbered {
+		return query
+	}
+
+	var b strings.Builder
+	n := 0
+	for _, c := range query {
+		if c == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+func (s *sqlStore) exec(query string, args ...interface{}) (sql.Result, error) {
+	return s.db.Exec(s.dialect.rebind(query), args...)
+}
+
+func (s *sqlStore) query(query string, args ...interface{}) (*sql.Rows, error) {
+	return s.db.Query(s.dialect.rebind(query), args...)
+}
+
+func (s *sqlStore) queryRow(query string, args ...interface{}) *sql.Row {
+	return s.db.QueryRow(s.dialect.rebind(query), args...)
+}
+
+// insert runs an INSERT statement and returns the ID of the new row
+func (s *sqlStore) insert(query string, args ...interface{}) (int, error) {
+	var id int
+	err := s.queryRow(query+" RETURNING id", args...).Scan(&id)
+	return id, err
+}
+
+func (s *sqlStore) begin() (*sqlTx, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	return &sqlTx{Tx: tx, dialect: s.dialect}, nil
+}
+
+func (s *sqlStore) Close() error {
+	return s.db.Close()
+}
+
+// sqlTx is a transaction that rewrites placeholders like sqlStore
+type sqlTx struct {
+	*sql.Tx
+	dialect dialect
+}
+
+func (tx *sqlTx) exec(query string, args ...interface{}) (sql.Result, error) {
+	return tx.Exec(tx.dialect.rebind(query), args...)
+}
+
+func (tx *sqlTx) query(query string, args ...interface{}) (*sql.Rows, error) {
+	return tx.Query(tx.dialect.rebind(query), args...)
+}
+
+func (tx *sqlTx) queryRow(query string, args ...interface{}) *sql.Row {
+	return tx.QueryRow(tx.dialect.rebind(query), args...)
+}
+
+func (tx *sqlTx) insert(query string, args ...interface{}) (int, error) {
+	var id int
+	err := tx.queryRow(query+" RETURNING id", args...).Scan(&id)
+	return id, err
+}
+
+// isUniqueViolation reports whether a statement failed on a unique index, in
+// either dialect
+func isUniqueViolation(err error) bool {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique || sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey
+	}
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+// archivedCondition returns the WHERE condition of tracker queries selecting
+// trackers by their archived state
+func archivedCondition(filter models.ArchiveFilter) string {
+	switch filter {
+	case models.ALL_TRACKERS:
+		return ""
+	case models.ARCHIVED_TRACKERS:
+		return " AND archived_at IS NOT NULL"
+	default:
+		return " AND archived_at IS NULL"
+	}
+}
+
+// setArchived sets archived_at of a tracker in one of the tracker tables
+func (s *sqlStore) setArchived(table string, userID int, id int, archivedAt *time.Time) error {
+	result, err := s.exec("UPDATE "+table+" SET archived_at = ? WHERE id = ? AND user_id = ? AND deleted_at IS NULL", archivedAt, id, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
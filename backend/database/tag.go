@@ -0,0 +1,250 @@
+package database
+
+import (
+	"database/sql"
+	"routine-tracker/models"
+)
+
+const tagColumns = `id, user_id, name, created_at`
+
+const categoryColumns = `id, user_id, name, color, icon, created_at`
+
+func (s *sqlStore) CreateTag(userID int, name string) (*models.Tag, error) {
+	id, err := s.insert("INSERT INTO tags (user_id, name) VALUES (?, ?)", userID, name)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetTagByID(userID, id)
+}
+
+func (s *sqlStore) GetTagByID(userID int, id int) (*models.Tag, error) {
+	var t models.Tag
+	err := s.queryRow(`SELECT `+tagColumns+` FROM tags WHERE id = ? AND user_id = ?`, id, userID).
+		Scan(&t.ID, &t.UserID, &t.Name, &t.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// GetTags returns the tags of a user ordered by name
+func (s *sqlStore) GetTags(userID int) ([]models.Tag, error) {
+	rows, err := s.query(`SELECT `+tagColumns+` FROM tags WHERE user_id = ? ORDER BY name, id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := make([]models.Tag, 0)
+	for rows.Next() {
+		var t models.Tag
+		if err := rows.Scan(&t.ID, &t.UserID, &t.Name, &t.CreatedAt); err != nil {
+			return nil, err
+		}
+		tags = append(tags, t)
+	}
+	return tags, rows.Err()
+}
+
+func (s *sqlStore) UpdateTag(userID int, id int, name string) (*models.Tag, error) {
+	result, err := s.exec("UPDATE tags SET name = ? WHERE id = ? AND user_id = ?", name, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireAffected(result); err != nil {
+		return nil, err
+	}
+	return s.GetTagByID(userID, id)
+}
+
+// DeleteTag deletes a tag and removes it from all trackers
+func (s *sqlStore) DeleteTag(userID int, id int) error {
+	tx, err := s.begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.exec("DELETE FROM tracker_tags WHERE tag_id IN (SELECT id FROM tags WHERE id = ? AND user_id = ?)", id, userID); err != nil {
+		return err
+	}
+	result, err := tx.exec("DELETE FROM tags WHERE id = ? AND user_id = ?", id, userID)
+	if err != nil {
+		return err
+	}
+	if err := requireAffected(result); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// SetTrackerTags replaces the tags of a tracker. The tags must belong to the
+// user, which the caller checks.
+func (s *sqlStore) SetTrackerTags(userID int, trackerType models.TrackerType, trackerID int, tagIDs []int) error {
+	tx, err := s.begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.exec(
+		"DELETE FROM tracker_tags WHERE tracker_type = ? AND tracker_id = ? AND tag_id IN (SELECT id FROM tags WHERE user_id = ?)",
+		trackerType, trackerID, userID,
+	)
+	if err != nil {
+		return err
+	}
+
+	added := make(map[int]bool)
+	for _, tagID := range tagIDs {
+		if added[tagID] {
+			continue
+		}
+		added[tagID] = true
+		if _, err := tx.exec("INSERT INTO tracker_tags (tag_id, tracker_type, tracker_id) VALUES (?, ?, ?)", tagID, trackerType, trackerID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// trackerTags returns the tags of the trackers of a user by tracker ID
+func (s *sqlStore) trackerTags(userID int, trackerType models.TrackerType) (map[int][]models.Tag, error) {
+	rows, err := s.query(`
+        SELECT tt.tracker_id, t.id, t.user_id, t.name, t.created_at
+        FROM tracker_tags tt JOIN tags t ON t.id = tt.tag_id
+        WHERE t.user_id = ? AND tt.tracker_type = ?
+        ORDER BY t.name, t.id
+    `, userID, trackerType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := make(map[int][]models.Tag)
+	for rows.Next() {
+		var trackerID int
+		var t models.Tag
+		if err := rows.Scan(&trackerID, &t.ID, &t.UserID, &t.Name, &t.CreatedAt); err != nil {
+			return nil, err
+		}
+		tags[trackerID] = append(tags[trackerID], t)
+	}
+	return tags, rows.Err()
+}
+
+// tagsOf returns the tags of a tracker, an empty list for untagged ones
+func tagsOf(tags map[int][]models.Tag, trackerID int) []models.Tag {
+	if trackerTags, ok := tags[trackerID]; ok {
+		return trackerTags
+	}
+	return []models.Tag{}
+}
+
+func (s *sqlStore) CreateCategory(userID int, category models.Category) (*models.Category, error) {
+	id, err := s.insert(
+		"INSERT INTO categories (user_id, name, color, icon) VALUES (?, ?, ?, ?)",
+		userID, category.Name, category.Color, category.Icon,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetCategoryByID(userID, id)
+}
+
+func (s *sqlStore) GetCategoryByID(userID int, id int) (*models.Category, error) {
+	var c models.Category
+	err := s.queryRow(`SELECT `+categoryColumns+` FROM categories WHERE id = ? AND user_id = ?`, id, userID).
+		Scan(&c.ID, &c.UserID, &c.Name, &c.Color, &c.Icon, &c.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// GetCategories returns the categories of a user ordered by name
+func (s *sqlStore) GetCategories(userID int) ([]models.Category, error) {
+	rows, err := s.query(`SELECT `+categoryColumns+` FROM categories WHERE user_id = ? ORDER BY name, id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories := make([]models.Category, 0)
+	for rows.Next() {
+		var c models.Category
+		if err := rows.Scan(&c.ID, &c.UserID, &c.Name, &c.Color, &c.Icon, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		categories = append(categories, c)
+	}
+	return categories, rows.Err()
+}
+
+func (s *sqlStore) UpdateCategory(userID int, id int, category models.Category) (*models.Category, error) {
+	result, err := s.exec(
+		"UPDATE categories SET name = ?, color = ?, icon = ? WHERE id = ? AND user_id = ?",
+		category.Name, category.Color, category.Icon, id, userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireAffected(result); err != nil {
+		return nil, err
+	}
+	return s.GetCategoryByID(userID, id)
+}
+
+// DeleteCategory deletes a category, its trackers become uncategorized
+func (s *sqlStore) DeleteCategory(userID int, id int) error {
+	tx, err := s.begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, table := range trackerTables {
+		if _, err := tx.exec("UPDATE "+table.name+" SET category_id = NULL WHERE category_id = ? AND user_id = ?", id, userID); err != nil {
+			return err
+		}
+	}
+	result, err := tx.exec("DELETE FROM categories WHERE id = ? AND user_id = ?", id, userID)
+	if err != nil {
+		return err
+	}
+	if err := requireAffected(result); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// SetTrackerCategory moves a tracker to a category of the user, nil removes
+// it from its category
+func (s *sqlStore) SetTrackerCategory(userID int, trackerType models.TrackerType, trackerID int, categoryID *int) error {
+	result, err := s.exec("UPDATE "+trackerTable(trackerType)+" SET category_id = ? WHERE id = ? AND user_id = ? AND deleted_at IS NULL", categoryID, trackerID, userID)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
+
+// requireAffected returns sql.ErrNoRows when a statement changed no rows
+func requireAffected(result sql.Result) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// nullableID converts a nullable ID column
+func nullableID(id sql.NullInt64) *int {
+	if !id.Valid {
+		return nil
+	}
+	value := int(id.Int64)
+	return &value
+}
@@ -1,12 +1,14 @@
 package database
 
 import (
+	"database/sql"
 	"encoding/json"
+	"routine-tracker/models"
 	"routine-tracker/trackers/target"
 	"time"
 )
 
-func CreateTargetTracker(t target.TargetTracker) (*target.TargetTracker, error) {
+func (s *sqlStore) CreateTargetTracker(userID int, t target.TargetTracker) (*target.TargetTracker, error) {
 	dueSpecificDays, _ := json.Marshal(t.Due.SpecificDays)
 	reminderTimes, _ := json.Marshal(t.Reminders.Times)
 
@@ -19,40 +21,58 @@ func CreateTargetTracker(t target.TargetTracker) (*target.TargetTracker, error)
 
 	query := `
         INSERT INTO target_trackers (
-            tracker_name, start_value, goal_value, start_date, goal_date, add_to_total, use_actual_bounds, trend_weight_type,
-            due_type, due_specific_days, due_interval_type, due_interval_value,
-            reminder_times, reminder_enabled
-        ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+            user_id, tracker_name, start_value, goal_value, start_date, goal_date, add_to_total, use_actual_bounds, trend_weight_type,
+            due_type, due_specific_days, due_interval_type, due_interval_value, due_rrule,
+            reminder_times, reminder_enabled, pinned, created_at
+        ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
     `
 
-	result, err := DB.Exec(query,
-		t.TrackerName, t.StartValue, t.GoalValue, t.StartDate, t.GoalDate, t.AddToTotal, t.UseActualBounds, trendWeightType,
-		t.Due.Type, string(dueSpecificDays), t.Due.IntervalType, t.Due.IntervalValue,
-		string(reminderTimes), t.Reminders.Enabled,
-	)
+	// The tracker and its initial segment are created together
+	tx, err := s.begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
 
+	id, err := tx.insert(query,
+		userID, t.TrackerName, t.StartValue, t.GoalValue, t.StartDate, t.GoalDate, t.AddToTotal, t.UseActualBounds, trendWeightType,
+		t.Due.Type, string(dueSpecificDays), t.Due.IntervalType, t.Due.IntervalValue, t.Due.RRule,
+		string(reminderTimes), t.Reminders.Enabled, t.Pinned, t.CreatedAt.UTC(),
+	)
 	if err != nil {
 		return nil, err
 	}
 
-	id, err := result.LastInsertId()
+	_, err = tx.insert(insertSegmentQuery, id, t.StartDate, t.GoalValue, t.GoalDate, t.AddToTotal, t.CreatedAt.UTC())
 	if err != nil {
 		return nil, err
 	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
 
 	// Fetch the created record to get database defaults applied
-	return GetTargetTrackerByID(int(id))
+	return s.GetTargetTrackerByID(userID, id)
 }
 
-func GetAllTargetTrackers() ([]target.TargetTracker, error) {
+func (s *sqlStore) GetAllTargetTrackers(userID int, filter models.ArchiveFilter) ([]target.TargetTracker, error) {
+	targets, err := s.queryTargetTrackers(userID, "deleted_at IS NULL"+archivedCondition(filter))
+	if err != nil {
+		return nil, err
+	}
+	return targets, s.attachSegments(userID, targets)
+}
+
+// queryTargetTrackers returns the target trackers of a user matching condition
+func (s *sqlStore) queryTargetTrackers(userID int, condition string) ([]target.TargetTracker, error) {
 	query := `
         SELECT id, tracker_name, start_value, goal_value, start_date, goal_date, add_to_total, use_actual_bounds, trend_weight_type,
-               due_type, due_specific_days, due_interval_type, due_interval_value,
-               reminder_times, reminder_enabled, created_at
-        FROM target_trackers ORDER BY created_at DESC
+               due_type, due_specific_days, due_interval_type, due_interval_value, due_rrule,
+               reminder_times, reminder_enabled, created_at, archived_at, deleted_at, category_id, position, pinned
+        FROM target_trackers WHERE user_id = ? AND ` + condition + ` ORDER BY ` + trackerOrder + `
     `
 
-	rows, err := DB.Query(query)
+	rows, err := s.query(query, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -63,61 +83,102 @@ func GetAllTargetTrackers() ([]target.TargetTracker, error) {
 	for rows.Next() {
 		var t target.TargetTracker
 		var dueSpecificDaysJSON, reminderTimesJSON string
+		var archivedAt, deletedAt sql.NullTime
+		var categoryID sql.NullInt64
 
 		err := rows.Scan(
 			&t.ID, &t.TrackerName, &t.StartValue, &t.GoalValue, &t.StartDate, &t.GoalDate, &t.AddToTotal, &t.UseActualBounds, &t.TrendWeightType,
-			&t.Due.Type, &dueSpecificDaysJSON, &t.Due.IntervalType, &t.Due.IntervalValue,
-			&reminderTimesJSON, &t.Reminders.Enabled, &t.CreatedAt,
+			&t.Due.Type, &dueSpecificDaysJSON, &t.Due.IntervalType, &t.Due.IntervalValue, &t.Due.RRule,
+			&reminderTimesJSON, &t.Reminders.Enabled, &t.CreatedAt, &archivedAt, &deletedAt, &categoryID, &t.Position, &t.Pinned,
 		)
 
 		if err != nil {
 			return nil, err
 		}
+		t.UserID = userID
 
 		json.Unmarshal([]byte(dueSpecificDaysJSON), &t.Due.SpecificDays)
 		json.Unmarshal([]byte(reminderTimesJSON), &t.Reminders.Times)
+		if archivedAt.Valid {
+			t.ArchivedAt = &archivedAt.Time
+		}
+		if deletedAt.Valid {
+			t.DeletedAt = &deletedAt.Time
+		}
+		t.CategoryID = nullableID(categoryID)
 
 		targets = append(targets, t)
 	}
 
+	tags, err := s.trackerTags(userID, models.TARGET)
+	if err != nil {
+		return nil, err
+	}
+	for i := range targets {
+		targets[i].Tags = tagsOf(tags, targets[i].ID)
+	}
+
 	return targets, nil
 }
 
-func GetTargetTrackerByID(id int) (*target.TargetTracker, error) {
+func (s *sqlStore) GetTargetTrackerByID(userID int, id int) (*target.TargetTracker, error) {
 
 	query := `
         SELECT id, tracker_name, start_value, goal_value, start_date, goal_date, add_to_total, use_actual_bounds, trend_weight_type,
-               due_type, due_specific_days, due_interval_type, due_interval_value,
-               reminder_times, reminder_enabled, created_at
-        FROM target_trackers WHERE id = ?
+               due_type, due_specific_days, due_interval_type, due_interval_value, due_rrule,
+               reminder_times, reminder_enabled, created_at, archived_at, category_id, position, pinned
+        FROM target_trackers WHERE id = ? AND user_id = ? AND deleted_at IS NULL
     `
 	var t target.TargetTracker
 	var dueSpecificDaysJSON, reminderTimesJSON string
+	var archivedAt sql.NullTime
+	var categoryID sql.NullInt64
 
-	err := DB.QueryRow(query, id).Scan(
+	err := s.queryRow(query, id, userID).Scan(
 		&t.ID, &t.TrackerName, &t.StartValue, &t.GoalValue, &t.StartDate, &t.GoalDate, &t.AddToTotal, &t.UseActualBounds, &t.TrendWeightType,
-		&t.Due.Type, &dueSpecificDaysJSON, &t.Due.IntervalType, &t.Due.IntervalValue,
-		&reminderTimesJSON, &t.Reminders.Enabled, &t.CreatedAt,
+		&t.Due.Type, &dueSpecificDaysJSON, &t.Due.IntervalType, &t.Due.IntervalValue, &t.Due.RRule,
+		&reminderTimesJSON, &t.Reminders.Enabled, &t.CreatedAt, &archivedAt, &categoryID, &t.Position, &t.Pinned,
 	)
 
 	if err != nil {
 		return nil, err
 	}
+	t.UserID = userID
 
 	json.Unmarshal([]byte(dueSpecificDaysJSON), &t.Due.SpecificDays)
 	json.Unmarshal([]byte(reminderTimesJSON), &t.Reminders.Times)
+	if archivedAt.Valid {
+		t.ArchivedAt = &archivedAt.Time
+	}
+	t.CategoryID = nullableID(categoryID)
 
-	return &t, nil
+	tags, err := s.trackerTags(userID, models.TARGET)
+	if err != nil {
+		return nil, err
+	}
+	t.Tags = tagsOf(tags, t.ID)
+
+	targets := []target.TargetTracker{t}
+	if err := s.attachSegments(userID, targets); err != nil {
+		return nil, err
+	}
+	return &targets[0], nil
 
 }
 
-func UpdateTargetTracker(id int, t target.UpdateTargetRequest) error {
+// UpdateTargetTracker updates a target tracker. A changed goal value, goal date
+// or addToTotal applies to the entries from effectiveFrom on. An effectiveFrom
+// after updatedAt plans the change, the tracker keeps its goal until then.
+func (s *sqlStore) UpdateTargetTracker(userID int, id int, t target.UpdateTargetRequest, effectiveFrom time.Time, updatedAt time.Time) error {
 	// First get the current target tracker to merge with updates
-	current, err := GetTargetTrackerByID(id)
+	current, err := s.GetTargetTrackerByID(userID, id)
 	if err != nil {
 		return err
 	}
-	
+	// Planned goal changes may have come into effect since the last update
+	*current = current.At(updatedAt)
+	previous := *current
+
 	// Apply updates to current values
 	if t.TrackerName != nil {
 		current.TrackerName = *t.TrackerName
@@ -157,7 +218,16 @@ func UpdateTargetTracker(id int, t target.UpdateTargetRequest) error {
 	if t.Reminders != nil {
 		current.Reminders = *t.Reminders
 	}
-	
+	if t.Pinned != nil {
+		current.Pinned = *t.Pinned
+	}
+
+	// A planned goal change leaves the goal in effect until it starts
+	goal := *current
+	if effectiveFrom.After(updatedAt) {
+		goal = previous
+	}
+
 	// Now update with the merged values
 	dueSpecificDays, _ := json.Marshal(current.Due.SpecificDays)
 	reminderTimes, _ := json.Marshal(current.Reminders.Times)
@@ -165,95 +235,78 @@ func UpdateTargetTracker(id int, t target.UpdateTargetRequest) error {
 	query := `
         UPDATE target_trackers SET
             tracker_name = ?, start_value = ?, goal_value = ?, start_date = ?, goal_date = ?, add_to_total = ?, use_actual_bounds = ?, trend_weight_type = ?,
-            due_type = ?, due_specific_days = ?, due_interval_type = ?, due_interval_value = ?,
-            reminder_times = ?, reminder_enabled = ?
-        WHERE id = ?
+            due_type = ?, due_specific_days = ?, due_interval_type = ?, due_interval_value = ?, due_rrule = ?,
+            reminder_times = ?, reminder_enabled = ?, pinned = ?
+        WHERE id = ? AND user_id = ?
     `
 
-	_, err = DB.Exec(query,
-		current.TrackerName, current.StartValue, current.GoalValue, current.StartDate, current.GoalDate, current.AddToTotal, current.UseActualBounds, current.TrendWeightType,
-		current.Due.Type, string(dueSpecificDays), current.Due.IntervalType, current.Due.IntervalValue,
-		string(reminderTimes), current.Reminders.Enabled, id,
-	)
-
-	return err
-}
-
-func DeleteTargetTracker(id int) error {
-	tx, err := DB.Begin()
+	tx, err := s.begin()
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
-	_, err = tx.Exec("DELETE FROM entries WHERE tracker_id = ? AND type = 'target'", id)
+	_, err = tx.exec(query,
+		current.TrackerName, current.StartValue, goal.GoalValue, current.StartDate, goal.GoalDate, goal.AddToTotal, current.UseActualBounds, current.TrendWeightType,
+		current.Due.Type, string(dueSpecificDays), current.Due.IntervalType, current.Due.IntervalValue, current.Due.RRule,
+		string(reminderTimes), current.Reminders.Enabled, current.Pinned, id, userID,
+	)
 	if err != nil {
 		return err
 	}
 
-	_, err = tx.Exec("DELETE FROM target_trackers WHERE id = ?", id)
-	if err != nil {
+	if err := saveSegment(tx, &previous, *current, effectiveFrom, updatedAt); err != nil {
 		return err
 	}
-
 	return tx.Commit()
 }
 
-// CalculateCurrentValue calculates the current value for a target tracker based on its entries
-func CalculateCurrentValue(tracker *target.TargetTracker) (float64, error) {
-	entries, err := GetEntriesByTracker(tracker.ID, "target")
+// SetTargetTrackerArchived archives a target tracker at archivedAt, or
+// restores it when archivedAt is nil
+func (s *sqlStore) SetTargetTrackerArchived(userID int, id int, archivedAt *time.Time) error {
+	return s.setArchived("target_trackers", userID, id, archivedAt)
+}
+
+// DeleteTargetTracker moves a target tracker and its entries to the trash
+func (s *sqlStore) DeleteTargetTracker(userID int, id int, deletedAt time.Time) error {
+	return s.trashTracker("target_trackers", models.TARGET, userID, id, deletedAt)
+}
+
+// RestoreTargetTracker takes a target tracker and the entries deleted with it
+// out of the trash
+func (s *sqlStore) RestoreTargetTracker(userID int, id int) error {
+	return s.restoreTracker("target_trackers", models.TARGET, userID, id)
+}
+
+// CalculateCurrentValue calculates the current value for a target tracker based
+// on its entries, each read with the goal configuration in effect at its date
+func (s *sqlStore) CalculateCurrentValue(tracker *target.TargetTracker) (float64, error) {
+	values, err := s.progress(tracker)
 	if err != nil {
 		return tracker.StartValue, err
 	}
 
-	if tracker.AddToTotal {
-		// Additive: sum all entry values and add to start value
-		total := tracker.StartValue
-		for _, entry := range entries {
-			total += entry.Value
-		}
-		return total, nil
-	} else {
-		// Replacement: use the most recent entry value, or start value if no entries
-		if len(entries) > 0 {
-			// entries are already ordered by date DESC from GetEntriesByTracker
-			return entries[0].Value, nil
-		}
+	if len(values) == 0 {
 		return tracker.StartValue, nil
 	}
+	return values[len(values)-1], nil
 }
 
 // GetAdjustedStartValue returns the adjusted start value based on UseActualBounds setting
-func GetAdjustedStartValue(tracker *target.TargetTracker) (float64, error) {
+func (s *sqlStore) GetAdjustedStartValue(tracker *target.TargetTracker) (float64, error) {
 	if !tracker.UseActualBounds {
 		return tracker.StartValue, nil
 	}
 
-	entries, err := GetEntriesByTracker(tracker.ID, "target")
+	allValues, err := s.progress(tracker)
 	if err != nil {
 		return tracker.StartValue, err
 	}
 
-	if len(entries) == 0 {
+	if len(allValues) == 0 {
 		return tracker.StartValue, nil
 	}
 
-	// Calculate all progress values
-	var allValues []float64
-	if tracker.AddToTotal {
-		// For additive targets, calculate cumulative values
-		cumulative := tracker.StartValue
-		for i := len(entries) - 1; i >= 0; i-- { // Process in chronological order
-			cumulative += entries[i].Value
-			allValues = append(allValues, cumulative)
-		}
-	} else {
-		// For replacement targets, use actual entry values
-		for _, entry := range entries {
-			allValues = append(allValues, entry.Value)
-		}
-	}
-
 	// Find min and max values
 	minValue := allValues[0]
 	maxValue := allValues[0]
@@ -280,3 +333,19 @@ func GetAdjustedStartValue(tracker *target.TargetTracker) (float64, error) {
 
 	return tracker.StartValue, nil
 }
+
+// progress returns the value of a target tracker after each of its entries in
+// chronological order
+func (s *sqlStore) progress(tracker *target.TargetTracker) ([]float64, error) {
+	entries, err := s.GetEntriesByTracker(tracker.UserID, tracker.ID, "target")
+	if err != nil {
+		return nil, err
+	}
+
+	// entries are ordered by date DESC from GetEntriesByTracker
+	chronological := make([]models.Entry, len(entries))
+	for i, entry := range entries {
+		chronological[len(entries)-1-i] = entry
+	}
+	return tracker.Progress(chronological), nil
+}
@@ -0,0 +1,101 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"routine-tracker/models"
+	"time"
+)
+
+func (s *sqlStore) CreateAPIToken(userID int, name string, scopes []string, tokenHash string) (*models.APIToken, error) {
+	scopesJSON, _ := json.Marshal(scopes)
+
+	id, err := s.insert(
+		"INSERT INTO api_tokens (user_id, name, token_hash, scopes) VALUES (?, ?, ?, ?)",
+		userID, name, tokenHash, string(scopesJSON),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetAPITokenByID(userID, id)
+}
+
+func (s *sqlStore) GetAPITokenByID(userID int, id int) (*models.APIToken, error) {
+	query := `SELECT id, name, scopes, last_used_at, created_at FROM api_tokens WHERE id = ? AND user_id = ?`
+	return scanAPIToken(s.queryRow(query, id, userID))
+}
+
+func (s *sqlStore) GetAPITokens(userID int) ([]models.APIToken, error) {
+	query := `SELECT id, name, scopes, last_used_at, created_at FROM api_tokens WHERE user_id = ? ORDER BY created_at DESC`
+
+	rows, err := s.query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens := make([]models.APIToken, 0)
+	for rows.Next() {
+		token, err := scanAPIToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, *token)
+	}
+
+	return tokens, nil
+}
+
+// AuthenticateAPIToken returns the owner, ID and scopes of an API token and records its use
+func (s *sqlStore) AuthenticateAPIToken(tokenHash string, usedAt time.Time) (int, int, []string, error) {
+	var id, userID int
+	var scopesJSON string
+	err := s.queryRow("SELECT id, user_id, scopes FROM api_tokens WHERE token_hash = ?", tokenHash).Scan(&id, &userID, &scopesJSON)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+
+	var scopes []string
+	json.Unmarshal([]byte(scopesJSON), &scopes)
+
+	if _, err := s.exec("UPDATE api_tokens SET last_used_at = ? WHERE id = ?", usedAt.UTC(), id); err != nil {
+		return 0, 0, nil, err
+	}
+
+	return userID, id, scopes, nil
+}
+
+func (s *sqlStore) DeleteAPIToken(userID int, id int) error {
+	result, err := s.exec("DELETE FROM api_tokens WHERE id = ? AND user_id = ?", id, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func scanAPIToken(row rowScanner) (*models.APIToken, error) {
+	var t models.APIToken
+	var scopesJSON string
+	var lastUsedAt sql.NullTime
+
+	if err := row.Scan(&t.ID, &t.Name, &scopesJSON, &lastUsedAt, &t.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	json.Unmarshal([]byte(scopesJSON), &t.Scopes)
+	if lastUsedAt.Valid {
+		t.LastUsedAt = &lastUsedAt.Time
+	}
+
+	return &t, nil
+}
@@ -0,0 +1,236 @@
+package database
+
+import (
+	"database/sql"
+	"routine-tracker/models"
+	"routine-tracker/trackers"
+	"routine-tracker/trackers/duration"
+	"routine-tracker/trackers/habit"
+	"routine-tracker/trackers/target"
+	"sort"
+	"time"
+)
+
+// trackerTables are the tables of the tracker types
+var trackerTables = []struct {
+	name        string
+	trackerType models.TrackerType
+}{{"habit_trackers", models.HABIT}, {"target_trackers", models.TARGET}, {"duration_trackers", models.DURATION}}
+
+// trackerTable returns the table of a tracker type
+func trackerTable(trackerType models.TrackerType) string {
+	for _, table := range trackerTables {
+		if table.trackerType == trackerType {
+			return table.name
+		}
+	}
+	return ""
+}
+
+// notOfTrashedTracker matches the rows of entries or pauses that do not belong
+// to a tracker in the trash. typeColumn holds the tracker type of a row.
+func notOfTrashedTracker(typeColumn string) string {
+	return `(tracker_id IS NULL OR NOT (
+        (` + typeColumn + ` = 'habit' AND tracker_id IN (SELECT id FROM habit_trackers WHERE deleted_at IS NOT NULL))
+        OR (` + typeColumn + ` = 'target' AND tracker_id IN (SELECT id FROM target_trackers WHERE deleted_at IS NOT NULL))
+        OR (` + typeColumn + ` = 'duration' AND tracker_id IN (SELECT id FROM duration_trackers WHERE deleted_at IS NOT NULL))))`
+}
+
+// trashTracker moves a tracker and its entries to the trash. The entries get
+// the same deleted_at as the tracker, restoreTracker finds them by it.
+func (s *sqlStore) trashTracker(table string, trackerType models.TrackerType, userID int, id int, deletedAt time.Time) error {
+	deletedAt = deletedAt.UTC()
+
+	tx, err := s.begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.exec("UPDATE "+table+" SET deleted_at = ? WHERE id = ? AND user_id = ? AND deleted_at IS NULL", deletedAt, id, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	_, err = tx.exec(
+		"UPDATE entries SET deleted_at = ? WHERE tracker_id = ? AND type = ? AND user_id = ? AND deleted_at IS NULL",
+		deletedAt, id, trackerType, userID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// restoreTracker takes a tracker out of the trash together with the entries
+// deleted with it. Entries deleted on their own before stay in the trash.
+func (s *sqlStore) restoreTracker(table string, trackerType models.TrackerType, userID int, id int) error {
+	tx, err := s.begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var deletedAt time.Time
+	err = tx.queryRow("SELECT deleted_at FROM "+table+" WHERE id = ? AND user_id = ? AND deleted_at IS NOT NULL", id, userID).Scan(&deletedAt)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.exec("UPDATE "+table+" SET deleted_at = NULL WHERE id = ? AND user_id = ?", id, userID); err != nil {
+		return err
+	}
+
+	_, err = tx.exec(
+		"UPDATE entries SET deleted_at = NULL WHERE tracker_id = ? AND type = ? AND user_id = ? AND deleted_at = ?",
+		id, trackerType, userID, deletedAt.UTC(),
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RestoreEntries takes entries out of the trash and returns the IDs of the
+// restored ones. Entries of trackers in the trash are left alone, they come
+// back with their tracker.
+func (s *sqlStore) RestoreEntries(userID int, entryIDs []int) ([]int, error) {
+	restored := make([]int, 0)
+	if len(entryIDs) == 0 {
+		return restored, nil
+	}
+
+	placeholders, args := inClause(entryIDs)
+	query := `UPDATE entries SET deleted_at = NULL
+        WHERE id IN (` + placeholders + `) AND user_id = ? AND deleted_at IS NOT NULL AND ` + notOfTrashedTracker("type") + `
+        RETURNING id`
+	args = append(args, userID)
+
+	rows, err := s.query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		restored = append(restored, id)
+	}
+	return restored, rows.Err()
+}
+
+// GetTrash lists the trackers and entries of a user in the trash, the most
+// recently deleted first
+func (s *sqlStore) GetTrash(userID int) (*trackers.TrashResponse, error) {
+	habits, err := s.queryHabitTrackers(userID, "deleted_at IS NOT NULL")
+	if err != nil {
+		return nil, err
+	}
+	targets, err := s.queryTargetTrackers(userID, "deleted_at IS NOT NULL")
+	if err != nil {
+		return nil, err
+	}
+	durations, err := s.queryDurationTrackers(userID, "deleted_at IS NOT NULL")
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.queryEntries(userID, `
+        SELECT `+entryColumns+` FROM entries
+        WHERE user_id = ? AND deleted_at IS NOT NULL AND `+notOfTrashedTracker("type")+`
+        ORDER BY deleted_at DESC, id`, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(habits, func(i, j int) bool { return habits[i].DeletedAt.After(*habits[j].DeletedAt) })
+	sort.SliceStable(targets, func(i, j int) bool { return targets[i].DeletedAt.After(*targets[j].DeletedAt) })
+	sort.SliceStable(durations, func(i, j int) bool { return durations[i].DeletedAt.After(*durations[j].DeletedAt) })
+
+	return &trackers.TrashResponse{
+		HabitTrackers:    append(make([]habit.HabitTracker, 0, len(habits)), habits...),
+		TargetTrackers:   append(make([]target.TargetTracker, 0, len(targets)), targets...),
+		DurationTrackers: append(make([]duration.DurationTracker, 0, len(durations)), durations...),
+		Entries:          append(make([]models.Entry, 0, len(entries)), entries...),
+	}, nil
+}
+
+// EmptyTrash deletes everything in a user's trash for good and returns the
+// number of deleted trackers and entries
+func (s *sqlStore) EmptyTrash(userID int) (int, error) {
+	return s.purge(" AND user_id = ?", userID)
+}
+
+// PurgeTrash deletes the trackers and entries of all users that were moved to
+// the trash before the given time for good and returns how many were deleted
+func (s *sqlStore) PurgeTrash(before time.Time) (int, error) {
+	return s.purge(" AND deleted_at < ?", before.UTC())
+}
+
+// purge deletes trashed trackers and entries matching condition, together with
+// the entries, pauses, notification channels, tags, segments and timers of
+// the trackers
+func (s *sqlStore) purge(condition string, args ...interface{}) (int, error) {
+	tx, err := s.begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	purged := 0
+	for _, table := range trackerTables {
+		trashed := "SELECT id FROM " + table.name + " WHERE deleted_at IS NOT NULL" + condition
+		trackerArgs := append([]interface{}{table.trackerType}, args...)
+
+		result, err := tx.exec("DELETE FROM entries WHERE type = ? AND tracker_id IN ("+trashed+")", trackerArgs...)
+		if err != nil {
+			return 0, err
+		}
+		entries, _ := result.RowsAffected()
+		purged += int(entries)
+
+		for _, dependent := range []string{"pauses", "notification_channels", "tracker_tags"} {
+			if _, err := tx.exec("DELETE FROM "+dependent+" WHERE tracker_type = ? AND tracker_id IN ("+trashed+")", trackerArgs...); err != nil {
+				return 0, err
+			}
+		}
+		if table.trackerType == models.TARGET {
+			if _, err := tx.exec("DELETE FROM target_segments WHERE tracker_id IN ("+trashed+")", args...); err != nil {
+				return 0, err
+			}
+		}
+		if table.trackerType == models.DURATION {
+			if _, err := tx.exec("DELETE FROM timers WHERE tracker_id IN ("+trashed+")", args...); err != nil {
+				return 0, err
+			}
+		}
+
+		result, err = tx.exec("DELETE FROM "+table.name+" WHERE deleted_at IS NOT NULL"+condition, args...)
+		if err != nil {
+			return 0, err
+		}
+		deleted, _ := result.RowsAffected()
+		purged += int(deleted)
+	}
+
+	result, err := tx.exec("DELETE FROM entries WHERE deleted_at IS NOT NULL"+condition, args...)
+	if err != nil {
+		return 0, err
+	}
+	entries, _ := result.RowsAffected()
+	purged += int(entries)
+
+	return purged, tx.Commit()
+}
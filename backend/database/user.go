@@ -0,0 +1,94 @@
+package database
+
+import (
+	"database/sql"
+	"routine-tracker/models"
+	"time"
+)
+
+// CreateUser creates a new user. The very first user also takes over the
+// trackers and entries that were created before multi-user support existed.
+func (s *sqlStore) CreateUser(username, passwordHash string) (*models.User, error) {
+	tx, err := s.begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var existingUsers int
+	if err := tx.queryRow("SELECT COUNT(*) FROM users").Scan(&existingUsers); err != nil {
+		return nil, err
+	}
+
+	id, err := tx.insert("INSERT INTO users (username, password_hash) VALUES (?, ?)", username, passwordHash)
+	if err != nil {
+		return nil, err
+	}
+
+	if existingUsers == 0 {
+		for _, table := range []string{"habit_trackers", "target_trackers", "entries"} {
+			if _, err := tx.exec("UPDATE "+table+" SET user_id = ? WHERE user_id IS NULL", id); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return s.GetUserByID(id)
+}
+
+func (s *sqlStore) GetUserByID(id int) (*models.User, error) {
+	var u models.User
+	err := s.queryRow("SELECT id, username, password_hash, time_zone, created_at FROM users WHERE id = ?", id).
+		Scan(&u.ID, &u.Username, &u.PasswordHash, &u.TimeZone, &u.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+func (s *sqlStore) GetUserByUsername(username string) (*models.User, error) {
+	var u models.User
+	err := s.queryRow("SELECT id, username, password_hash, time_zone, created_at FROM users WHERE username = ?", username).
+		Scan(&u.ID, &u.Username, &u.PasswordHash, &u.TimeZone, &u.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// UpdateUserTimeZone sets the IANA time zone the user's days are counted in
+func (s *sqlStore) UpdateUserTimeZone(id int, timeZone string) error {
+	_, err := s.exec("UPDATE users SET time_zone = ? WHERE id = ?", timeZone, id)
+	return err
+}
+
+func (s *sqlStore) CreateSession(userID int, tokenHash string, expiresAt time.Time) error {
+	_, err := s.exec("INSERT INTO sessions (user_id, token_hash, expires_at) VALUES (?, ?, ?)", userID, tokenHash, expiresAt)
+	return err
+}
+
+// GetSessionUserID returns the owner of a session token that has not expired yet
+func (s *sqlStore) GetSessionUserID(tokenHash string, now time.Time) (int, error) {
+	var userID int
+	var expiresAt time.Time
+	err := s.queryRow("SELECT user_id, expires_at FROM sessions WHERE token_hash = ?", tokenHash).Scan(&userID, &expiresAt)
+	if err != nil {
+		return 0, err
+	}
+
+	if now.After(expiresAt) {
+		s.exec("DELETE FROM sessions WHERE token_hash = ?", tokenHash)
+		return 0, sql.ErrNoRows
+	}
+
+	return userID, nil
+}
+
+func (s *sqlStore) DeleteSession(tokenHash string) error {
+	_, err := s.exec("DELETE FROM sessions WHERE token_hash = ?", tokenHash)
+	return err
+}
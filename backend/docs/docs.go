@@ -15,30 +15,49 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/dashboard": {
+        "/agenda": {
             "get": {
-                "description": "Get trackers that are due for a specific date (defaults to today)",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the habit, target and duration trackers due on each day from \"from\" to \"to\", both inclusive.\n\"from\" defaults to today and \"to\" to six days later, a range can be at most 366 days long.\nPaused and skipped days of a tracker are left out.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "General"
                 ],
-                "summary": "Get dashboard",
+                "summary": "Get agenda",
                 "parameters": [
                     {
                         "type": "string",
-                        "example": "2024-01-15",
-                        "description": "Date in YYYY-MM-DD format (defaults to today)",
-                        "name": "date",
+                        "example": "2024-01-01",
+                        "description": "First day in YYYY-MM-DD format (defaults to today)",
+                        "name": "from",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2024-01-07",
+                        "description": "Last day in YYYY-MM-DD format (defaults to six days after from)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "Europe/Istanbul",
+                        "description": "IANA time zone overriding the user's",
+                        "name": "X-Time-Zone",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/trackers.DashboardResponse"
+                            "$ref": "#/definitions/trackers.AgendaResponse"
                         }
                     },
                     "400": {
@@ -56,53 +75,76 @@ const docTemplate = `{
                 }
             }
         },
-        "/entries": {
+        "/audit": {
             "get": {
-                "description": "Retrieve all tracking entries",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the changes made to the user's data, the newest first. Every event records how the user was authenticated, what was done to which entity and the changed fields with their values before and after.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "General"
+                    "Audit"
                 ],
-                "summary": "Get all entries",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Entry"
-                            }
-                        }
+                "summary": "Get audit log",
+                "parameters": [
+                    {
+                        "enum": [
+                            "habit_tracker",
+                            "target_tracker",
+                            "duration_tracker",
+                            "entry",
+                            "timer",
+                            "pause",
+                            "notification_channel",
+                            "api_token",
+                            "tag",
+                            "category",
+                            "user"
+                        ],
+                        "type": "string",
+                        "description": "Only events of this kind of entity",
+                        "name": "entity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only events of the entity with this ID, needs entity",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of events, defaults to 100, at most 1000",
+                        "name": "limit",
+                        "in": "query"
                     }
-                }
-            }
-        },
-        "/habit-trackers": {
-            "get": {
-                "description": "Retrieve all created habit trackers",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Habit Trackers"
                 ],
-                "summary": "Get all habit trackers",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/habit.HabitTracker"
+                                "$ref": "#/definitions/models.AuditEvent"
                             }
                         }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
-            },
+            }
+        },
+        "/auth/login": {
             "post": {
-                "description": "Create a new habit tracker with specified configuration",
+                "description": "Exchange a username and password for a bearer token",
                 "consumes": [
                     "application/json"
                 ],
@@ -110,25 +152,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Habit Trackers"
+                    "Auth"
                 ],
-                "summary": "Create habit tracker",
+                "summary": "Login",
                 "parameters": [
                     {
-                        "description": "Habit tracker configuration",
-                        "name": "habit",
+                        "description": "Account credentials",
+                        "name": "credentials",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/habit.CreateHabitRequest"
+                            "$ref": "#/definitions/models.LoginRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/habit.HabitTracker"
+                            "$ref": "#/definitions/models.LoginResponse"
                         }
                     },
                     "400": {
@@ -136,44 +178,65 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "401": {
+                        "description": "Invalid username or password",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
         },
-        "/habit-trackers/{id}": {
-            "get": {
-                "description": "Retrieve a specific habit tracker with all its details",
-                "produces": [
-                    "application/json"
+        "/auth/logout": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Revoke the bearer token used for this request",
                 "tags": [
-                    "Habit Trackers"
+                    "Auth"
                 ],
-                "summary": "Get habit tracker by ID",
-                "parameters": [
+                "summary": "Logout",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/me": {
+            "get": {
+                "security": [
                     {
-                        "type": "integer",
-                        "description": "Habit Tracker ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "BearerAuth": []
                     }
                 ],
+                "description": "Get the user the bearer token belongs to",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Get current user",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/habit.HabitTracker"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.User"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "string"
                         }
@@ -181,7 +244,12 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Update a specific habit tracker (partial updates supported)",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update the settings of the user the bearer token belongs to, e.g. the IANA time zone in which days start",
                 "consumes": [
                     "application/json"
                 ],
@@ -189,24 +257,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Habit Trackers"
+                    "Auth"
                 ],
-                "summary": "Update habit tracker",
+                "summary": "Update current user",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Habit Tracker ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Updated habit tracker data",
-                        "name": "habit",
+                        "description": "Settings to change",
+                        "name": "user",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/habit.UpdateHabitRequest"
+                            "$ref": "#/definitions/models.UpdateUserRequest"
                         }
                     }
                 ],
@@ -214,7 +275,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/habit.HabitTracker"
+                            "$ref": "#/definitions/models.User"
                         }
                     },
                     "400": {
@@ -223,32 +284,45 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete a specific habit tracker and all its associated entries",
+            }
+        },
+        "/auth/register": {
+            "post": {
+                "description": "Create a new user account. The first registered user takes over trackers created before accounts existed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Habit Trackers"
+                    "Auth"
                 ],
-                "summary": "Delete habit tracker",
+                "summary": "Register",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Habit Tracker ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Account credentials",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RegisterRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -256,8 +330,8 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "409": {
+                        "description": "Username already taken",
                         "schema": {
                             "type": "string"
                         }
@@ -265,52 +339,44 @@ const docTemplate = `{
                 }
             }
         },
-        "/habit-trackers/{id}/entries": {
-            "post": {
-                "description": "Add a new entry to a specific habit tracker",
-                "consumes": [
-                    "application/json"
+        "/calendar.ics": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Get an iCalendar feed with a repeating all-day event for the due schedule of each tracker, its reminder times as alarms and an event on the goal date of each target tracker.\nCalendar apps cannot send headers, so a personal API token with the trackers:read scope can be passed in the \"token\" query parameter.",
                 "produces": [
-                    "application/json"
+                    "text/calendar"
                 ],
                 "tags": [
-                    "Habit Trackers"
+                    "General"
                 ],
-                "summary": "Add habit entry",
+                "summary": "Get calendar feed",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Habit Tracker ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Entry data",
-                        "name": "entry",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.AddEntryRequest"
-                        }
+                        "type": "string",
+                        "description": "Personal API token, instead of the Authorization header",
+                        "name": "token",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "iCalendar feed",
                         "schema": {
-                            "$ref": "#/definitions/models.Entry"
+                            "type": "string"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "string"
                         }
@@ -318,30 +384,40 @@ const docTemplate = `{
                 }
             }
         },
-        "/target-trackers": {
+        "/categories": {
             "get": {
-                "description": "Retrieve all created target trackers",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List every category ordered by name, the order of the groups in tracker listings",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Target Trackers"
+                    "Tags \u0026 Categories"
                 ],
-                "summary": "Get all target trackers",
+                "summary": "Get categories",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/target.TargetTracker"
+                                "$ref": "#/definitions/models.Category"
                             }
                         }
                     }
                 }
             },
             "post": {
-                "description": "Create a new target tracker with specified configuration",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a category with an optional color and icon. Trackers are put in it with categoryId.",
                 "consumes": [
                     "application/json"
                 ],
@@ -349,17 +425,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Target Trackers"
+                    "Tags \u0026 Categories"
                 ],
-                "summary": "Create target tracker",
+                "summary": "Create category",
                 "parameters": [
                     {
-                        "description": "Target tracker configuration",
-                        "name": "target",
+                        "description": "Category",
+                        "name": "category",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/target.CreateTargetRequest"
+                            "$ref": "#/definitions/models.CreateCategoryRequest"
                         }
                     }
                 ],
@@ -367,7 +443,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/target.TargetTracker"
+                            "$ref": "#/definitions/models.Category"
                         }
                     },
                     "400": {
@@ -375,34 +451,57 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
         },
-        "/target-trackers/{id}": {
-            "get": {
-                "description": "Retrieve a specific target tracker with all its details",
+        "/categories/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update the name, color or icon of a category",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Target Trackers"
+                    "Tags \u0026 Categories"
                 ],
-                "summary": "Get target tracker by ID",
+                "summary": "Update category",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Target Tracker ID",
+                        "description": "Category ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Fields to update",
+                        "name": "category",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateCategoryRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/target.TargetTracker"
+                            "$ref": "#/definitions/models.Category"
                         }
                     },
                     "400": {
@@ -416,44 +515,104 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
-                    }
-                }
-            },
-            "put": {
-                "description": "Update a specific target tracker (partial updates supported)",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Delete a category, its trackers become uncategorized",
                 "tags": [
-                    "Target Trackers"
+                    "Tags \u0026 Categories"
                 ],
-                "summary": "Update target tracker",
+                "summary": "Delete category",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Target Tracker ID",
+                        "description": "Category ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
                     },
-                    {
-                        "description": "Updated target tracker data",
-                        "name": "target",
-                        "in": "body",
-                        "required": true,
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/target.UpdateTargetRequest"
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
                         }
                     }
+                }
+            }
+        },
+        "/dashboard": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get trackers that are due for a specific date (defaults to today) in the user-defined order, also grouped by category. Archived trackers, paused and skipped days are left out.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "General"
+                ],
+                "summary": "Get dashboard",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "2024-01-15",
+                        "description": "Date in YYYY-MM-DD format (defaults to today)",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "Europe/Istanbul",
+                        "description": "IANA time zone overriding the user's",
+                        "name": "X-Time-Zone",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "example": "morning",
+                        "description": "Only trackers with this tag",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "Health",
+                        "description": "Only trackers in this category",
+                        "name": "category",
+                        "in": "query"
+                    }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/target.TargetTracker"
+                            "$ref": "#/definitions/trackers.DashboardResponse"
                         }
                     },
                     "400": {
@@ -462,32 +621,163 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/duration-trackers": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve all created duration trackers in the user-defined order with the time spent in the current period, archived ones are left out by default",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Duration Trackers"
+                ],
+                "summary": "Get all duration trackers",
+                "parameters": [
+                    {
+                        "enum": [
+                            "false",
+                            "true",
+                            "all"
+                        ],
+                        "type": "string",
+                        "description": "false for active trackers (default), true for archived ones or all",
+                        "name": "archived",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "morning",
+                        "description": "Only trackers with this tag",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "Health",
+                        "description": "Only trackers in this category",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "Europe/Istanbul",
+                        "description": "IANA time zone overriding the user's",
+                        "name": "X-Time-Zone",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/duration.DurationTracker"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
             },
-            "delete": {
-                "description": "Delete a specific target tracker and all its associated entries",
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new duration tracker with a goal in seconds per time period",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Target Trackers"
+                    "Duration Trackers"
                 ],
-                "summary": "Delete target tracker",
+                "summary": "Create duration tracker",
+                "parameters": [
+                    {
+                        "description": "Duration tracker configuration",
+                        "name": "tracker",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/duration.CreateDurationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/duration.DurationTracker"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/duration-trackers/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a specific duration tracker with its running timer and the time spent in the current period",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Duration Trackers"
+                ],
+                "summary": "Get duration tracker by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Target Tracker ID",
+                        "description": "Duration Tracker ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "Europe/Istanbul",
+                        "description": "IANA time zone overriding the user's",
+                        "name": "X-Time-Zone",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/duration.DurationTracker"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -502,11 +792,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/target-trackers/{id}/entries": {
-            "post": {
-                "description": "Add a new entry to a specific target tracker",
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update a specific duration tracker (partial updates supported)",
                 "consumes": [
                     "application/json"
                 ],
@@ -514,32 +807,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Target Trackers"
+                    "Duration Trackers"
                 ],
-                "summary": "Add target entry",
+                "summary": "Update duration tracker",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Target Tracker ID",
+                        "description": "Duration Tracker ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Entry data",
-                        "name": "entry",
+                        "description": "Updated duration tracker data",
+                        "name": "tracker",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.AddEntryRequest"
+                            "$ref": "#/definitions/duration.UpdateDurationRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Entry"
+                            "$ref": "#/definitions/duration.DurationTracker"
                         }
                     },
                     "400": {
@@ -555,53 +848,262 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/trackers": {
-            "get": {
-                "description": "Retrieve all habit and target trackers",
-                "produces": [
-                    "application/json"
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Move a specific duration tracker and all its associated entries to the trash, they can be restored until the trash is purged. A running timer of the tracker is discarded.",
                 "tags": [
-                    "General"
+                    "Duration Trackers"
+                ],
+                "summary": "Delete duration tracker",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Duration Tracker ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get all trackers (combined)",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/trackers.TrackersResponse"
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
                         }
                     }
                 }
             }
         },
-        "/{type}-trackers/{id}/entries": {
-            "get": {
-                "description": "Get all entries for a specific tracker",
+        "/duration-trackers/{id}/archive": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Take a duration tracker off the dashboard, agenda, calendar feed and reminders while keeping its entries and stats readable",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "General"
+                    "Duration Trackers"
                 ],
-                "summary": "Get tracker entries",
+                "summary": "Archive duration tracker",
                 "parameters": [
-                    {
-                        "enum": [
-                            "habit",
-                            "target"
-                        ],
-                        "type": "string",
-                        "description": "Tracker type",
-                        "name": "type",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "type": "integer",
-                        "description": "Tracker ID",
+                        "description": "Duration Tracker ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/duration.DurationTracker"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/duration-trackers/{id}/entries": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Log time spent without a timer, e.g. afterwards. The duration in seconds is required, the entry counts on the day of its date. Entries overlapping the running timer of the tracker are refused. Other entries are not checked for overlaps, overlapping ones both count toward the totals.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Duration Trackers"
+                ],
+                "summary": "Add duration entry",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Duration Tracker ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Entry data",
+                        "name": "entry",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AddEntryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Entry"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/duration-trackers/{id}/entries.csv": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Download all entries of a duration tracker with the columns date, value, done, note, skipped and duration",
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "Duration Trackers"
+                ],
+                "summary": "Export duration entries as CSV",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Duration Tracker ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "CSV file",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Upload a CSV file (raw body or multipart field \"file\") with the columns date, value, done, note, skipped and duration.\nDates are RFC3339 or YYYY-MM-DD, duration is required in seconds and duration entries cannot be skipped. Rows matching an existing entry are skipped, so uploading the same file twice is safe.",
+                "consumes": [
+                    "text/csv"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Duration Trackers"
+                ],
+                "summary": "Import duration entries from CSV",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Duration Tracker ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CSVImportResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/duration-trackers/{id}/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the changes of a duration tracker's settings, the newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Duration Trackers"
+                ],
+                "summary": "Get duration tracker history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Duration Tracker ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -613,7 +1115,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Entry"
+                                "$ref": "#/definitions/models.AuditEvent"
                             }
                         }
                     },
@@ -622,29 +1124,4745 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "habit.CreateHabitRequest": {
+        },
+        "/duration-trackers/{id}/next-due": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the next days, starting today, on which a duration tracker is due. Paused days are left out.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Duration Trackers"
+                ],
+                "summary": "Get next due dates of a duration tracker",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Duration Tracker ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "example": 5,
+                        "description": "Number of dates, at most 100 (defaults to 5)",
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2024-01-15",
+                        "description": "Start from this date in YYYY-MM-DD format (defaults to today)",
+                        "name": "date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/trackers.NextDueResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/duration-trackers/{id}/stats": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the time spent in each period towards the goal, the total time and the completion rate",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Duration Trackers"
+                ],
+                "summary": "Get duration tracker stats",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Duration Tracker ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "2024-01-15",
+                        "description": "Calculate stats as of this date in YYYY-MM-DD format (defaults to today)",
+                        "name": "date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/duration.Stats"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/duration-trackers/{id}/timer/start": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Start timing the time spent on a duration tracker. Only one timer can run at a time, starting a second one fails until the running one is stopped. Archived trackers cannot be timed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Duration Trackers"
+                ],
+                "summary": "Start timer",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Duration Tracker ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Note of the entry the timer becomes",
+                        "name": "timer",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/duration.StartTimerRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/duration.Timer"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/duration-trackers/{id}/timer/stop": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stop the running timer of a duration tracker. It becomes an entry with the time since the start in seconds, dated at the start of the timer.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Duration Trackers"
+                ],
+                "summary": "Stop timer",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Duration Tracker ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Entry"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/duration-trackers/{id}/unarchive": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Restore an archived duration tracker, it is due again on its schedule",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Duration Trackers"
+                ],
+                "summary": "Unarchive duration tracker",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Duration Tracker ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/duration.DurationTracker"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/entries": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve all tracking entries",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "General"
+                ],
+                "summary": "Get all entries",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Entry"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Move multiple entries to the trash by their IDs, they can be restored until the trash is purged",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "General"
+                ],
+                "summary": "Bulk delete entries",
+                "parameters": [
+                    {
+                        "description": "Array of entry IDs to delete",
+                        "name": "ids",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "integer"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/entries/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update a specific entry by ID. Skipping a habit entry marks it as not done, marking it as done un-skips it.\nA date without a time is midnight in the user's time zone.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "General"
+                ],
+                "summary": "Update entry",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Entry ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update entry request",
+                        "name": "entry",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateEntryRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "example": "Europe/Istanbul",
+                        "description": "IANA time zone overriding the user's",
+                        "name": "X-Time-Zone",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Entry"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Entry not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Move a specific entry to the trash, it can be restored until the trash is purged",
+                "tags": [
+                    "General"
+                ],
+                "summary": "Delete entry",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Entry ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Entry not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Download every habit tracker, target tracker and entry as a versioned JSON archive that can be imported again",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Import \u0026 Export"
+                ],
+                "summary": "Export all data",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/trackers.Archive"
+                        }
+                    }
+                }
+            }
+        },
+        "/habit-trackers": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve all created habit trackers in the user-defined order, archived ones are left out by default",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Habit Trackers"
+                ],
+                "summary": "Get all habit trackers",
+                "parameters": [
+                    {
+                        "enum": [
+                            "false",
+                            "true",
+                            "all"
+                        ],
+                        "type": "string",
+                        "description": "false for active trackers (default), true for archived ones or all",
+                        "name": "archived",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "morning",
+                        "description": "Only trackers with this tag",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "Health",
+                        "description": "Only trackers in this category",
+                        "name": "category",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/habit.HabitTracker"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new habit tracker with specified configuration",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Habit Trackers"
+                ],
+                "summary": "Create habit tracker",
+                "parameters": [
+                    {
+                        "description": "Habit tracker configuration",
+                        "name": "habit",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/habit.CreateHabitRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/habit.HabitTracker"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/habit-trackers/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a specific habit tracker with all its details",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Habit Trackers"
+                ],
+                "summary": "Get habit tracker by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Habit Tracker ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/habit.HabitTracker"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update a specific habit tracker (partial updates supported)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Habit Trackers"
+                ],
+                "summary": "Update habit tracker",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Habit Tracker ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated habit tracker data",
+                        "name": "habit",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/habit.UpdateHabitRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/habit.HabitTracker"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Move a specific habit tracker and all its associated entries to the trash, they can be restored until the trash is purged",
+                "tags": [
+                    "Habit Trackers"
+                ],
+                "summary": "Delete habit tracker",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Habit Tracker ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/habit-trackers/{id}/archive": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Take a habit tracker off the dashboard, agenda, calendar feed and reminders while keeping its entries and stats readable",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Habit Trackers"
+                ],
+                "summary": "Archive habit tracker",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Habit Tracker ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/habit.HabitTracker"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/habit-trackers/{id}/entries": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add a new entry to a specific habit tracker. With skipped the day is left out of the dashboard and streaks instead of being done.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Habit Trackers"
+                ],
+                "summary": "Add habit entry",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Habit Tracker ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Entry data",
+                        "name": "entry",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AddEntryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Entry"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/habit-trackers/{id}/entries.csv": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Download all entries of a habit tracker with the columns date, value, done, note, skipped and duration",
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "Habit Trackers"
+                ],
+                "summary": "Export habit entries as CSV",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Habit Tracker ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "CSV file",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Upload a CSV file (raw body or multipart field \"file\") with the columns date, value, done, note, skipped and duration.\nDates are RFC3339 or YYYY-MM-DD, done defaults to true unless the day is skipped. Rows matching an existing entry are skipped, so uploading the same file twice is safe.",
+                "consumes": [
+                    "text/csv"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Habit Trackers"
+                ],
+                "summary": "Import habit entries from CSV",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Habit Tracker ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CSVImportResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/habit-trackers/{id}/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the changes of a habit tracker's settings, the newest first, from its creation to archiving, deleting and restoring it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Habit Trackers"
+                ],
+                "summary": "Get habit tracker history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Habit Tracker ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AuditEvent"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/habit-trackers/{id}/next-due": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the next days, starting today, on which a habit tracker is due. Paused and skipped days are left out.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Habit Trackers"
+                ],
+                "summary": "Get next due dates of a habit tracker",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Habit Tracker ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "example": 5,
+                        "description": "Number of dates, at most 100 (defaults to 5)",
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2024-01-15",
+                        "description": "Start from this date in YYYY-MM-DD format (defaults to today)",
+                        "name": "date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/trackers.NextDueResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/habit-trackers/{id}/stats": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get per-period buckets, current and best streak, completion rate and goal streak progress",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Habit Trackers"
+                ],
+                "summary": "Get habit tracker stats",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Habit Tracker ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "2024-01-15",
+                        "description": "Calculate stats as of this date in YYYY-MM-DD format (defaults to today)",
+                        "name": "date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/habit.Stats"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/habit-trackers/{id}/unarchive": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Restore an archived habit tracker, it is due again on its schedule",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Habit Trackers"
+                ],
+                "summary": "Unarchive habit tracker",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Habit Tracker ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/habit.HabitTracker"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/import": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Import an archive created by the export endpoint in a single transaction. IDs are reassigned.\nIn merge mode existing data is kept and trackers with the same type and name are combined, identical entries are skipped.\nIn replace mode all existing trackers and entries are deleted first.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Import \u0026 Export"
+                ],
+                "summary": "Import data",
+                "parameters": [
+                    {
+                        "enum": [
+                            "merge",
+                            "replace"
+                        ],
+                        "type": "string",
+                        "default": "merge",
+                        "description": "Conflict handling: merge or replace",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Report what would change without saving anything",
+                        "name": "dryRun",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Exported archive",
+                        "name": "archive",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/trackers.Archive"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/trackers.ImportResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/import/loop": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Import the SQLite backup (.db) or the zipped CSV export of the Loop Habit Tracker Android app, as raw body or multipart field \"file\".\nYes/no habits become habit trackers, numerical habits become target trackers and checkmarks become entries.\nSettings and data without an equivalent are listed in unmapped.",
+                "consumes": [
+                    "application/octet-stream"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Import \u0026 Export"
+                ],
+                "summary": "Import Loop Habit Tracker backup",
+                "parameters": [
+                    {
+                        "enum": [
+                            "merge",
+                            "replace"
+                        ],
+                        "type": "string",
+                        "default": "merge",
+                        "description": "Conflict handling: merge or replace",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Report what would change without saving anything",
+                        "name": "dryRun",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/importer.LoopImportResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/channels": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List every notification channel, both the ones for all trackers and the tracker specific ones",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Get notification channels",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.NotificationChannel"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add an email, webhook, ntfy or Gotify channel. Without trackerType and trackerId the channel receives the notifications of all trackers, otherwise it replaces them for that tracker.\nWebhook, ntfy and Gotify targets must be http(s) URLs of public hosts, unless the server allows internal hosts.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Create notification channel",
+                "parameters": [
+                    {
+                        "description": "Notification channel configuration",
+                        "name": "channel",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateNotificationChannelRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.NotificationChannel"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/channels/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update the name, target, token or enabled state of a notification channel",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Update notification channel",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Notification Channel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to update",
+                        "name": "channel",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateNotificationChannelRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.NotificationChannel"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a notification channel",
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Delete notification channel",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Notification Channel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/test": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Send a test message to one channel, or to every channel of the user when no channelId is given, and report the result of each",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Send test notification",
+                "parameters": [
+                    {
+                        "description": "Channel to test",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.TestNotificationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TestNotificationResult"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/pauses": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List every pause, both the ones for all trackers and the tracker specific ones, ordered by start date",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pauses"
+                ],
+                "summary": "Get pauses",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Pause"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Pause trackers from startDate to endDate, both inclusive. Paused days are not due and do not break streaks.\nWithout trackerType and trackerId the pause applies to all trackers, e.g. for a vacation.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pauses"
+                ],
+                "summary": "Create pause",
+                "parameters": [
+                    {
+                        "description": "Pause configuration",
+                        "name": "pause",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreatePauseRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Pause"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/pauses/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update the dates or reason of a pause, e.g. to end it early",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pauses"
+                ],
+                "summary": "Update pause",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pause ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to update",
+                        "name": "pause",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdatePauseRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Pause"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a pause, its days count again",
+                "tags": [
+                    "Pauses"
+                ],
+                "summary": "Delete pause",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pause ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List every tag ordered by name",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags \u0026 Categories"
+                ],
+                "summary": "Get tags",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Tag"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a tag that can be given to any number of trackers with tagIds",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags \u0026 Categories"
+                ],
+                "summary": "Create tag",
+                "parameters": [
+                    {
+                        "description": "Tag",
+                        "name": "tag",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Tag"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Rename a tag, the trackers keep it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags \u0026 Categories"
+                ],
+                "summary": "Update tag",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tag",
+                        "name": "tag",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Tag"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a tag and remove it from all trackers",
+                "tags": [
+                    "Tags \u0026 Categories"
+                ],
+                "summary": "Delete tag",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/target-trackers": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve all created target trackers in the user-defined order, archived ones are left out by default",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Target Trackers"
+                ],
+                "summary": "Get all target trackers",
+                "parameters": [
+                    {
+                        "enum": [
+                            "false",
+                            "true",
+                            "all"
+                        ],
+                        "type": "string",
+                        "description": "false for active trackers (default), true for archived ones or all",
+                        "name": "archived",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "morning",
+                        "description": "Only trackers with this tag",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "Health",
+                        "description": "Only trackers in this category",
+                        "name": "category",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/target.TargetTracker"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new target tracker with specified configuration",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Target Trackers"
+                ],
+                "summary": "Create target tracker",
+                "parameters": [
+                    {
+                        "description": "Target tracker configuration",
+                        "name": "target",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/target.CreateTargetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/target.TargetTracker"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/target-trackers/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a specific target tracker with all its details",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Target Trackers"
+                ],
+                "summary": "Get target tracker by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Target Tracker ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/target.TargetTracker"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update a specific target tracker (partial updates supported). A new goalValue, goalDate or addToTotal applies to the entries from effectiveFrom on, earlier entries keep their configuration. A future effectiveFrom plans the change.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Target Trackers"
+                ],
+                "summary": "Update target tracker",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Target Tracker ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated target tracker data",
+                        "name": "target",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/target.UpdateTargetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/target.TargetTracker"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Move a specific target tracker and all its associated entries to the trash, they can be restored until the trash is purged",
+                "tags": [
+                    "Target Trackers"
+                ],
+                "summary": "Delete target tracker",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Target Tracker ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/target-trackers/{id}/archive": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Take a target tracker off the dashboard, agenda, calendar feed and reminders while keeping its entries and projection readable",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Target Trackers"
+                ],
+                "summary": "Archive target tracker",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Target Tracker ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/target.TargetTracker"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/target-trackers/{id}/entries": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add a new entry to a specific target tracker",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Target Trackers"
+                ],
+                "summary": "Add target entry",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Target Tracker ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Entry data",
+                        "name": "entry",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AddEntryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Entry"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/target-trackers/{id}/entries.csv": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Download all entries of a target tracker with the columns date, value, done, note, skipped and duration",
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "Target Trackers"
+                ],
+                "summary": "Export target entries as CSV",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Target Tracker ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "CSV file",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Upload a CSV file (raw body or multipart field \"file\") with the columns date, value, done, note, skipped and duration.\nDates are RFC3339 or YYYY-MM-DD, value is required and target entries cannot be skipped. Rows matching an existing entry are skipped, so uploading the same file twice is safe.",
+                "consumes": [
+                    "text/csv"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Target Trackers"
+                ],
+                "summary": "Import target entries from CSV",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Target Tracker ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CSVImportResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/target-trackers/{id}/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the changes of a target tracker's settings, the newest first, e.g. to explain a changed goal value or start date in the charts",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Target Trackers"
+                ],
+                "summary": "Get target tracker history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Target Tracker ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AuditEvent"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/target-trackers/{id}/next-due": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the next days, starting today, on which a target tracker is due. Paused days are left out.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Target Trackers"
+                ],
+                "summary": "Get next due dates of a target tracker",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Target Tracker ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "example": 5,
+                        "description": "Number of dates, at most 100 (defaults to 5)",
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2024-01-15",
+                        "description": "Start from this date in YYYY-MM-DD format (defaults to today)",
+                        "name": "date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/trackers.NextDueResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/target-trackers/{id}/projection": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the weighted trend line, projected completion date, projected value on the goal date and the daily rate needed to stay on pace",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Target Trackers"
+                ],
+                "summary": "Get target tracker projection",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Target Tracker ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "2024-03-01",
+                        "description": "Calculate the projection as of this date in YYYY-MM-DD format (defaults to now)",
+                        "name": "date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/analytics.Projection"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/target-trackers/{id}/segments": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the goal value, goal date and addToTotal configurations of a target tracker in the order they took effect. Each applies to the entries until the next one, the first also to the entries before it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Target Trackers"
+                ],
+                "summary": "Get target tracker segments",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Target Tracker ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/target.Segment"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/target-trackers/{id}/unarchive": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Restore an archived target tracker, it is due again on its schedule",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Target Trackers"
+                ],
+                "summary": "Unarchive target tracker",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Target Tracker ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/target.TargetTracker"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tokens": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List personal API tokens with their scopes and last use. Token values are never returned again.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API Tokens"
+                ],
+                "summary": "Get API tokens",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.APIToken"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a long-lived token restricted to the given scopes. The token value is only returned in this response.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API Tokens"
+                ],
+                "summary": "Create API token",
+                "parameters": [
+                    {
+                        "description": "Token name and scopes",
+                        "name": "token",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateAPITokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateAPITokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tokens/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revoke a personal API token, requests using it are rejected right away",
+                "tags": [
+                    "API Tokens"
+                ],
+                "summary": "Revoke API token",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "API Token ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/trackers": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve all habit, target and duration trackers in the user-defined order, also grouped by category. Archived ones are left out by default.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "General"
+                ],
+                "summary": "Get all trackers (combined)",
+                "parameters": [
+                    {
+                        "enum": [
+                            "false",
+                            "true",
+                            "all"
+                        ],
+                        "type": "string",
+                        "description": "false for active trackers (default), true for archived ones or all",
+                        "name": "archived",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "morning",
+                        "description": "Only trackers with this tag",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "Health",
+                        "description": "Only trackers in this category",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "Europe/Istanbul",
+                        "description": "IANA time zone overriding the user's",
+                        "name": "X-Time-Zone",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/trackers.TrackersResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/trackers/order": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Set the positions and pinned flags of a batch of habit, target and duration trackers at once. Lists and the dashboard show pinned trackers first, then the others by position, trackers of all types interleaved.\nEither every tracker of the batch is updated or, when one of them is not found, none.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "General"
+                ],
+                "summary": "Reorder trackers",
+                "parameters": [
+                    {
+                        "description": "New positions",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the deleted trackers and entries that can still be restored, the most recently deleted first.\nEntries of deleted trackers are not listed, they are restored with their tracker. Items are purged retentionDays after they were deleted.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Trash"
+                ],
+                "summary": "Get trash",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/trackers.TrashResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete all trackers and entries in the trash for good, without waiting for the retention period",
+                "tags": [
+                    "Trash"
+                ],
+                "summary": "Empty trash",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/trash/duration-trackers/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Restore a deleted duration tracker together with the entries deleted with it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Trash"
+                ],
+                "summary": "Restore duration tracker",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Duration Tracker ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/duration.DurationTracker"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash/entries/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Restore deleted entries by their IDs, e.g. to undo a delete. Entries of deleted trackers are restored with their tracker instead.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Trash"
+                ],
+                "summary": "Restore entries",
+                "parameters": [
+                    {
+                        "description": "Array of entry IDs to restore",
+                        "name": "ids",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "integer"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash/habit-trackers/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Restore a deleted habit tracker together with the entries deleted with it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Trash"
+                ],
+                "summary": "Restore habit tracker",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Habit Tracker ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/habit.HabitTracker"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash/target-trackers/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Restore a deleted target tracker together with the entries deleted with it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Trash"
+                ],
+                "summary": "Restore target tracker",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Target Tracker ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/target.TargetTracker"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/{type}-trackers/{id}/entries": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all entries for a specific tracker",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "General"
+                ],
+                "summary": "Get tracker entries",
+                "parameters": [
+                    {
+                        "enum": [
+                            "habit",
+                            "target",
+                            "duration"
+                        ],
+                        "type": "string",
+                        "description": "Tracker type",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Tracker ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Entry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "analytics.Projection": {
+            "type": "object",
+            "properties": {
+                "addToTotal": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "currentValue": {
+                    "type": "number",
+                    "example": 1234.56
+                },
+                "dataPoints": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2024-03-01"
+                },
+                "daysUntilGoal": {
+                    "type": "integer",
+                    "example": 305
+                },
+                "expectedValue": {
+                    "description": "value on a straight line from start to goal",
+                    "type": "number",
+                    "example": 830
+                },
+                "goalDate": {
+                    "type": "string",
+                    "example": "2024-12-31T00:00:00Z"
+                },
+                "goalValue": {
+                    "type": "number",
+                    "example": 5000
+                },
+                "intercept": {
+                    "description": "trend value on the start date",
+                    "type": "number",
+                    "example": 4.2
+                },
+                "onPace": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "projectedCompletionDate": {
+                    "description": "null when the trend never reaches the goal",
+                    "type": "string",
+                    "example": "2024-11-20T00:00:00Z"
+                },
+                "projectedValueOnGoalDate": {
+                    "type": "number",
+                    "example": 5521.3
+                },
+                "remainingValue": {
+                    "type": "number",
+                    "example": 3765.44
+                },
+                "requiredDailyRate": {
+                    "description": "null once the goal date has passed",
+                    "type": "number",
+                    "example": 12.35
+                },
+                "slope": {
+                    "description": "value change per day",
+                    "type": "number",
+                    "example": 13.7
+                },
+                "startValue": {
+                    "type": "number",
+                    "example": 0
+                },
+                "trackerId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "trendWeightType": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/analytics.WeightType"
+                        }
+                    ],
+                    "example": "none"
+                }
+            }
+        },
+        "analytics.WeightType": {
+            "type": "string",
+            "enum": [
+                "none",
+                "linear",
+                "sqrt",
+                "quadratic",
+                "exponential_low",
+                "exponential_high"
+            ],
+            "x-enum-comments": {
+                "WEIGHT_EXPONENTIAL_HIGH": "weight = exp(2i/n)",
+                "WEIGHT_EXPONENTIAL_LOW": "weight = exp(i/n)",
+                "WEIGHT_LINEAR": "weight = 1 + i",
+                "WEIGHT_NONE": "all points equal weight",
+                "WEIGHT_QUADRATIC": "weight = 1 + (i/n)²",
+                "WEIGHT_SQRT": "weight = 1 + sqrt(i)"
+            },
+            "x-enum-varnames": [
+                "WEIGHT_NONE",
+                "WEIGHT_LINEAR",
+                "WEIGHT_SQRT",
+                "WEIGHT_QUADRATIC",
+                "WEIGHT_EXPONENTIAL_LOW",
+                "WEIGHT_EXPONENTIAL_HIGH"
+            ]
+        },
+        "duration.CreateDurationRequest": {
+            "type": "object",
+            "properties": {
+                "categoryId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "due": {
+                    "$ref": "#/definitions/models.Due"
+                },
+                "goal": {
+                    "description": "seconds per time period",
+                    "type": "integer",
+                    "example": 1800
+                },
+                "pinned": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "reminders": {
+                    "$ref": "#/definitions/models.Reminder"
+                },
+                "startDate": {
+                    "description": "\"2024-01-01\" format",
+                    "type": "string",
+                    "example": "2024-01-01"
+                },
+                "tagIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "timePeriod": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.TimePeriod"
+                        }
+                    ],
+                    "example": "perDay"
+                },
+                "trackerName": {
+                    "type": "string",
+                    "example": "Guitar Practice"
+                }
+            }
+        },
+        "duration.DurationTracker": {
+            "type": "object",
+            "properties": {
+                "archivedAt": {
+                    "description": "set while the tracker is archived",
+                    "type": "string",
+                    "example": "2024-06-01T10:00:00Z"
+                },
+                "categoryId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2024-01-01T10:00:00Z"
+                },
+                "currentTotal": {
+                    "description": "Calculated field, seconds spent in the current period",
+                    "type": "integer",
+                    "example": 900
+                },
+                "deletedAt": {
+                    "description": "set while the tracker is in the trash",
+                    "type": "string",
+                    "example": "2024-06-01T10:00:00Z"
+                },
+                "due": {
+                    "$ref": "#/definitions/models.Due"
+                },
+                "goal": {
+                    "description": "seconds per time period",
+                    "type": "integer",
+                    "example": 1800
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "pinned": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "position": {
+                    "description": "place in the user-defined order, see PUT /trackers/order",
+                    "type": "integer",
+                    "example": 0
+                },
+                "reminders": {
+                    "$ref": "#/definitions/models.Reminder"
+                },
+                "startDate": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Tag"
+                    }
+                },
+                "timePeriod": {
+                    "description": "per day, week, month, year",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.TimePeriod"
+                        }
+                    ],
+                    "example": "perDay"
+                },
+                "timer": {
+                    "description": "the running timer, null when stopped",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/duration.Timer"
+                        }
+                    ]
+                },
+                "trackerName": {
+                    "type": "string",
+                    "example": "Guitar Practice"
+                }
+            }
+        },
+        "duration.Period": {
+            "type": "object",
+            "properties": {
+                "daysOff": {
+                    "description": "paused days in the period",
+                    "type": "integer",
+                    "example": 0
+                },
+                "end": {
+                    "type": "string",
+                    "example": "2024-01-07T23:59:59Z"
+                },
+                "goal": {
+                    "description": "seconds needed, less in periods with days off",
+                    "type": "integer",
+                    "example": 1800
+                },
+                "goalMet": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "isCurrent": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "skipped": {
+                    "description": "every day is off, the period does not count",
+                    "type": "boolean",
+                    "example": false
+                },
+                "start": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                },
+                "total": {
+                    "description": "seconds spent in the period",
+                    "type": "integer",
+                    "example": 2700
+                }
+            }
+        },
+        "duration.StartTimerRequest": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "description": "becomes the note of the entry",
+                    "type": "string",
+                    "example": "Scales"
+                }
+            }
+        },
+        "duration.Stats": {
+            "type": "object",
+            "properties": {
+                "completedPeriods": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "completionRate": {
+                    "description": "between 0 and 1",
+                    "type": "number",
+                    "example": 0.71
+                },
+                "currentProgress": {
+                    "description": "current total / goal of the current period, can exceed 1",
+                    "type": "number",
+                    "example": 0.5
+                },
+                "currentTotal": {
+                    "description": "seconds spent in the current period",
+                    "type": "integer",
+                    "example": 900
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2024-01-15"
+                },
+                "goal": {
+                    "type": "integer",
+                    "example": 1800
+                },
+                "periods": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/duration.Period"
+                    }
+                },
+                "skippedPeriods": {
+                    "description": "left out of the completion rate",
+                    "type": "integer",
+                    "example": 2
+                },
+                "timePeriod": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.TimePeriod"
+                        }
+                    ],
+                    "example": "perDay"
+                },
+                "totalDuration": {
+                    "description": "seconds spent since the start date",
+                    "type": "integer",
+                    "example": 36000
+                },
+                "totalPeriods": {
+                    "type": "integer",
+                    "example": 14
+                },
+                "trackerId": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "duration.Timer": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "note": {
+                    "type": "string",
+                    "example": "Scales"
+                },
+                "startedAt": {
+                    "type": "string",
+                    "example": "2024-01-01T10:00:00Z"
+                },
+                "trackerId": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "duration.UpdateDurationRequest": {
+            "type": "object",
+            "properties": {
+                "categoryId": {
+                    "description": "0 removes the tracker from its category",
+                    "type": "integer"
+                },
+                "due": {
+                    "$ref": "#/definitions/models.Due"
+                },
+                "goal": {
+                    "type": "integer"
+                },
+                "pinned": {
+                    "type": "boolean"
+                },
+                "reminders": {
+                    "$ref": "#/definitions/models.Reminder"
+                },
+                "startDate": {
+                    "type": "string"
+                },
+                "tagIds": {
+                    "description": "replaces the tags of the tracker",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "timePeriod": {
+                    "$ref": "#/definitions/models.TimePeriod"
+                },
+                "trackerName": {
+                    "type": "string"
+                }
+            }
+        },
+        "habit.CreateHabitRequest": {
+            "type": "object",
+            "properties": {
+                "badHabit": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "categoryId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "due": {
+                    "$ref": "#/definitions/models.Due"
+                },
+                "goal": {
+                    "type": "number",
+                    "example": 8
+                },
+                "goalStreak": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "pinned": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "reminders": {
+                    "$ref": "#/definitions/models.Reminder"
+                },
+                "startDate": {
+                    "description": "\"2024-01-01\" format",
+                    "type": "string",
+                    "example": "2024-01-01"
+                },
+                "tagIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "timePeriod": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.TimePeriod"
+                        }
+                    ],
+                    "example": "per_day"
+                },
+                "trackerName": {
+                    "type": "string",
+                    "example": "Drink Water"
+                }
+            }
+        },
+        "habit.HabitTracker": {
+            "type": "object",
+            "properties": {
+                "archivedAt": {
+                    "description": "set while the tracker is archived",
+                    "type": "string",
+                    "example": "2024-06-01T10:00:00Z"
+                },
+                "badHabit": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "categoryId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2024-01-01T10:00:00Z"
+                },
+                "deletedAt": {
+                    "description": "set while the tracker is in the trash",
+                    "type": "string",
+                    "example": "2024-06-01T10:00:00Z"
+                },
+                "due": {
+                    "$ref": "#/definitions/models.Due"
+                },
+                "goal": {
+                    "description": "how many times",
+                    "type": "number",
+                    "example": 8
+                },
+                "goalStreak": {
+                    "description": "null or int",
+                    "type": "integer",
+                    "example": 30
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "pinned": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "position": {
+                    "description": "place in the user-defined order, see PUT /trackers/order",
+                    "type": "integer",
+                    "example": 0
+                },
+                "reminders": {
+                    "$ref": "#/definitions/models.Reminder"
+                },
+                "startDate": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Tag"
+                    }
+                },
+                "timePeriod": {
+                    "description": "per day, week, month, year",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.TimePeriod"
+                        }
+                    ],
+                    "example": "per_day"
+                },
+                "trackerName": {
+                    "type": "string",
+                    "example": "Drink Water"
+                }
+            }
+        },
+        "habit.Period": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "description": "number of done entries in the period",
+                    "type": "integer",
+                    "example": 3
+                },
+                "daysOff": {
+                    "description": "paused or skipped days in the period",
+                    "type": "integer",
+                    "example": 0
+                },
+                "end": {
+                    "type": "string",
+                    "example": "2024-01-07T23:59:59Z"
+                },
+                "goalMet": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "isCurrent": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "skipped": {
+                    "description": "every day is off, the period does not count",
+                    "type": "boolean",
+                    "example": false
+                },
+                "start": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                }
+            }
+        },
+        "habit.Stats": {
+            "type": "object",
+            "properties": {
+                "badHabit": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "bestStreak": {
+                    "description": "negative when the goal was never met",
+                    "type": "integer",
+                    "example": 12
+                },
+                "completedPeriods": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "completionRate": {
+                    "description": "between 0 and 1",
+                    "type": "number",
+                    "example": 0.71
+                },
+                "currentStreak": {
+                    "description": "negative when the goal was missed in a row",
+                    "type": "integer",
+                    "example": 5
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2024-01-15"
+                },
+                "goal": {
+                    "type": "number",
+                    "example": 1
+                },
+                "goalStreak": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "goalStreakProgress": {
+                    "description": "current streak / goal streak, capped at 1",
+                    "type": "number",
+                    "example": 0.16
+                },
+                "periods": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/habit.Period"
+                    }
+                },
+                "skippedPeriods": {
+                    "description": "left out of streaks and the completion rate",
+                    "type": "integer",
+                    "example": 2
+                },
+                "timePeriod": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.TimePeriod"
+                        }
+                    ],
+                    "example": "perDay"
+                },
+                "totalPeriods": {
+                    "type": "integer",
+                    "example": 14
+                },
+                "trackerId": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "habit.UpdateHabitRequest": {
+            "type": "object",
+            "properties": {
+                "badHabit": {
+                    "type": "boolean"
+                },
+                "categoryId": {
+                    "description": "0 removes the tracker from its category",
+                    "type": "integer"
+                },
+                "due": {
+                    "$ref": "#/definitions/models.Due"
+                },
+                "goal": {
+                    "type": "number"
+                },
+                "goalStreak": {
+                    "type": "integer"
+                },
+                "pinned": {
+                    "type": "boolean"
+                },
+                "reminders": {
+                    "$ref": "#/definitions/models.Reminder"
+                },
+                "startDate": {
+                    "type": "string"
+                },
+                "tagIds": {
+                    "description": "replaces the tags of the tracker",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "timePeriod": {
+                    "$ref": "#/definitions/models.TimePeriod"
+                },
+                "trackerName": {
+                    "type": "string"
+                }
+            }
+        },
+        "importer.LoopImportResult": {
+            "type": "object",
+            "properties": {
+                "dryRun": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "durationTrackersCreated": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "entriesCreated": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "entriesDeleted": {
+                    "description": "replace mode only",
+                    "type": "integer",
+                    "example": 0
+                },
+                "entriesSkipped": {
+                    "description": "identical entries that already existed",
+                    "type": "integer",
+                    "example": 3
+                },
+                "habitTrackersCreated": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "mode": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/trackers.ImportMode"
+                        }
+                    ],
+                    "example": "merge"
+                },
+                "pausesCreated": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "targetTrackersCreated": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "trackersDeleted": {
+                    "description": "replace mode only",
+                    "type": "integer",
+                    "example": 0
+                },
+                "trackersMerged": {
+                    "description": "imported into an existing tracker with the same name",
+                    "type": "integer",
+                    "example": 1
+                },
+                "unmapped": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/importer.Unmapped"
+                    }
+                }
+            }
+        },
+        "importer.Unmapped": {
+            "type": "object",
+            "properties": {
+                "habit": {
+                    "type": "string",
+                    "example": "Meditate"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "'at most' targets are not supported, the target is treated as 'at least'"
+                }
+            }
+        },
+        "models.APIToken": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string",
+                    "example": "2024-01-01T10:00:00Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "lastUsedAt": {
+                    "description": "null until the token is used",
+                    "type": "string",
+                    "example": "2024-01-01T10:00:00Z"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Phone shortcut"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "entries:write"
+                    ]
+                }
+            }
+        },
+        "models.AddEntryRequest": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "description": "optional, defaults to now. Supports both date (YYYY-MM-DD) and datetime (RFC3339) formats",
+                    "type": "string",
+                    "example": "2024-01-01T15:30:00Z"
+                },
+                "done": {
+                    "description": "For habit trackers",
+                    "type": "boolean"
+                },
+                "duration": {
+                    "description": "For duration trackers, seconds spent",
+                    "type": "integer",
+                    "example": 1800
+                },
+                "note": {
+                    "type": "string",
+                    "example": "Felt great today"
+                },
+                "skipped": {
+                    "description": "For habit trackers, marks the day as skipped instead of done",
+                    "type": "boolean"
+                },
+                "value": {
+                    "description": "For target trackers",
+                    "type": "number"
+                }
+            }
+        },
+        "models.AuditAction": {
+            "type": "string",
+            "enum": [
+                "create",
+                "update",
+                "delete",
+                "restore",
+                "archive",
+                "unarchive",
+                "import",
+                "purge"
+            ],
+            "x-enum-comments": {
+                "AUDIT_DELETE": "moved to the trash, or deleted for good for entities without trash",
+                "AUDIT_PURGE": "the trash was emptied",
+                "AUDIT_RESTORE": "taken out of the trash"
+            },
+            "x-enum-varnames": [
+                "AUDIT_CREATE",
+                "AUDIT_UPDATE",
+                "AUDIT_DELETE",
+                "AUDIT_RESTORE",
+                "AUDIT_ARCHIVE",
+                "AUDIT_UNARCHIVE",
+                "AUDIT_IMPORT",
+                "AUDIT_PURGE"
+            ]
+        },
+        "models.AuditActor": {
+            "type": "string",
+            "enum": [
+                "session",
+                "api_token"
+            ],
+            "x-enum-comments": {
+                "ACTOR_API_TOKEN": "a personal API token, see APITokenID",
+                "ACTOR_SESSION": "a login session, e.g. the web app"
+            },
+            "x-enum-varnames": [
+                "ACTOR_SESSION",
+                "ACTOR_API_TOKEN"
+            ]
+        },
+        "models.AuditChange": {
+            "type": "object",
+            "properties": {
+                "after": {},
+                "before": {}
+            }
+        },
+        "models.AuditEntity": {
+            "type": "string",
+            "enum": [
+                "habit_tracker",
+                "target_tracker",
+                "duration_tracker",
+                "entry",
+                "timer",
+                "pause",
+                "notification_channel",
+                "api_token",
+                "tag",
+                "category",
+                "user"
+            ],
+            "x-enum-comments": {
+                "AUDIT_USER": "account settings, imports and the trash"
+            },
+            "x-enum-varnames": [
+                "AUDIT_HABIT_TRACKER",
+                "AUDIT_TARGET_TRACKER",
+                "AUDIT_DURATION_TRACKER",
+                "AUDIT_ENTRY",
+                "AUDIT_TIMER",
+                "AUDIT_PAUSE",
+                "AUDIT_NOTIFICATION_CHANNEL",
+                "AUDIT_API_TOKEN",
+                "AUDIT_TAG",
+                "AUDIT_CATEGORY",
+                "AUDIT_USER"
+            ]
+        },
+        "models.AuditEvent": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.AuditAction"
+                        }
+                    ],
+                    "example": "update"
+                },
+                "actor": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.AuditActor"
+                        }
+                    ],
+                    "example": "session"
+                },
+                "apiTokenId": {
+                    "description": "the token used when actor is api_token",
+                    "type": "integer",
+                    "example": 2
+                },
+                "changes": {
+                    "description": "changed fields by their JSON name",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.AuditChange"
+                    }
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2024-01-01T10:00:00Z"
+                },
+                "entity": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.AuditEntity"
+                        }
+                    ],
+                    "example": "target_tracker"
+                },
+                "entityId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "models.CSVImportResult": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "duplicates": {
+                    "description": "rows matching an existing entry, not imported again",
+                    "type": "integer",
+                    "example": 3
+                },
+                "errors": {
+                    "description": "rows that could not be imported",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CSVLineError"
+                    }
+                }
+            }
+        },
+        "models.CSVLineError": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "invalid value 'abc'"
+                },
+                "line": {
+                    "type": "integer",
+                    "example": 7
+                }
+            }
+        },
+        "models.Category": {
+            "type": "object",
+            "properties": {
+                "color": {
+                    "type": "string",
+                    "example": "#4caf50"
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2024-01-01T10:00:00Z"
+                },
+                "icon": {
+                    "type": "string",
+                    "example": "heart"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Health"
+                }
+            }
+        },
+        "models.ChannelType": {
+            "type": "string",
+            "enum": [
+                "email",
+                "webhook",
+                "ntfy",
+                "gotify"
+            ],
+            "x-enum-comments": {
+                "CHANNEL_EMAIL": "target is an email address, sent through the server's SMTP settings",
+                "CHANNEL_GOTIFY": "target is the Gotify server URL, token is the application token",
+                "CHANNEL_NTFY": "target is an ntfy topic URL, e.g. https://ntfy.sh/my-habits",
+                "CHANNEL_WEBHOOK": "target is a URL receiving a JSON POST"
+            },
+            "x-enum-varnames": [
+                "CHANNEL_EMAIL",
+                "CHANNEL_WEBHOOK",
+                "CHANNEL_NTFY",
+                "CHANNEL_GOTIFY"
+            ]
+        },
+        "models.CreateAPITokenRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "Phone shortcut"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "entries:write",
+                        "trackers:read"
+                    ]
+                }
+            }
+        },
+        "models.CreateAPITokenResponse": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string",
+                    "example": "2024-01-01T10:00:00Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "lastUsedAt": {
+                    "description": "null until the token is used",
+                    "type": "string",
+                    "example": "2024-01-01T10:00:00Z"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Phone shortcut"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "entries:write"
+                    ]
+                },
+                "token": {
+                    "description": "only returned once, send as \"Authorization: Bearer \u003ctoken\u003e\"",
+                    "type": "string",
+                    "example": "pat_4f9c2e..."
+                }
+            }
+        },
+        "models.CreateCategoryRequest": {
+            "type": "object",
+            "properties": {
+                "color": {
+                    "type": "string",
+                    "example": "#4caf50"
+                },
+                "icon": {
+                    "type": "string",
+                    "example": "heart"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Health"
+                }
+            }
+        },
+        "models.CreateNotificationChannelRequest": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "description": "defaults to true",
+                    "type": "boolean",
+                    "example": true
+                },
+                "name": {
+                    "type": "string",
+                    "example": "My phone"
+                },
+                "target": {
+                    "type": "string",
+                    "example": "https://ntfy.sh/my-habits"
+                },
+                "token": {
+                    "type": "string",
+                    "example": "tk_secret"
+                },
+                "trackerId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "trackerType": {
+                    "description": "optional, restricts the channel to one tracker",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.TrackerType"
+                        }
+                    ],
+                    "example": "habit"
+                },
+                "type": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ChannelType"
+                        }
+                    ],
+                    "example": "ntfy"
+                }
+            }
+        },
+        "models.CreatePauseRequest": {
+            "type": "object",
+            "properties": {
+                "endDate": {
+                    "description": "defaults to the start date",
+                    "type": "string",
+                    "example": "2024-07-14"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "Vacation"
+                },
+                "startDate": {
+                    "type": "string",
+                    "example": "2024-07-01"
+                },
+                "trackerId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "trackerType": {
+                    "description": "optional, restricts the pause to one tracker",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.TrackerType"
+                        }
+                    ],
+                    "example": "habit"
+                }
+            }
+        },
+        "models.Due": {
+            "type": "object",
+            "properties": {
+                "intervalType": {
+                    "description": "\"day\", \"week\", \"month\", \"year\", due on the start date's weekday, day of month or date of every Nth period, not on each of its days",
+                    "type": "string",
+                    "example": "day"
+                },
+                "intervalValue": {
+                    "description": "every X days/weeks/etc",
+                    "type": "integer",
+                    "example": 3
+                },
+                "rrule": {
+                    "description": "RFC 5545 recurrence rule starting on the start date",
+                    "type": "string",
+                    "example": "FREQ=MONTHLY;BYDAY=1MO"
+                },
+                "specificDays": {
+                    "description": "[\"sunday\", \"monday\", etc.]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "sunday",
+                        "monday",
+                        "wednesday"
+                    ]
+                },
+                "type": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.DueType"
+                        }
+                    ],
+                    "example": "specificDays"
+                }
+            }
+        },
+        "models.DueType": {
+            "type": "string",
+            "enum": [
+                "specificDays",
+                "interval",
+                "rrule"
+            ],
+            "x-enum-comments": {
+                "INTERVAL": "e.g., every 3 days/weeks/months/years",
+                "RRULE": "e.g., \"FREQ=MONTHLY;BYDAY=1MO\" for the first Monday of each month",
+                "SPECIFIC_DAYS": "e.g., [\"sunday\", \"monday\", \"wednesday\"]"
+            },
+            "x-enum-varnames": [
+                "SPECIFIC_DAYS",
+                "INTERVAL",
+                "RRULE"
+            ]
+        },
+        "models.Entry": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string",
+                    "example": "2024-01-01T10:00:00Z"
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                },
+                "deletedAt": {
+                    "description": "set while the entry is in the trash",
+                    "type": "string",
+                    "example": "2024-06-01T10:00:00Z"
+                },
+                "done": {
+                    "description": "For habit trackers (true/false)",
+                    "type": "boolean"
+                },
+                "duration": {
+                    "description": "For duration trackers, seconds spent from date on",
+                    "type": "integer",
+                    "example": 1800
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "note": {
+                    "type": "string",
+                    "example": "Felt great today"
+                },
+                "skipped": {
+                    "description": "For habit trackers, the day does not count",
+                    "type": "boolean"
+                },
+                "trackerId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "type": {
+                    "description": "\"habit\", \"target\" or \"duration\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.TrackerType"
+                        }
+                    ],
+                    "example": "habit"
+                },
+                "value": {
+                    "description": "For target trackers",
+                    "type": "number"
+                }
+            }
+        },
+        "models.LoginRequest": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "example": "correct horse battery staple"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "sahin"
+                }
+            }
+        },
+        "models.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "expiresAt": {
+                    "type": "string",
+                    "example": "2024-02-01T10:00:00Z"
+                },
+                "token": {
+                    "description": "send as \"Authorization: Bearer \u003ctoken\u003e\"",
+                    "type": "string",
+                    "example": "4f9c2e..."
+                },
+                "user": {
+                    "$ref": "#/definitions/models.User"
+                }
+            }
+        },
+        "models.NotificationChannel": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string",
+                    "example": "2024-01-01T10:00:00Z"
+                },
+                "enabled": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "type": "string",
+                    "example": "My phone"
+                },
+                "target": {
+                    "type": "string",
+                    "example": "https://ntfy.sh/my-habits"
+                },
+                "token": {
+                    "description": "sent as bearer token (webhook, ntfy) or app token (gotify)",
+                    "type": "string",
+                    "example": "tk_secret"
+                },
+                "trackerId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "trackerType": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.TrackerType"
+                        }
+                    ],
+                    "example": "habit"
+                },
+                "type": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ChannelType"
+                        }
+                    ],
+                    "example": "ntfy"
+                }
+            }
+        },
+        "models.Pause": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string",
+                    "example": "2024-01-01T10:00:00Z"
+                },
+                "endDate": {
+                    "description": "inclusive",
+                    "type": "string",
+                    "example": "2024-07-14T00:00:00Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "Vacation"
+                },
+                "startDate": {
+                    "type": "string",
+                    "example": "2024-07-01T00:00:00Z"
+                },
+                "trackerId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "trackerType": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.TrackerType"
+                        }
+                    ],
+                    "example": "habit"
+                }
+            }
+        },
+        "models.RegisterRequest": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "example": "correct horse battery staple"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "sahin"
+                }
+            }
+        },
+        "models.Reminder": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "times": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.Tag": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string",
+                    "example": "2024-01-01T10:00:00Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "type": "string",
+                    "example": "morning"
+                }
+            }
+        },
+        "models.TagRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "morning"
+                }
+            }
+        },
+        "models.TestNotificationRequest": {
+            "type": "object",
+            "properties": {
+                "channelId": {
+                    "description": "optional, defaults to every channel of the user",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "models.TestNotificationResult": {
+            "type": "object",
+            "properties": {
+                "channelId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "error": {
+                    "type": "string",
+                    "example": "webhook responded with 500 Internal Server Error"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "My phone"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "type": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ChannelType"
+                        }
+                    ],
+                    "example": "ntfy"
+                }
+            }
+        },
+        "models.TimePeriod": {
+            "type": "string",
+            "enum": [
+                "perDay",
+                "perWeek",
+                "perMonth",
+                "perYear"
+            ],
+            "x-enum-varnames": [
+                "PER_DAY",
+                "PER_WEEK",
+                "PER_MONTH",
+                "PER_YEAR"
+            ]
+        },
+        "models.TrackerPosition": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "pinned": {
+                    "description": "unchanged when left out",
+                    "type": "boolean",
+                    "example": true
+                },
+                "position": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "type": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.TrackerType"
+                        }
+                    ],
+                    "example": "habit"
+                }
+            }
+        },
+        "models.TrackerType": {
+            "type": "string",
+            "enum": [
+                "habit",
+                "target",
+                "duration"
+            ],
+            "x-enum-varnames": [
+                "HABIT",
+                "TARGET",
+                "DURATION"
+            ]
+        },
+        "models.UpdateCategoryRequest": {
+            "type": "object",
+            "properties": {
+                "color": {
+                    "type": "string",
+                    "example": "#2196f3"
+                },
+                "icon": {
+                    "type": "string",
+                    "example": "dumbbell"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Fitness"
+                }
+            }
+        },
+        "models.UpdateEntryRequest": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "description": "Supports both date (YYYY-MM-DD) and datetime (RFC3339) formats",
+                    "type": "string",
+                    "example": "2024-01-01T15:30:00Z"
+                },
+                "done": {
+                    "description": "For habit trackers",
+                    "type": "boolean"
+                },
+                "duration": {
+                    "description": "For duration trackers, seconds spent",
+                    "type": "integer",
+                    "example": 1800
+                },
+                "note": {
+                    "type": "string",
+                    "example": "Updated note"
+                },
+                "skipped": {
+                    "description": "For habit trackers",
+                    "type": "boolean"
+                },
+                "value": {
+                    "description": "For target trackers",
+                    "type": "number"
+                }
+            }
+        },
+        "models.UpdateNotificationChannelRequest": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "name": {
+                    "type": "string",
+                    "example": "My phone"
+                },
+                "target": {
+                    "type": "string",
+                    "example": "https://ntfy.sh/my-habits"
+                },
+                "token": {
+                    "type": "string",
+                    "example": "tk_secret"
+                }
+            }
+        },
+        "models.UpdateOrderRequest": {
+            "type": "object",
+            "properties": {
+                "trackers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TrackerPosition"
+                    }
+                }
+            }
+        },
+        "models.UpdatePauseRequest": {
+            "type": "object",
+            "properties": {
+                "endDate": {
+                    "type": "string",
+                    "example": "2024-07-21"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "Longer vacation"
+                },
+                "startDate": {
+                    "type": "string",
+                    "example": "2024-07-01"
+                }
+            }
+        },
+        "models.UpdateUserRequest": {
+            "type": "object",
+            "properties": {
+                "timeZone": {
+                    "type": "string",
+                    "example": "America/New_York"
+                }
+            }
+        },
+        "models.User": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string",
+                    "example": "2024-01-01T10:00:00Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "timeZone": {
+                    "description": "IANA name, days start at midnight in this zone",
+                    "type": "string",
+                    "example": "Europe/Istanbul"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "sahin"
+                }
+            }
+        },
+        "target.CreateTargetRequest": {
             "type": "object",
             "properties": {
-                "badHabit": {
+                "addToTotal": {
                     "type": "boolean",
                     "example": false
                 },
+                "categoryId": {
+                    "type": "integer",
+                    "example": 1
+                },
                 "due": {
                     "$ref": "#/definitions/models.Due"
                 },
-                "goal": {
+                "goalDate": {
+                    "description": "\"2024-12-31\" format",
+                    "type": "string",
+                    "example": "2024-12-31"
+                },
+                "goalValue": {
                     "type": "number",
-                    "example": 8
+                    "example": 5000
                 },
-                "goalStreak": {
-                    "type": "integer",
-                    "example": 30
+                "pinned": {
+                    "type": "boolean",
+                    "example": false
                 },
                 "reminders": {
                     "$ref": "#/definitions/models.Reminder"
@@ -654,24 +5872,30 @@ const docTemplate = `{
                     "type": "string",
                     "example": "2024-01-01"
                 },
-                "timePeriod": {
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.TimePeriod"
-                        }
-                    ],
-                    "example": "per_day"
+                "startValue": {
+                    "type": "number",
+                    "example": 0
+                },
+                "tagIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 },
                 "trackerName": {
                     "type": "string",
-                    "example": "Drink Water"
+                    "example": "Save Money"
+                },
+                "trendWeightType": {
+                    "type": "string",
+                    "example": "none"
                 }
             }
         },
-        "habit.HabitTracker": {
+        "target.Segment": {
             "type": "object",
             "properties": {
-                "badHabit": {
+                "addToTotal": {
                     "type": "boolean",
                     "example": false
                 },
@@ -679,23 +5903,88 @@ const docTemplate = `{
                     "type": "string",
                     "example": "2024-01-01T10:00:00Z"
                 },
-                "due": {
-                    "$ref": "#/definitions/models.Due"
+                "effectiveFrom": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
                 },
-                "goal": {
-                    "description": "how many times",
+                "goalDate": {
+                    "type": "string",
+                    "example": "2024-12-31T00:00:00Z"
+                },
+                "goalValue": {
                     "type": "number",
-                    "example": 8
+                    "example": 5000
                 },
-                "goalStreak": {
-                    "description": "null or int",
+                "id": {
                     "type": "integer",
-                    "example": 30
+                    "example": 1
+                },
+                "trackerId": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "target.TargetTracker": {
+            "type": "object",
+            "properties": {
+                "addToTotal": {
+                    "description": "default false",
+                    "type": "boolean",
+                    "example": false
+                },
+                "archivedAt": {
+                    "description": "set while the tracker is archived",
+                    "type": "string",
+                    "example": "2024-06-01T10:00:00Z"
+                },
+                "categoryId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2024-01-01T10:00:00Z"
+                },
+                "currentValue": {
+                    "description": "Calculated field, not stored in DB",
+                    "type": "number",
+                    "example": 1234.56
+                },
+                "deletedAt": {
+                    "description": "set while the tracker is in the trash",
+                    "type": "string",
+                    "example": "2024-06-01T10:00:00Z"
+                },
+                "due": {
+                    "$ref": "#/definitions/models.Due"
+                },
+                "goalDate": {
+                    "type": "string",
+                    "example": "2024-12-31T00:00:00Z"
+                },
+                "goalValue": {
+                    "type": "number",
+                    "example": 5000
                 },
                 "id": {
                     "type": "integer",
                     "example": 1
                 },
+                "originalStartValue": {
+                    "description": "Always the original user-set value",
+                    "type": "number",
+                    "example": 0
+                },
+                "pinned": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "position": {
+                    "description": "place in the user-defined order, see PUT /trackers/order",
+                    "type": "integer",
+                    "example": 0
+                },
                 "reminders": {
                     "$ref": "#/definitions/models.Reminder"
                 },
@@ -703,35 +5992,59 @@ const docTemplate = `{
                     "type": "string",
                     "example": "2024-01-01T00:00:00Z"
                 },
-                "timePeriod": {
-                    "description": "per day, week, month, year",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.TimePeriod"
-                        }
-                    ],
-                    "example": "per_day"
+                "startValue": {
+                    "description": "Adjusted value when useActualBounds is true",
+                    "type": "number",
+                    "example": 0
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Tag"
+                    }
                 },
                 "trackerName": {
                     "type": "string",
-                    "example": "Drink Water"
+                    "example": "Save Money"
+                },
+                "trendWeightType": {
+                    "description": "Weighting algorithm for trend line",
+                    "type": "string",
+                    "example": "none"
+                },
+                "useActualBounds": {
+                    "description": "default false",
+                    "type": "boolean",
+                    "example": false
                 }
             }
         },
-        "habit.UpdateHabitRequest": {
+        "target.UpdateTargetRequest": {
             "type": "object",
             "properties": {
-                "badHabit": {
+                "addToTotal": {
                     "type": "boolean"
                 },
+                "categoryId": {
+                    "description": "0 removes the tracker from its category",
+                    "type": "integer"
+                },
                 "due": {
                     "$ref": "#/definitions/models.Due"
                 },
-                "goal": {
+                "effectiveFrom": {
+                    "description": "when a new goalValue, goalDate or addToTotal starts to apply, defaults to today and may be in the future",
+                    "type": "string",
+                    "example": "2024-06-01"
+                },
+                "goalDate": {
+                    "type": "string"
+                },
+                "goalValue": {
                     "type": "number"
                 },
-                "goalStreak": {
-                    "type": "integer"
+                "pinned": {
+                    "type": "boolean"
                 },
                 "reminders": {
                     "$ref": "#/definitions/models.Reminder"
@@ -739,284 +6052,352 @@ const docTemplate = `{
                 "startDate": {
                     "type": "string"
                 },
-                "timePeriod": {
-                    "$ref": "#/definitions/models.TimePeriod"
+                "startValue": {
+                    "type": "number"
+                },
+                "tagIds": {
+                    "description": "replaces the tags of the tracker",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 },
                 "trackerName": {
                     "type": "string"
+                },
+                "trendWeightType": {
+                    "type": "string"
+                },
+                "useActualBounds": {
+                    "type": "boolean"
                 }
             }
         },
-        "models.AddEntryRequest": {
+        "trackers.AgendaDay": {
             "type": "object",
             "properties": {
                 "date": {
-                    "description": "optional, defaults to now. Supports both date (YYYY-MM-DD) and datetime (RFC3339) formats",
                     "type": "string",
-                    "example": "2024-01-01T15:30:00Z"
+                    "example": "2024-01-01"
                 },
-                "done": {
-                    "description": "For habit trackers",
-                    "type": "boolean"
+                "durationTrackers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/duration.DurationTracker"
+                    }
                 },
-                "note": {
-                    "type": "string",
-                    "example": "Felt great today"
+                "habitTrackers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/habit.HabitTracker"
+                    }
                 },
-                "value": {
-                    "description": "For target trackers",
-                    "type": "number"
+                "targetTrackers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/target.TargetTracker"
+                    }
                 }
             }
         },
-        "models.Due": {
+        "trackers.AgendaResponse": {
             "type": "object",
             "properties": {
-                "intervalType": {
-                    "description": "\"day\", \"week\", \"month\", \"year\"",
-                    "type": "string",
-                    "example": "day"
-                },
-                "intervalValue": {
-                    "description": "every X days/weeks/etc",
-                    "type": "integer",
-                    "example": 3
-                },
-                "specificDays": {
-                    "description": "[\"sunday\", \"monday\", etc.]",
+                "days": {
                     "type": "array",
                     "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "sunday",
-                        "monday",
-                        "wednesday"
-                    ]
+                        "$ref": "#/definitions/trackers.AgendaDay"
+                    }
                 },
-                "type": {
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.DueType"
-                        }
-                    ],
-                    "example": "specificDays"
+                "from": {
+                    "type": "string",
+                    "example": "2024-01-01"
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2024-01-07"
                 }
             }
         },
-        "models.DueType": {
-            "type": "string",
-            "enum": [
-                "specificDays",
-                "interval"
-            ],
-            "x-enum-comments": {
-                "INTERVAL": "e.g., every 3 days/weeks/months/years",
-                "SPECIFIC_DAYS": "e.g., [\"sunday\", \"monday\", \"wednesday\"]"
-            },
-            "x-enum-varnames": [
-                "SPECIFIC_DAYS",
-                "INTERVAL"
-            ]
-        },
-        "models.Entry": {
+        "trackers.Archive": {
             "type": "object",
             "properties": {
-                "createdAt": {
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Category"
+                    }
+                },
+                "durationTrackers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/duration.DurationTracker"
+                    }
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Entry"
+                    }
+                },
+                "exportedAt": {
                     "type": "string",
                     "example": "2024-01-01T10:00:00Z"
                 },
-                "date": {
-                    "type": "string",
-                    "example": "2024-01-01T00:00:00Z"
+                "habitTrackers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/habit.HabitTracker"
+                    }
                 },
-                "done": {
-                    "description": "For habit trackers (true/false)",
-                    "type": "boolean"
+                "pauses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Pause"
+                    }
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Tag"
+                    }
                 },
-                "id": {
-                    "type": "integer",
-                    "example": 1
+                "targetSegments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/target.Segment"
+                    }
                 },
-                "note": {
-                    "type": "string",
-                    "example": "Felt great today"
+                "targetTrackers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/target.TargetTracker"
+                    }
                 },
-                "trackerId": {
+                "version": {
                     "type": "integer",
                     "example": 1
-                },
-                "type": {
-                    "description": "\"habit\" or \"target\"",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.TrackerType"
-                        }
-                    ],
-                    "example": "habit"
-                },
-                "value": {
-                    "description": "For target trackers",
-                    "type": "number"
                 }
             }
         },
-        "models.Reminder": {
+        "trackers.DashboardResponse": {
             "type": "object",
             "properties": {
-                "enabled": {
-                    "type": "boolean"
+                "date": {
+                    "type": "string",
+                    "example": "2024-01-01"
                 },
-                "times": {
+                "durationTrackers": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/duration.DurationTracker"
+                    }
+                },
+                "groups": {
+                    "description": "the same trackers grouped by category",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/trackers.TrackerGroup"
+                    }
+                },
+                "habitTrackers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/habit.HabitTracker"
+                    }
+                },
+                "order": {
+                    "description": "trackers of all types interleaved in the user-defined order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/trackers.TrackerRef"
+                    }
+                },
+                "targetTrackers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/target.TargetTracker"
                     }
                 }
             }
         },
-        "models.TimePeriod": {
-            "type": "string",
-            "enum": [
-                "perDay",
-                "perWeek",
-                "perMonth",
-                "perYear"
-            ],
-            "x-enum-varnames": [
-                "PER_DAY",
-                "PER_WEEK",
-                "PER_MONTH",
-                "PER_YEAR"
-            ]
-        },
-        "models.TrackerType": {
+        "trackers.ImportMode": {
             "type": "string",
             "enum": [
-                "habit",
-                "target"
+                "merge",
+                "replace"
             ],
+            "x-enum-comments": {
+                "IMPORT_MERGE": "keep existing data, trackers with the same name and type are combined",
+                "IMPORT_REPLACE": "delete all existing trackers, entries and pauses first"
+            },
             "x-enum-varnames": [
-                "HABIT",
-                "TARGET"
+                "IMPORT_MERGE",
+                "IMPORT_REPLACE"
             ]
         },
-        "target.CreateTargetRequest": {
+        "trackers.ImportResult": {
             "type": "object",
             "properties": {
-                "addToTotal": {
+                "categoriesCreated": {
+                    "description": "categories and tags with a new name",
+                    "type": "integer",
+                    "example": 1
+                },
+                "dryRun": {
                     "type": "boolean",
                     "example": false
                 },
-                "due": {
-                    "$ref": "#/definitions/models.Due"
+                "durationTrackersCreated": {
+                    "type": "integer",
+                    "example": 1
                 },
-                "goalDate": {
-                    "description": "\"2024-12-31\" format",
-                    "type": "string",
-                    "example": "2024-12-31"
+                "entriesCreated": {
+                    "type": "integer",
+                    "example": 120
                 },
-                "goalValue": {
-                    "type": "number",
-                    "example": 5000
+                "entriesDeleted": {
+                    "description": "replace mode only",
+                    "type": "integer",
+                    "example": 0
                 },
-                "reminders": {
-                    "$ref": "#/definitions/models.Reminder"
+                "entriesSkipped": {
+                    "description": "identical entries that already existed",
+                    "type": "integer",
+                    "example": 3
                 },
-                "startDate": {
-                    "description": "\"2024-01-01\" format",
-                    "type": "string",
-                    "example": "2024-01-01"
+                "habitTrackersCreated": {
+                    "type": "integer",
+                    "example": 2
                 },
-                "startValue": {
-                    "type": "number",
+                "mode": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/trackers.ImportMode"
+                        }
+                    ],
+                    "example": "merge"
+                },
+                "pausesCreated": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "tagsCreated": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "targetTrackersCreated": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "trackersDeleted": {
+                    "description": "replace mode only",
+                    "type": "integer",
                     "example": 0
                 },
-                "trackerName": {
-                    "type": "string",
-                    "example": "Save Money"
+                "trackersMerged": {
+                    "description": "imported into an existing tracker with the same name",
+                    "type": "integer",
+                    "example": 1
                 }
             }
         },
-        "target.TargetTracker": {
+        "trackers.NextDueResponse": {
             "type": "object",
             "properties": {
-                "addToTotal": {
-                    "description": "default false",
-                    "type": "boolean",
-                    "example": false
-                },
-                "createdAt": {
-                    "type": "string",
-                    "example": "2024-01-01T10:00:00Z"
-                },
-                "due": {
-                    "$ref": "#/definitions/models.Due"
+                "dates": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "2024-01-01",
+                        "2024-01-08"
+                    ]
                 },
-                "goalDate": {
+                "from": {
                     "type": "string",
-                    "example": "2024-12-31T00:00:00Z"
-                },
-                "goalValue": {
-                    "type": "number",
-                    "example": 5000
+                    "example": "2024-01-01"
                 },
-                "id": {
+                "trackerId": {
                     "type": "integer",
                     "example": 1
                 },
-                "reminders": {
-                    "$ref": "#/definitions/models.Reminder"
-                },
-                "startDate": {
-                    "type": "string",
-                    "example": "2024-01-01T00:00:00Z"
-                },
-                "startValue": {
-                    "type": "number",
-                    "example": 0
-                },
-                "trackerName": {
-                    "type": "string",
-                    "example": "Save Money"
+                "type": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.TrackerType"
+                        }
+                    ],
+                    "example": "habit"
                 }
             }
         },
-        "target.UpdateTargetRequest": {
+        "trackers.TrackerGroup": {
             "type": "object",
             "properties": {
-                "addToTotal": {
-                    "type": "boolean"
-                },
-                "due": {
-                    "$ref": "#/definitions/models.Due"
-                },
-                "goalDate": {
-                    "type": "string"
+                "category": {
+                    "$ref": "#/definitions/models.Category"
                 },
-                "goalValue": {
-                    "type": "number"
+                "durationTrackers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/duration.DurationTracker"
+                    }
                 },
-                "reminders": {
-                    "$ref": "#/definitions/models.Reminder"
+                "habitTrackers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/habit.HabitTracker"
+                    }
                 },
-                "startDate": {
-                    "type": "string"
+                "order": {
+                    "description": "trackers of all types interleaved in the user-defined order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/trackers.TrackerRef"
+                    }
                 },
-                "startValue": {
-                    "type": "number"
+                "targetTrackers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/target.TargetTracker"
+                    }
+                }
+            }
+        },
+        "trackers.TrackerRef": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 1
                 },
-                "trackerName": {
-                    "type": "string"
+                "type": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.TrackerType"
+                        }
+                    ],
+                    "example": "habit"
                 }
             }
         },
-        "trackers.DashboardResponse": {
+        "trackers.TrackersResponse": {
             "type": "object",
             "properties": {
-                "date": {
-                    "type": "string",
-                    "example": "2024-01-01"
+                "durationTrackers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/duration.DurationTracker"
+                    }
+                },
+                "groups": {
+                    "description": "the same trackers grouped by category",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/trackers.TrackerGroup"
+                    }
                 },
                 "habitTrackers": {
                     "type": "array",
@@ -1024,6 +6405,13 @@ const docTemplate = `{
                         "$ref": "#/definitions/habit.HabitTracker"
                     }
                 },
+                "order": {
+                    "description": "trackers of all types interleaved in the user-defined order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/trackers.TrackerRef"
+                    }
+                },
                 "targetTrackers": {
                     "type": "array",
                     "items": {
@@ -1032,15 +6420,32 @@ const docTemplate = `{
                 }
             }
         },
-        "trackers.TrackersResponse": {
+        "trackers.TrashResponse": {
             "type": "object",
             "properties": {
+                "durationTrackers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/duration.DurationTracker"
+                    }
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Entry"
+                    }
+                },
                 "habitTrackers": {
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/habit.HabitTracker"
                     }
                 },
+                "retentionDays": {
+                    "description": "days before deleted items are purged",
+                    "type": "integer",
+                    "example": 30
+                },
                 "targetTrackers": {
                     "type": "array",
                     "items": {
@@ -1049,6 +6454,13 @@ const docTemplate = `{
                 }
             }
         }
+    },
+    "securityDefinitions": {
+        "BearerAuth": {
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        }
     }
 }`
 
@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"routine-tracker/auth"
+	"routine-tracker/models"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// ArchiveHabitTracker archives a habit tracker
+// @Summary Archive habit tracker
+// @Description Take a habit tracker off the dashboard, agenda, calendar feed and reminders while keeping its entries and stats readable
+// @Tags Habit Trackers
+// @Produce json
+// @Param id path int true "Habit Tracker ID"
+// @Success 200 {object} habit.HabitTracker
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Not Found"
+// @Security BearerAuth
+// @Router /habit-trackers/{id}/archive [post]
+func (h *Handler) ArchiveHabitTracker(w http.ResponseWriter, r *http.Request) {
+	h.setArchived(w, r, models.HABIT, true)
+}
+
+// UnarchiveHabitTracker restores an archived habit tracker
+// @Summary Unarchive habit tracker
+// @Description Restore an archived habit tracker, it is due again on its schedule
+// @Tags Habit Trackers
+// @Produce json
+// @Param id path int true "Habit Tracker ID"
+// @Success 200 {object} habit.HabitTracker
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Not Found"
+// @Security BearerAuth
+// @Router /habit-trackers/{id}/unarchive [post]
+func (h *Handler) UnarchiveHabitTracker(w http.ResponseWriter, r *http.Request) {
+	h.setArchived(w, r, models.HABIT, false)
+}
+
+// ArchiveTargetTracker archives a target tracker
+// @Summary Archive target tracker
+// @Description Take a target tracker off the dashboard, agenda, calendar feed and reminders while keeping its entries and projection readable
+// @Tags Target Trackers
+// @Produce json
+// @Param id path int true "Target Tracker ID"
+// @Success 200 {object} target.TargetTracker
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Not Found"
+// @Security BearerAuth
+// @Router /target-trackers/{id}/archive [post]
+func (h *Handler) ArchiveTargetTracker(w http.ResponseWriter, r *http.Request) {
+	h.setArchived(w, r, models.TARGET, true)
+}
+
+// UnarchiveTargetTracker restores an archived target tracker
+// @Summary Unarchive target tracker
+// @Description Restore an archived target tracker, it is due again on its schedule
+// @Tags Target Trackers
+// @Produce json
+// @Param id path int true "Target Tracker ID"
+// @Success 200 {object} target.TargetTracker
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Not Found"
+// @Security BearerAuth
+// @Router /target-trackers/{id}/unarchive [post]
+func (h *Handler) UnarchiveTargetTracker(w http.ResponseWriter, r *http.Request) {
+	h.setArchived(w, r, models.TARGET, false)
+}
+
+// ArchiveDurationTracker archives a duration tracker
+// @Summary Archive duration tracker
+// @Description Take a duration tracker off the dashboard, agenda, calendar feed and reminders while keeping its entries and stats readable
+// @Tags Duration Trackers
+// @Produce json
+// @Param id path int true "Duration Tracker ID"
+// @Success 200 {object} duration.DurationTracker
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Not Found"
+// @Security BearerAuth
+// @Router /duration-trackers/{id}/archive [post]
+func (h *Handler) ArchiveDurationTracker(w http.ResponseWriter, r *http.Request) {
+	h.setArchived(w, r, models.DURATION, true)
+}
+
+// UnarchiveDurationTracker restores an archived duration tracker
+// @Summary Unarchive duration tracker
+// @Description Restore an archived duration tracker, it is due again on its schedule
+// @Tags Duration Trackers
+// @Produce json
+// @Param id path int true "Duration Tracker ID"
+// @Success 200 {object} duration.DurationTracker
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Not Found"
+// @Security BearerAuth
+// @Router /duration-trackers/{id}/unarchive [post]
+func (h *Handler) UnarchiveDurationTracker(w http.ResponseWriter, r *http.Request) {
+	h.setArchived(w, r, models.DURATION, false)
+}
+
+// setArchived archives or restores a tracker and responds with the tracker.
+// Archiving an archived tracker keeps its original archivedAt.
+func (h *Handler) setArchived(w http.ResponseWriter, r *http.Request, trackerType models.TrackerType, archived bool) {
+	userID := auth.UserID(r.Context())
+	trackerID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid tracker ID", http.StatusBadRequest)
+		return
+	}
+
+	action := models.AUDIT_UNARCHIVE
+	var archivedAt *time.Time
+	if archived {
+		action = models.AUDIT_ARCHIVE
+		now := h.Now().UTC()
+		archivedAt = &now
+	}
+
+	var tracker interface{}
+	switch trackerType {
+	case models.HABIT:
+		habit, err := h.Store.GetHabitTrackerByID(userID, trackerID)
+		if err != nil {
+			http.Error(w, "Habit tracker not found", http.StatusNotFound)
+			return
+		}
+		if archived && habit.ArchivedAt != nil {
+			archivedAt = habit.ArchivedAt
+		}
+		if err := h.Store.SetHabitTrackerArchived(userID, trackerID, archivedAt); err != nil {
+			http.Error(w, "Failed to update habit tracker: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		before := *habit
+		habit.ArchivedAt = archivedAt
+		h.audit(r, action, models.AUDIT_HABIT_TRACKER, trackerID, before, habit)
+		tracker = habit
+	case models.TARGET:
+		target, err := h.Store.GetTargetTrackerByID(userID, trackerID)
+		if err != nil {
+			http.Error(w, "Target tracker not found", http.StatusNotFound)
+			return
+		}
+		if archived && target.ArchivedAt != nil {
+			archivedAt = target.ArchivedAt
+		}
+		if err := h.Store.SetTargetTrackerArchived(userID, trackerID, archivedAt); err != nil {
+			http.Error(w, "Failed to update target tracker: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		before := *target
+		target.ArchivedAt = archivedAt
+		h.audit(r, action, models.AUDIT_TARGET_TRACKER, trackerID, before, target)
+		h.setCurrentValues(target)
+		tracker = target
+	case models.DURATION:
+		duration, err := h.Store.GetDurationTrackerByID(userID, trackerID)
+		if err != nil {
+			http.Error(w, "Duration tracker not found", http.StatusNotFound)
+			return
+		}
+		if archived && duration.ArchivedAt != nil {
+			archivedAt = duration.ArchivedAt
+		}
+		if err := h.Store.SetDurationTrackerArchived(userID, trackerID, archivedAt); err != nil {
+			http.Error(w, "Failed to update duration tracker: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		before := *duration
+		duration.ArchivedAt = archivedAt
+		h.audit(r, action, models.AUDIT_DURATION_TRACKER, trackerID, before, duration)
+		if today, err := h.today(r); err == nil {
+			h.setCurrentTotal(duration, today)
+		}
+		tracker = duration
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tracker)
+}
+
+// archiveFilter reads the "archived" query parameter of tracker lists:
+// false (the default) for active trackers, true for archived ones or all
+func archiveFilter(r *http.Request) (models.ArchiveFilter, error) {
+	switch r.URL.Query().Get("archived") {
+	case "", "false":
+		return models.ACTIVE_TRACKERS, nil
+	case "true":
+		return models.ARCHIVED_TRACKERS, nil
+	case "all":
+		return models.ALL_TRACKERS, nil
+	}
+	return "", errors.New("Invalid archived value. Use 'false', 'true' or 'all'")
+}
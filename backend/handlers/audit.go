@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"routine-tracker/auth"
+	"routine-tracker/models"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// Limits of the number of audit events in a response
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+// secretAuditFields are JSON fields whose values are kept out of the audit
+// log, a change of them is still recorded
+var secretAuditFields = map[string]bool{"token": true}
+
+// GetAuditEvents gets the audit log
+// @Summary Get audit log
+// @Description List the changes made to the user's data, the newest first. Every event records how the user was authenticated, what was done to which entity and the changed fields with their values before and after.
+// @Tags Audit
+// @Produce json
+// @Param entity query string false "Only events of this kind of entity" Enums(habit_tracker, target_tracker, duration_tracker, entry, timer, pause, notification_channel, api_token, tag, category, user)
+// @Param id query int false "Only events of the entity with this ID, needs entity"
+// @Param limit query int false "Maximum number of events, defaults to 100, at most 1000"
+// @Success 200 {array} models.AuditEvent
+// @Failure 400 {string} string "Bad Request"
+// @Security BearerAuth
+// @Router /audit [get]
+func (h *Handler) GetAuditEvents(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+	query := r.URL.Query()
+	filter := models.AuditFilter{Limit: defaultAuditLimit}
+
+	if entity := query.Get("entity"); entity != "" {
+		filter.Entity = models.AuditEntity(entity)
+		if !filter.Entity.IsValid() {
+			http.Error(w, "Invalid entity '"+entity+"'", http.StatusBadRequest)
+			return
+		}
+	}
+	if value := query.Get("id"); value != "" {
+		if filter.Entity == "" {
+			http.Error(w, "id needs an entity", http.StatusBadRequest)
+			return
+		}
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			http.Error(w, "Invalid id", http.StatusBadRequest)
+			return
+		}
+		filter.EntityID = &id
+	}
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxAuditLimit {
+			http.Error(w, "limit must be a number from 1 to "+strconv.Itoa(maxAuditLimit), http.StatusBadRequest)
+			return
+		}
+		filter.Limit = limit
+	}
+
+	events, err := h.Store.GetAuditEvents(userID, filter)
+	if err != nil {
+		http.Error(w, "Failed to get audit events: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(events)
+}
+
+// GetHabitTrackerHistory gets the settings history of a habit tracker
+// @Summary Get habit tracker history
+// @Description List the changes of a habit tracker's settings, the newest first, from its creation to archiving, deleting and restoring it
+// @Tags Habit Trackers
+// @Produce json
+// @Param id path int true "Habit Tracker ID"
+// @Success 200 {array} models.AuditEvent
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Not Found"
+// @Security BearerAuth
+// @Router /habit-trackers/{id}/history [get]
+func (h *Handler) GetHabitTrackerHistory(w http.ResponseWriter, r *http.Request) {
+	h.trackerHistory(w, r, models.HABIT)
+}
+
+// GetTargetTrackerHistory gets the settings history of a target tracker
+// @Summary Get target tracker history
+// @Description List the changes of a target tracker's settings, the newest first, e.g. to explain a changed goal value or start date in the charts
+// @Tags Target Trackers
+// @Produce json
+// @Param id path int true "Target Tracker ID"
+// @Success 200 {array} models.AuditEvent
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Not Found"
+// @Security BearerAuth
+// @Router /target-trackers/{id}/history [get]
+func (h *Handler) GetTargetTrackerHistory(w http.ResponseWriter, r *http.Request) {
+	h.trackerHistory(w, r, models.TARGET)
+}
+
+// GetDurationTrackerHistory gets the settings history of a duration tracker
+// @Summary Get duration tracker history
+// @Description List the changes of a duration tracker's settings, the newest first
+// @Tags Duration Trackers
+// @Produce json
+// @Param id path int true "Duration Tracker ID"
+// @Success 200 {array} models.AuditEvent
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Not Found"
+// @Security BearerAuth
+// @Router /duration-trackers/{id}/history [get]
+func (h *Handler) GetDurationTrackerHistory(w http.ResponseWriter, r *http.Request) {
+	h.trackerHistory(w, r, models.DURATION)
+}
+
+// trackerHistory responds with the audit events of a tracker
+func (h *Handler) trackerHistory(w http.ResponseWriter, r *http.Request, trackerType models.TrackerType) {
+	userID := auth.UserID(r.Context())
+	trackerID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid tracker ID", http.StatusBadRequest)
+		return
+	}
+
+	filter := models.AuditFilter{EntityID: &trackerID}
+	switch trackerType {
+	case models.HABIT:
+		_, err = h.Store.GetHabitTrackerByID(userID, trackerID)
+		filter.Entity = models.AUDIT_HABIT_TRACKER
+	case models.TARGET:
+		_, err = h.Store.GetTargetTrackerByID(userID, trackerID)
+		filter.Entity = models.AUDIT_TARGET_TRACKER
+	case models.DURATION:
+		_, err = h.Store.GetDurationTrackerByID(userID, trackerID)
+		filter.Entity = models.AUDIT_DURATION_TRACKER
+	}
+	if err != nil {
+		http.Error(w, "Tracker not found", http.StatusNotFound)
+		return
+	}
+
+	events, err := h.Store.GetAuditEvents(userID, filter)
+	if err != nil {
+		http.Error(w, "Failed to get tracker history: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(events)
+}
+
+// audit records a change of an entity in the audit log. before is nil for new
+// entities and after is nil for deleted ones, only the fields that differ are
+// kept. The change is already made, so a failure is only logged.
+func (h *Handler) audit(r *http.Request, action models.AuditAction, entity models.AuditEntity, entityID int, before, after interface{}) {
+	event := models.AuditEvent{
+		UserID:    auth.UserID(r.Context()),
+		Actor:     models.ACTOR_SESSION,
+		Action:    action,
+		Entity:    entity,
+		EntityID:  entityID,
+		Changes:   auditChanges(before, after),
+		CreatedAt: h.Now(),
+	}
+	if tokenID, ok := auth.APITokenID(r.Context()); ok {
+		event.Actor = models.ACTOR_API_TOKEN
+		event.APITokenID = &tokenID
+	}
+
+	if _, err := h.Store.CreateAuditEvent(event); err != nil {
+		h.Logger.Error("failed to write audit event", "action", action, "entity", entity, "id", entityID, "error", err)
+	}
+}
+
+// auditChanges compares the JSON fields of two values and returns the ones
+// that differ
+func auditChanges(before, after interface{}) map[string]models.AuditChange {
+	beforeFields, afterFields := jsonFields(before), jsonFields(after)
+
+	changes := make(map[string]models.AuditChange)
+	for name, value := range beforeFields {
+		if other, ok := afterFields[name]; !ok || !reflect.DeepEqual(value, other) {
+			changes[name] = models.AuditChange{Before: value, After: other}
+		}
+	}
+	for name, value := range afterFields {
+		if _, ok := beforeFields[name]; !ok {
+			changes[name] = models.AuditChange{After: value}
+		}
+	}
+
+	for name, change := range changes {
+		if !secretAuditFields[name] {
+			continue
+		}
+		if change.Before != nil {
+			change.Before = "[redacted]"
+		}
+		if change.After != nil {
+			change.After = "[redacted]"
+		}
+		changes[name] = change
+	}
+	return changes
+}
+
+// jsonFields returns the fields of a value's JSON object, none for nil
+func jsonFields(value interface{}) map[string]interface{} {
+	fields := make(map[string]interface{})
+	if value == nil {
+		return fields
+	}
+	data, err := json.Marshal(value)
+	if err != nil || json.Unmarshal(data, &fields) != nil || fields == nil {
+		return make(map[string]interface{})
+	}
+	return fields
+}
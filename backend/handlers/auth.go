@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"routine-tracker/auth"
+	"routine-tracker/models"
+	"strings"
+	"time"
+)
+
+// SessionDuration is how long a login token stays valid
+const SessionDuration = 30 * 24 * time.Hour
+
+// Register creates a new user account
+// @Summary Register
+// @Description Create a new user account. The first registered user takes over trackers created before accounts existed.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param user body models.RegisterRequest true "Account credentials"
+// @Success 201 {object} models.User
+// @Failure 400 {string} string "Bad Request"
+// @Failure 409 {string} string "Username already taken"
+// @Router /auth/register [post]
+func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
+	var req models.RegisterRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	req.Username = strings.TrimSpace(req.Username)
+	if req.Username == "" {
+		http.Error(w, "Username is required", http.StatusBadRequest)
+		return
+	}
+	if len(req.Password) < 8 {
+		http.Error(w, "Password must be at least 8 characters", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := h.Store.GetUserByUsername(req.Username); err == nil {
+		http.Error(w, "Username already taken", http.StatusConflict)
+		return
+	}
+
+	passwordHash, err := auth.HashPassword(req.Password)
+	if err != nil {
+		http.Error(w, "Failed to hash password", http.StatusInternalServerError)
+		return
+	}
+
+	user, err := h.Store.CreateUser(req.Username, passwordHash)
+	if err != nil {
+		http.Error(w, "Failed to create user: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.audit(r.WithContext(auth.WithUserID(r.Context(), user.ID)), models.AUDIT_CREATE, models.AUDIT_USER, user.ID, nil, user)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(user)
+}
+
+// Login issues a bearer token for valid credentials
+// @Summary Login
+// @Description Exchange a username and password for a bearer token
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param credentials body models.LoginRequest true "Account credentials"
+// @Success 200 {object} models.LoginResponse
+// @Failure 400 {string} string "Bad Request"
+// @Failure 401 {string} string "Invalid username or password"
+// @Router /auth/login [post]
+func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
+	var req models.LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.Store.GetUserByUsername(strings.TrimSpace(req.Username))
+	if err != nil || !auth.CheckPassword(user.PasswordHash, req.Password) {
+		http.Error(w, "Invalid username or password", http.StatusUnauthorized)
+		return
+	}
+
+	token, err := auth.NewToken()
+	if err != nil {
+		http.Error(w, "Failed to create token", http.StatusInternalServerError)
+		return
+	}
+
+	expiresAt := h.Now().Add(SessionDuration).UTC()
+	if err := h.Store.CreateSession(user.ID, auth.HashToken(token), expiresAt); err != nil {
+		http.Error(w, "Failed to create session: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := models.LoginResponse{
+		Token:     token,
+		ExpiresAt: expiresAt,
+		User:      *user,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// Logout revokes the bearer token used for the request
+// @Summary Logout
+// @Description Revoke the bearer token used for this request
+// @Tags Auth
+// @Security BearerAuth
+// @Success 204 "No Content"
+// @Failure 401 {string} string "Unauthorized"
+// @Router /auth/logout [post]
+func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
+	if err := h.Store.DeleteSession(auth.HashToken(auth.BearerToken(r))); err != nil {
+		http.Error(w, "Failed to logout: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetCurrentUser gets the authenticated user
+// @Summary Get current user
+// @Description Get the user the bearer token belongs to
+// @Tags Auth
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.User
+// @Failure 401 {string} string "Unauthorized"
+// @Router /auth/me [get]
+func (h *Handler) GetCurrentUser(w http.ResponseWriter, r *http.Request) {
+	user, err := h.Store.GetUserByID(auth.UserID(r.Context()))
+	if err != nil {
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+// UpdateCurrentUser updates the settings of the authenticated user
+// @Summary Update current user
+// @Description Update the settings of the user the bearer token belongs to, e.g. the IANA time zone in which days start
+// @Tags Auth
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param user body models.UpdateUserRequest true "Settings to change"
+// @Success 200 {object} models.User
+// @Failure 400 {string} string "Bad Request"
+// @Failure 401 {string} string "Unauthorized"
+// @Router /auth/me [put]
+func (h *Handler) UpdateCurrentUser(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+	var req models.UpdateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	before, err := h.Store.GetUserByID(userID)
+	if err != nil {
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
+
+	if req.TimeZone != nil {
+		if _, err := loadTimeZone(*req.TimeZone); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := h.Store.UpdateUserTimeZone(userID, *req.TimeZone); err != nil {
+			http.Error(w, "Failed to update user: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	if after, err := h.Store.GetUserByID(userID); err == nil {
+		h.audit(r, models.AUDIT_UPDATE, models.AUDIT_USER, userID, before, after)
+	}
+
+	h.GetCurrentUser(w, r)
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"net/http"
+	"routine-tracker/auth"
+	"routine-tracker/calendar"
+	"routine-tracker/models"
+	"routine-tracker/trackers"
+	"time"
+)
+
+// calendarRefresh is how often calendar apps are asked to fetch the feed, so
+// that edits of trackers show up
+const calendarRefresh = time.Hour
+
+// GetCalendar gets an iCalendar feed of the user's trackers
+// @Summary Get calendar feed
+// @Description Get an iCalendar feed with a repeating all-day event for the due schedule of each tracker, its reminder times as alarms and an event on the goal date of each target tracker.
+// @Description Calendar apps cannot send headers, so a personal API token with the trackers:read scope can be passed in the "token" query parameter.
+// @Tags General
+// @Produce text/calendar
+// @Param token query string false "Personal API token, instead of the Authorization header"
+// @Success 200 {string} string "iCalendar feed"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Internal Server Error"
+// @Security BearerAuth
+// @Router /calendar.ics [get]
+func (h *Handler) GetCalendar(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+	habits, err := h.Store.GetAllHabitTrackers(userID, models.ACTIVE_TRACKERS)
+	if err != nil {
+		http.Error(w, "Failed to get habit trackers: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	targets, err := h.Store.GetAllTargetTrackers(userID, models.ACTIVE_TRACKERS)
+	if err != nil {
+		http.Error(w, "Failed to get target trackers: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	durations, err := h.Store.GetAllDurationTrackers(userID, models.ACTIVE_TRACKERS)
+	if err != nil {
+		http.Error(w, "Failed to get duration trackers: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	feed := calendar.Calendar{
+		Name:    "Progress",
+		Refresh: calendarRefresh,
+		Events:  trackers.CalendarEvents(habits, targets, durations),
+	}
+
+	w.Header().Set("Content-Type", calendar.ContentType)
+	w.Header().Set("Content-Disposition", `inline; filename="progress.ics"`)
+	feed.Write(w, h.Now())
+}
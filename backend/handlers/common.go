@@ -3,59 +3,80 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
-	"routine-tracker/database"
+	"routine-tracker/auth"
+	"routine-tracker/models"
 	"routine-tracker/trackers"
+	"routine-tracker/trackers/duration"
 	"routine-tracker/trackers/habit"
 	"routine-tracker/trackers/target"
-	"strings"
-	"time"
 )
 
 // GetAllTrackers gets all trackers
 // @Summary Get all trackers (combined)
-// @Description Retrieve all habit and target trackers
+// @Description Retrieve all habit, target and duration trackers in the user-defined order, also grouped by category. Archived ones are left out by default.
 // @Tags General
 // @Produce json
+// @Param archived query string false "false for active trackers (default), true for archived ones or all" Enums(false, true, all)
+// @Param tag query string false "Only trackers with this tag" example(morning)
+// @Param category query string false "Only trackers in this category" example(Health)
+// @Param X-Time-Zone header string false "IANA time zone overriding the user's" example(Europe/Istanbul)
 // @Success 200 {object} trackers.TrackersResponse
+// @Failure 400 {string} string "Bad Request"
+// @Security BearerAuth
 // @Router /trackers [get]
-func GetAllTrackers(w http.ResponseWriter, r *http.Request) {
-	habits, err := database.GetAllHabitTrackers()
+func (h *Handler) GetAllTrackers(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+	filter, err := archiveFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	habits, err := h.Store.GetAllHabitTrackers(userID, filter)
 	if err != nil {
 		http.Error(w, "Failed to get habit trackers: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	targets, err := database.GetAllTargetTrackers()
+	targets, err := h.Store.GetAllTargetTrackers(userID, filter)
 
 	if err != nil {
 		http.Error(w, "Failed to get target trackers: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	durations, err := h.Store.GetAllDurationTrackers(userID, filter)
+	if err != nil {
+		http.Error(w, "Failed to get duration trackers: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	today, err := h.today(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	// Calculate current values for all target trackers
 	for i := range targets {
-		currentValue, err := database.CalculateCurrentValue(&targets[i])
-		if err != nil {
-			// Log error but continue with start value
-			currentValue = targets[i].StartValue
-		}
-		targets[i].CurrentValue = &currentValue
-
-		// Set original start value (always the database value)
-		targets[i].OriginalStartValue = targets[i].StartValue
+		h.setCurrentValues(&targets[i])
+	}
+	for i := range durations {
+		h.setCurrentTotal(&durations[i], today)
+	}
 
-		// Adjust start value if UseActualBounds is true
-		if targets[i].UseActualBounds {
-			adjustedStartValue, err := database.GetAdjustedStartValue(&targets[i])
-			if err == nil {
-				targets[i].StartValue = adjustedStartValue
-			}
-		}
+	habits, targets, durations, groups, err := h.groupTrackers(r, habits, targets, durations)
+	if err != nil {
+		http.Error(w, "Failed to get categories: "+err.Error(), http.StatusInternalServerError)
+		return
 	}
 
 	response := trackers.TrackersResponse{
-		HabitTrackers:  habits,
-		TargetTrackers: targets,
+		HabitTrackers:    habits,
+		TargetTrackers:   targets,
+		DurationTrackers: durations,
+		Groups:           groups,
+		Order:            trackers.Order(habits, targets, durations),
 	}
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
@@ -63,86 +84,118 @@ func GetAllTrackers(w http.ResponseWriter, r *http.Request) {
 
 // GetDashboard gets dashboard with trackers due for a specific date
 // @Summary Get dashboard
-// @Description Get trackers that are due for a specific date (defaults to today)
+// @Description Get trackers that are due for a specific date (defaults to today) in the user-defined order, also grouped by category. Archived trackers, paused and skipped days are left out.
 // @Tags General
 // @Produce json
 // @Param date query string false "Date in YYYY-MM-DD format (defaults to today)" example(2024-01-15)
+// @Param X-Time-Zone header string false "IANA time zone overriding the user's" example(Europe/Istanbul)
+// @Param tag query string false "Only trackers with this tag" example(morning)
+// @Param category query string false "Only trackers in this category" example(Health)
 // @Success 200 {object} trackers.DashboardResponse
 // @Failure 400 {string} string "Bad Request"
 // @Failure 500 {string} string "Internal Server Error"
+// @Security BearerAuth
 // @Router /dashboard [get]
-func GetDashboard(w http.ResponseWriter, r *http.Request) {
-	// Parse date parameter or default to today
-	var targetDate time.Time
-	var err error
-
-	dateParam := r.URL.Query().Get("date")
-	if dateParam != "" {
-		targetDate, err = time.Parse("2006-01-02", dateParam)
-		if err != nil {
-			http.Error(w, "Invalid date format. Please use YYYY-MM-DD format", http.StatusBadRequest)
-			return
-		}
-	} else {
-		targetDate = time.Now()
+func (h *Handler) GetDashboard(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+	// Parse date parameter or default to today in the user's time zone
+	targetDate, err := h.today(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
-	targetWeekday := strings.ToLower(targetDate.Weekday().String())
-
-	habitTrackers, err := database.GetAllHabitTrackers()
+	habitTrackers, err := h.Store.GetAllHabitTrackers(userID, models.ACTIVE_TRACKERS)
 	if err != nil {
 		http.Error(w, "Failed to get habit trackers: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	targetTrackers, err := database.GetAllTargetTrackers()
+	targetTrackers, err := h.Store.GetAllTargetTrackers(userID, models.ACTIVE_TRACKERS)
 	if err != nil {
 		http.Error(w, "Failed to get target trackers: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	durationTrackers, err := h.Store.GetAllDurationTrackers(userID, models.ACTIVE_TRACKERS)
+	if err != nil {
+		http.Error(w, "Failed to get duration trackers: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	daysOff, err := h.allDaysOff(userID, targetDate.Location())
+	if err != nil {
+		http.Error(w, "Failed to get pauses: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	var dashboardHabits []habit.HabitTracker
 	var dashboardTargets []target.TargetTracker
+	var dashboardDurations []duration.DurationTracker
 
-	// Check habit trackers
+	// Check habit trackers, paused and skipped days are left out
 	for _, habit := range habitTrackers {
-		if trackers.IsTrackerDueToday(habit.Due, habit.StartDate, targetDate, targetWeekday) {
+		if trackers.IsTrackerDueToday(habit.Due, habit.StartDate, targetDate) && !daysOff.Contains(models.HABIT, habit.ID, targetDate) {
 			dashboardHabits = append(dashboardHabits, habit)
 		}
 	}
 
 	// Check target trackers
 	for _, target := range targetTrackers {
-		if trackers.IsTrackerDueToday(target.Due, target.StartDate, targetDate, targetWeekday) {
-			// Calculate current value for the target tracker
-			currentValue, err := database.CalculateCurrentValue(&target)
-			if err != nil {
-				// Log error but continue with start value
-				currentValue = target.StartValue
-			}
-			target.CurrentValue = &currentValue
-
-			// Set original start value (always the database value)
-			target.OriginalStartValue = target.StartValue
-
-			// Adjust start value if UseActualBounds is true
-			if target.UseActualBounds {
-				adjustedStartValue, err := database.GetAdjustedStartValue(&target)
-				if err == nil {
-					target.StartValue = adjustedStartValue
-				}
-			}
-
+		if trackers.IsTrackerDueToday(target.Due, target.StartDate, targetDate) && !daysOff.Contains(models.TARGET, target.ID, targetDate) {
+			h.setCurrentValues(&target)
 			dashboardTargets = append(dashboardTargets, target)
 		}
 	}
 
+	// Check duration trackers, with the time spent in the current period
+	for _, d := range durationTrackers {
+		if trackers.IsTrackerDueToday(d.Due, d.StartDate, targetDate) && !daysOff.Contains(models.DURATION, d.ID, targetDate) {
+			h.setCurrentTotal(&d, targetDate)
+			dashboardDurations = append(dashboardDurations, d)
+		}
+	}
+
+	dashboardHabits, dashboardTargets, dashboardDurations, groups, err := h.groupTrackers(r, dashboardHabits, dashboardTargets, dashboardDurations)
+	if err != nil {
+		http.Error(w, "Failed to get categories: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	response := trackers.DashboardResponse{
-		Date:           targetDate.Format("2006-01-02"),
-		HabitTrackers:  dashboardHabits,
-		TargetTrackers: dashboardTargets,
+		Date:             targetDate.Format("2006-01-02"),
+		HabitTrackers:    dashboardHabits,
+		TargetTrackers:   dashboardTargets,
+		DurationTrackers: dashboardDurations,
+		Groups:           groups,
+		Order:            trackers.Order(dashboardHabits, dashboardTargets, dashboardDurations),
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
+
+// setCurrentValues sets the goal in effect now and the current value of a
+// target tracker and, with UseActualBounds, moves its start value to the
+// actual one
+func (h *Handler) setCurrentValues(t *target.TargetTracker) {
+	*t = t.At(h.Now())
+
+	currentValue, err := h.Store.CalculateCurrentValue(t)
+	if err != nil {
+		// Log error but continue with start value
+		currentValue = t.StartValue
+	}
+	t.CurrentValue = &currentValue
+
+	// Set original start value (always the database value)
+	t.OriginalStartValue = t.StartValue
+
+	// Adjust start value if UseActualBounds is true
+	if t.UseActualBounds {
+		adjustedStartValue, err := h.Store.GetAdjustedStartValue(t)
+		if err == nil {
+			t.StartValue = adjustedStartValue
+		}
+	}
+}
@@ -0,0 +1,375 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"routine-tracker/auth"
+	"routine-tracker/models"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// csvColumns is the column order of exported entry files. New columns are
+// added at the end, so that files without a header row keep their meaning.
+var csvColumns = []string{"date", "value", "done", "note", "skipped", "duration"}
+
+// ExportHabitEntriesCSV exports the entries of a habit tracker as CSV
+// @Summary Export habit entries as CSV
+// @Description Download all entries of a habit tracker with the columns date, value, done, note, skipped and duration
+// @Tags Habit Trackers
+// @Security BearerAuth
+// @Produce text/csv
+// @Param id path int true "Habit Tracker ID"
+// @Success 200 {string} string "CSV file"
+// @Failure 404 {string} string "Not Found"
+// @Router /habit-trackers/{id}/entries.csv [get]
+func (h *Handler) ExportHabitEntriesCSV(w http.ResponseWriter, r *http.Request) {
+	h.exportEntriesCSV(w, r, models.HABIT)
+}
+
+// ExportTargetEntriesCSV exports the entries of a target tracker as CSV
+// @Summary Export target entries as CSV
+// @Description Download all entries of a target tracker with the columns date, value, done, note, skipped and duration
+// @Tags Target Trackers
+// @Security BearerAuth
+// @Produce text/csv
+// @Param id path int true "Target Tracker ID"
+// @Success 200 {string} string "CSV file"
+// @Failure 404 {string} string "Not Found"
+// @Router /target-trackers/{id}/entries.csv [get]
+func (h *Handler) ExportTargetEntriesCSV(w http.ResponseWriter, r *http.Request) {
+	h.exportEntriesCSV(w, r, models.TARGET)
+}
+
+// ExportDurationEntriesCSV exports the entries of a duration tracker as CSV
+// @Summary Export duration entries as CSV
+// @Description Download all entries of a duration tracker with the columns date, value, done, note, skipped and duration
+// @Tags Duration Trackers
+// @Security BearerAuth
+// @Produce text/csv
+// @Param id path int true "Duration Tracker ID"
+// @Success 200 {string} string "CSV file"
+// @Failure 404 {string} string "Not Found"
+// @Router /duration-trackers/{id}/entries.csv [get]
+func (h *Handler) ExportDurationEntriesCSV(w http.ResponseWriter, r *http.Request) {
+	h.exportEntriesCSV(w, r, models.DURATION)
+}
+
+// ImportHabitEntriesCSV imports habit entries from CSV
+// @Summary Import habit entries from CSV
+// @Description Upload a CSV file (raw body or multipart field "file") with the columns date, value, done, note, skipped and duration.
+// @Description Dates are RFC3339 or YYYY-MM-DD, done defaults to true unless the day is skipped. Rows matching an existing entry are skipped, so uploading the same file twice is safe.
+// @Tags Habit Trackers
+// @Security BearerAuth
+// @Accept text/csv
+// @Produce json
+// @Param id path int true "Habit Tracker ID"
+// @Success 200 {object} models.CSVImportResult
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Not Found"
+// @Router /habit-trackers/{id}/entries.csv [post]
+func (h *Handler) ImportHabitEntriesCSV(w http.ResponseWriter, r *http.Request) {
+	h.importEntriesCSV(w, r, models.HABIT)
+}
+
+// ImportTargetEntriesCSV imports target entries from CSV
+// @Summary Import target entries from CSV
+// @Description Upload a CSV file (raw body or multipart field "file") with the columns date, value, done, note, skipped and duration.
+// @Description Dates are RFC3339 or YYYY-MM-DD, value is required and target entries cannot be skipped. Rows matching an existing entry are skipped, so uploading the same file twice is safe.
+// @Tags Target Trackers
+// @Security BearerAuth
+// @Accept text/csv
+// @Produce json
+// @Param id path int true "Target Tracker ID"
+// @Success 200 {object} models.CSVImportResult
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Not Found"
+// @Router /target-trackers/{id}/entries.csv [post]
+func (h *Handler) ImportTargetEntriesCSV(w http.ResponseWriter, r *http.Request) {
+	h.importEntriesCSV(w, r, models.TARGET)
+}
+
+// ImportDurationEntriesCSV imports duration entries from CSV
+// @Summary Import duration entries from CSV
+// @Description Upload a CSV file (raw body or multipart field "file") with the columns date, value, done, note, skipped and duration.
+// @Description Dates are RFC3339 or YYYY-MM-DD, duration is required in seconds and duration entries cannot be skipped. Rows matching an existing entry are skipped, so uploading the same file twice is safe.
+// @Tags Duration Trackers
+// @Security BearerAuth
+// @Accept text/csv
+// @Produce json
+// @Param id path int true "Duration Tracker ID"
+// @Success 200 {object} models.CSVImportResult
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Not Found"
+// @Router /duration-trackers/{id}/entries.csv [post]
+func (h *Handler) ImportDurationEntriesCSV(w http.ResponseWriter, r *http.Request) {
+	h.importEntriesCSV(w, r, models.DURATION)
+}
+
+func (h *Handler) exportEntriesCSV(w http.ResponseWriter, r *http.Request, trackerType models.TrackerType) {
+	userID := auth.UserID(r.Context())
+	trackerID, ok := h.csvTracker(w, r, trackerType)
+	if !ok {
+		return
+	}
+
+	entries, err := h.Store.GetAllTrackerEntries(userID, trackerID, trackerType)
+	if err != nil {
+		http.Error(w, "Failed to get entries: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	filename := fmt.Sprintf("%s-tracker-%d-entries.csv", trackerType, trackerID)
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+
+	// Oldest first, the way spreadsheets are usually read
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Date.Before(entries[j].Date)
+	})
+
+	writer := csv.NewWriter(w)
+	writer.Write(csvColumns)
+	for _, e := range entries {
+		value, done, skipped, seconds := "", "", "", ""
+		if trackerType == models.TARGET {
+			value = strconv.FormatFloat(e.Value, 'f', -1, 64)
+		}
+		if e.Done != nil {
+			done = strconv.FormatBool(*e.Done)
+		}
+		if trackerType == models.HABIT {
+			skipped = strconv.FormatBool(e.Skipped)
+		}
+		if e.Duration != nil {
+			seconds = strconv.Itoa(*e.Duration)
+		}
+		writer.Write([]string{e.Date.UTC().Format(time.RFC3339), value, done, e.Note, skipped, seconds})
+	}
+	writer.Flush()
+}
+
+func (h *Handler) importEntriesCSV(w http.ResponseWriter, r *http.Request, trackerType models.TrackerType) {
+	userID := auth.UserID(r.Context())
+	trackerID, ok := h.csvTracker(w, r, trackerType)
+	if !ok {
+		return
+	}
+
+	var body io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, "Missing CSV file in form field 'file'", http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		http.Error(w, "Invalid CSV: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// The header row is optional, without it the export column order is assumed
+	columns := make(map[string]int)
+	for i, name := range csvColumns {
+		columns[name] = i
+	}
+	firstLine := 1
+	if len(records) > 0 {
+		header := make(map[string]int)
+		for i, name := range records[0] {
+			header[strings.ToLower(strings.TrimSpace(name))] = i
+		}
+		if _, ok := header["date"]; ok {
+			columns = header
+			records = records[1:]
+			firstLine = 2
+		}
+	}
+
+	// Dates without a time of day are days in the user's time zone
+	loc, err := h.location(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	existing, err := h.Store.GetAllTrackerEntries(userID, trackerID, trackerType)
+	if err != nil {
+		http.Error(w, "Failed to get entries: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	result := models.CSVImportResult{Errors: make([]models.CSVLineError, 0)}
+	newEntries := make([]models.Entry, 0, len(records))
+	for i, record := range records {
+		line := firstLine + i
+		entry, dateOnly, err := parseCSVEntry(record, columns, trackerType, loc)
+		if err != nil {
+			result.Errors = append(result.Errors, models.CSVLineError{Line: line, Error: err.Error()})
+			continue
+		}
+		entry.TrackerID = trackerID
+
+		if isDuplicateEntry(existing, entry, dateOnly, loc) || isDuplicateEntry(newEntries, entry, dateOnly, loc) {
+			result.Duplicates++
+			continue
+		}
+		newEntries = append(newEntries, entry)
+	}
+
+	if err := h.Store.CreateEntries(userID, newEntries); err != nil {
+		http.Error(w, "Failed to create entries: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	result.Created = len(newEntries)
+
+	entity := models.AUDIT_HABIT_TRACKER
+	switch trackerType {
+	case models.TARGET:
+		entity = models.AUDIT_TARGET_TRACKER
+	case models.DURATION:
+		entity = models.AUDIT_DURATION_TRACKER
+	}
+	h.audit(r, models.AUDIT_IMPORT, entity, trackerID, nil, result)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// csvTracker reads the tracker ID from the path and checks that it exists
+func (h *Handler) csvTracker(w http.ResponseWriter, r *http.Request, trackerType models.TrackerType) (int, bool) {
+	userID := auth.UserID(r.Context())
+	trackerID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid tracker ID", http.StatusBadRequest)
+		return 0, false
+	}
+
+	switch trackerType {
+	case models.HABIT:
+		_, err = h.Store.GetHabitTrackerByID(userID, trackerID)
+	case models.TARGET:
+		_, err = h.Store.GetTargetTrackerByID(userID, trackerID)
+	case models.DURATION:
+		_, err = h.Store.GetDurationTrackerByID(userID, trackerID)
+	}
+	if err != nil {
+		http.Error(w, "Tracker not found", http.StatusNotFound)
+		return 0, false
+	}
+
+	return trackerID, true
+}
+
+// parseCSVEntry turns a CSV record into an entry
+func parseCSVEntry(record []string, columns map[string]int, trackerType models.TrackerType, loc *time.Location) (models.Entry, bool, error) {
+	field := func(name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	entry := models.Entry{Type: trackerType, Note: field("note")}
+
+	if field("date") == "" {
+		return entry, false, errors.New("date is required")
+	}
+	date, dateOnly, err := parseEntryDate(field("date"), loc)
+	if err != nil {
+		return entry, false, err
+	}
+	entry.Date = date
+
+	if value := field("value"); value != "" {
+		entry.Value, err = strconv.ParseFloat(value, 64)
+		if err != nil {
+			return entry, false, fmt.Errorf("invalid value '%s'", value)
+		}
+	} else if trackerType == models.TARGET {
+		return entry, false, errors.New("value is required")
+	}
+
+	if trackerType == models.DURATION {
+		seconds, err := strconv.Atoi(field("duration"))
+		if err != nil || seconds <= 0 {
+			return entry, false, fmt.Errorf("invalid duration '%s', use a positive number of seconds", field("duration"))
+		}
+		entry.Duration = &seconds
+	}
+
+	if value := field("skipped"); value != "" {
+		entry.Skipped, err = parseCSVBool("skipped", value)
+		if err != nil {
+			return entry, false, err
+		}
+	}
+
+	if trackerType == models.HABIT {
+		// Default to true (yes) like new entries from the API, skipped days are not done
+		done := !entry.Skipped
+		if value := field("done"); value != "" {
+			done, err = parseCSVBool("done", value)
+			if err != nil {
+				return entry, false, err
+			}
+		}
+		if done && entry.Skipped {
+			return entry, false, errors.New("an entry cannot be both done and skipped")
+		}
+		entry.Done = &done
+	} else if entry.Skipped {
+		return entry, false, errors.New("only habit entries can be skipped")
+	}
+
+	return entry, dateOnly, nil
+}
+
+func parseCSVBool(column, value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "true", "yes", "y", "1", "x":
+		return true, nil
+	case "false", "no", "n", "0":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid %s value '%s', use true or false", column, value)
+}
+
+// isDuplicateEntry reports whether an equal entry exists. Rows without a
+// time of day match any entry on the same day in loc.
+func isDuplicateEntry(entries []models.Entry, entry models.Entry, dateOnly bool, loc *time.Location) bool {
+	for _, other := range entries {
+		// Exported files are accurate to the second
+		sameDate := other.Date.Truncate(time.Second).Equal(entry.Date.Truncate(time.Second))
+		if dateOnly {
+			sameDate = other.Date.In(loc).Format("2006-01-02") == entry.Date.In(loc).Format("2006-01-02")
+		}
+		if !sameDate || other.Value != entry.Value || other.Note != entry.Note || other.Skipped != entry.Skipped {
+			continue
+		}
+		if (other.Done == nil) != (entry.Done == nil) || (other.Done != nil && *other.Done != *entry.Done) {
+			continue
+		}
+		if (other.Duration == nil) != (entry.Duration == nil) || (other.Duration != nil && *other.Duration != *entry.Duration) {
+			continue
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,550 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"routine-tracker/auth"
+	"routine-tracker/database"
+	"routine-tracker/models"
+	"routine-tracker/trackers"
+	"routine-tracker/trackers/duration"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// GetDurationTrackers gets all duration trackers
+// @Summary Get all duration trackers
+// @Description Retrieve all created duration trackers in the user-defined order with the time spent in the current period, archived ones are left out by default
+// @Tags Duration Trackers
+// @Produce json
+// @Param archived query string false "false for active trackers (default), true for archived ones or all" Enums(false, true, all)
+// @Param tag query string false "Only trackers with this tag" example(morning)
+// @Param category query string false "Only trackers in this category" example(Health)
+// @Param X-Time-Zone header string false "IANA time zone overriding the user's" example(Europe/Istanbul)
+// @Success 200 {array} duration.DurationTracker
+// @Failure 400 {string} string "Bad Request"
+// @Security BearerAuth
+// @Router /duration-trackers [get]
+func (h *Handler) GetDurationTrackers(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+	filter, err := archiveFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	today, err := h.today(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	durations, err := h.Store.GetAllDurationTrackers(userID, filter)
+	if err != nil {
+		http.Error(w, "Failed to get duration trackers: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	for i := range durations {
+		h.setCurrentTotal(&durations[i], today)
+	}
+
+	_, _, durations, _, err = h.groupTrackers(r, nil, nil, durations)
+	if err != nil {
+		http.Error(w, "Failed to get categories: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(durations)
+}
+
+// GetDurationTracker gets a specific duration tracker by ID
+// @Summary Get duration tracker by ID
+// @Description Retrieve a specific duration tracker with its running timer and the time spent in the current period
+// @Tags Duration Trackers
+// @Produce json
+// @Param id path int true "Duration Tracker ID"
+// @Param X-Time-Zone header string false "IANA time zone overriding the user's" example(Europe/Istanbul)
+// @Success 200 {object} duration.DurationTracker
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Not Found"
+// @Security BearerAuth
+// @Router /duration-trackers/{id} [get]
+func (h *Handler) GetDurationTracker(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+	trackerID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid tracker ID", http.StatusBadRequest)
+		return
+	}
+	today, err := h.today(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tracker, err := h.Store.GetDurationTrackerByID(userID, trackerID)
+	if err != nil {
+		http.Error(w, "Duration tracker not found", http.StatusNotFound)
+		return
+	}
+	h.setCurrentTotal(tracker, today)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tracker)
+}
+
+// GetDurationTrackerStats gets the per-period totals of a duration tracker
+// @Summary Get duration tracker stats
+// @Description Get the time spent in each period towards the goal, the total time and the completion rate
+// @Tags Duration Trackers
+// @Produce json
+// @Param id path int true "Duration Tracker ID"
+// @Param date query string false "Calculate stats as of this date in YYYY-MM-DD format (defaults to today)" example(2024-01-15)
+// @Success 200 {object} duration.Stats
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Not Found"
+// @Failure 500 {string} string "Internal Server Error"
+// @Security BearerAuth
+// @Router /duration-trackers/{id}/stats [get]
+func (h *Handler) GetDurationTrackerStats(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+	trackerID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid tracker ID", http.StatusBadRequest)
+		return
+	}
+
+	today, err := h.today(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tracker, err := h.Store.GetDurationTrackerByID(userID, trackerID)
+	if err != nil {
+		http.Error(w, "Duration tracker not found", http.StatusNotFound)
+		return
+	}
+
+	entries, err := h.Store.GetEntriesByTracker(userID, trackerID, string(models.DURATION))
+	if err != nil {
+		http.Error(w, "Failed to get entries: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	daysOff, err := h.daysOff(userID, entries, today.Location())
+	if err != nil {
+		http.Error(w, "Failed to get pauses: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	stats := duration.CalculateStats(*tracker, entries, daysOff, today)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stats)
+}
+
+// CreateDurationTracker creates a new duration tracker
+// @Summary Create duration tracker
+// @Description Create a new duration tracker with a goal in seconds per time period
+// @Tags Duration Trackers
+// @Accept json
+// @Produce json
+// @Param tracker body duration.CreateDurationRequest true "Duration tracker configuration"
+// @Success 201 {object} duration.DurationTracker
+// @Failure 400 {string} string "Bad Request"
+// @Security BearerAuth
+// @Router /duration-trackers [post]
+func (h *Handler) CreateDurationTracker(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+	var req duration.CreateDurationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		http.Error(w, "Invalid start_date format. Use YYYY-MM-DD", http.StatusBadRequest)
+		return
+	}
+	if err := validateDurationGoal(req.Goal, req.TimePeriod); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := trackers.ValidateDue(req.Due); err != nil {
+		http.Error(w, "Invalid due: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.checkLabels(userID, req.CategoryID, req.TagIDs); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	reminders := req.Reminders
+	if len(reminders.Times) == 0 && reminders.Enabled {
+		reminders = models.Reminder{
+			Times:   []string{"18:00"},
+			Enabled: true,
+		}
+	}
+
+	tracker := duration.DurationTracker{
+		TrackerName: req.TrackerName,
+		Goal:        req.Goal,
+		TimePeriod:  req.TimePeriod,
+		StartDate:   startDate,
+		Due:         req.Due,
+		Reminders:   reminders,
+		Pinned:      req.Pinned,
+		CreatedAt:   h.Now(),
+	}
+
+	created, err := h.Store.CreateDurationTracker(userID, tracker)
+	if err != nil {
+		http.Error(w, "Failed to create duration tracker: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if req.CategoryID != nil || len(req.TagIDs) > 0 {
+		if err := h.setLabels(userID, models.DURATION, created.ID, req.CategoryID, &req.TagIDs); err != nil {
+			http.Error(w, "Failed to set category and tags: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if created, err = h.Store.GetDurationTrackerByID(userID, created.ID); err != nil {
+			http.Error(w, "Failed to get duration tracker: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	h.audit(r, models.AUDIT_CREATE, models.AUDIT_DURATION_TRACKER, created.ID, nil, created)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// UpdateDurationTracker updates a duration tracker
+// @Summary Update duration tracker
+// @Description Update a specific duration tracker (partial updates supported)
+// @Tags Duration Trackers
+// @Accept json
+// @Produce json
+// @Param id path int true "Duration Tracker ID"
+// @Param tracker body duration.UpdateDurationRequest true "Updated duration tracker data"
+// @Success 200 {object} duration.DurationTracker
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Not Found"
+// @Security BearerAuth
+// @Router /duration-trackers/{id} [put]
+func (h *Handler) UpdateDurationTracker(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+	trackerID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid tracker ID", http.StatusBadRequest)
+		return
+	}
+
+	var req duration.UpdateDurationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Due != nil {
+		if err := trackers.ValidateDue(*req.Due); err != nil {
+			http.Error(w, "Invalid due: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if req.StartDate != nil {
+		if _, err := time.Parse("2006-01-02", *req.StartDate); err != nil {
+			http.Error(w, "Invalid start_date format. Use YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+	}
+	before, err := h.Store.GetDurationTrackerByID(userID, trackerID)
+	if err != nil {
+		http.Error(w, "Duration tracker not found", http.StatusNotFound)
+		return
+	}
+
+	goal, timePeriod := before.Goal, before.TimePeriod
+	if req.Goal != nil {
+		goal = *req.Goal
+	}
+	if req.TimePeriod != nil {
+		timePeriod = *req.TimePeriod
+	}
+	if err := validateDurationGoal(goal, timePeriod); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var tagIDs []int
+	if req.TagIDs != nil {
+		tagIDs = *req.TagIDs
+	}
+	if err := h.checkLabels(userID, req.CategoryID, tagIDs); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	err = h.Store.UpdateDurationTracker(userID, trackerID, req)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Duration tracker not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to update duration tracker: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := h.setLabels(userID, models.DURATION, trackerID, req.CategoryID, req.TagIDs); err != nil {
+		http.Error(w, "Failed to set category and tags: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	tracker, err := h.Store.GetDurationTrackerByID(userID, trackerID)
+	if err != nil {
+		http.Error(w, "Failed to get duration tracker: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.audit(r, models.AUDIT_UPDATE, models.AUDIT_DURATION_TRACKER, trackerID, before, tracker)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tracker)
+}
+
+// DeleteDurationTracker moves a duration tracker and all its entries to the trash
+// @Summary Delete duration tracker
+// @Description Move a specific duration tracker and all its associated entries to the trash, they can be restored until the trash is purged. A running timer of the tracker is discarded.
+// @Tags Duration Trackers
+// @Param id path int true "Duration Tracker ID"
+// @Success 204 "No Content"
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Not Found"
+// @Security BearerAuth
+// @Router /duration-trackers/{id} [delete]
+func (h *Handler) DeleteDurationTracker(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+	trackerID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid tracker ID", http.StatusBadRequest)
+		return
+	}
+
+	before, _ := h.Store.GetDurationTrackerByID(userID, trackerID)
+	err = h.Store.DeleteDurationTracker(userID, trackerID, h.Now())
+	if err == sql.ErrNoRows {
+		http.Error(w, "Duration tracker not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to delete duration tracker: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.audit(r, models.AUDIT_DELETE, models.AUDIT_DURATION_TRACKER, trackerID, before, nil)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AddDurationEntry logs time spent on a duration tracker
+// @Summary Add duration entry
+// @Description Log time spent without a timer, e.g. afterwards. The duration in seconds is required, the entry counts on the day of its date. Entries overlapping the running timer of the tracker are refused. Other entries are not checked for overlaps, overlapping ones both count toward the totals.
+// @Tags Duration Trackers
+// @Accept json
+// @Produce json
+// @Param id path int true "Duration Tracker ID"
+// @Param entry body models.AddEntryRequest true "Entry data"
+// @Success 201 {object} models.Entry
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Not Found"
+// @Failure 409 {string} string "Conflict"
+// @Security BearerAuth
+// @Router /duration-trackers/{id}/entries [post]
+func (h *Handler) AddDurationEntry(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+	trackerID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid tracker ID", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := h.Store.GetDurationTrackerByID(userID, trackerID); err != nil {
+		http.Error(w, "Duration tracker not found", http.StatusNotFound)
+		return
+	}
+
+	var req models.AddEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Duration == nil || *req.Duration <= 0 {
+		http.Error(w, "A duration in seconds is required", http.StatusBadRequest)
+		return
+	}
+
+	entryDate := h.Now().UTC()
+	if req.Date != "" {
+		loc, err := h.location(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		entryDate, _, err = parseEntryDate(req.Date, loc)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	// The running timer becomes an entry of its own when it stops. Manual
+	// entries are not checked against each other, most only have a date.
+	timer, err := h.Store.GetRunningTimer(userID)
+	if err != nil && err != sql.ErrNoRows {
+		http.Error(w, "Failed to get running timer: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if timer != nil && timer.TrackerID == trackerID && entryDate.Add(time.Duration(*req.Duration)*time.Second).After(timer.StartedAt) {
+		http.Error(w, "The entry overlaps the running timer, stop it first", http.StatusConflict)
+		return
+	}
+
+	entry := models.Entry{
+		TrackerID: trackerID,
+		Type:      models.DURATION,
+		Duration:  req.Duration,
+		Date:      entryDate,
+		Note:      req.Note,
+		CreatedAt: h.Now(),
+	}
+
+	created, err := h.Store.CreateEntry(userID, entry)
+	if err != nil {
+		http.Error(w, "Failed to create entry: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.audit(r, models.AUDIT_CREATE, models.AUDIT_ENTRY, created.ID, nil, created)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// StartDurationTimer starts a timer on a duration tracker
+// @Summary Start timer
+// @Description Start timing the time spent on a duration tracker. Only one timer can run at a time, starting a second one fails until the running one is stopped. Archived trackers cannot be timed.
+// @Tags Duration Trackers
+// @Accept json
+// @Produce json
+// @Param id path int true "Duration Tracker ID"
+// @Param timer body duration.StartTimerRequest false "Note of the entry the timer becomes"
+// @Success 201 {object} duration.Timer
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Not Found"
+// @Failure 409 {string} string "Conflict"
+// @Security BearerAuth
+// @Router /duration-trackers/{id}/timer/start [post]
+func (h *Handler) StartDurationTimer(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+	trackerID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid tracker ID", http.StatusBadRequest)
+		return
+	}
+
+	// The body is optional
+	var req duration.StartTimerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		http.Error(w, "Invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if _, err := h.Store.GetDurationTrackerByID(userID, trackerID); err != nil {
+		http.Error(w, "Duration tracker not found", http.StatusNotFound)
+		return
+	}
+
+	timer, err := h.Store.StartTimer(userID, trackerID, h.Now(), req.Note)
+	if errors.Is(err, database.ErrTimerRunning) {
+		http.Error(w, "A timer is already running, stop it first", http.StatusConflict)
+		return
+	}
+	if errors.Is(err, database.ErrTrackerArchived) {
+		http.Error(w, "Duration tracker is archived, unarchive it first", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to start timer: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.audit(r, models.AUDIT_CREATE, models.AUDIT_TIMER, timer.ID, nil, timer)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(timer)
+}
+
+// StopDurationTimer stops the running timer of a duration tracker
+// @Summary Stop timer
+// @Description Stop the running timer of a duration tracker. It becomes an entry with the time since the start in seconds, dated at the start of the timer.
+// @Tags Duration Trackers
+// @Produce json
+// @Param id path int true "Duration Tracker ID"
+// @Success 201 {object} models.Entry
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Not Found"
+// @Security BearerAuth
+// @Router /duration-trackers/{id}/timer/stop [post]
+func (h *Handler) StopDurationTimer(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+	trackerID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid tracker ID", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := h.Store.GetDurationTrackerByID(userID, trackerID); err != nil {
+		http.Error(w, "Duration tracker not found", http.StatusNotFound)
+		return
+	}
+
+	entry, err := h.Store.StopTimer(userID, trackerID, h.Now())
+	if err == sql.ErrNoRows {
+		http.Error(w, "No timer is running on this tracker", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to stop timer: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.audit(r, models.AUDIT_CREATE, models.AUDIT_ENTRY, entry.ID, nil, entry)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(entry)
+}
+
+// setCurrentTotal sets the time spent in the current period of a duration
+// tracker, it is left out when the entries cannot be read
+func (h *Handler) setCurrentTotal(d *duration.DurationTracker, today time.Time) {
+	entries, err := h.Store.GetEntriesByTracker(d.UserID, d.ID, string(models.DURATION))
+	if err != nil {
+		return
+	}
+	total := duration.CurrentTotal(*d, entries, today)
+	d.CurrentTotal = &total
+}
+
+// validateDurationGoal checks the goal and time period of a duration tracker
+func validateDurationGoal(goal int, timePeriod models.TimePeriod) error {
+	if goal <= 0 {
+		return errors.New("Goal must be a positive number of seconds")
+	}
+	if !timePeriod.Valid() {
+		return errors.New("Invalid timePeriod. Use 'perDay', 'perWeek', 'perMonth' or 'perYear'")
+	}
+	return nil
+}
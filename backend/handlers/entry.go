@@ -3,23 +3,27 @@ package handlers
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"github.com/gorilla/mux"
 	"net/http"
+	"routine-tracker/auth"
 	"routine-tracker/database"
 	"routine-tracker/models"
 	"strconv"
+	"time"
 )
 
-
 // GetAllEntries gets all entries
 // @Summary Get all entries
 // @Description Retrieve all tracking entries
 // @Tags General
 // @Produce json
 // @Success 200 {array} models.Entry
+// @Security BearerAuth
 // @Router /entries [get]
-func GetAllEntries(w http.ResponseWriter, r *http.Request) {
-  entries, _ := database.GetAllEntries()
+func (h *Handler) GetAllEntries(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+	entries, _ := h.Store.GetAllEntries(userID)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(entries)
 }
@@ -29,12 +33,14 @@ func GetAllEntries(w http.ResponseWriter, r *http.Request) {
 // @Description Get all entries for a specific tracker
 // @Tags General
 // @Produce json
-// @Param type path string true "Tracker type" Enums(habit, target)
+// @Param type path string true "Tracker type" Enums(habit, target, duration)
 // @Param id path int true "Tracker ID"
 // @Success 200 {array} models.Entry
 // @Failure 400 {string} string "Bad Request"
+// @Security BearerAuth
 // @Router /{type}-trackers/{id}/entries [get]
-func GetTrackerEntries(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) GetTrackerEntries(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
 	vars := mux.Vars(r)
 	trackerID, err := strconv.Atoi(vars["id"])
 	if err != nil {
@@ -43,27 +49,33 @@ func GetTrackerEntries(w http.ResponseWriter, r *http.Request) {
 	}
 
 	trackerType := vars["type"]
-	if trackerType != "habit" && trackerType != "target" {
-		http.Error(w, "Invalid tracker type. Use 'habit' or 'target'", http.StatusBadRequest)
+	if trackerType != "habit" && trackerType != "target" && trackerType != "duration" {
+		http.Error(w, "Invalid tracker type. Use 'habit', 'target' or 'duration'", http.StatusBadRequest)
 		return
 	}
 
-  entries, err := database.GetEntriesByTracker(trackerID, trackerType)
+	entries, err := h.Store.GetEntriesByTracker(userID, trackerID, trackerType)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Tracker not found", http.StatusNotFound)
+		return
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(entries)
 }
 
-// DeleteEntry deletes a specific entry
+// DeleteEntry moves a specific entry to the trash
 // @Summary Delete entry
-// @Description Delete a specific entry by ID
+// @Description Move a specific entry to the trash, it can be restored until the trash is purged
 // @Tags General
 // @Param id path int true "Entry ID"
 // @Success 204 "No Content"
 // @Failure 400 {string} string "Bad Request"
 // @Failure 404 {string} string "Entry not found"
+// @Security BearerAuth
 // @Router /entries/{id} [delete]
-func DeleteEntry(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) DeleteEntry(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
 	vars := mux.Vars(r)
 	entryID, err := strconv.Atoi(vars["id"])
 	if err != nil {
@@ -71,7 +83,8 @@ func DeleteEntry(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = database.DeleteEntry(entryID)
+	before, _ := h.Store.GetEntryByID(userID, entryID)
+	err = h.Store.DeleteEntry(userID, entryID, h.Now())
 	if err != nil {
 		if err.Error() == "sql: no rows in result set" {
 			http.Error(w, "Entry not found", http.StatusNotFound)
@@ -80,23 +93,28 @@ func DeleteEntry(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Failed to delete entry", http.StatusInternalServerError)
 		return
 	}
+	h.audit(r, models.AUDIT_DELETE, models.AUDIT_ENTRY, entryID, before, nil)
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
 // UpdateEntry updates a specific entry
 // @Summary Update entry
-// @Description Update a specific entry by ID
+// @Description Update a specific entry by ID. Skipping a habit entry marks it as not done, marking it as done un-skips it.
+// @Description A date without a time is midnight in the user's time zone.
 // @Tags General
 // @Accept json
 // @Produce json
 // @Param id path int true "Entry ID"
 // @Param entry body models.UpdateEntryRequest true "Update entry request"
+// @Param X-Time-Zone header string false "IANA time zone overriding the user's" example(Europe/Istanbul)
 // @Success 200 {object} models.Entry
 // @Failure 400 {string} string "Bad Request"
 // @Failure 404 {string} string "Entry not found"
+// @Security BearerAuth
 // @Router /entries/{id} [put]
-func UpdateEntry(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) UpdateEntry(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
 	vars := mux.Vars(r)
 	entryID, err := strconv.Atoi(vars["id"])
 	if err != nil {
@@ -110,47 +128,112 @@ func UpdateEntry(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	updatedEntry, err := database.UpdateEntry(entryID, req)
+	if req.Skipped != nil && *req.Skipped && req.Done != nil && *req.Done {
+		http.Error(w, "An entry cannot be both done and skipped", http.StatusBadRequest)
+		return
+	}
+	if req.Duration != nil && *req.Duration <= 0 {
+		http.Error(w, "Duration must be a positive number of seconds", http.StatusBadRequest)
+		return
+	}
+	// Date-only values are midnight in the user's time zone, like new entries
+	if req.Date != nil {
+		loc, err := h.location(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		date, _, err := parseEntryDate(*req.Date, loc)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		normalized := date.Format(time.RFC3339Nano)
+		req.Date = &normalized
+	}
+
+	before, _ := h.Store.GetEntryByID(userID, entryID)
+	updatedEntry, err := h.Store.UpdateEntry(userID, entryID, req)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			http.Error(w, "Entry not found", http.StatusNotFound)
 			return
 		}
+		if errors.Is(err, database.ErrSkippedTarget) {
+			http.Error(w, "Only habit entries can be skipped", http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, database.ErrNotDuration) {
+			http.Error(w, "Only duration entries have a duration", http.StatusBadRequest)
+			return
+		}
 		http.Error(w, "Failed to update entry: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
+	h.audit(r, models.AUDIT_UPDATE, models.AUDIT_ENTRY, entryID, before, updatedEntry)
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(updatedEntry)
 }
 
-// BulkDeleteEntries deletes multiple entries
+// BulkDeleteEntries moves multiple entries to the trash
 // @Summary Bulk delete entries
-// @Description Delete multiple entries by their IDs
+// @Description Move multiple entries to the trash by their IDs, they can be restored until the trash is purged
 // @Tags General
 // @Accept json
 // @Param ids body []int true "Array of entry IDs to delete"
 // @Success 204 "No Content"
 // @Failure 400 {string} string "Bad Request"
+// @Security BearerAuth
 // @Router /entries [delete]
-func BulkDeleteEntries(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) BulkDeleteEntries(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
 	var entryIDs []int
-	
+
 	if err := json.NewDecoder(r.Body).Decode(&entryIDs); err != nil {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
-	
+
 	if len(entryIDs) == 0 {
 		http.Error(w, "No entry IDs provided", http.StatusBadRequest)
 		return
 	}
-	
-	err := database.BulkDeleteEntries(entryIDs)
+
+	// Entries of other users or already deleted ones are left out of the audit log
+	deleted := make([]*models.Entry, 0, len(entryIDs))
+	for _, id := range entryIDs {
+		if entry, err := h.Store.GetEntryByID(userID, id); err == nil {
+			deleted = append(deleted, entry)
+		}
+	}
+
+	err := h.Store.BulkDeleteEntries(userID, entryIDs, h.Now())
 	if err != nil {
 		http.Error(w, "Failed to delete entries", http.StatusInternalServerError)
 		return
 	}
-	
+	for _, entry := range deleted {
+		h.audit(r, models.AUDIT_DELETE, models.AUDIT_ENTRY, entry.ID, entry, nil)
+	}
+
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// parseEntryDate parses an entry date given as RFC3339 or YYYY-MM-DD and
+// returns it in UTC. Date-only values are the start of that day in loc,
+// dateOnly reports that the time was not given.
+func parseEntryDate(value string, loc *time.Location) (date time.Time, dateOnly bool, err error) {
+	// Try RFC3339 format first (datetime with timezone)
+	date, err = time.Parse(time.RFC3339, value)
+	if err == nil {
+		return date.UTC(), false, nil
+	}
+
+	// Try date-only format - midnight in the user's time zone, then convert to UTC
+	date, err = time.ParseInLocation("2006-01-02", value, loc)
+	if err != nil {
+		return time.Time{}, false, errors.New("Invalid date format. Use YYYY-MM-DD or RFC3339 (2006-01-02T15:04:05Z07:00)")
+	}
+	return date.UTC(), true, nil
+}
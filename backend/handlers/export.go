@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"routine-tracker/auth"
+	"routine-tracker/importer"
+	"routine-tracker/models"
+	"routine-tracker/trackers"
+	"strings"
+)
+
+// ExportData exports all data of the current user
+// @Summary Export all data
+// @Description Download every habit tracker, target tracker and entry as a versioned JSON archive that can be imported again
+// @Tags Import & Export
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} trackers.Archive
+// @Router /export [get]
+func (h *Handler) ExportData(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+	archive, err := h.Store.ExportArchive(userID, h.Now())
+	if err != nil {
+		http.Error(w, "Failed to export data: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	filename := "tracker-export-" + h.Now().Format("2006-01-02") + ".json"
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	json.NewEncoder(w).Encode(archive)
+}
+
+// ImportData imports a JSON archive
+// @Summary Import data
+// @Description Import an archive created by the export endpoint in a single transaction. IDs are reassigned.
+// @Description In merge mode existing data is kept and trackers with the same type and name are combined, identical entries are skipped.
+// @Description In replace mode all existing trackers and entries are deleted first.
+// @Tags Import & Export
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param mode query string false "Conflict handling: merge or replace" Enums(merge, replace) default(merge)
+// @Param dryRun query bool false "Report what would change without saving anything"
+// @Param archive body trackers.Archive true "Exported archive"
+// @Success 200 {object} trackers.ImportResult
+// @Failure 400 {string} string "Bad Request"
+// @Router /import [post]
+func (h *Handler) ImportData(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+
+	mode, dryRun, ok := importOptions(w, r)
+	if !ok {
+		return
+	}
+
+	var archive trackers.Archive
+	if err := json.NewDecoder(r.Body).Decode(&archive); err != nil {
+		http.Error(w, "Invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := archive.Validate(); err != nil {
+		http.Error(w, "Invalid archive: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.Store.ImportArchive(userID, archive, mode, dryRun, h.Now())
+	if err != nil {
+		http.Error(w, "Failed to import data: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !dryRun {
+		h.audit(r, models.AUDIT_IMPORT, models.AUDIT_USER, userID, nil, result)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// ImportLoopBackup imports a Loop Habit Tracker backup
+// @Summary Import Loop Habit Tracker backup
+// @Description Import the SQLite backup (.db) or the zipped CSV export of the Loop Habit Tracker Android app, as raw body or multipart field "file".
+// @Description Yes/no habits become habit trackers, numerical habits become target trackers and checkmarks become entries.
+// @Description Settings and data without an equivalent are listed in unmapped.
+// @Tags Import & Export
+// @Security BearerAuth
+// @Accept octet-stream
+// @Produce json
+// @Param mode query string false "Conflict handling: merge or replace" Enums(merge, replace) default(merge)
+// @Param dryRun query bool false "Report what would change without saving anything"
+// @Success 200 {object} importer.LoopImportResult
+// @Failure 400 {string} string "Bad Request"
+// @Router /import/loop [post]
+func (h *Handler) ImportLoopBackup(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+
+	mode, dryRun, ok := importOptions(w, r)
+	if !ok {
+		return
+	}
+
+	var body io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, "Missing backup file in form field 'file'", http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		http.Error(w, "Failed to read backup: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	habits, err := importer.ParseLoopBackup(data)
+	if err != nil {
+		http.Error(w, "Invalid backup: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	loc, err := h.location(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	archive, unmapped := importer.LoopArchive(habits, h.Now(), loc)
+	result, err := h.Store.ImportArchive(userID, archive, mode, dryRun, h.Now())
+	if err != nil {
+		http.Error(w, "Failed to import backup: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !dryRun {
+		h.audit(r, models.AUDIT_IMPORT, models.AUDIT_USER, userID, nil, result)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(importer.LoopImportResult{ImportResult: *result, Unmapped: unmapped})
+}
+
+// importOptions reads the mode and dryRun query parameters of imports
+func importOptions(w http.ResponseWriter, r *http.Request) (trackers.ImportMode, bool, bool) {
+	mode := trackers.ImportMode(r.URL.Query().Get("mode"))
+	if mode == "" {
+		mode = trackers.IMPORT_MERGE
+	}
+	if mode != trackers.IMPORT_MERGE && mode != trackers.IMPORT_REPLACE {
+		http.Error(w, "Invalid mode. Use 'merge' or 'replace'", http.StatusBadRequest)
+		return "", false, false
+	}
+	return mode, r.URL.Query().Get("dryRun") == "true", true
+}
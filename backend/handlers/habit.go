@@ -1,12 +1,14 @@
 package handlers
 
 import (
+	"database/sql"
 	"encoding/json"
 	"net/http"
 
-	"routine-tracker/models"
 	"github.com/gorilla/mux"
-	"routine-tracker/database"
+	"routine-tracker/auth"
+	"routine-tracker/models"
+	"routine-tracker/trackers"
 	"routine-tracker/trackers/habit"
 	"strconv"
 	"time"
@@ -14,23 +16,39 @@ import (
 
 // GetHabitTrackers gets all habit trackers
 // @Summary Get all habit trackers
-// @Description Retrieve all created habit trackers
+// @Description Retrieve all created habit trackers in the user-defined order, archived ones are left out by default
 // @Tags Habit Trackers
 // @Produce json
+// @Param archived query string false "false for active trackers (default), true for archived ones or all" Enums(false, true, all)
+// @Param tag query string false "Only trackers with this tag" example(morning)
+// @Param category query string false "Only trackers in this category" example(Health)
 // @Success 200 {array} habit.HabitTracker
+// @Failure 400 {string} string "Bad Request"
+// @Security BearerAuth
 // @Router /habit-trackers [get]
-func GetHabitTrackers(w http.ResponseWriter, r *http.Request) {
-	habits, err := database.GetAllHabitTrackers()
+func (h *Handler) GetHabitTrackers(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+	filter, err := archiveFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	habits, err := h.Store.GetAllHabitTrackers(userID, filter)
 	if err != nil {
 		http.Error(w, "Failed to get habit trackers: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
+	habits, _, _, _, err = h.groupTrackers(r, habits, nil, nil)
+	if err != nil {
+		http.Error(w, "Failed to get categories: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(habits)
 }
 
-
 // GetHabitTracker gets a specific habit tracker by ID
 // @Summary Get habit tracker by ID
 // @Description Retrieve a specific habit tracker with all its details
@@ -40,8 +58,10 @@ func GetHabitTrackers(w http.ResponseWriter, r *http.Request) {
 // @Success 200 {object} habit.HabitTracker
 // @Failure 400 {string} string "Bad Request"
 // @Failure 404 {string} string "Not Found"
+// @Security BearerAuth
 // @Router /habit-trackers/{id} [get]
-func GetHabitTracker(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) GetHabitTracker(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
 	vars := mux.Vars(r)
 	trackerID, err := strconv.Atoi(vars["id"])
 	if err != nil {
@@ -49,7 +69,7 @@ func GetHabitTracker(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tracker, err := database.GetHabitTrackerByID(trackerID)
+	tracker, err := h.Store.GetHabitTrackerByID(userID, trackerID)
 	if err != nil {
 		http.Error(w, "Habit tracker not found", http.StatusNotFound)
 		return
@@ -59,6 +79,58 @@ func GetHabitTracker(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(tracker)
 }
 
+// GetHabitTrackerStats gets streak and period analytics for a habit tracker
+// @Summary Get habit tracker stats
+// @Description Get per-period buckets, current and best streak, completion rate and goal streak progress
+// @Tags Habit Trackers
+// @Produce json
+// @Param id path int true "Habit Tracker ID"
+// @Param date query string false "Calculate stats as of this date in YYYY-MM-DD format (defaults to today)" example(2024-01-15)
+// @Success 200 {object} habit.Stats
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Not Found"
+// @Failure 500 {string} string "Internal Server Error"
+// @Security BearerAuth
+// @Router /habit-trackers/{id}/stats [get]
+func (h *Handler) GetHabitTrackerStats(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+	vars := mux.Vars(r)
+	trackerID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid tracker ID", http.StatusBadRequest)
+		return
+	}
+
+	today, err := h.today(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tracker, err := h.Store.GetHabitTrackerByID(userID, trackerID)
+	if err != nil {
+		http.Error(w, "Habit tracker not found", http.StatusNotFound)
+		return
+	}
+
+	entries, err := h.Store.GetEntriesByTracker(userID, trackerID, "habit")
+	if err != nil {
+		http.Error(w, "Failed to get entries: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	daysOff, err := h.daysOff(userID, entries, today.Location())
+	if err != nil {
+		http.Error(w, "Failed to get pauses: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	stats := habit.CalculateStats(*tracker, entries, daysOff, today)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stats)
+}
+
 // CreateHabitTracker creates a new habit tracker
 // @Summary Create habit tracker
 // @Description Create a new habit tracker with specified configuration
@@ -68,8 +140,10 @@ func GetHabitTracker(w http.ResponseWriter, r *http.Request) {
 // @Param habit body habit.CreateHabitRequest true "Habit tracker configuration"
 // @Success 201 {object} habit.HabitTracker
 // @Failure 400 {string} string "Bad Request"
+// @Security BearerAuth
 // @Router /habit-trackers [post]
-func CreateHabitTracker(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) CreateHabitTracker(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
 	var req habit.CreateHabitRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "Invalid JSON: "+err.Error(), http.StatusBadRequest)
@@ -83,6 +157,17 @@ func CreateHabitTracker(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Check the recurrence rule of RRULE schedules
+	if err := trackers.ValidateDue(req.Due); err != nil {
+		http.Error(w, "Invalid due: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.checkLabels(userID, req.CategoryID, req.TagIDs); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	// Set default reminder if none provided
 	reminders := req.Reminders
 	if len(reminders.Times) == 0 && reminders.Enabled {
@@ -101,30 +186,43 @@ func CreateHabitTracker(w http.ResponseWriter, r *http.Request) {
 		Reminders:   reminders,
 		BadHabit:    req.BadHabit,
 		GoalStreak:  req.GoalStreak,
-		CreatedAt:   time.Now(),
+		CreatedAt:   h.Now(),
 	}
 
-	created, err := database.CreateHabitTracker(tracker)
+	created, err := h.Store.CreateHabitTracker(userID, tracker)
 	if err != nil {
 		http.Error(w, "Failed to create habit tracker: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
+	if req.CategoryID != nil || len(req.TagIDs) > 0 {
+		if err := h.setLabels(userID, models.HABIT, created.ID, req.CategoryID, &req.TagIDs); err != nil {
+			http.Error(w, "Failed to set category and tags: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if created, err = h.Store.GetHabitTrackerByID(userID, created.ID); err != nil {
+			http.Error(w, "Failed to get habit tracker: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	h.audit(r, models.AUDIT_CREATE, models.AUDIT_HABIT_TRACKER, created.ID, nil, created)
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(created)
 }
 
-// DeleteHabitTracker deletes a habit tracker and all its entries
+// DeleteHabitTracker moves a habit tracker and all its entries to the trash
 // @Summary Delete habit tracker
-// @Description Delete a specific habit tracker and all its associated entries
+// @Description Move a specific habit tracker and all its associated entries to the trash, they can be restored until the trash is purged
 // @Tags Habit Trackers
 // @Param id path int true "Habit Tracker ID"
 // @Success 204 "No Content"
 // @Failure 400 {string} string "Bad Request"
 // @Failure 404 {string} string "Not Found"
+// @Security BearerAuth
 // @Router /habit-trackers/{id} [delete]
-func DeleteHabitTracker(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) DeleteHabitTracker(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
@@ -132,11 +230,17 @@ func DeleteHabitTracker(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = database.DeleteHabitTracker(id)
+	before, _ := h.Store.GetHabitTrackerByID(userID, id)
+	err = h.Store.DeleteHabitTracker(userID, id, h.Now())
 	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Habit tracker not found", http.StatusNotFound)
+			return
+		}
 		http.Error(w, "Failed to delete habit tracker: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
+	h.audit(r, models.AUDIT_DELETE, models.AUDIT_HABIT_TRACKER, id, before, nil)
 
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -152,8 +256,10 @@ func DeleteHabitTracker(w http.ResponseWriter, r *http.Request) {
 // @Success 200 {object} habit.HabitTracker
 // @Failure 400 {string} string "Bad Request"
 // @Failure 404 {string} string "Not Found"
+// @Security BearerAuth
 // @Router /habit-trackers/{id} [put]
-func UpdateHabitTracker(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) UpdateHabitTracker(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
 	vars := mux.Vars(r)
 	trackerID, err := strconv.Atoi(vars["id"])
 	if err != nil {
@@ -166,19 +272,59 @@ func UpdateHabitTracker(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Invalid JSON: "+err.Error(), http.StatusBadRequest)
 		return
 	}
-	database.UpdateHabitTracker(trackerID, req)
+	if req.Due != nil {
+		if err := trackers.ValidateDue(*req.Due); err != nil {
+			http.Error(w, "Invalid due: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if req.StartDate != nil {
+		if _, err := time.Parse("2006-01-02", *req.StartDate); err != nil {
+			http.Error(w, "Invalid start_date format. Use YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+	}
+	before, err := h.Store.GetHabitTrackerByID(userID, trackerID)
+	if err != nil {
+		http.Error(w, "Habit tracker not found", http.StatusNotFound)
+		return
+	}
+	var tagIDs []int
+	if req.TagIDs != nil {
+		tagIDs = *req.TagIDs
+	}
+	if err := h.checkLabels(userID, req.CategoryID, tagIDs); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	err = h.Store.UpdateHabitTracker(userID, trackerID, req)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Habit tracker not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to update habit tracker: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := h.setLabels(userID, models.HABIT, trackerID, req.CategoryID, req.TagIDs); err != nil {
+		http.Error(w, "Failed to set category and tags: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
 
-	tracker, err := database.GetHabitTrackerByID(trackerID)
+	tracker, err := h.Store.GetHabitTrackerByID(userID, trackerID)
+	if err != nil {
+		http.Error(w, "Failed to get habit tracker: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.audit(r, models.AUDIT_UPDATE, models.AUDIT_HABIT_TRACKER, trackerID, before, tracker)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(tracker)
 	return
 }
 
-
-
 // AddHabitEntry adds an entry to a habit tracker
 // @Summary Add habit entry
-// @Description Add a new entry to a specific habit tracker
+// @Description Add a new entry to a specific habit tracker. With skipped the day is left out of the dashboard and streaks instead of being done.
 // @Tags Habit Trackers
 // @Accept json
 // @Produce json
@@ -187,72 +333,78 @@ func UpdateHabitTracker(w http.ResponseWriter, r *http.Request) {
 // @Success 201 {object} models.Entry
 // @Failure 400 {string} string "Bad Request"
 // @Failure 404 {string} string "Not Found"
+// @Security BearerAuth
 // @Router /habit-trackers/{id}/entries [post]
-func AddHabitEntry(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) AddHabitEntry(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
 	vars := mux.Vars(r)
 	trackerID, err := strconv.Atoi(vars["id"])
 	if err != nil {
 		http.Error(w, "Invalid tracker ID", http.StatusBadRequest)
 		return
 	}
-	
+
 	// Check if habit tracker exists using database
-	_, err = database.GetHabitTrackerByID(trackerID)
+	_, err = h.Store.GetHabitTrackerByID(userID, trackerID)
 	if err != nil {
 		http.Error(w, "Habit tracker not found", http.StatusNotFound)
 		return
 	}
-	
+
 	var req models.AddEntryRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "Invalid JSON: "+err.Error(), http.StatusBadRequest)
 		return
 	}
-	
+
 	// Parse date or use now
-	var entryDate time.Time
+	entryDate := h.Now().UTC()
 	if req.Date != "" {
-		// Try RFC3339 format first (datetime with timezone)
-		entryDate, err = time.Parse(time.RFC3339, req.Date)
+		loc, err := h.location(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		entryDate, _, err = parseEntryDate(req.Date, loc)
 		if err != nil {
-			// Try date-only format - assume local timezone, then convert to UTC
-			localDate, err := time.Parse("2006-01-02", req.Date)
-			if err != nil {
-				http.Error(w, "Invalid date format. Use YYYY-MM-DD or RFC3339 (2006-01-02T15:04:05Z07:00)", http.StatusBadRequest)
-				return
-			}
-			// For date-only, use current time but with the specified date
-			now := time.Now()
-			entryDate = time.Date(localDate.Year(), localDate.Month(), localDate.Day(), 
-				now.Hour(), now.Minute(), now.Second(), now.Nanosecond(), now.Location())
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
 		}
-		// Convert to UTC for consistent storage
-		entryDate = entryDate.UTC()
-	} else {
-		entryDate = time.Now().UTC()
 	}
-	
+
 	done := true // Default to true (yes)
 	if req.Done != nil {
 		done = *req.Done
 	}
-	
+
+	// A skipped day does not count, neither as done nor as missed
+	skipped := req.Skipped != nil && *req.Skipped
+	if skipped {
+		if req.Done != nil && *req.Done {
+			http.Error(w, "An entry cannot be both done and skipped", http.StatusBadRequest)
+			return
+		}
+		done = false
+	}
+
 	entry := models.Entry{
 		TrackerID: trackerID,
 		Type:      models.HABIT,
 		Done:      &done,
+		Skipped:   skipped,
 		Date:      entryDate,
 		Note:      req.Note,
-		CreatedAt: time.Now(),
+		CreatedAt: h.Now(),
 	}
-	
+
 	// Use your database helper
-	createdEntry, err := database.CreateEntry(entry)
+	createdEntry, err := h.Store.CreateEntry(userID, entry)
 	if err != nil {
 		http.Error(w, "Failed to create entry: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
-	
+	h.audit(r, models.AUDIT_CREATE, models.AUDIT_ENTRY, createdEntry.ID, nil, createdEntry)
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(createdEntry)
@@ -0,0 +1,15 @@
+package handlers
+
+import (
+	"routine-tracker/app"
+)
+
+// Handler serves the API of an app, the handlers are its methods
+type Handler struct {
+	*app.App
+}
+
+// New creates the handlers of an app
+func New(a *app.App) *Handler {
+	return &Handler{App: a}
+}
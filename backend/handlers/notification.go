@@ -0,0 +1,304 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"routine-tracker/auth"
+	"routine-tracker/models"
+	"routine-tracker/notify"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// GetNotificationChannels gets the notification channels of the current user
+// @Summary Get notification channels
+// @Description List every notification channel, both the ones for all trackers and the tracker specific ones
+// @Tags Notifications
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} models.NotificationChannel
+// @Router /notifications/channels [get]
+func (h *Handler) GetNotificationChannels(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+	channels, err := h.Store.GetNotificationChannels(userID)
+	if err != nil {
+		http.Error(w, "Failed to get notification channels: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(channels)
+}
+
+// CreateNotificationChannel creates a notification channel
+// @Summary Create notification channel
+// @Description Add an email, webhook, ntfy or Gotify channel. Without trackerType and trackerId the channel receives the notifications of all trackers, otherwise it replaces them for that tracker.
+// @Description Webhook, ntfy and Gotify targets must be http(s) URLs of public hosts, unless the server allows internal hosts.
+// @Tags Notifications
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param channel body models.CreateNotificationChannelRequest true "Notification channel configuration"
+// @Success 201 {object} models.NotificationChannel
+// @Failure 400 {string} string "Bad Request"
+// @Router /notifications/channels [post]
+func (h *Handler) CreateNotificationChannel(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+
+	var req models.CreateNotificationChannelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		http.Error(w, "Channel name is required", http.StatusBadRequest)
+		return
+	}
+	if err := h.validateChannelTarget(r.Context(), req.Type, req.Target); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if (req.TrackerType == nil) != (req.TrackerID == nil) {
+		http.Error(w, "trackerType and trackerId must be given together", http.StatusBadRequest)
+		return
+	}
+	if req.TrackerType != nil {
+		var err error
+		switch *req.TrackerType {
+		case models.HABIT:
+			_, err = h.Store.GetHabitTrackerByID(userID, *req.TrackerID)
+		case models.TARGET:
+			_, err = h.Store.GetTargetTrackerByID(userID, *req.TrackerID)
+		case models.DURATION:
+			_, err = h.Store.GetDurationTrackerByID(userID, *req.TrackerID)
+		default:
+			http.Error(w, "Invalid trackerType. Use 'habit', 'target' or 'duration'", http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Tracker not found", http.StatusBadRequest)
+			return
+		}
+	}
+
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+
+	channel := models.NotificationChannel{
+		Name:        req.Name,
+		Type:        req.Type,
+		Target:      req.Target,
+		Token:       req.Token,
+		TrackerType: req.TrackerType,
+		TrackerID:   req.TrackerID,
+		Enabled:     enabled,
+	}
+
+	created, err := h.Store.CreateNotificationChannel(userID, channel)
+	if err != nil {
+		http.Error(w, "Failed to create notification channel: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.audit(r, models.AUDIT_CREATE, models.AUDIT_NOTIFICATION_CHANNEL, created.ID, nil, created)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// UpdateNotificationChannel updates a notification channel
+// @Summary Update notification channel
+// @Description Update the name, target, token or enabled state of a notification channel
+// @Tags Notifications
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Notification Channel ID"
+// @Param channel body models.UpdateNotificationChannelRequest true "Fields to update"
+// @Success 200 {object} models.NotificationChannel
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Not Found"
+// @Router /notifications/channels/{id} [put]
+func (h *Handler) UpdateNotificationChannel(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid channel ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.UpdateNotificationChannelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	existing, err := h.Store.GetNotificationChannelByID(userID, id)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Notification channel not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to get notification channel: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if req.Name != nil && strings.TrimSpace(*req.Name) == "" {
+		http.Error(w, "Channel name is required", http.StatusBadRequest)
+		return
+	}
+	if req.Target != nil {
+		if err := h.validateChannelTarget(r.Context(), existing.Type, *req.Target); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	updated, err := h.Store.UpdateNotificationChannel(userID, id, req)
+	if err != nil {
+		http.Error(w, "Failed to update notification channel: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.audit(r, models.AUDIT_UPDATE, models.AUDIT_NOTIFICATION_CHANNEL, id, existing, updated)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+// DeleteNotificationChannel deletes a notification channel
+// @Summary Delete notification channel
+// @Description Delete a notification channel
+// @Tags Notifications
+// @Security BearerAuth
+// @Param id path int true "Notification Channel ID"
+// @Success 204 "No Content"
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Not Found"
+// @Router /notifications/channels/{id} [delete]
+func (h *Handler) DeleteNotificationChannel(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid channel ID", http.StatusBadRequest)
+		return
+	}
+
+	before, _ := h.Store.GetNotificationChannelByID(userID, id)
+	err = h.Store.DeleteNotificationChannel(userID, id)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Notification channel not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to delete notification channel: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.audit(r, models.AUDIT_DELETE, models.AUDIT_NOTIFICATION_CHANNEL, id, before, nil)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SendTestNotification sends a test notification
+// @Summary Send test notification
+// @Description Send a test message to one channel, or to every channel of the user when no channelId is given, and report the result of each
+// @Tags Notifications
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.TestNotificationRequest false "Channel to test"
+// @Success 200 {array} models.TestNotificationResult
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Not Found"
+// @Router /notifications/test [post]
+func (h *Handler) SendTestNotification(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+
+	var req models.TestNotificationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "Invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var channels []models.NotificationChannel
+	if req.ChannelID != nil {
+		channel, err := h.Store.GetNotificationChannelByID(userID, *req.ChannelID)
+		if err == sql.ErrNoRows {
+			http.Error(w, "Notification channel not found", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Failed to get notification channel: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		channels = append(channels, *channel)
+	} else {
+		var err error
+		channels, err = h.Store.GetNotificationChannels(userID)
+		if err != nil {
+			http.Error(w, "Failed to get notification channels: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	if len(channels) == 0 {
+		http.Error(w, "No notification channels configured", http.StatusBadRequest)
+		return
+	}
+
+	msg := notify.Message{
+		UserID:       userID,
+		Title:        "Test notification",
+		Body:         "Notifications are set up correctly",
+		ScheduledFor: h.Now(),
+	}
+
+	results := make([]models.TestNotificationResult, 0, len(channels))
+	for _, channel := range channels {
+		result := models.TestNotificationResult{ChannelID: channel.ID, Name: channel.Name, Type: channel.Type, Success: true}
+		if err := h.Notifier.Send(r.Context(), channel, msg); err != nil {
+			result.Success = false
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(results)
+}
+
+// validateChannelTarget checks that the target fits the channel type and that
+// URLs do not point to internal hosts, unless the server allows them
+func (h *Handler) validateChannelTarget(ctx context.Context, channelType models.ChannelType, target string) error {
+	switch channelType {
+	case models.CHANNEL_EMAIL:
+		if _, err := mail.ParseAddress(target); err != nil {
+			return errors.New("Invalid target, email channels need an email address")
+		}
+	case models.CHANNEL_WEBHOOK, models.CHANNEL_NTFY, models.CHANNEL_GOTIFY:
+		parsed, err := url.Parse(target)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return errors.New("Invalid target, " + string(channelType) + " channels need an http(s) URL")
+		}
+		if err := h.Notifier.CheckTarget(ctx, channelType, target); err != nil {
+			return errors.New("Invalid target: " + err.Error())
+		}
+	default:
+		return errors.New("Invalid type. Use 'email', 'webhook', 'ntfy' or 'gotify'")
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"routine-tracker/auth"
+	"routine-tracker/models"
+	"routine-tracker/trackers"
+)
+
+// UpdateTrackerOrder reorders trackers
+// @Summary Reorder trackers
+// @Description Set the positions and pinned flags of a batch of habit, target and duration trackers at once. Lists and the dashboard show pinned trackers first, then the others by position, trackers of all types interleaved.
+// @Description Either every tracker of the batch is updated or, when one of them is not found, none.
+// @Tags General
+// @Security BearerAuth
+// @Accept json
+// @Param order body models.UpdateOrderRequest true "New positions"
+// @Success 204 "No Content"
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Not Found"
+// @Router /trackers/order [put]
+func (h *Handler) UpdateTrackerOrder(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+
+	var req models.UpdateOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(req.Trackers) == 0 {
+		http.Error(w, "No trackers given", http.StatusBadRequest)
+		return
+	}
+
+	type change struct {
+		entity        models.AuditEntity
+		before, after interface{}
+		changed       bool
+	}
+	changes := make([]change, 0, len(req.Trackers))
+	seen := make(map[trackers.TrackerRef]bool)
+	for _, p := range req.Trackers {
+		key := trackers.TrackerRef{Type: p.Type, ID: p.ID}
+		if seen[key] {
+			http.Error(w, "A tracker appears more than once", http.StatusBadRequest)
+			return
+		}
+		seen[key] = true
+
+		switch p.Type {
+		case models.HABIT:
+			habit, err := h.Store.GetHabitTrackerByID(userID, p.ID)
+			if err != nil {
+				http.Error(w, "Tracker not found", http.StatusNotFound)
+				return
+			}
+			after := *habit
+			after.Position = p.Position
+			if p.Pinned != nil {
+				after.Pinned = *p.Pinned
+			}
+			changed := after.Position != habit.Position || after.Pinned != habit.Pinned
+			changes = append(changes, change{models.AUDIT_HABIT_TRACKER, habit, after, changed})
+		case models.TARGET:
+			target, err := h.Store.GetTargetTrackerByID(userID, p.ID)
+			if err != nil {
+				http.Error(w, "Tracker not found", http.StatusNotFound)
+				return
+			}
+			after := *target
+			after.Position = p.Position
+			if p.Pinned != nil {
+				after.Pinned = *p.Pinned
+			}
+			changed := after.Position != target.Position || after.Pinned != target.Pinned
+			changes = append(changes, change{models.AUDIT_TARGET_TRACKER, target, after, changed})
+		case models.DURATION:
+			duration, err := h.Store.GetDurationTrackerByID(userID, p.ID)
+			if err != nil {
+				http.Error(w, "Tracker not found", http.StatusNotFound)
+				return
+			}
+			after := *duration
+			after.Position = p.Position
+			if p.Pinned != nil {
+				after.Pinned = *p.Pinned
+			}
+			changed := after.Position != duration.Position || after.Pinned != duration.Pinned
+			changes = append(changes, change{models.AUDIT_DURATION_TRACKER, duration, after, changed})
+		default:
+			http.Error(w, "Invalid type. Use 'habit', 'target' or 'duration'", http.StatusBadRequest)
+			return
+		}
+	}
+
+	err := h.Store.UpdateTrackerOrder(userID, req.Trackers)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Tracker not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to update order: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	// Trackers that keep their place are left out of the audit log
+	for i, c := range changes {
+		if !c.changed {
+			continue
+		}
+		h.audit(r, models.AUDIT_UPDATE, c.entity, req.Trackers[i].ID, c.before, c.after)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"routine-tracker/auth"
+	"routine-tracker/models"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// GetPauses gets the pauses of the current user
+// @Summary Get pauses
+// @Description List every pause, both the ones for all trackers and the tracker specific ones, ordered by start date
+// @Tags Pauses
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} models.Pause
+// @Router /pauses [get]
+func (h *Handler) GetPauses(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+	pauses, err := h.Store.GetPauses(userID)
+	if err != nil {
+		http.Error(w, "Failed to get pauses: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pauses)
+}
+
+// CreatePause creates a pause
+// @Summary Create pause
+// @Description Pause trackers from startDate to endDate, both inclusive. Paused days are not due and do not break streaks.
+// @Description Without trackerType and trackerId the pause applies to all trackers, e.g. for a vacation.
+// @Tags Pauses
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param pause body models.CreatePauseRequest true "Pause configuration"
+// @Success 201 {object} models.Pause
+// @Failure 400 {string} string "Bad Request"
+// @Router /pauses [post]
+func (h *Handler) CreatePause(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+
+	var req models.CreatePauseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if (req.TrackerType == nil) != (req.TrackerID == nil) {
+		http.Error(w, "trackerType and trackerId must be given together", http.StatusBadRequest)
+		return
+	}
+	if req.TrackerType != nil {
+		var err error
+		switch *req.TrackerType {
+		case models.HABIT:
+			_, err = h.Store.GetHabitTrackerByID(userID, *req.TrackerID)
+		case models.TARGET:
+			_, err = h.Store.GetTargetTrackerByID(userID, *req.TrackerID)
+		case models.DURATION:
+			_, err = h.Store.GetDurationTrackerByID(userID, *req.TrackerID)
+		default:
+			http.Error(w, "Invalid trackerType. Use 'habit', 'target' or 'duration'", http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Tracker not found", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if req.EndDate == "" {
+		req.EndDate = req.StartDate
+	}
+	pause := models.Pause{
+		TrackerType: req.TrackerType,
+		TrackerID:   req.TrackerID,
+		Reason:      req.Reason,
+	}
+	if err := setPauseDates(&pause, req.StartDate, req.EndDate); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	created, err := h.Store.CreatePause(userID, pause)
+	if err != nil {
+		http.Error(w, "Failed to create pause: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.audit(r, models.AUDIT_CREATE, models.AUDIT_PAUSE, created.ID, nil, created)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// UpdatePause updates a pause
+// @Summary Update pause
+// @Description Update the dates or reason of a pause, e.g. to end it early
+// @Tags Pauses
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Pause ID"
+// @Param pause body models.UpdatePauseRequest true "Fields to update"
+// @Success 200 {object} models.Pause
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Not Found"
+// @Router /pauses/{id} [put]
+func (h *Handler) UpdatePause(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid pause ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.UpdatePauseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	pause, err := h.Store.GetPauseByID(userID, id)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Pause not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to get pause: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	before := *pause
+	startDate, endDate := pause.StartDate.UTC().Format("2006-01-02"), pause.EndDate.UTC().Format("2006-01-02")
+	if req.StartDate != nil {
+		startDate = *req.StartDate
+	}
+	if req.EndDate != nil {
+		endDate = *req.EndDate
+	}
+	if err := setPauseDates(pause, startDate, endDate); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Reason != nil {
+		pause.Reason = *req.Reason
+	}
+
+	updated, err := h.Store.UpdatePause(userID, id, *pause)
+	if err != nil {
+		http.Error(w, "Failed to update pause: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.audit(r, models.AUDIT_UPDATE, models.AUDIT_PAUSE, id, before, updated)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+// DeletePause deletes a pause
+// @Summary Delete pause
+// @Description Delete a pause, its days count again
+// @Tags Pauses
+// @Security BearerAuth
+// @Param id path int true "Pause ID"
+// @Success 204 "No Content"
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Not Found"
+// @Router /pauses/{id} [delete]
+func (h *Handler) DeletePause(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid pause ID", http.StatusBadRequest)
+		return
+	}
+
+	before, _ := h.Store.GetPauseByID(userID, id)
+	err = h.Store.DeletePause(userID, id)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Pause not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to delete pause: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.audit(r, models.AUDIT_DELETE, models.AUDIT_PAUSE, id, before, nil)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// setPauseDates parses the YYYY-MM-DD dates of a pause and checks that it
+// does not end before it starts
+func setPauseDates(pause *models.Pause, startDate, endDate string) error {
+	start, err := time.Parse("2006-01-02", startDate)
+	if err != nil {
+		return errors.New("Invalid startDate format. Please use YYYY-MM-DD format")
+	}
+	end, err := time.Parse("2006-01-02", endDate)
+	if err != nil {
+		return errors.New("Invalid endDate format. Please use YYYY-MM-DD format")
+	}
+	if end.Before(start) {
+		return errors.New("endDate must not be before startDate")
+	}
+
+	pause.StartDate, pause.EndDate = start, end
+	return nil
+}
+
+// daysOff combines the user's pauses with the skipped entries among entries.
+// Entry dates are assigned to days in loc.
+func (h *Handler) daysOff(userID int, entries []models.Entry, loc *time.Location) (models.DaysOff, error) {
+	pauses, err := h.Store.GetPauses(userID)
+	if err != nil {
+		return models.DaysOff{}, err
+	}
+	return models.NewDaysOff(pauses, entries, loc), nil
+}
+
+// allDaysOff is daysOff of all trackers of the user
+func (h *Handler) allDaysOff(userID int, loc *time.Location) (models.DaysOff, error) {
+	entries, err := h.Store.GetAllEntries(userID)
+	if err != nil {
+		return models.DaysOff{}, err
+	}
+	return h.daysOff(userID, entries, loc)
+}
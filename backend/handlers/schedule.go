@@ -0,0 +1,306 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"routine-tracker/auth"
+	"routine-tracker/models"
+	"routine-tracker/trackers"
+	"routine-tracker/trackers/duration"
+	"routine-tracker/trackers/habit"
+	"routine-tracker/trackers/target"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	// defaultAgendaDays is the length of an agenda without "to", a week
+	defaultAgendaDays = 7
+	// maxAgendaDays limits an agenda to about a year
+	maxAgendaDays = 366
+	// defaultNextDueCount and maxNextDueCount limit the dates of next-due
+	defaultNextDueCount = 5
+	maxNextDueCount     = 100
+)
+
+// GetAgenda gets the trackers due on each day of a date range
+// @Summary Get agenda
+// @Description Get the habit, target and duration trackers due on each day from "from" to "to", both inclusive.
+// @Description "from" defaults to today and "to" to six days later, a range can be at most 366 days long.
+// @Description Paused and skipped days of a tracker are left out.
+// @Tags General
+// @Produce json
+// @Param from query string false "First day in YYYY-MM-DD format (defaults to today)" example(2024-01-01)
+// @Param to query string false "Last day in YYYY-MM-DD format (defaults to six days after from)" example(2024-01-07)
+// @Param X-Time-Zone header string false "IANA time zone overriding the user's" example(Europe/Istanbul)
+// @Success 200 {object} trackers.AgendaResponse
+// @Failure 400 {string} string "Bad Request"
+// @Failure 500 {string} string "Internal Server Error"
+// @Security BearerAuth
+// @Router /agenda [get]
+func (h *Handler) GetAgenda(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+	from, to, err := h.agendaRange(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	habitTrackers, err := h.Store.GetAllHabitTrackers(userID, models.ACTIVE_TRACKERS)
+	if err != nil {
+		http.Error(w, "Failed to get habit trackers: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	targetTrackers, err := h.Store.GetAllTargetTrackers(userID, models.ACTIVE_TRACKERS)
+	if err != nil {
+		http.Error(w, "Failed to get target trackers: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	durationTrackers, err := h.Store.GetAllDurationTrackers(userID, models.ACTIVE_TRACKERS)
+	if err != nil {
+		http.Error(w, "Failed to get duration trackers: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	daysOff, err := h.allDaysOff(userID, from.Location())
+	if err != nil {
+		http.Error(w, "Failed to get pauses: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// One entry per day, even when nothing is due
+	response := trackers.AgendaResponse{
+		From: from.Format("2006-01-02"),
+		To:   to.Format("2006-01-02"),
+		Days: make([]trackers.AgendaDay, 0),
+	}
+	index := make(map[string]int)
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		date := d.Format("2006-01-02")
+		index[date] = len(response.Days)
+		response.Days = append(response.Days, trackers.AgendaDay{
+			Date:             date,
+			HabitTrackers:    make([]habit.HabitTracker, 0),
+			TargetTrackers:   make([]target.TargetTracker, 0),
+			DurationTrackers: make([]duration.DurationTracker, 0),
+		})
+	}
+
+	// Each schedule is expanded once for the whole range, days off are left out
+	for _, habit := range habitTrackers {
+		for _, d := range trackers.DueDates(habit.Due, habit.StartDate, from, to) {
+			if daysOff.Contains(models.HABIT, habit.ID, d) {
+				continue
+			}
+			day := &response.Days[index[d.Format("2006-01-02")]]
+			day.HabitTrackers = append(day.HabitTrackers, habit)
+		}
+	}
+
+	for _, target := range targetTrackers {
+		dates := make([]time.Time, 0)
+		for _, d := range trackers.DueDates(target.Due, target.StartDate, from, to) {
+			if !daysOff.Contains(models.TARGET, target.ID, d) {
+				dates = append(dates, d)
+			}
+		}
+		if len(dates) == 0 {
+			continue
+		}
+		h.setCurrentValues(&target)
+		for _, d := range dates {
+			day := &response.Days[index[d.Format("2006-01-02")]]
+			day.TargetTrackers = append(day.TargetTrackers, target)
+		}
+	}
+
+	for _, tracker := range durationTrackers {
+		dates := make([]time.Time, 0)
+		for _, d := range trackers.DueDates(tracker.Due, tracker.StartDate, from, to) {
+			if !daysOff.Contains(models.DURATION, tracker.ID, d) {
+				dates = append(dates, d)
+			}
+		}
+		if len(dates) == 0 {
+			continue
+		}
+		h.setCurrentTotal(&tracker, h.Now().In(from.Location()))
+		for _, d := range dates {
+			day := &response.Days[index[d.Format("2006-01-02")]]
+			day.DurationTrackers = append(day.DurationTrackers, tracker)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetHabitNextDue gets the next days a habit tracker is due on
+// @Summary Get next due dates of a habit tracker
+// @Description Get the next days, starting today, on which a habit tracker is due. Paused and skipped days are left out.
+// @Tags Habit Trackers
+// @Produce json
+// @Param id path int true "Habit Tracker ID"
+// @Param count query int false "Number of dates, at most 100 (defaults to 5)" example(5)
+// @Param date query string false "Start from this date in YYYY-MM-DD format (defaults to today)" example(2024-01-15)
+// @Success 200 {object} trackers.NextDueResponse
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Not Found"
+// @Security BearerAuth
+// @Router /habit-trackers/{id}/next-due [get]
+func (h *Handler) GetHabitNextDue(w http.ResponseWriter, r *http.Request) {
+	h.nextDue(w, r, models.HABIT)
+}
+
+// GetTargetNextDue gets the next days a target tracker is due on
+// @Summary Get next due dates of a target tracker
+// @Description Get the next days, starting today, on which a target tracker is due. Paused days are left out.
+// @Tags Target Trackers
+// @Produce json
+// @Param id path int true "Target Tracker ID"
+// @Param count query int false "Number of dates, at most 100 (defaults to 5)" example(5)
+// @Param date query string false "Start from this date in YYYY-MM-DD format (defaults to today)" example(2024-01-15)
+// @Success 200 {object} trackers.NextDueResponse
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Not Found"
+// @Security BearerAuth
+// @Router /target-trackers/{id}/next-due [get]
+func (h *Handler) GetTargetNextDue(w http.ResponseWriter, r *http.Request) {
+	h.nextDue(w, r, models.TARGET)
+}
+
+// GetDurationNextDue gets the next days a duration tracker is due on
+// @Summary Get next due dates of a duration tracker
+// @Description Get the next days, starting today, on which a duration tracker is due. Paused days are left out.
+// @Tags Duration Trackers
+// @Produce json
+// @Param id path int true "Duration Tracker ID"
+// @Param count query int false "Number of dates, at most 100 (defaults to 5)" example(5)
+// @Param date query string false "Start from this date in YYYY-MM-DD format (defaults to today)" example(2024-01-15)
+// @Success 200 {object} trackers.NextDueResponse
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Not Found"
+// @Security BearerAuth
+// @Router /duration-trackers/{id}/next-due [get]
+func (h *Handler) GetDurationNextDue(w http.ResponseWriter, r *http.Request) {
+	h.nextDue(w, r, models.DURATION)
+}
+
+func (h *Handler) nextDue(w http.ResponseWriter, r *http.Request, trackerType models.TrackerType) {
+	userID := auth.UserID(r.Context())
+	trackerID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid tracker ID", http.StatusBadRequest)
+		return
+	}
+
+	count := defaultNextDueCount
+	if value := r.URL.Query().Get("count"); value != "" {
+		count, err = strconv.Atoi(value)
+		if err != nil || count < 1 || count > maxNextDueCount {
+			http.Error(w, "Invalid count, use a number from 1 to 100", http.StatusBadRequest)
+			return
+		}
+	}
+
+	today, err := h.today(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var due models.Due
+	var startDate time.Time
+	switch trackerType {
+	case models.HABIT:
+		tracker, err := h.Store.GetHabitTrackerByID(userID, trackerID)
+		if err != nil {
+			http.Error(w, "Habit tracker not found", http.StatusNotFound)
+			return
+		}
+		due, startDate = tracker.Due, tracker.StartDate
+	case models.TARGET:
+		tracker, err := h.Store.GetTargetTrackerByID(userID, trackerID)
+		if err != nil {
+			http.Error(w, "Target tracker not found", http.StatusNotFound)
+			return
+		}
+		due, startDate = tracker.Due, tracker.StartDate
+	case models.DURATION:
+		tracker, err := h.Store.GetDurationTrackerByID(userID, trackerID)
+		if err != nil {
+			http.Error(w, "Duration tracker not found", http.StatusNotFound)
+			return
+		}
+		due, startDate = tracker.Due, tracker.StartDate
+	}
+
+	entries, err := h.Store.GetEntriesByTracker(userID, trackerID, string(trackerType))
+	if err != nil {
+		http.Error(w, "Failed to get entries: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	daysOff, err := h.daysOff(userID, entries, today.Location())
+	if err != nil {
+		http.Error(w, "Failed to get pauses: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := trackers.NextDueResponse{
+		TrackerID: trackerID,
+		Type:      trackerType,
+		From:      today.Format("2006-01-02"),
+		Dates:     make([]string, 0),
+	}
+	off := func(day time.Time) bool { return daysOff.Contains(trackerType, trackerID, day) }
+	for _, d := range trackers.NextDueDatesExcept(due, startDate, today, count, off) {
+		response.Dates = append(response.Dates, d.Format("2006-01-02"))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// agendaRange reads the "from" and "to" query parameters as days in the
+// request's time zone
+func (h *Handler) agendaRange(r *http.Request) (time.Time, time.Time, error) {
+	loc, err := h.location(r)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	parse := func(name string, fallback time.Time) (time.Time, error) {
+		value := r.URL.Query().Get(name)
+		if value == "" {
+			return fallback, nil
+		}
+		date, err := time.ParseInLocation("2006-01-02", value, loc)
+		if err != nil {
+			return time.Time{}, errors.New("Invalid " + name + " date format. Please use YYYY-MM-DD format")
+		}
+		return date, nil
+	}
+
+	now := h.Now().In(loc)
+	from, err := parse("from", time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc))
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	to, err := parse("to", from.AddDate(0, 0, defaultAgendaDays-1))
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, errors.New("to must not be before from")
+	}
+	if to.After(from.AddDate(0, 0, maxAgendaDays-1)) {
+		return time.Time{}, time.Time{}, errors.New("An agenda can be at most 366 days long")
+	}
+	return from, to, nil
+}
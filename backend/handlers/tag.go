@@ -0,0 +1,417 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"routine-tracker/auth"
+	"routine-tracker/models"
+	"routine-tracker/trackers"
+	"routine-tracker/trackers/duration"
+	"routine-tracker/trackers/habit"
+	"routine-tracker/trackers/target"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// GetTags gets the tags of the current user
+// @Summary Get tags
+// @Description List every tag ordered by name
+// @Tags Tags & Categories
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} models.Tag
+// @Router /tags [get]
+func (h *Handler) GetTags(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+	tags, err := h.Store.GetTags(userID)
+	if err != nil {
+		http.Error(w, "Failed to get tags: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tags)
+}
+
+// CreateTag creates a tag
+// @Summary Create tag
+// @Description Create a tag that can be given to any number of trackers with tagIds
+// @Tags Tags & Categories
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param tag body models.TagRequest true "Tag"
+// @Success 201 {object} models.Tag
+// @Failure 400 {string} string "Bad Request"
+// @Failure 409 {string} string "Conflict"
+// @Router /tags [post]
+func (h *Handler) CreateTag(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+
+	var req models.TagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !h.checkTagName(w, userID, 0, req.Name) {
+		return
+	}
+
+	created, err := h.Store.CreateTag(userID, strings.TrimSpace(req.Name))
+	if err != nil {
+		http.Error(w, "Failed to create tag: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.audit(r, models.AUDIT_CREATE, models.AUDIT_TAG, created.ID, nil, created)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// UpdateTag renames a tag
+// @Summary Update tag
+// @Description Rename a tag, the trackers keep it
+// @Tags Tags & Categories
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Tag ID"
+// @Param tag body models.TagRequest true "Tag"
+// @Success 200 {object} models.Tag
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Not Found"
+// @Failure 409 {string} string "Conflict"
+// @Router /tags/{id} [put]
+func (h *Handler) UpdateTag(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid tag ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.TagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	before, err := h.Store.GetTagByID(userID, id)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Tag not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to get tag: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !h.checkTagName(w, userID, id, req.Name) {
+		return
+	}
+
+	updated, err := h.Store.UpdateTag(userID, id, strings.TrimSpace(req.Name))
+	if err != nil {
+		http.Error(w, "Failed to update tag: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.audit(r, models.AUDIT_UPDATE, models.AUDIT_TAG, id, before, updated)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+// DeleteTag deletes a tag
+// @Summary Delete tag
+// @Description Delete a tag and remove it from all trackers
+// @Tags Tags & Categories
+// @Security BearerAuth
+// @Param id path int true "Tag ID"
+// @Success 204 "No Content"
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Not Found"
+// @Router /tags/{id} [delete]
+func (h *Handler) DeleteTag(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid tag ID", http.StatusBadRequest)
+		return
+	}
+
+	before, _ := h.Store.GetTagByID(userID, id)
+	err = h.Store.DeleteTag(userID, id)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Tag not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to delete tag: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.audit(r, models.AUDIT_DELETE, models.AUDIT_TAG, id, before, nil)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetCategories gets the categories of the current user
+// @Summary Get categories
+// @Description List every category ordered by name, the order of the groups in tracker listings
+// @Tags Tags & Categories
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} models.Category
+// @Router /categories [get]
+func (h *Handler) GetCategories(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+	categories, err := h.Store.GetCategories(userID)
+	if err != nil {
+		http.Error(w, "Failed to get categories: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(categories)
+}
+
+// CreateCategory creates a category
+// @Summary Create category
+// @Description Create a category with an optional color and icon. Trackers are put in it with categoryId.
+// @Tags Tags & Categories
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param category body models.CreateCategoryRequest true "Category"
+// @Success 201 {object} models.Category
+// @Failure 400 {string} string "Bad Request"
+// @Failure 409 {string} string "Conflict"
+// @Router /categories [post]
+func (h *Handler) CreateCategory(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+
+	var req models.CreateCategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !h.checkCategoryName(w, userID, 0, req.Name) {
+		return
+	}
+
+	created, err := h.Store.CreateCategory(userID, models.Category{
+		Name:  strings.TrimSpace(req.Name),
+		Color: req.Color,
+		Icon:  req.Icon,
+	})
+	if err != nil {
+		http.Error(w, "Failed to create category: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.audit(r, models.AUDIT_CREATE, models.AUDIT_CATEGORY, created.ID, nil, created)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// UpdateCategory updates a category
+// @Summary Update category
+// @Description Update the name, color or icon of a category
+// @Tags Tags & Categories
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Category ID"
+// @Param category body models.UpdateCategoryRequest true "Fields to update"
+// @Success 200 {object} models.Category
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Not Found"
+// @Failure 409 {string} string "Conflict"
+// @Router /categories/{id} [put]
+func (h *Handler) UpdateCategory(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid category ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.UpdateCategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	category, err := h.Store.GetCategoryByID(userID, id)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Category not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to get category: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	before := *category
+	if req.Name != nil {
+		if !h.checkCategoryName(w, userID, id, *req.Name) {
+			return
+		}
+		category.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Color != nil {
+		category.Color = *req.Color
+	}
+	if req.Icon != nil {
+		category.Icon = *req.Icon
+	}
+
+	updated, err := h.Store.UpdateCategory(userID, id, *category)
+	if err != nil {
+		http.Error(w, "Failed to update category: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.audit(r, models.AUDIT_UPDATE, models.AUDIT_CATEGORY, id, before, updated)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+// DeleteCategory deletes a category
+// @Summary Delete category
+// @Description Delete a category, its trackers become uncategorized
+// @Tags Tags & Categories
+// @Security BearerAuth
+// @Param id path int true "Category ID"
+// @Success 204 "No Content"
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Not Found"
+// @Router /categories/{id} [delete]
+func (h *Handler) DeleteCategory(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid category ID", http.StatusBadRequest)
+		return
+	}
+
+	before, _ := h.Store.GetCategoryByID(userID, id)
+	err = h.Store.DeleteCategory(userID, id)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Category not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to delete category: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.audit(r, models.AUDIT_DELETE, models.AUDIT_CATEGORY, id, before, nil)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// checkTagName writes an error unless name is a valid name for the tag with
+// the given ID, 0 for a new tag. Names are unique regardless of case.
+func (h *Handler) checkTagName(w http.ResponseWriter, userID int, id int, name string) bool {
+	if strings.TrimSpace(name) == "" {
+		http.Error(w, "Name is required", http.StatusBadRequest)
+		return false
+	}
+	tags, err := h.Store.GetTags(userID)
+	if err != nil {
+		http.Error(w, "Failed to get tags: "+err.Error(), http.StatusInternalServerError)
+		return false
+	}
+	for _, tag := range tags {
+		if tag.ID != id && strings.EqualFold(tag.Name, strings.TrimSpace(name)) {
+			http.Error(w, "Tag already exists", http.StatusConflict)
+			return false
+		}
+	}
+	return true
+}
+
+// checkCategoryName is checkTagName for categories
+func (h *Handler) checkCategoryName(w http.ResponseWriter, userID int, id int, name string) bool {
+	if strings.TrimSpace(name) == "" {
+		http.Error(w, "Name is required", http.StatusBadRequest)
+		return false
+	}
+	categories, err := h.Store.GetCategories(userID)
+	if err != nil {
+		http.Error(w, "Failed to get categories: "+err.Error(), http.StatusInternalServerError)
+		return false
+	}
+	for _, category := range categories {
+		if category.ID != id && strings.EqualFold(category.Name, strings.TrimSpace(name)) {
+			http.Error(w, "Category already exists", http.StatusConflict)
+			return false
+		}
+	}
+	return true
+}
+
+// checkLabels checks that a category and tags given for a tracker belong to
+// the user. Category ID 0 removes a tracker from its category.
+func (h *Handler) checkLabels(userID int, categoryID *int, tagIDs []int) error {
+	if categoryID != nil && *categoryID != 0 {
+		if _, err := h.Store.GetCategoryByID(userID, *categoryID); err != nil {
+			return errors.New("Category not found")
+		}
+	}
+	for _, tagID := range tagIDs {
+		if _, err := h.Store.GetTagByID(userID, tagID); err != nil {
+			return errors.New("Tag not found")
+		}
+	}
+	return nil
+}
+
+// setLabels sets the category and tags of a tracker checked by checkLabels,
+// nil leaves them unchanged
+func (h *Handler) setLabels(userID int, trackerType models.TrackerType, trackerID int, categoryID *int, tagIDs *[]int) error {
+	if categoryID != nil {
+		var id *int
+		if *categoryID != 0 {
+			id = categoryID
+		}
+		if err := h.Store.SetTrackerCategory(userID, trackerType, trackerID, id); err != nil {
+			return err
+		}
+	}
+	if tagIDs != nil {
+		return h.Store.SetTrackerTags(userID, trackerType, trackerID, *tagIDs)
+	}
+	return nil
+}
+
+// trackerFilter reads the tag and category filters of tracker listings
+func trackerFilter(r *http.Request) models.TrackerFilter {
+	return models.TrackerFilter{
+		Tag:      r.URL.Query().Get("tag"),
+		Category: r.URL.Query().Get("category"),
+	}
+}
+
+// groupTrackers applies the tag and category filters of a listing and groups
+// the remaining trackers by category. The returned lists keep their order.
+func (h *Handler) groupTrackers(r *http.Request, habits []habit.HabitTracker, targets []target.TargetTracker, durations []duration.DurationTracker) ([]habit.HabitTracker, []target.TargetTracker, []duration.DurationTracker, []trackers.TrackerGroup, error) {
+	categories, err := h.Store.GetCategories(auth.UserID(r.Context()))
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	filter := trackerFilter(r)
+	habits = trackers.FilterHabits(habits, filter, categories)
+	targets = trackers.FilterTargets(targets, filter, categories)
+	durations = trackers.FilterDurations(durations, filter, categories)
+	return habits, targets, durations, trackers.GroupByCategory(categories, habits, targets, durations), nil
+}
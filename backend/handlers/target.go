@@ -1,13 +1,18 @@
 package handlers
 
 import (
+	"database/sql"
 	"encoding/json"
-  // "fmt"
+	"errors"
+	// "fmt"
 	"github.com/gorilla/mux"
 
 	"net/http"
+	"routine-tracker/analytics"
+	"routine-tracker/auth"
 	"routine-tracker/database"
 	"routine-tracker/models"
+	"routine-tracker/trackers"
 	"routine-tracker/trackers/target"
 	"strconv"
 	"time"
@@ -15,13 +20,25 @@ import (
 
 // GetTargetTrackers gets all target trackers
 // @Summary Get all target trackers
-// @Description Retrieve all created target trackers
+// @Description Retrieve all created target trackers in the user-defined order, archived ones are left out by default
 // @Tags Target Trackers
 // @Produce json
+// @Param archived query string false "false for active trackers (default), true for archived ones or all" Enums(false, true, all)
+// @Param tag query string false "Only trackers with this tag" example(morning)
+// @Param category query string false "Only trackers in this category" example(Health)
 // @Success 200 {array} target.TargetTracker
+// @Failure 400 {string} string "Bad Request"
+// @Security BearerAuth
 // @Router /target-trackers [get]
-func GetTargetTrackers(w http.ResponseWriter, r *http.Request) {
-	targets, err := database.GetAllTargetTrackers()
+func (h *Handler) GetTargetTrackers(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+	filter, err := archiveFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	targets, err := h.Store.GetAllTargetTrackers(userID, filter)
 	if err != nil {
 		http.Error(w, "Failed to get target trackers: "+err.Error(), http.StatusInternalServerError)
 		return
@@ -29,7 +46,8 @@ func GetTargetTrackers(w http.ResponseWriter, r *http.Request) {
 
 	// Calculate current values and adjust start values for all target trackers
 	for i := range targets {
-		currentValue, err := database.CalculateCurrentValue(&targets[i])
+		targets[i] = targets[i].At(h.Now())
+		currentValue, err := h.Store.CalculateCurrentValue(&targets[i])
 		if err != nil {
 			// Log error but continue with start value
 			currentValue = targets[i].StartValue
@@ -41,13 +59,19 @@ func GetTargetTrackers(w http.ResponseWriter, r *http.Request) {
 
 		// Adjust start value if UseActualBounds is true
 		if targets[i].UseActualBounds {
-			adjustedStartValue, err := database.GetAdjustedStartValue(&targets[i])
+			adjustedStartValue, err := h.Store.GetAdjustedStartValue(&targets[i])
 			if err == nil {
 				targets[i].StartValue = adjustedStartValue
 			}
 		}
 	}
 
+	_, targets, _, _, err = h.groupTrackers(r, nil, targets, nil)
+	if err != nil {
+		http.Error(w, "Failed to get categories: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(targets)
 }
@@ -61,8 +85,10 @@ func GetTargetTrackers(w http.ResponseWriter, r *http.Request) {
 // @Success 200 {object} target.TargetTracker
 // @Failure 400 {string} string "Bad Request"
 // @Failure 404 {string} string "Not Found"
+// @Security BearerAuth
 // @Router /target-trackers/{id} [get]
-func GetTargetTracker(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) GetTargetTracker(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
 	vars := mux.Vars(r)
 	trackerID, err := strconv.Atoi(vars["id"])
 	if err != nil {
@@ -70,14 +96,15 @@ func GetTargetTracker(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tracker, err := database.GetTargetTrackerByID(trackerID)
+	tracker, err := h.Store.GetTargetTrackerByID(userID, trackerID)
 	if err != nil {
 		http.Error(w, "Target tracker not found", http.StatusNotFound)
 		return
 	}
 
-	// Calculate current value for the target tracker
-	currentValue, err := database.CalculateCurrentValue(tracker)
+	// Calculate current value for the goal in effect now
+	*tracker = tracker.At(h.Now())
+	currentValue, err := h.Store.CalculateCurrentValue(tracker)
 	if err != nil {
 		// Log error but continue with start value
 		currentValue = tracker.StartValue
@@ -89,7 +116,7 @@ func GetTargetTracker(w http.ResponseWriter, r *http.Request) {
 
 	// Adjust start value if UseActualBounds is true
 	if tracker.UseActualBounds {
-		adjustedStartValue, err := database.GetAdjustedStartValue(tracker)
+		adjustedStartValue, err := h.Store.GetAdjustedStartValue(tracker)
 		if err == nil {
 			tracker.StartValue = adjustedStartValue
 		}
@@ -99,6 +126,52 @@ func GetTargetTracker(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(tracker)
 }
 
+// GetTargetTrackerProjection gets the pace, weighted trend and projection of a target tracker
+// @Summary Get target tracker projection
+// @Description Get the weighted trend line, projected completion date, projected value on the goal date and the daily rate needed to stay on pace
+// @Tags Target Trackers
+// @Produce json
+// @Param id path int true "Target Tracker ID"
+// @Param date query string false "Calculate the projection as of this date in YYYY-MM-DD format (defaults to now)" example(2024-03-01)
+// @Success 200 {object} analytics.Projection
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Not Found"
+// @Failure 500 {string} string "Internal Server Error"
+// @Security BearerAuth
+// @Router /target-trackers/{id}/projection [get]
+func (h *Handler) GetTargetTrackerProjection(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+	vars := mux.Vars(r)
+	trackerID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid tracker ID", http.StatusBadRequest)
+		return
+	}
+
+	now, err := h.today(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tracker, err := h.Store.GetTargetTrackerByID(userID, trackerID)
+	if err != nil {
+		http.Error(w, "Target tracker not found", http.StatusNotFound)
+		return
+	}
+
+	entries, err := h.Store.GetEntriesByTracker(userID, trackerID, "target")
+	if err != nil {
+		http.Error(w, "Failed to get entries: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	projection := analytics.ProjectTarget(*tracker, entries, now)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(projection)
+}
+
 // CreateTargetTracker creates a new target tracker
 // @Summary Create target tracker
 // @Description Create a new target tracker with specified configuration
@@ -108,8 +181,10 @@ func GetTargetTracker(w http.ResponseWriter, r *http.Request) {
 // @Param target body target.CreateTargetRequest true "Target tracker configuration"
 // @Success 201 {object} target.TargetTracker
 // @Failure 400 {string} string "Bad Request"
+// @Security BearerAuth
 // @Router /target-trackers [post]
-func CreateTargetTracker(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) CreateTargetTracker(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
 	var req target.CreateTargetRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "Invalid JSON: "+err.Error(), http.StatusBadRequest)
@@ -129,6 +204,17 @@ func CreateTargetTracker(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Check the recurrence rule of RRULE schedules
+	if err := trackers.ValidateDue(req.Due); err != nil {
+		http.Error(w, "Invalid due: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.checkLabels(userID, req.CategoryID, req.TagIDs); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	// Set default reminder if none provided
 	reminders := req.Reminders
 	if len(reminders.Times) == 0 && reminders.Enabled {
@@ -149,27 +235,37 @@ func CreateTargetTracker(w http.ResponseWriter, r *http.Request) {
 		TrendWeightType: req.TrendWeightType,
 		Due:             req.Due,
 		Reminders:       reminders,
-		CreatedAt:       time.Now(),
+		CreatedAt:       h.Now(),
 	}
 
-	created, err := database.CreateTargetTracker(tracker)
+	created, err := h.Store.CreateTargetTracker(userID, tracker)
 	if err != nil {
 		http.Error(w, "Failed to create target tracker: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
+	if req.CategoryID != nil || len(req.TagIDs) > 0 {
+		if err := h.setLabels(userID, models.TARGET, created.ID, req.CategoryID, &req.TagIDs); err != nil {
+			http.Error(w, "Failed to set category and tags: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if created, err = h.Store.GetTargetTrackerByID(userID, created.ID); err != nil {
+			http.Error(w, "Failed to get target tracker: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
 
 	// Set OriginalStartValue for consistency with other endpoints
 	created.OriginalStartValue = created.StartValue
+	h.audit(r, models.AUDIT_CREATE, models.AUDIT_TARGET_TRACKER, created.ID, nil, created)
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(created)
 }
 
-
 // UpdateTargetTracker updates a target tracker
 // @Summary Update target tracker
-// @Description Update a specific target tracker (partial updates supported)
+// @Description Update a specific target tracker (partial updates supported). A new goalValue, goalDate or addToTotal applies to the entries from effectiveFrom on, earlier entries keep their configuration. A future effectiveFrom plans the change.
 // @Tags Target Trackers
 // @Accept json
 // @Produce json
@@ -178,8 +274,10 @@ func CreateTargetTracker(w http.ResponseWriter, r *http.Request) {
 // @Success 200 {object} target.TargetTracker
 // @Failure 400 {string} string "Bad Request"
 // @Failure 404 {string} string "Not Found"
+// @Security BearerAuth
 // @Router /target-trackers/{id} [put]
-func UpdateTargetTracker(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) UpdateTargetTracker(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
 	vars := mux.Vars(r)
 	trackerID, err := strconv.Atoi(vars["id"])
 	if err != nil {
@@ -192,37 +290,133 @@ func UpdateTargetTracker(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Invalid JSON: "+err.Error(), http.StatusBadRequest)
 		return
 	}
-	// fmt.Println(req.Due.SpecificDays)
-	err = database.UpdateTargetTracker(trackerID, req)
+	if req.Due != nil {
+		if err := trackers.ValidateDue(*req.Due); err != nil {
+			http.Error(w, "Invalid due: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if req.StartDate != nil {
+		if _, err := time.Parse("2006-01-02", *req.StartDate); err != nil {
+			http.Error(w, "Invalid start_date format. Use YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+	}
+	if req.GoalDate != nil {
+		if _, err := time.Parse("2006-01-02", *req.GoalDate); err != nil {
+			http.Error(w, "Invalid goal_date format. Use YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+	}
+	before, err := h.Store.GetTargetTrackerByID(userID, trackerID)
+	if err != nil {
+		http.Error(w, "Target tracker not found", http.StatusNotFound)
+		return
+	}
+	var tagIDs []int
+	if req.TagIDs != nil {
+		tagIDs = *req.TagIDs
+	}
+	if err := h.checkLabels(userID, req.CategoryID, tagIDs); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Goal changes apply from the start of today unless a date is given
+	loc, err := h.location(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	now := h.Now().In(loc)
+	effectiveFrom := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	if req.EffectiveFrom != nil {
+		effectiveFrom, _, err = parseEntryDate(*req.EffectiveFrom, loc)
+		if err != nil {
+			http.Error(w, "Invalid effectiveFrom format. Use YYYY-MM-DD or RFC3339", http.StatusBadRequest)
+			return
+		}
+	}
+
+	err = h.Store.UpdateTargetTracker(userID, trackerID, req, effectiveFrom, h.Now())
+	if err == sql.ErrNoRows {
+		http.Error(w, "Target tracker not found", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, database.ErrSegmentOrder) {
+		http.Error(w, "effectiveFrom cannot be before the latest goal change", http.StatusBadRequest)
+		return
+	}
 	if err != nil {
 		http.Error(w, "Failed to update target tracker: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
+	if err := h.setLabels(userID, models.TARGET, trackerID, req.CategoryID, req.TagIDs); err != nil {
+		http.Error(w, "Failed to set category and tags: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
 
-	tracker, err := database.GetTargetTrackerByID(trackerID)
+	tracker, err := h.Store.GetTargetTrackerByID(userID, trackerID)
 	if err != nil {
 		http.Error(w, "Target tracker not found after update", http.StatusNotFound)
 		return
 	}
 
 	// Set OriginalStartValue for consistency
+	*before, *tracker = before.At(h.Now()), tracker.At(h.Now())
+	before.OriginalStartValue = before.StartValue
 	tracker.OriginalStartValue = tracker.StartValue
+	h.audit(r, models.AUDIT_UPDATE, models.AUDIT_TARGET_TRACKER, trackerID, before, tracker)
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(tracker)
 }
 
+// GetTargetTrackerSegments gets the goal configurations of a target tracker
+// @Summary Get target tracker segments
+// @Description List the goal value, goal date and addToTotal configurations of a target tracker in the order they took effect. Each applies to the entries until the next one, the first also to the entries before it.
+// @Tags Target Trackers
+// @Produce json
+// @Param id path int true "Target Tracker ID"
+// @Success 200 {array} target.Segment
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Not Found"
+// @Security BearerAuth
+// @Router /target-trackers/{id}/segments [get]
+func (h *Handler) GetTargetTrackerSegments(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+	trackerID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid tracker ID", http.StatusBadRequest)
+		return
+	}
+
+	segments, err := h.Store.GetTargetSegments(userID, trackerID)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Target tracker not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to get segments: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(segments)
+}
 
-// DeleteTargetTracker deletes a target tracker and all its entries
+// DeleteTargetTracker moves a target tracker and all its entries to the trash
 // @Summary Delete target tracker
-// @Description Delete a specific target tracker and all its associated entries
+// @Description Move a specific target tracker and all its associated entries to the trash, they can be restored until the trash is purged
 // @Tags Target Trackers
 // @Param id path int true "Target Tracker ID"
 // @Success 204 "No Content"
 // @Failure 400 {string} string "Bad Request"
 // @Failure 404 {string} string "Not Found"
+// @Security BearerAuth
 // @Router /target-trackers/{id} [delete]
-func DeleteTargetTracker(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) DeleteTargetTracker(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
 	vars := mux.Vars(r)
 	trackerID, err := strconv.Atoi(vars["id"])
 	if err != nil {
@@ -230,18 +424,22 @@ func DeleteTargetTracker(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-  err = database.DeleteTargetTracker(trackerID)
-
+	before, _ := h.Store.GetTargetTrackerByID(userID, trackerID)
+	err = h.Store.DeleteTargetTracker(userID, trackerID, h.Now())
 
+	if err == sql.ErrNoRows {
+		http.Error(w, "Target tracker not found", http.StatusNotFound)
+		return
+	}
 	if err != nil {
 		http.Error(w, "Failed to delete target tracker: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
+	h.audit(r, models.AUDIT_DELETE, models.AUDIT_TARGET_TRACKER, trackerID, before, nil)
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
-
 // AddTargetEntry adds an entry to a target tracker
 // @Summary Add target entry
 // @Description Add a new entry to a specific target tracker
@@ -253,67 +451,66 @@ func DeleteTargetTracker(w http.ResponseWriter, r *http.Request) {
 // @Success 201 {object} models.Entry
 // @Failure 400 {string} string "Bad Request"
 // @Failure 404 {string} string "Not Found"
+// @Security BearerAuth
 // @Router /target-trackers/{id}/entries [post]
-func AddTargetEntry(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) AddTargetEntry(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
 	vars := mux.Vars(r)
 	trackerID, err := strconv.Atoi(vars["id"])
 	if err != nil {
 		http.Error(w, "Invalid tracker ID", http.StatusBadRequest)
 		return
 	}
-	
+
 	// Check if target tracker exists using database
-	_, err = database.GetTargetTrackerByID(trackerID)
+	_, err = h.Store.GetTargetTrackerByID(userID, trackerID)
 	if err != nil {
 		http.Error(w, "Target tracker not found", http.StatusNotFound)
 		return
 	}
-	
+
 	var req models.AddEntryRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "Invalid JSON: "+err.Error(), http.StatusBadRequest)
 		return
 	}
-	
+	if req.Skipped != nil && *req.Skipped {
+		http.Error(w, "Only habit entries can be skipped", http.StatusBadRequest)
+		return
+	}
+
 	// Parse date or use now
-	var entryDate time.Time
+	entryDate := h.Now().UTC()
 	if req.Date != "" {
-		// Try RFC3339 format first (datetime with timezone)
-		entryDate, err = time.Parse(time.RFC3339, req.Date)
+		loc, err := h.location(r)
 		if err != nil {
-			// Try date-only format - assume local timezone, then convert to UTC
-			localDate, err := time.Parse("2006-01-02", req.Date)
-			if err != nil {
-				http.Error(w, "Invalid date format. Use YYYY-MM-DD or RFC3339 (2006-01-02T15:04:05Z07:00)", http.StatusBadRequest)
-				return
-			}
-			// For date-only, use current time but with the specified date
-			now := time.Now()
-			entryDate = time.Date(localDate.Year(), localDate.Month(), localDate.Day(), 
-				now.Hour(), now.Minute(), now.Second(), now.Nanosecond(), now.Location())
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		entryDate, _, err = parseEntryDate(req.Date, loc)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
 		}
-		// Convert to UTC for consistent storage
-		entryDate = entryDate.UTC()
-	} else {
-		entryDate = time.Now().UTC()
 	}
-	
+
 	entry := models.Entry{
 		TrackerID: trackerID,
 		Type:      models.TARGET,
 		Value:     req.Value,
 		Date:      entryDate,
 		Note:      req.Note,
-		CreatedAt: time.Now(),
+		CreatedAt: h.Now(),
 	}
-	
+
 	// Use your database helper
-	createdEntry, err := database.CreateEntry(entry)
+	createdEntry, err := h.Store.CreateEntry(userID, entry)
 	if err != nil {
 		http.Error(w, "Failed to create entry: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
-	
+	h.audit(r, models.AUDIT_CREATE, models.AUDIT_ENTRY, createdEntry.ID, nil, createdEntry)
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(createdEntry)
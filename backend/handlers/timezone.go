@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"routine-tracker/auth"
+	"time"
+)
+
+// TimeZoneHeader overrides the user's time zone for a single request
+const TimeZoneHeader = "X-Time-Zone"
+
+// loadTimeZone parses an IANA time zone name. The server's own zone is not
+// accepted because it depends on where the server runs.
+func loadTimeZone(name string) (*time.Location, error) {
+	if name == "" || name == "Local" {
+		return nil, errors.New("Invalid time zone '" + name + "'")
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, errors.New("Invalid time zone '" + name + "'")
+	}
+	return loc, nil
+}
+
+// location returns the time zone of the request: the X-Time-Zone header when
+// it is given, the user's time zone otherwise
+func (h *Handler) location(r *http.Request) (*time.Location, error) {
+	if name := r.Header.Get(TimeZoneHeader); name != "" {
+		return loadTimeZone(name)
+	}
+
+	user, err := h.Store.GetUserByID(auth.UserID(r.Context()))
+	if err != nil {
+		return nil, err
+	}
+	return user.Location(), nil
+}
+
+// today returns the day of the "date" query parameter, or the current time,
+// in the request's time zone
+func (h *Handler) today(r *http.Request) (time.Time, error) {
+	loc, err := h.location(r)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	dateParam := r.URL.Query().Get("date")
+	if dateParam == "" {
+		return h.Now().In(loc), nil
+	}
+
+	date, err := time.ParseInLocation("2006-01-02", dateParam, loc)
+	if err != nil {
+		return time.Time{}, errors.New("Invalid date format. Please use YYYY-MM-DD format")
+	}
+	return date, nil
+}
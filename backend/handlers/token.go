@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"routine-tracker/auth"
+	"routine-tracker/models"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// GetAPITokens gets the personal API tokens of the current user
+// @Summary Get API tokens
+// @Description List personal API tokens with their scopes and last use. Token values are never returned again.
+// @Tags API Tokens
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} models.APIToken
+// @Router /tokens [get]
+func (h *Handler) GetAPITokens(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+	tokens, err := h.Store.GetAPITokens(userID)
+	if err != nil {
+		http.Error(w, "Failed to get API tokens: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tokens)
+}
+
+// CreateAPIToken creates a personal API token
+// @Summary Create API token
+// @Description Create a long-lived token restricted to the given scopes. The token value is only returned in this response.
+// @Tags API Tokens
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param token body models.CreateAPITokenRequest true "Token name and scopes"
+// @Success 201 {object} models.CreateAPITokenResponse
+// @Failure 400 {string} string "Bad Request"
+// @Router /tokens [post]
+func (h *Handler) CreateAPIToken(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+
+	var req models.CreateAPITokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		http.Error(w, "Token name is required", http.StatusBadRequest)
+		return
+	}
+	if len(req.Scopes) == 0 {
+		http.Error(w, "At least one scope is required. Valid scopes: "+strings.Join(auth.Scopes, ", "), http.StatusBadRequest)
+		return
+	}
+	for _, scope := range req.Scopes {
+		if !auth.IsValidScope(scope) {
+			http.Error(w, "Invalid scope '"+scope+"'. Valid scopes: "+strings.Join(auth.Scopes, ", "), http.StatusBadRequest)
+			return
+		}
+	}
+
+	secret, err := auth.NewToken()
+	if err != nil {
+		http.Error(w, "Failed to create token", http.StatusInternalServerError)
+		return
+	}
+	token := auth.APITokenPrefix + secret
+
+	created, err := h.Store.CreateAPIToken(userID, req.Name, req.Scopes, auth.HashToken(token))
+	if err != nil {
+		http.Error(w, "Failed to create API token: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.audit(r, models.AUDIT_CREATE, models.AUDIT_API_TOKEN, created.ID, nil, created)
+
+	response := models.CreateAPITokenResponse{
+		APIToken: *created,
+		Token:    token,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+// RevokeAPIToken revokes a personal API token
+// @Summary Revoke API token
+// @Description Revoke a personal API token, requests using it are rejected right away
+// @Tags API Tokens
+// @Security BearerAuth
+// @Param id path int true "API Token ID"
+// @Success 204 "No Content"
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Not Found"
+// @Router /tokens/{id} [delete]
+func (h *Handler) RevokeAPIToken(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+	vars := mux.Vars(r)
+	tokenID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid token ID", http.StatusBadRequest)
+		return
+	}
+
+	before, _ := h.Store.GetAPITokenByID(userID, tokenID)
+	err = h.Store.DeleteAPIToken(userID, tokenID)
+	if err == sql.ErrNoRows {
+		http.Error(w, "API token not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to revoke API token: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.audit(r, models.AUDIT_DELETE, models.AUDIT_API_TOKEN, tokenID, before, nil)
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"routine-tracker/auth"
+	"routine-tracker/models"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// GetTrash lists the deleted trackers and entries
+// @Summary Get trash
+// @Description List the deleted trackers and entries that can still be restored, the most recently deleted first.
+// @Description Entries of deleted trackers are not listed, they are restored with their tracker. Items are purged retentionDays after they were deleted.
+// @Tags Trash
+// @Produce json
+// @Success 200 {object} trackers.TrashResponse
+// @Security BearerAuth
+// @Router /trash [get]
+func (h *Handler) GetTrash(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+	trash, err := h.Store.GetTrash(userID)
+	if err != nil {
+		http.Error(w, "Failed to get trash: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	trash.RetentionDays = h.Config.Trash.RetentionDays
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(trash)
+}
+
+// EmptyTrash deletes everything in the trash for good
+// @Summary Empty trash
+// @Description Delete all trackers and entries in the trash for good, without waiting for the retention period
+// @Tags Trash
+// @Success 204 "No Content"
+// @Security BearerAuth
+// @Router /trash [delete]
+func (h *Handler) EmptyTrash(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+	purged, err := h.Store.EmptyTrash(userID)
+	if err != nil {
+		http.Error(w, "Failed to empty trash: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.audit(r, models.AUDIT_PURGE, models.AUDIT_USER, userID, nil, map[string]int{"purged": purged})
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RestoreEntries takes entries out of the trash
+// @Summary Restore entries
+// @Description Restore deleted entries by their IDs, e.g. to undo a delete. Entries of deleted trackers are restored with their tracker instead.
+// @Tags Trash
+// @Accept json
+// @Param ids body []int true "Array of entry IDs to restore"
+// @Success 204 "No Content"
+// @Failure 400 {string} string "Bad Request"
+// @Security BearerAuth
+// @Router /trash/entries/restore [post]
+func (h *Handler) RestoreEntries(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+	var entryIDs []int
+
+	if err := json.NewDecoder(r.Body).Decode(&entryIDs); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if len(entryIDs) == 0 {
+		http.Error(w, "No entry IDs provided", http.StatusBadRequest)
+		return
+	}
+
+	restored, err := h.Store.RestoreEntries(userID, entryIDs)
+	if err != nil {
+		http.Error(w, "Failed to restore entries: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	for _, id := range restored {
+		entry, err := h.Store.GetEntryByID(userID, id)
+		if err == nil {
+			h.audit(r, models.AUDIT_RESTORE, models.AUDIT_ENTRY, id, nil, entry)
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RestoreHabitTracker takes a habit tracker out of the trash
+// @Summary Restore habit tracker
+// @Description Restore a deleted habit tracker together with the entries deleted with it
+// @Tags Trash
+// @Produce json
+// @Param id path int true "Habit Tracker ID"
+// @Success 200 {object} habit.HabitTracker
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Not Found"
+// @Security BearerAuth
+// @Router /trash/habit-trackers/{id}/restore [post]
+func (h *Handler) RestoreHabitTracker(w http.ResponseWriter, r *http.Request) {
+	h.restoreTracker(w, r, models.HABIT)
+}
+
+// RestoreTargetTracker takes a target tracker out of the trash
+// @Summary Restore target tracker
+// @Description Restore a deleted target tracker together with the entries deleted with it
+// @Tags Trash
+// @Produce json
+// @Param id path int true "Target Tracker ID"
+// @Success 200 {object} target.TargetTracker
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Not Found"
+// @Security BearerAuth
+// @Router /trash/target-trackers/{id}/restore [post]
+func (h *Handler) RestoreTargetTracker(w http.ResponseWriter, r *http.Request) {
+	h.restoreTracker(w, r, models.TARGET)
+}
+
+// RestoreDurationTracker takes a duration tracker out of the trash
+// @Summary Restore duration tracker
+// @Description Restore a deleted duration tracker together with the entries deleted with it
+// @Tags Trash
+// @Produce json
+// @Param id path int true "Duration Tracker ID"
+// @Success 200 {object} duration.DurationTracker
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Not Found"
+// @Security BearerAuth
+// @Router /trash/duration-trackers/{id}/restore [post]
+func (h *Handler) RestoreDurationTracker(w http.ResponseWriter, r *http.Request) {
+	h.restoreTracker(w, r, models.DURATION)
+}
+
+// restoreTracker restores a tracker from the trash and responds with it
+func (h *Handler) restoreTracker(w http.ResponseWriter, r *http.Request, trackerType models.TrackerType) {
+	userID := auth.UserID(r.Context())
+	trackerID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid tracker ID", http.StatusBadRequest)
+		return
+	}
+
+	switch trackerType {
+	case models.HABIT:
+		err = h.Store.RestoreHabitTracker(userID, trackerID)
+	case models.TARGET:
+		err = h.Store.RestoreTargetTracker(userID, trackerID)
+	case models.DURATION:
+		err = h.Store.RestoreDurationTracker(userID, trackerID)
+	}
+	if err == sql.ErrNoRows {
+		http.Error(w, "Tracker not found in the trash", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to restore tracker: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var tracker interface{}
+	switch trackerType {
+	case models.HABIT:
+		habit, err := h.Store.GetHabitTrackerByID(userID, trackerID)
+		if err != nil {
+			http.Error(w, "Failed to get habit tracker: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		h.audit(r, models.AUDIT_RESTORE, models.AUDIT_HABIT_TRACKER, trackerID, nil, habit)
+		tracker = habit
+	case models.TARGET:
+		target, err := h.Store.GetTargetTrackerByID(userID, trackerID)
+		if err != nil {
+			http.Error(w, "Failed to get target tracker: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		h.audit(r, models.AUDIT_RESTORE, models.AUDIT_TARGET_TRACKER, trackerID, nil, target)
+		h.setCurrentValues(target)
+		tracker = target
+	case models.DURATION:
+		duration, err := h.Store.GetDurationTrackerByID(userID, trackerID)
+		if err != nil {
+			http.Error(w, "Failed to get duration tracker: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		h.audit(r, models.AUDIT_RESTORE, models.AUDIT_DURATION_TRACKER, trackerID, nil, duration)
+		if today, err := h.today(r); err == nil {
+			h.setCurrentTotal(duration, today)
+		}
+		tracker = duration
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tracker)
+}
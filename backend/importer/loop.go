@@ -0,0 +1,355 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Loop Habit Tracker checkmark values of yes/no habits
+const (
+	LOOP_UNKNOWN    = -1
+	LOOP_NO         = 0
+	LOOP_YES_AUTO   = 1 // implied by the habit's frequency, not entered by the user
+	LOOP_YES_MANUAL = 2
+	LOOP_SKIP       = 3
+)
+
+// LoopHabit is a habit read from a Loop Habit Tracker backup
+type LoopHabit struct {
+	Name         string
+	FreqNum      int
+	FreqDen      int
+	Numerical    bool
+	TargetValue  float64
+	TargetAtMost bool // numerical habits only, "at most" instead of "at least"
+	Unit         string
+	Archived     bool
+	ReminderHour *int
+	ReminderMin  *int
+	ReminderDays int // bitmask, bit 0 is Sunday
+	Checkmarks   []LoopCheckmark
+}
+
+// LoopCheckmark is the value of a habit on one day. Yes/no habits use the
+// LOOP_* values, numerical habits the amount entered.
+type LoopCheckmark struct {
+	Date  time.Time
+	Value float64
+}
+
+// ParseLoopBackup reads a Loop Habit Tracker backup, either the SQLite
+// database (.db) or the zipped CSV export
+func ParseLoopBackup(data []byte) ([]LoopHabit, error) {
+	switch {
+	case bytes.HasPrefix(data, []byte("SQLite format 3\x00")):
+		return parseLoopDatabase(data)
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")):
+		return parseLoopCSVExport(data)
+	default:
+		return nil, errors.New("unknown backup format, expected a Loop Habit Tracker .db backup or CSV export .zip")
+	}
+}
+
+func parseLoopDatabase(data []byte) ([]LoopHabit, error) {
+	// The SQLite driver can only open files
+	file, err := os.CreateTemp("", "loop-backup-*.db")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return nil, err
+	}
+	file.Close()
+
+	db, err := sql.Open("sqlite3", "file:"+file.Name()+"?mode=ro")
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	habitRows, err := queryMaps(db, "SELECT * FROM Habits ORDER BY position")
+	if err != nil {
+		return nil, fmt.Errorf("reading habits: %w", err)
+	}
+
+	habits := make([]LoopHabit, 0, len(habitRows))
+	ids := make(map[int64]int)
+	for _, row := range habitRows {
+		h := LoopHabit{
+			Name:         asString(row["name"]),
+			FreqNum:      int(asInt(row["freq_num"], 1)),
+			FreqDen:      int(asInt(row["freq_den"], 1)),
+			Numerical:    asInt(row["type"], 0) == 1,
+			TargetValue:  asFloat(row["target_value"]),
+			TargetAtMost: asInt(row["target_type"], 0) == 1,
+			Unit:         asString(row["unit"]),
+			Archived:     asInt(row["archived"], 0) == 1,
+			ReminderDays: int(asInt(row["reminder_days"], 127)),
+		}
+		if row["reminder_hour"] != nil && row["reminder_min"] != nil {
+			hour, min := int(asInt(row["reminder_hour"], 0)), int(asInt(row["reminder_min"], 0))
+			h.ReminderHour, h.ReminderMin = &hour, &min
+		}
+		ids[asInt(row["id"], 0)] = len(habits)
+		habits = append(habits, h)
+	}
+
+	repetitions, err := queryMaps(db, "SELECT * FROM Repetitions ORDER BY timestamp")
+	if err != nil {
+		return nil, fmt.Errorf("reading repetitions: %w", err)
+	}
+
+	for _, row := range repetitions {
+		i, ok := ids[asInt(row["habit"], 0)]
+		if !ok {
+			continue
+		}
+		value := asFloat(row["value"])
+		if habits[i].Numerical {
+			// Numerical values are stored in thousandths
+			value /= 1000
+		}
+		habits[i].Checkmarks = append(habits[i].Checkmarks, LoopCheckmark{
+			Date:  time.UnixMilli(asInt(row["timestamp"], 0)).UTC(),
+			Value: value,
+		})
+	}
+
+	return habits, nil
+}
+
+func parseLoopCSVExport(data []byte) ([]LoopHabit, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]*zip.File)
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+
+	habitsFile, ok := files["Habits.csv"]
+	if !ok {
+		return nil, errors.New("Habits.csv is missing from the export")
+	}
+	records, err := readZippedCSV(habitsFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading Habits.csv: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, errors.New("Habits.csv is empty")
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	field := func(record []string, names ...string) string {
+		for _, name := range names {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+		}
+		return ""
+	}
+
+	habits := make([]LoopHabit, 0, len(records)-1)
+	for _, record := range records[1:] {
+		h := LoopHabit{
+			Name:         field(record, "name"),
+			FreqNum:      atoiDefault(field(record, "frequencynumerator", "numrepetitions"), 1),
+			FreqDen:      atoiDefault(field(record, "frequencydenominator", "interval"), 1),
+			Unit:         field(record, "unit"),
+			ReminderDays: 127,
+		}
+		habitType := strings.ToUpper(field(record, "type"))
+		h.Numerical = habitType == "NUMERICAL" || habitType == "1"
+		targetType := strings.ToUpper(field(record, "target type"))
+		h.TargetAtMost = targetType == "AT_MOST" || targetType == "1"
+		h.TargetValue, _ = strconv.ParseFloat(field(record, "target value"), 64)
+		archived := strings.ToLower(field(record, "archived?", "archived"))
+		h.Archived = archived == "true" || archived == "1"
+
+		// Each habit has a folder like "001 Meditate" with its own Checkmarks.csv
+		position := field(record, "position")
+		for name, f := range files {
+			dir, base := path.Split(name)
+			dir = strings.TrimSuffix(dir, "/")
+			if base != "Checkmarks.csv" || dir == "" {
+				continue
+			}
+			if dir != position+" "+h.Name && !(position == "" && strings.HasSuffix(dir, " "+h.Name)) {
+				continue
+			}
+			checkmarks, err := readLoopCheckmarks(f, h.Numerical)
+			if err != nil {
+				return nil, fmt.Errorf("reading %s: %w", name, err)
+			}
+			h.Checkmarks = checkmarks
+		}
+
+		habits = append(habits, h)
+	}
+
+	return habits, nil
+}
+
+func readLoopCheckmarks(f *zip.File, numerical bool) ([]LoopCheckmark, error) {
+	records, err := readZippedCSV(f)
+	if err != nil {
+		return nil, err
+	}
+
+	checkmarks := make([]LoopCheckmark, 0, len(records))
+	for _, record := range records {
+		if len(record) < 2 {
+			continue
+		}
+		date, err := time.Parse("2006-01-02", strings.TrimSpace(record[0]))
+		if err != nil {
+			// Header row or a line we cannot read
+			continue
+		}
+
+		raw := strings.TrimSpace(record[1])
+		var value float64
+		switch strings.ToUpper(raw) {
+		case "YES_MANUAL":
+			value = LOOP_YES_MANUAL
+		case "YES_AUTO":
+			value = LOOP_YES_AUTO
+		case "NO":
+			value = LOOP_NO
+		case "SKIP":
+			value = LOOP_SKIP
+		case "UNKNOWN", "":
+			value = LOOP_UNKNOWN
+		default:
+			value, err = strconv.ParseFloat(raw, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid checkmark value '%s'", raw)
+			}
+		}
+		if numerical && value < 0 {
+			continue
+		}
+
+		checkmarks = append(checkmarks, LoopCheckmark{Date: date, Value: value})
+	}
+
+	sort.Slice(checkmarks, func(i, j int) bool {
+		return checkmarks[i].Date.Before(checkmarks[j].Date)
+	})
+	return checkmarks, nil
+}
+
+func readZippedCSV(f *zip.File) ([][]string, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	content, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.FieldsPerRecord = -1
+	return reader.ReadAll()
+}
+
+// queryMaps returns rows as column name to value maps, Loop's schema
+// changed between versions so columns are looked up by name
+func queryMaps(db *sql.DB, query string) ([]map[string]interface{}, error) {
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]map[string]interface{}, 0)
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+
+		row := make(map[string]interface{})
+		for i, column := range columns {
+			row[strings.ToLower(column)] = values[i]
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
+func asString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	}
+	return ""
+}
+
+func asInt(value interface{}, fallback int64) int64 {
+	switch v := value.(type) {
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	case bool:
+		if v {
+			return 1
+		}
+		return 0
+	}
+	return fallback
+}
+
+func asFloat(value interface{}) float64 {
+	switch v := value.(type) {
+	case int64:
+		return float64(v)
+	case float64:
+		return v
+	}
+	return 0
+}
+
+func atoiDefault(value string, fallback int) int {
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		return fallback
+	}
+	return n
+}
@@ -0,0 +1,185 @@
+package importer
+
+import (
+	"fmt"
+	"math"
+	"routine-tracker/models"
+	"routine-tracker/trackers"
+	"routine-tracker/trackers/habit"
+	"routine-tracker/trackers/target"
+	"time"
+)
+
+// Unmapped describes a setting or data of the backup that could not be
+// imported as is
+type Unmapped struct {
+	Habit  string `json:"habit" example:"Meditate"`
+	Reason string `json:"reason" example:"'at most' targets are not supported, the target is treated as 'at least'"`
+}
+
+// LoopImportResult reports what a Loop Habit Tracker import changed
+type LoopImportResult struct {
+	trackers.ImportResult
+	Unmapped []Unmapped `json:"unmapped"`
+}
+
+var weekdays = []string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}
+
+// LoopArchive converts Loop habits into an archive that can be imported.
+// Yes/no habits become habit trackers, numerical habits target trackers.
+// Days are placed in loc, the time zone of the importing user.
+func LoopArchive(habits []LoopHabit, now time.Time, loc *time.Location) (trackers.Archive, []Unmapped) {
+	archive := trackers.Archive{
+		Version:        trackers.ArchiveVersion,
+		ExportedAt:     now,
+		HabitTrackers:  make([]habit.HabitTracker, 0),
+		TargetTrackers: make([]target.TargetTracker, 0),
+		Entries:        make([]models.Entry, 0),
+	}
+	unmapped := make([]Unmapped, 0)
+
+	for i, h := range habits {
+		id := i + 1
+		report := func(format string, args ...interface{}) {
+			unmapped = append(unmapped, Unmapped{Habit: h.Name, Reason: fmt.Sprintf(format, args...)})
+		}
+
+		if h.Name == "" {
+			report("habit %d has no name and was not imported", id)
+			continue
+		}
+		// Habits archived in Loop stay archived, from the time of the import
+		var archivedAt *time.Time
+		if h.Archived {
+			archivedAt = &now
+		}
+
+		goal, period, due, approximated := loopFrequency(h)
+		if approximated {
+			report("frequency of %d times every %d days was approximated as %v times per week", h.FreqNum, h.FreqDen, goal)
+		}
+
+		reminders := models.Reminder{Times: []string{}}
+		if h.ReminderHour != nil && h.ReminderMin != nil {
+			reminders = models.Reminder{Times: []string{fmt.Sprintf("%02d:%02d", *h.ReminderHour, *h.ReminderMin)}, Enabled: true}
+		}
+
+		entries := loopEntries(h, id, loc)
+
+		startDate := today(now, loc)
+		if len(entries) > 0 {
+			first := entries[0].Date.In(loc)
+			startDate = time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, time.UTC)
+		}
+
+		if h.Numerical {
+			name := h.Name
+			if h.Unit != "" {
+				name += " (" + h.Unit + ")"
+			}
+			goalDate := today(now, loc).AddDate(1, 0, 0)
+			report("numerical habit imported as a target tracker with a goal of %v per entry, the goal date was set to %s", h.TargetValue, goalDate.Format("2006-01-02"))
+			if h.TargetAtMost {
+				report("'at most' targets are not supported, the target is treated as 'at least'")
+			}
+
+			archive.TargetTrackers = append(archive.TargetTrackers, target.TargetTracker{
+				ID:          id,
+				TrackerName: name,
+				StartValue:  0,
+				GoalValue:   h.TargetValue,
+				StartDate:   startDate,
+				GoalDate:    goalDate,
+				Due:         due,
+				Reminders:   reminders,
+				CreatedAt:   now,
+				ArchivedAt:  archivedAt,
+			})
+		} else {
+			archive.HabitTrackers = append(archive.HabitTrackers, habit.HabitTracker{
+				ID:          id,
+				TrackerName: h.Name,
+				Goal:        goal,
+				TimePeriod:  period,
+				StartDate:   startDate,
+				Due:         due,
+				Reminders:   reminders,
+				CreatedAt:   now,
+				ArchivedAt:  archivedAt,
+			})
+		}
+
+		archive.Entries = append(archive.Entries, entries...)
+	}
+
+	return archive, unmapped
+}
+
+// loopFrequency maps Loop's "num times every den days" to a goal per period
+// and the days the tracker is due. approximated is true when the frequency
+// has no exact equivalent.
+func loopFrequency(h LoopHabit) (float64, models.TimePeriod, models.Due, bool) {
+	num, den := h.FreqNum, h.FreqDen
+	daily := models.Due{Type: models.INTERVAL, IntervalType: "day", IntervalValue: 1}
+
+	switch {
+	case num >= den:
+		return 1, models.PER_DAY, daily, false
+	case den == 7:
+		// Weekly habits with reminders on exactly as many days are due on those days
+		days := make([]string, 0)
+		for i, day := range weekdays {
+			if h.ReminderDays&(1<<i) != 0 {
+				days = append(days, day)
+			}
+		}
+		if len(days) == num {
+			return float64(num), models.PER_WEEK, models.Due{Type: models.SPECIFIC_DAYS, SpecificDays: days}, false
+		}
+		return float64(num), models.PER_WEEK, daily, false
+	case den >= 28 && den <= 31:
+		return float64(num), models.PER_MONTH, daily, false
+	case den == 365 || den == 366:
+		return float64(num), models.PER_YEAR, daily, false
+	case num == 1:
+		return 1, models.PER_DAY, models.Due{Type: models.INTERVAL, IntervalType: "day", IntervalValue: den}, false
+	default:
+		perWeek := math.Max(1, math.Round(float64(num)*7/float64(den)))
+		return perWeek, models.PER_WEEK, daily, true
+	}
+}
+
+// loopEntries turns checkmarks into entries. Only values the user entered are
+// imported, days Loop fills in itself are left out. Skipped days become
+// skipped entries.
+func loopEntries(h LoopHabit, trackerID int, loc *time.Location) []models.Entry {
+	entries := make([]models.Entry, 0)
+
+	for _, c := range h.Checkmarks {
+		// Loop stores days as UTC midnight, entries are placed at noon in loc
+		date := time.Date(c.Date.Year(), c.Date.Month(), c.Date.Day(), 12, 0, 0, 0, loc).UTC()
+
+		if h.Numerical {
+			if c.Value > 0 {
+				entries = append(entries, models.Entry{TrackerID: trackerID, Type: models.TARGET, Value: c.Value, Date: date})
+			}
+			continue
+		}
+
+		switch c.Value {
+		case LOOP_YES_MANUAL:
+			done := true
+			entries = append(entries, models.Entry{TrackerID: trackerID, Type: models.HABIT, Done: &done, Date: date})
+		case LOOP_SKIP:
+			done := false
+			entries = append(entries, models.Entry{TrackerID: trackerID, Type: models.HABIT, Done: &done, Skipped: true, Date: date})
+		}
+	}
+
+	return entries
+}
+
+func today(now time.Time, loc *time.Location) time.Time {
+	now = now.In(loc)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -1,48 +1,92 @@
 package main
 
 import (
+	"context"
 	"log"
+	"log/slog"
 	"net/http"
+	"os"
+	"routine-tracker/app"
+	"routine-tracker/config"
 	"routine-tracker/database"
-  "routine-tracker/router"
+	"routine-tracker/handlers"
+	"routine-tracker/reminders"
+	"routine-tracker/router"
+	"routine-tracker/trash"
 
 	"github.com/rs/cors"
 	_ "routine-tracker/docs" // This will be generated
 )
 
-
 // @title Habit & Target Tracker API
 // @version 1.0
 // @description API for managing habit and target trackers
 // @host localhost:8080
 // @BasePath /api
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
 
 func main() {
-	if err := database.Init(); err != nil {
+	cfg, args, err := config.Load(os.Args[1:], os.Getenv)
+	if err != nil {
+		log.Fatal("Invalid configuration: ", err)
+	}
+
+	level, _ := cfg.LogLevel()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+	slog.SetDefault(logger)
+
+	// Manage the schema without starting the server:
+	// progress [flags] migrate status | up [version] | down [steps]
+	if len(args) > 0 && args[0] == "migrate" {
+		store, err := database.Open(cfg.Database.DSN, logger)
+		if err != nil {
+			log.Fatal("Failed to open database:", err)
+		}
+		defer store.Close()
+
+		if err := database.MigrateCommand(store, args[1:], os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	store, err := database.Init(cfg.Database.DSN, logger)
+	if err != nil {
 		log.Fatal("Failed to initialize database:", err)
 	}
-	defer database.Close()
+	defer store.Close()
+
+	application := app.New(cfg, store, logger)
+
+	// Send tracker reminders in the background
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if cfg.Features.Reminders {
+		go reminders.NewScheduler(application).Run(ctx)
+	}
+
+	// Delete trashed trackers and entries after the retention period
+	go trash.NewPurger(application).Run(ctx)
+
 	// Initialize router
-  r := router.Setup()
+	r := router.Setup(application)
 
 	// Setup CORS
 	c := cors.New(cors.Options{
-		AllowedOrigins: []string{
-			"http://localhost:5173",  // Vite dev server
-			"http://localhost:5174",  // Vite dev server
-			"http://localhost:3000",  // Docker serve
-			"http://192.168.1.101:5173", // Mobile testing
-			"https://my-progress.sahinakkaya.dev", // Personal instance
-			"https://progress.sahinakkaya.dev",    // Showcase instance
-		},
-		AllowedMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders: []string{"Content-Type", "Authorization"},
+		AllowedOrigins:   cfg.CORS.AllowedOrigins,
+		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Content-Type", "Authorization", handlers.TimeZoneHeader},
 		AllowCredentials: true,
 	})
 
 	handler := c.Handler(r)
-  router.PrintRoutes("8080")
-
+	r.PrintRoutes()
+	cfg.Print(os.Stdout)
 
-	log.Fatal(http.ListenAndServe(":8080", handler))
+	if cfg.TLS() {
+		log.Fatal(http.ListenAndServeTLS(cfg.Server.Addr, cfg.Server.TLSCert, cfg.Server.TLSKey, handler))
+	}
+	log.Fatal(http.ListenAndServe(cfg.Server.Addr, handler))
 }
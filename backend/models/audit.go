@@ -0,0 +1,85 @@
+package models
+
+import (
+	"time"
+)
+
+// AuditAction is what happened to an audited entity
+type AuditAction string
+
+const (
+	AUDIT_CREATE    AuditAction = "create"
+	AUDIT_UPDATE    AuditAction = "update"
+	AUDIT_DELETE    AuditAction = "delete"  // moved to the trash, or deleted for good for entities without trash
+	AUDIT_RESTORE   AuditAction = "restore" // taken out of the trash
+	AUDIT_ARCHIVE   AuditAction = "archive"
+	AUDIT_UNARCHIVE AuditAction = "unarchive"
+	AUDIT_IMPORT    AuditAction = "import"
+	AUDIT_PURGE     AuditAction = "purge" // the trash was emptied
+)
+
+// AuditEntity is the kind of data an audit event is about
+type AuditEntity string
+
+const (
+	AUDIT_HABIT_TRACKER        AuditEntity = "habit_tracker"
+	AUDIT_TARGET_TRACKER       AuditEntity = "target_tracker"
+	AUDIT_DURATION_TRACKER     AuditEntity = "duration_tracker"
+	AUDIT_ENTRY                AuditEntity = "entry"
+	AUDIT_TIMER                AuditEntity = "timer"
+	AUDIT_PAUSE                AuditEntity = "pause"
+	AUDIT_NOTIFICATION_CHANNEL AuditEntity = "notification_channel"
+	AUDIT_API_TOKEN            AuditEntity = "api_token"
+	AUDIT_TAG                  AuditEntity = "tag"
+	AUDIT_CATEGORY             AuditEntity = "category"
+	AUDIT_USER                 AuditEntity = "user" // account settings, imports and the trash
+)
+
+// AuditEntities lists every kind of audited entity
+var AuditEntities = []AuditEntity{AUDIT_HABIT_TRACKER, AUDIT_TARGET_TRACKER, AUDIT_DURATION_TRACKER, AUDIT_ENTRY, AUDIT_TIMER, AUDIT_PAUSE, AUDIT_NOTIFICATION_CHANNEL, AUDIT_API_TOKEN, AUDIT_TAG, AUDIT_CATEGORY, AUDIT_USER}
+
+// IsValid reports whether e is one of the known entities
+func (e AuditEntity) IsValid() bool {
+	for _, entity := range AuditEntities {
+		if entity == e {
+			return true
+		}
+	}
+	return false
+}
+
+// AuditActor tells how the user making a change was authenticated
+type AuditActor string
+
+const (
+	ACTOR_SESSION   AuditActor = "session"   // a login session, e.g. the web app
+	ACTOR_API_TOKEN AuditActor = "api_token" // a personal API token, see APITokenID
+)
+
+// AuditChange is the value of a field before and after a change. Before is
+// null for new entities and after is null for deleted ones.
+type AuditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// AuditEvent records one change of a user's data. Events are never changed
+// or deleted.
+type AuditEvent struct {
+	ID         int                    `json:"id" example:"1"`
+	UserID     int                    `json:"-"`
+	Actor      AuditActor             `json:"actor" example:"session"`
+	APITokenID *int                   `json:"apiTokenId,omitempty" example:"2"` // the token used when actor is api_token
+	Action     AuditAction            `json:"action" example:"update"`
+	Entity     AuditEntity            `json:"entity" example:"target_tracker"`
+	EntityID   int                    `json:"entityId" example:"1"`
+	Changes    map[string]AuditChange `json:"changes"` // changed fields by their JSON name
+	CreatedAt  time.Time              `json:"createdAt" example:"2024-01-01T10:00:00Z"`
+}
+
+// AuditFilter selects audit events, the zero value selects all of a user
+type AuditFilter struct {
+	Entity   AuditEntity
+	EntityID *int
+	Limit    int // at most this many of the newest events, 0 for all
+}
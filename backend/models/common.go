@@ -8,8 +8,18 @@ import (
 type TrackerType string
 
 const (
-	HABIT  TrackerType = "habit"
-	TARGET TrackerType = "target"
+	HABIT    TrackerType = "habit"
+	TARGET   TrackerType = "target"
+	DURATION TrackerType = "duration"
+)
+
+// ArchiveFilter selects trackers by whether they are archived
+type ArchiveFilter string
+
+const (
+	ACTIVE_TRACKERS   ArchiveFilter = "active"   // the default, archived trackers are left out
+	ARCHIVED_TRACKERS ArchiveFilter = "archived" // only archived trackers
+	ALL_TRACKERS      ArchiveFilter = "all"
 )
 
 // TimePeriod represents the time period for habit goals
@@ -22,20 +32,59 @@ const (
 	PER_YEAR  TimePeriod = "perYear"
 )
 
+// Valid reports whether p is one of the time periods
+func (p TimePeriod) Valid() bool {
+	switch p {
+	case PER_DAY, PER_WEEK, PER_MONTH, PER_YEAR:
+		return true
+	}
+	return false
+}
+
+// Start returns the first day of the period containing day. Weeks start on
+// Monday.
+func (p TimePeriod) Start(day time.Time) time.Time {
+	switch p {
+	case PER_WEEK:
+		daysFromMonday := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -daysFromMonday)
+	case PER_MONTH:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, day.Location())
+	case PER_YEAR:
+		return time.Date(day.Year(), time.January, 1, 0, 0, 0, 0, day.Location())
+	}
+	return day
+}
+
+// Next returns the start of the period following the one starting at start
+func (p TimePeriod) Next(start time.Time) time.Time {
+	switch p {
+	case PER_WEEK:
+		return start.AddDate(0, 0, 7)
+	case PER_MONTH:
+		return start.AddDate(0, 1, 0)
+	case PER_YEAR:
+		return start.AddDate(1, 0, 0)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
 // DueType represents how the due scheduling works
 type DueType string
 
 const (
 	SPECIFIC_DAYS DueType = "specificDays" // e.g., ["sunday", "monday", "wednesday"]
-	INTERVAL      DueType = "interval"      // e.g., every 3 days/weeks/months/years
+	INTERVAL      DueType = "interval"     // e.g., every 3 days/weeks/months/years
+	RRULE         DueType = "rrule"        // e.g., "FREQ=MONTHLY;BYDAY=1MO" for the first Monday of each month
 )
 
 // Due represents when a tracker should appear on dashboard
 type Due struct {
 	Type          DueType  `json:"type" example:"specificDays"`
 	SpecificDays  []string `json:"specificDays,omitempty" example:"sunday,monday,wednesday"` // ["sunday", "monday", etc.]
-	IntervalType  string   `json:"intervalType,omitempty" example:"day"`                     // "day", "week", "month", "year"
+	IntervalType  string   `json:"intervalType,omitempty" example:"day"`                     // "day", "week", "month", "year", due on the start date's weekday, day of month or date of every Nth period, not on each of its days
 	IntervalValue int      `json:"intervalValue,omitempty" example:"3"`                      // every X days/weeks/etc
+	RRule         string   `json:"rrule,omitempty" example:"FREQ=MONTHLY;BYDAY=1MO"`         // RFC 5545 recurrence rule starting on the start date
 }
 
 // Reminder represents a notification time
@@ -47,26 +96,33 @@ type Reminder struct {
 // Entry represents a single tracking entry
 type Entry struct {
 	ID        int         `json:"id" example:"1"`
+	UserID    int         `json:"-"`
 	TrackerID int         `json:"trackerId" example:"1"`
-	Type      TrackerType `json:"type" example:"habit"` // "habit" or "target"
-	Value     float64     `json:"value"`                // For target trackers
-	Done      *bool       `json:"done,omitempty"`       // For habit trackers (true/false)
+	Type      TrackerType `json:"type" example:"habit"`              // "habit", "target" or "duration"
+	Value     float64     `json:"value"`                             // For target trackers
+	Done      *bool       `json:"done,omitempty"`                    // For habit trackers (true/false)
+	Skipped   bool        `json:"skipped,omitempty"`                 // For habit trackers, the day does not count
+	Duration  *int        `json:"duration,omitempty" example:"1800"` // For duration trackers, seconds spent from date on
 	Date      time.Time   `json:"date" example:"2024-01-01T00:00:00Z"`
 	Note      string      `json:"note,omitempty" example:"Felt great today"`
 	CreatedAt time.Time   `json:"createdAt" example:"2024-01-01T10:00:00Z"`
+	DeletedAt *time.Time  `json:"deletedAt,omitempty" example:"2024-06-01T10:00:00Z"` // set while the entry is in the trash
 }
 
-
 type AddEntryRequest struct {
-	Value float64 `json:"value,omitempty"`        // For target trackers
-	Done  *bool   `json:"done,omitempty"`         // For habit trackers
-	Date  string  `json:"date,omitempty" example:"2024-01-01T15:30:00Z"` // optional, defaults to now. Supports both date (YYYY-MM-DD) and datetime (RFC3339) formats
-	Note  string  `json:"note,omitempty" example:"Felt great today"`
+	Value    float64 `json:"value,omitempty"`                               // For target trackers
+	Done     *bool   `json:"done,omitempty"`                                // For habit trackers
+	Skipped  *bool   `json:"skipped,omitempty"`                             // For habit trackers, marks the day as skipped instead of done
+	Duration *int    `json:"duration,omitempty" example:"1800"`             // For duration trackers, seconds spent
+	Date     string  `json:"date,omitempty" example:"2024-01-01T15:30:00Z"` // optional, defaults to now. Supports both date (YYYY-MM-DD) and datetime (RFC3339) formats
+	Note     string  `json:"note,omitempty" example:"Felt great today"`
 }
 
 type UpdateEntryRequest struct {
-	Value *float64 `json:"value,omitempty"`        // For target trackers
-	Done  *bool    `json:"done,omitempty"`         // For habit trackers
-	Date  *string  `json:"date,omitempty" example:"2024-01-01T15:30:00Z"` // Supports both date (YYYY-MM-DD) and datetime (RFC3339) formats
-	Note  *string  `json:"note,omitempty" example:"Updated note"`
+	Value    *float64 `json:"value,omitempty"`                               // For target trackers
+	Done     *bool    `json:"done,omitempty"`                                // For habit trackers
+	Skipped  *bool    `json:"skipped,omitempty"`                             // For habit trackers
+	Duration *int     `json:"duration,omitempty" example:"1800"`             // For duration trackers, seconds spent
+	Date     *string  `json:"date,omitempty" example:"2024-01-01T15:30:00Z"` // Supports both date (YYYY-MM-DD) and datetime (RFC3339) formats
+	Note     *string  `json:"note,omitempty" example:"Updated note"`
 }
@@ -0,0 +1,14 @@
+package models
+
+// CSVImportResult reports the outcome of an entries CSV upload
+type CSVImportResult struct {
+	Created    int            `json:"created" example:"42"`
+	Duplicates int            `json:"duplicates" example:"3"` // rows matching an existing entry, not imported again
+	Errors     []CSVLineError `json:"errors"`                 // rows that could not be imported
+}
+
+// CSVLineError describes why a CSV row was rejected
+type CSVLineError struct {
+	Line  int    `json:"line" example:"7"`
+	Error string `json:"error" example:"invalid value 'abc'"`
+}
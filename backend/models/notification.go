@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+)
+
+// ChannelType represents how a notification is delivered
+type ChannelType string
+
+const (
+	CHANNEL_EMAIL   ChannelType = "email"   // target is an email address, sent through the server's SMTP settings
+	CHANNEL_WEBHOOK ChannelType = "webhook" // target is a URL receiving a JSON POST
+	CHANNEL_NTFY    ChannelType = "ntfy"    // target is an ntfy topic URL, e.g. https://ntfy.sh/my-habits
+	CHANNEL_GOTIFY  ChannelType = "gotify"  // target is the Gotify server URL, token is the application token
+)
+
+// NotificationChannel is a place where a user's notifications are delivered.
+// Channels without a tracker apply to all trackers of the user, a tracker's own
+// channels replace them for that tracker.
+type NotificationChannel struct {
+	ID          int          `json:"id" example:"1"`
+	UserID      int          `json:"-"`
+	Name        string       `json:"name" example:"My phone"`
+	Type        ChannelType  `json:"type" example:"ntfy"`
+	Target      string       `json:"target" example:"https://ntfy.sh/my-habits"`
+	Token       string       `json:"token,omitempty" example:"tk_secret"` // sent as bearer token (webhook, ntfy) or app token (gotify)
+	TrackerType *TrackerType `json:"trackerType,omitempty" example:"habit"`
+	TrackerID   *int         `json:"trackerId,omitempty" example:"1"`
+	Enabled     bool         `json:"enabled" example:"true"`
+	CreatedAt   time.Time    `json:"createdAt" example:"2024-01-01T10:00:00Z"`
+}
+
+type CreateNotificationChannelRequest struct {
+	Name        string       `json:"name" example:"My phone"`
+	Type        ChannelType  `json:"type" example:"ntfy"`
+	Target      string       `json:"target" example:"https://ntfy.sh/my-habits"`
+	Token       string       `json:"token,omitempty" example:"tk_secret"`
+	TrackerType *TrackerType `json:"trackerType,omitempty" example:"habit"` // optional, restricts the channel to one tracker
+	TrackerID   *int         `json:"trackerId,omitempty" example:"1"`
+	Enabled     *bool        `json:"enabled,omitempty" example:"true"` // defaults to true
+}
+
+type UpdateNotificationChannelRequest struct {
+	Name    *string `json:"name,omitempty" example:"My phone"`
+	Target  *string `json:"target,omitempty" example:"https://ntfy.sh/my-habits"`
+	Token   *string `json:"token,omitempty" example:"tk_secret"`
+	Enabled *bool   `json:"enabled,omitempty" example:"false"`
+}
+
+type TestNotificationRequest struct {
+	ChannelID *int `json:"channelId,omitempty" example:"1"` // optional, defaults to every channel of the user
+}
+
+// TestNotificationResult reports the outcome of a test notification on one channel
+type TestNotificationResult struct {
+	ChannelID int         `json:"channelId" example:"1"`
+	Name      string      `json:"name" example:"My phone"`
+	Type      ChannelType `json:"type" example:"ntfy"`
+	Success   bool        `json:"success" example:"true"`
+	Error     string      `json:"error,omitempty" example:"webhook responded with 500 Internal Server Error"`
+}
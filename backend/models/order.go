@@ -0,0 +1,14 @@
+package models
+
+// TrackerPosition places a tracker in the user-defined order. Lower positions
+// come first, pinned trackers before all others.
+type TrackerPosition struct {
+	Type     TrackerType `json:"type" example:"habit"`
+	ID       int         `json:"id" example:"1"`
+	Position int         `json:"position" example:"0"`
+	Pinned   *bool       `json:"pinned,omitempty" example:"true"` // unchanged when left out
+}
+
+type UpdateOrderRequest struct {
+	Trackers []TrackerPosition `json:"trackers"`
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"time"
+)
+
+// Pause is a date range, e.g. a vacation or sick days, in which trackers are
+// not due and missed periods do not break streaks. Pauses without a tracker
+// apply to all trackers of the user.
+type Pause struct {
+	ID          int          `json:"id" example:"1"`
+	UserID      int          `json:"-"`
+	TrackerType *TrackerType `json:"trackerType,omitempty" example:"habit"`
+	TrackerID   *int         `json:"trackerId,omitempty" example:"1"`
+	StartDate   time.Time    `json:"startDate" example:"2024-07-01T00:00:00Z"`
+	EndDate     time.Time    `json:"endDate" example:"2024-07-14T00:00:00Z"` // inclusive
+	Reason      string       `json:"reason,omitempty" example:"Vacation"`
+	CreatedAt   time.Time    `json:"createdAt" example:"2024-01-01T10:00:00Z"`
+}
+
+type CreatePauseRequest struct {
+	TrackerType *TrackerType `json:"trackerType,omitempty" example:"habit"` // optional, restricts the pause to one tracker
+	TrackerID   *int         `json:"trackerId,omitempty" example:"1"`
+	StartDate   string       `json:"startDate" example:"2024-07-01"`
+	EndDate     string       `json:"endDate,omitempty" example:"2024-07-14"` // defaults to the start date
+	Reason      string       `json:"reason,omitempty" example:"Vacation"`
+}
+
+type UpdatePauseRequest struct {
+	StartDate *string `json:"startDate,omitempty" example:"2024-07-01"`
+	EndDate   *string `json:"endDate,omitempty" example:"2024-07-21"`
+	Reason    *string `json:"reason,omitempty" example:"Longer vacation"`
+}
+
+// AppliesTo reports whether the pause covers the given tracker
+func (p Pause) AppliesTo(trackerType TrackerType, trackerID int) bool {
+	return p.TrackerType == nil || (*p.TrackerType == trackerType && p.TrackerID != nil && *p.TrackerID == trackerID)
+}
+
+// DaysOff tells on which days a tracker does not count: the days of its
+// pauses and the days with a skipped entry. The zero value has no days off.
+type DaysOff struct {
+	pauses  []Pause
+	skipped map[skippedDay]bool
+}
+
+type skippedDay struct {
+	trackerType TrackerType
+	trackerID   int
+	date        string
+}
+
+// NewDaysOff collects the days off of a user's trackers. Entry dates are
+// assigned to days in loc.
+func NewDaysOff(pauses []Pause, entries []Entry, loc *time.Location) DaysOff {
+	d := DaysOff{pauses: pauses, skipped: make(map[skippedDay]bool)}
+	for _, e := range entries {
+		if e.Skipped {
+			d.skipped[skippedDay{e.Type, e.TrackerID, e.Date.In(loc).Format("2006-01-02")}] = true
+		}
+	}
+	return d
+}
+
+// Contains reports whether the calendar date of day is a day off for the tracker
+func (d DaysOff) Contains(trackerType TrackerType, trackerID int, day time.Time) bool {
+	date := day.Format("2006-01-02")
+	if d.skipped[skippedDay{trackerType, trackerID, date}] {
+		return true
+	}
+
+	for _, p := range d.pauses {
+		if p.AppliesTo(trackerType, trackerID) &&
+			date >= p.StartDate.UTC().Format("2006-01-02") && date <= p.EndDate.UTC().Format("2006-01-02") {
+			return true
+		}
+	}
+	return false
+}
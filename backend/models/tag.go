@@ -0,0 +1,72 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Tag is a free-form label, a tracker can have any number of tags
+type Tag struct {
+	ID        int       `json:"id" example:"1"`
+	UserID    int       `json:"-"`
+	Name      string    `json:"name" example:"morning"`
+	CreatedAt time.Time `json:"createdAt" example:"2024-01-01T10:00:00Z"`
+}
+
+type TagRequest struct {
+	Name string `json:"name" example:"morning"`
+}
+
+// Category groups trackers in listings, a tracker has at most one category
+type Category struct {
+	ID        int       `json:"id" example:"1"`
+	UserID    int       `json:"-"`
+	Name      string    `json:"name" example:"Health"`
+	Color     string    `json:"color" example:"#4caf50"`
+	Icon      string    `json:"icon" example:"heart"`
+	CreatedAt time.Time `json:"createdAt" example:"2024-01-01T10:00:00Z"`
+}
+
+type CreateCategoryRequest struct {
+	Name  string `json:"name" example:"Health"`
+	Color string `json:"color,omitempty" example:"#4caf50"`
+	Icon  string `json:"icon,omitempty" example:"heart"`
+}
+
+type UpdateCategoryRequest struct {
+	Name  *string `json:"name,omitempty" example:"Fitness"`
+	Color *string `json:"color,omitempty" example:"#2196f3"`
+	Icon  *string `json:"icon,omitempty" example:"dumbbell"`
+}
+
+// TrackerFilter restricts tracker listings to a tag and/or a category, both
+// given by name. Empty fields do not filter.
+type TrackerFilter struct {
+	Tag      string
+	Category string
+}
+
+// Matches reports whether a tracker with the given category and tags passes
+// the filter. Names are compared case-insensitively.
+func (f TrackerFilter) Matches(categories []Category, categoryID *int, tags []Tag) bool {
+	if f.Category != "" {
+		found := false
+		for _, category := range categories {
+			if categoryID != nil && category.ID == *categoryID && strings.EqualFold(category.Name, f.Category) {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.Tag != "" {
+		for _, tag := range tags {
+			if strings.EqualFold(tag.Name, f.Tag) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+)
+
+// APIToken represents a long-lived personal token for scripts and integrations
+type APIToken struct {
+	ID         int        `json:"id" example:"1"`
+	Name       string     `json:"name" example:"Phone shortcut"`
+	Scopes     []string   `json:"scopes" example:"entries:write"`
+	LastUsedAt *time.Time `json:"lastUsedAt" example:"2024-01-01T10:00:00Z"` // null until the token is used
+	CreatedAt  time.Time  `json:"createdAt" example:"2024-01-01T10:00:00Z"`
+}
+
+type CreateAPITokenRequest struct {
+	Name   string   `json:"name" example:"Phone shortcut"`
+	Scopes []string `json:"scopes" example:"entries:write,trackers:read"`
+}
+
+type CreateAPITokenResponse struct {
+	APIToken
+	Token string `json:"token" example:"pat_4f9c2e..."` // only returned once, send as "Authorization: Bearer <token>"
+}
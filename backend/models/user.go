@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+)
+
+// User represents an account owning trackers and entries
+type User struct {
+	ID           int       `json:"id" example:"1"`
+	Username     string    `json:"username" example:"sahin"`
+	PasswordHash string    `json:"-"`
+	TimeZone     string    `json:"timeZone" example:"Europe/Istanbul"` // IANA name, days start at midnight in this zone
+	CreatedAt    time.Time `json:"createdAt" example:"2024-01-01T10:00:00Z"`
+}
+
+// Location returns the user's time zone, UTC when it is unknown
+func (u User) Location() *time.Location {
+	loc, err := time.LoadLocation(u.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+type UpdateUserRequest struct {
+	TimeZone *string `json:"timeZone,omitempty" example:"America/New_York"`
+}
+
+type RegisterRequest struct {
+	Username string `json:"username" example:"sahin"`
+	Password string `json:"password" example:"correct horse battery staple"`
+}
+
+type LoginRequest struct {
+	Username string `json:"username" example:"sahin"`
+	Password string `json:"password" example:"correct horse battery staple"`
+}
+
+type LoginResponse struct {
+	Token     string    `json:"token" example:"4f9c2e..."` // send as "Authorization: Bearer <token>"
+	ExpiresAt time.Time `json:"expiresAt" example:"2024-02-01T10:00:00Z"`
+	User      User      `json:"user"`
+}
@@ -0,0 +1,115 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"routine-tracker/database"
+	"routine-tracker/models"
+	"time"
+)
+
+// Dispatcher sends messages through the notification channels the user
+// configured for the tracker
+type Dispatcher struct {
+	Store    database.Store
+	SMTP     SMTPConfig
+	Client   *http.Client
+	Fallback Notifier // used when the user has no channels, may be nil
+	Logger   *slog.Logger
+
+	// AllowInternalHosts lets webhook, ntfy and Gotify channels reach
+	// loopback, private and link-local hosts, e.g. a Gotify server on the LAN
+	AllowInternalHosts bool
+}
+
+// NewDispatcher creates a dispatcher that looks up channels in the store and
+// logs messages of users without channels to logger
+func NewDispatcher(store database.Store, smtp SMTPConfig, allowInternalHosts bool, logger *slog.Logger) *Dispatcher {
+	d := &Dispatcher{
+		Store:              store,
+		SMTP:               smtp,
+		Fallback:           LogNotifier{Logger: logger},
+		Logger:             logger,
+		AllowInternalHosts: allowInternalHosts,
+	}
+
+	dialer := &net.Dialer{Timeout: 10 * time.Second, Control: d.checkDial}
+	d.Client = &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: &http.Transport{DialContext: dialer.DialContext},
+	}
+	return d
+}
+
+// Notifier returns the notifier delivering to a single channel
+func (d *Dispatcher) Notifier(channel models.NotificationChannel) (Notifier, error) {
+	switch channel.Type {
+	case models.CHANNEL_EMAIL:
+		return EmailNotifier{SMTP: d.SMTP, To: channel.Target}, nil
+	case models.CHANNEL_WEBHOOK:
+		return WebhookNotifier{Client: d.Client, URL: channel.Target, Token: channel.Token}, nil
+	case models.CHANNEL_NTFY:
+		return NtfyNotifier{Client: d.Client, URL: channel.Target, Token: channel.Token}, nil
+	case models.CHANNEL_GOTIFY:
+		return GotifyNotifier{Client: d.Client, URL: channel.Target, Token: channel.Token}, nil
+	default:
+		return nil, fmt.Errorf("unknown channel type '%s'", channel.Type)
+	}
+}
+
+// CheckTarget checks that the URL of a webhook, ntfy or Gotify channel may
+// be used, see CheckURL
+func (d *Dispatcher) CheckTarget(ctx context.Context, channelType models.ChannelType, target string) error {
+	switch channelType {
+	case models.CHANNEL_WEBHOOK, models.CHANNEL_NTFY, models.CHANNEL_GOTIFY:
+		return CheckURL(ctx, target, d.AllowInternalHosts)
+	}
+	return nil
+}
+
+// Send delivers a message to a single channel
+func (d *Dispatcher) Send(ctx context.Context, channel models.NotificationChannel, msg Message) error {
+	if err := d.CheckTarget(ctx, channel.Type, channel.Target); err != nil {
+		return err
+	}
+	notifier, err := d.Notifier(channel)
+	if err != nil {
+		return err
+	}
+	return notifier.Notify(ctx, msg)
+}
+
+// Notify delivers a message to every channel of the tracker. It fails only
+// when none of the channels accepted the message.
+func (d *Dispatcher) Notify(ctx context.Context, msg Message) error {
+	channels, err := d.Store.GetTrackerNotificationChannels(msg.UserID, string(msg.TrackerType), msg.TrackerID)
+	if err != nil {
+		return err
+	}
+
+	if len(channels) == 0 {
+		if d.Fallback == nil {
+			return nil
+		}
+		return d.Fallback.Notify(ctx, msg)
+	}
+
+	var errs []error
+	for _, channel := range channels {
+		if err := d.Send(ctx, channel, msg); err != nil {
+			errs = append(errs, fmt.Errorf("channel '%s': %w", channel.Name, err))
+		}
+	}
+
+	if len(errs) == len(channels) {
+		return errors.Join(errs...)
+	}
+	for _, err := range errs {
+		d.Logger.Error("failed to notify user", "user", msg.UserID, "error", err)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// SMTPConfig holds the outgoing mail server shared by all email channels
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// Configured reports whether email can be sent at all
+func (c SMTPConfig) Configured() bool {
+	return c.Host != "" && c.From != ""
+}
+
+// EmailNotifier sends messages as plain text email
+type EmailNotifier struct {
+	SMTP SMTPConfig
+	To   string
+}
+
+func (n EmailNotifier) Notify(ctx context.Context, msg Message) error {
+	if !n.SMTP.Configured() {
+		return errors.New("SMTP server is not configured")
+	}
+
+	addr := net.JoinHostPort(n.SMTP.Host, strconv.Itoa(n.SMTP.Port))
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(30 * time.Second)
+	}
+	conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, n.SMTP.Host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: n.SMTP.Host}); err != nil {
+			return err
+		}
+	}
+	if n.SMTP.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", n.SMTP.Username, n.SMTP.Password, n.SMTP.Host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(n.SMTP.From); err != nil {
+		return err
+	}
+	if err := client.Rcpt(n.To); err != nil {
+		return err
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(n.email(msg)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+func (n EmailNotifier) email(msg Message) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", n.SMTP.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", n.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Title))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(msg.Body)
+	buf.WriteString("\r\n")
+	return buf.Bytes()
+}
@@ -0,0 +1,61 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"syscall"
+)
+
+// ErrInternalHost is returned for URLs of loopback, private and link-local
+// hosts, which would let users reach services of the server's network
+var ErrInternalHost = errors.New("notifications cannot be sent to loopback, private or link-local hosts")
+
+// CheckURL checks that a webhook, ntfy or Gotify URL uses http or https and,
+// unless internal hosts are allowed, that its host resolves to public
+// addresses only
+func CheckURL(ctx context.Context, rawURL string, allowInternal bool) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return fmt.Errorf("'%s' is not an http(s) URL", rawURL)
+	}
+	if allowInternal {
+		return nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, u.Hostname())
+	if err != nil {
+		return fmt.Errorf("cannot resolve %s: %w", u.Hostname(), err)
+	}
+	for _, addr := range addrs {
+		if isInternalIP(addr.IP) {
+			return ErrInternalHost
+		}
+	}
+	return nil
+}
+
+// isInternalIP reports whether ip belongs to the server's host or network
+func isInternalIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast()
+}
+
+// checkDial refuses connections to internal addresses. It runs after DNS
+// resolution for every connection, so hosts that resolve to another address
+// after CheckURL, and redirects, cannot reach internal hosts either.
+func (d *Dispatcher) checkDial(network, address string, c syscall.RawConn) error {
+	if d.AllowInternalHosts {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || isInternalIP(ip) {
+		return ErrInternalHost
+	}
+	return nil
+}
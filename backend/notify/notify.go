@@ -0,0 +1,35 @@
+package notify
+
+import (
+	"context"
+	"log/slog"
+	"routine-tracker/models"
+	"time"
+)
+
+// Message represents a single notification about a tracker
+type Message struct {
+	UserID       int                `json:"-"`
+	TrackerType  models.TrackerType `json:"trackerType" example:"habit"`
+	TrackerID    int                `json:"trackerId" example:"1"`
+	TrackerName  string             `json:"trackerName" example:"Drink Water"`
+	Title        string             `json:"title" example:"Reminder: Drink Water"`
+	Body         string             `json:"body" example:"Drink Water is due today"`
+	ScheduledFor time.Time          `json:"scheduledFor" example:"2024-01-01T18:00:00Z"`
+}
+
+// Notifier delivers messages to the user
+type Notifier interface {
+	Notify(ctx context.Context, msg Message) error
+}
+
+// LogNotifier writes messages to the server log, it is used when no other
+// delivery channel is configured
+type LogNotifier struct {
+	Logger *slog.Logger
+}
+
+func (n LogNotifier) Notify(ctx context.Context, msg Message) error {
+	n.Logger.Info("🔔 "+msg.Title, "user", msg.UserID, "body", msg.Body)
+	return nil
+}
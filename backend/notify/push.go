@@ -0,0 +1,67 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// NtfyNotifier publishes messages to an ntfy topic URL
+type NtfyNotifier struct {
+	Client *http.Client
+	URL    string // e.g. https://ntfy.sh/my-habits
+	Token  string // optional access token
+}
+
+func (n NtfyNotifier) Notify(ctx context.Context, msg Message) error {
+	req, err := http.NewRequestWithContext(ctx, "POST", n.URL, strings.NewReader(msg.Body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Title", msg.Title)
+	req.Header.Set("Tags", "bell")
+	if n.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+n.Token)
+	}
+
+	return send(n.Client, req)
+}
+
+// GotifyNotifier sends messages to a Gotify server
+type GotifyNotifier struct {
+	Client   *http.Client
+	URL      string // server URL, e.g. https://gotify.example.com
+	Token    string // application token
+	Priority int
+}
+
+// gotifyMessage is the body of Gotify's POST /message
+type gotifyMessage struct {
+	Title    string `json:"title"`
+	Message  string `json:"message"`
+	Priority int    `json:"priority"`
+}
+
+func (n GotifyNotifier) Notify(ctx context.Context, msg Message) error {
+	priority := n.Priority
+	if priority == 0 {
+		priority = 5
+	}
+
+	body, err := json.Marshal(gotifyMessage{Title: msg.Title, Message: msg.Body, Priority: priority})
+	if err != nil {
+		return err
+	}
+
+	url := strings.TrimSuffix(n.URL, "/") + "/message"
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Gotify-Key", n.Token)
+
+	return send(n.Client, req)
+}
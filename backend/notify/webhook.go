@@ -0,0 +1,69 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// WebhookPayload is the JSON body posted to webhook channels
+type WebhookPayload struct {
+	Event string `json:"event" example:"reminder"`
+	Message
+}
+
+// WebhookNotifier posts messages as JSON to an arbitrary URL
+type WebhookNotifier struct {
+	Client *http.Client
+	URL    string
+	Token  string // optional, sent as bearer token
+	Event  string
+}
+
+func (n WebhookNotifier) Notify(ctx context.Context, msg Message) error {
+	event := n.Event
+	if event == "" {
+		event = "reminder"
+	}
+
+	body, err := json.Marshal(WebhookPayload{Event: event, Message: msg})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", n.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+n.Token)
+	}
+
+	return send(n.Client, req)
+}
+
+// send performs the request and turns non 2xx responses into errors
+func send(client *http.Client, req *http.Request) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+		return fmt.Errorf("'%s' is not an http(s) URL", req.URL)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s responded with %s", req.URL.Host, resp.Status)
+	}
+	return nil
+}
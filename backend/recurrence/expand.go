@@ -0,0 +1,231 @@
+package recurrence
+
+import "time"
+
+// A rule that never matches again, like the 30th of February, would be
+// expanded forever, so give up after about eight years without an occurrence
+var maxEmptyPeriods = map[Frequency]int{
+	DAILY:   3000,
+	WEEKLY:  450,
+	MONTHLY: 100,
+	YEARLY:  10,
+}
+
+// Occurs reports whether the rule starting on start has an occurrence on day
+func (r *Rule) Occurs(start, day time.Time) bool {
+	return len(r.Between(start, day, day)) > 0
+}
+
+// Between returns the occurrences of the rule starting on start from from to
+// to, both inclusive. Only calendar dates matter, the result is UTC midnight.
+func (r *Rule) Between(start, from, to time.Time) []time.Time {
+	to = dateOf(to)
+
+	var dates []time.Time
+	r.each(start, from, func(d time.Time) bool {
+		if d.After(to) {
+			return false
+		}
+		dates = append(dates, d)
+		return true
+	})
+	return dates
+}
+
+// Next returns up to n occurrences of the rule starting on start that are on
+// or after from
+func (r *Rule) Next(start, from time.Time, n int) []time.Time {
+	var dates []time.Time
+	if n <= 0 {
+		return dates
+	}
+	r.each(start, from, func(d time.Time) bool {
+		dates = append(dates, d)
+		return len(dates) < n
+	})
+	return dates
+}
+
+// each calls fn with the occurrences on or after from in order until fn
+// returns false or the rule ends. Days before start are never occurrences,
+// and start itself is only one if it matches the rule.
+func (r *Rule) each(start, from time.Time, fn func(time.Time) bool) {
+	start = dateOf(start)
+	from = dateOf(from)
+	interval := r.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	period := r.periodStart(start)
+
+	// Without COUNT nothing before from matters, so skip to its period
+	if r.Count == 0 && from.After(start) {
+		n := r.periodsBetween(period, r.periodStart(from))
+		period = r.advance(period, n-n%interval)
+	}
+
+	count, empty := 0, 0
+	for empty < maxEmptyPeriods[r.Freq] {
+		days := r.expand(period, start)
+		empty++
+
+		for _, d := range days {
+			if d.Before(start) {
+				continue
+			}
+			if !r.Until.IsZero() && d.After(r.Until) {
+				return
+			}
+			count++
+			if r.Count > 0 && count > r.Count {
+				return
+			}
+			empty = 0
+			if !d.Before(from) && !fn(d) {
+				return
+			}
+		}
+
+		period = r.advance(period, interval)
+	}
+}
+
+// expand returns the sorted occurrences in the period starting on period
+func (r *Rule) expand(period, start time.Time) []time.Time {
+	end := r.advance(period, 1)
+
+	var days []time.Time
+	for d := period; d.Before(end); d = d.AddDate(0, 0, 1) {
+		if r.matches(d, start) {
+			days = append(days, d)
+		}
+	}
+
+	if len(r.BySetPos) == 0 {
+		return days
+	}
+
+	var selected []time.Time
+	for i, d := range days {
+		for _, pos := range r.BySetPos {
+			if pos == i+1 || pos == i-len(days) {
+				selected = append(selected, d)
+				break
+			}
+		}
+	}
+	return selected
+}
+
+// matches reports whether d satisfies all parts of the rule. Parts that are
+// not given default to the date of start the way RFC 5545 describes.
+func (r *Rule) matches(d, start time.Time) bool {
+	if len(r.ByMonth) > 0 {
+		if !containsMonth(r.ByMonth, d.Month()) {
+			return false
+		}
+	} else if r.Freq == YEARLY && len(r.ByDay) == 0 && len(r.ByMonthDay) == 0 && d.Month() != start.Month() {
+		return false
+	}
+
+	if len(r.ByMonthDay) > 0 {
+		if !r.matchesMonthDay(d) {
+			return false
+		}
+	} else if (r.Freq == MONTHLY || r.Freq == YEARLY) && len(r.ByDay) == 0 && d.Day() != start.Day() {
+		return false
+	}
+
+	if len(r.ByDay) > 0 {
+		return r.matchesByDay(d)
+	}
+	return r.Freq != WEEKLY || d.Weekday() == start.Weekday()
+}
+
+func (r *Rule) matchesMonthDay(d time.Time) bool {
+	last := daysIn(d.Year(), d.Month())
+	for _, n := range r.ByMonthDay {
+		if n == d.Day() || last+1+n == d.Day() {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesByDay checks the weekdays, numbered ones count within the month for
+// monthly rules and yearly rules with BYMONTH, otherwise within the year
+func (r *Rule) matchesByDay(d time.Time) bool {
+	day, last := d.Day(), daysIn(d.Year(), d.Month())
+	if r.Freq == YEARLY && len(r.ByMonth) == 0 {
+		day, last = d.YearDay(), time.Date(d.Year(), 12, 31, 0, 0, 0, 0, time.UTC).YearDay()
+	}
+
+	for _, wd := range r.ByDay {
+		if wd.Weekday != d.Weekday() {
+			continue
+		}
+		if wd.N == 0 || wd.N == (day-1)/7+1 || wd.N == -((last-day)/7+1) {
+			return true
+		}
+	}
+	return false
+}
+
+// periodStart returns the first day of the period d is in
+func (r *Rule) periodStart(d time.Time) time.Time {
+	switch r.Freq {
+	case WEEKLY:
+		return d.AddDate(0, 0, -((int(d.Weekday()) - int(r.WeekStart) + 7) % 7))
+	case MONTHLY:
+		return time.Date(d.Year(), d.Month(), 1, 0, 0, 0, 0, time.UTC)
+	case YEARLY:
+		return time.Date(d.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return d
+}
+
+// advance moves the start of a period n periods ahead
+func (r *Rule) advance(period time.Time, n int) time.Time {
+	switch r.Freq {
+	case WEEKLY:
+		return period.AddDate(0, 0, 7*n)
+	case MONTHLY:
+		return period.AddDate(0, n, 0)
+	case YEARLY:
+		return period.AddDate(n, 0, 0)
+	}
+	return period.AddDate(0, 0, n)
+}
+
+// periodsBetween counts the periods from the period starting on a to the one
+// starting on b
+func (r *Rule) periodsBetween(a, b time.Time) int {
+	switch r.Freq {
+	case WEEKLY:
+		return int(b.Sub(a).Hours()/24) / 7
+	case MONTHLY:
+		return (b.Year()-a.Year())*12 + int(b.Month()-a.Month())
+	case YEARLY:
+		return b.Year() - a.Year()
+	}
+	return int(b.Sub(a).Hours() / 24)
+}
+
+func containsMonth(months []time.Month, m time.Month) bool {
+	for _, month := range months {
+		if month == m {
+			return true
+		}
+	}
+	return false
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// dateOf returns the calendar date of t in its own location as UTC midnight
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
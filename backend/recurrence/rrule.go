@@ -0,0 +1,251 @@
+// Package recurrence implements RFC 5545 recurrence rules (RRULE) for
+// trackers. Trackers are due on whole days, so only the parts of a rule that
+// select days are supported: FREQ from DAILY to YEARLY, INTERVAL, COUNT,
+// UNTIL, BYDAY, BYMONTHDAY, BYMONTH, BYSETPOS and WKST.
+package recurrence
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency is the period a rule repeats in
+type Frequency string
+
+const (
+	DAILY   Frequency = "DAILY"
+	WEEKLY  Frequency = "WEEKLY"
+	MONTHLY Frequency = "MONTHLY"
+	YEARLY  Frequency = "YEARLY"
+)
+
+// WeekdayNum is a BYDAY value. N selects the n-th such weekday of the month
+// or year, counting from the end when negative, and 0 selects all of them.
+type WeekdayNum struct {
+	N       int
+	Weekday time.Weekday
+}
+
+// Rule is a parsed recurrence rule. The first day of the schedule (DTSTART)
+// is not part of the rule, it is passed to the expansion methods instead.
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	Count      int       // 0 for no limit
+	Until      time.Time // zero for no limit, otherwise the last possible date
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	ByMonth    []time.Month
+	BySetPos   []int
+	WeekStart  time.Weekday
+}
+
+var weekdayCodes = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// Parse parses a rule like "FREQ=MONTHLY;BYDAY=1MO". An "RRULE:" prefix is
+// allowed.
+func Parse(s string) (*Rule, error) {
+	s = strings.TrimSpace(s)
+	if len(s) >= 6 && strings.EqualFold(s[:6], "RRULE:") {
+		s = s[6:]
+	}
+	if s == "" {
+		return nil, fmt.Errorf("empty rule")
+	}
+
+	r := &Rule{Interval: 1, WeekStart: time.Monday}
+	seen := make(map[string]bool)
+
+	for _, part := range strings.Split(s, ";") {
+		name, value, ok := strings.Cut(part, "=")
+		name = strings.ToUpper(strings.TrimSpace(name))
+		value = strings.ToUpper(strings.TrimSpace(value))
+		if !ok || name == "" || value == "" {
+			return nil, fmt.Errorf("invalid rule part %q", part)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("%s is given more than once", name)
+		}
+		seen[name] = true
+
+		var err error
+		switch name {
+		case "FREQ":
+			switch f := Frequency(value); f {
+			case DAILY, WEEKLY, MONTHLY, YEARLY:
+				r.Freq = f
+			case "SECONDLY", "MINUTELY", "HOURLY":
+				err = fmt.Errorf("FREQ=%s is not supported, trackers repeat on whole days", value)
+			default:
+				err = fmt.Errorf("unknown FREQ %q", value)
+			}
+		case "INTERVAL":
+			r.Interval, err = parseNumber(value, 1, 0)
+		case "COUNT":
+			r.Count, err = parseNumber(value, 1, 0)
+		case "UNTIL":
+			r.Until, err = parseUntil(value)
+		case "BYDAY":
+			r.ByDay, err = parseByDay(value)
+		case "BYMONTHDAY":
+			r.ByMonthDay, err = parseNumbers(value, 31)
+		case "BYMONTH":
+			var months []int
+			months, err = parseNumbers(value, 12)
+			for _, m := range months {
+				if m < 0 {
+					err = fmt.Errorf("invalid BYMONTH %d", m)
+				}
+				r.ByMonth = append(r.ByMonth, time.Month(m))
+			}
+		case "BYSETPOS":
+			r.BySetPos, err = parseNumbers(value, 366)
+		case "WKST":
+			r.WeekStart, err = parseWeekday(value)
+		case "BYHOUR", "BYMINUTE", "BYSECOND", "BYYEARDAY", "BYWEEKNO":
+			err = fmt.Errorf("%s is not supported", name)
+		default:
+			err = fmt.Errorf("unknown rule part %s", name)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if r.Freq == "" {
+		return nil, fmt.Errorf("FREQ is required")
+	}
+	if r.Count > 0 && !r.Until.IsZero() {
+		return nil, fmt.Errorf("COUNT and UNTIL cannot be combined")
+	}
+	if len(r.ByMonthDay) > 0 && r.Freq == WEEKLY {
+		return nil, fmt.Errorf("BYMONTHDAY cannot be used with FREQ=WEEKLY")
+	}
+	if len(r.BySetPos) > 0 && len(r.ByDay) == 0 && len(r.ByMonthDay) == 0 && len(r.ByMonth) == 0 {
+		return nil, fmt.Errorf("BYSETPOS needs another BY rule part")
+	}
+	for _, d := range r.ByDay {
+		if d.N != 0 && r.Freq != MONTHLY && r.Freq != YEARLY {
+			return nil, fmt.Errorf("numbered BYDAY values need FREQ=MONTHLY or FREQ=YEARLY")
+		}
+		if d.N != 0 && (r.Freq == MONTHLY || len(r.ByMonth) > 0) && (d.N > 5 || d.N < -5) {
+			return nil, fmt.Errorf("a month has at most 5 of each weekday")
+		}
+	}
+
+	return r, nil
+}
+
+// String formats the rule in its canonical form, leaving out default values
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.Format("20060102"))
+	}
+	if len(r.ByMonth) > 0 {
+		months := make([]int, len(r.ByMonth))
+		for i, m := range r.ByMonth {
+			months[i] = int(m)
+		}
+		parts = append(parts, "BYMONTH="+joinNumbers(months))
+	}
+	if len(r.ByMonthDay) > 0 {
+		parts = append(parts, "BYMONTHDAY="+joinNumbers(r.ByMonthDay))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, d := range r.ByDay {
+			days[i] = weekdayCodes[d.Weekday]
+			if d.N != 0 {
+				days[i] = strconv.Itoa(d.N) + days[i]
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.BySetPos) > 0 {
+		parts = append(parts, "BYSETPOS="+joinNumbers(r.BySetPos))
+	}
+	if r.WeekStart != time.Monday {
+		parts = append(parts, "WKST="+weekdayCodes[r.WeekStart])
+	}
+	return strings.Join(parts, ";")
+}
+
+// parseNumber parses a number of at least min and, if max is not 0, at most max
+func parseNumber(value string, min, max int) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < min || (max != 0 && n > max) {
+		return 0, fmt.Errorf("invalid number %q", value)
+	}
+	return n, nil
+}
+
+// parseNumbers parses a list of non-zero numbers between -max and max
+func parseNumbers(value string, max int) ([]int, error) {
+	var numbers []int
+	for _, s := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(strings.TrimPrefix(s, "+"))
+		if err != nil || n == 0 || n > max || n < -max {
+			return nil, fmt.Errorf("invalid number %q", s)
+		}
+		numbers = append(numbers, n)
+	}
+	return numbers, nil
+}
+
+func joinNumbers(numbers []int) string {
+	s := make([]string, len(numbers))
+	for i, n := range numbers {
+		s[i] = strconv.Itoa(n)
+	}
+	return strings.Join(s, ",")
+}
+
+func parseWeekday(value string) (time.Weekday, error) {
+	for i, code := range weekdayCodes {
+		if value == code {
+			return time.Weekday(i), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid weekday %q", value)
+}
+
+func parseByDay(value string) ([]WeekdayNum, error) {
+	var days []WeekdayNum
+	for _, s := range strings.Split(value, ",") {
+		if len(s) < 2 {
+			return nil, fmt.Errorf("invalid BYDAY %q", s)
+		}
+		weekday, err := parseWeekday(s[len(s)-2:])
+		if err != nil {
+			return nil, err
+		}
+		day := WeekdayNum{Weekday: weekday}
+		if prefix := s[:len(s)-2]; prefix != "" {
+			day.N, err = strconv.Atoi(strings.TrimPrefix(prefix, "+"))
+			if err != nil || day.N == 0 || day.N > 53 || day.N < -53 {
+				return nil, fmt.Errorf("invalid BYDAY %q", s)
+			}
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+// parseUntil accepts a date or a date-time, only the date is used
+func parseUntil(value string) (time.Time, error) {
+	date, _, _ := strings.Cut(value, "T")
+	until, err := time.Parse("20060102", date)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid UNTIL %q, use YYYYMMDD", value)
+	}
+	return until, nil
+}
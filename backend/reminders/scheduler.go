@@ -0,0 +1,269 @@
+package reminders
+
+import (
+	"context"
+	"log/slog"
+	"routine-tracker/app"
+	"routine-tracker/database"
+	"routine-tracker/models"
+	"routine-tracker/notify"
+	"routine-tracker/trackers"
+	"routine-tracker/trackers/duration"
+	"routine-tracker/trackers/habit"
+	"time"
+)
+
+// Scheduler periodically sends the reminders of trackers that are due
+type Scheduler struct {
+	Store    database.Store
+	Notifier notify.Notifier
+	Clock    app.Clock
+	Logger   *slog.Logger
+	Interval time.Duration // how often reminders are checked
+	CatchUp  time.Duration // how late a reminder may still be sent, e.g. after a restart
+}
+
+// reminder is a single reminder time of a tracker on a specific day
+type reminder struct {
+	userID       int
+	trackerType  models.TrackerType
+	trackerID    int
+	trackerName  string
+	scheduledFor time.Time
+}
+
+// NewScheduler creates a scheduler of the app checking reminders every minute
+func NewScheduler(a *app.App) *Scheduler {
+	return &Scheduler{
+		Store:    a.Store,
+		Notifier: a.Notifier,
+		Clock:    a.Clock,
+		Logger:   a.Logger,
+		Interval: time.Minute,
+		CatchUp:  time.Hour,
+	}
+}
+
+// Run checks reminders until the context is cancelled
+func (s *Scheduler) Run(ctx context.Context) {
+	s.Logger.Info("⏰ Reminder scheduler started")
+
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Tick(ctx, s.Clock.Now()); err != nil {
+			s.Logger.Error("failed to send reminders", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			s.Logger.Info("⏰ Reminder scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick sends every reminder that is due at now and was not delivered yet.
+// Failing to look up the reminders of a user is logged and does not keep the
+// other users from getting theirs.
+func (s *Scheduler) Tick(ctx context.Context, now time.Time) error {
+	userIDs, err := s.Store.GetUserIDs()
+	if err != nil {
+		return err
+	}
+
+	for _, userID := range userIDs {
+		user, err := s.Store.GetUserByID(userID)
+		if err != nil {
+			s.Logger.Error("failed to get user for reminders", "user", userID, "error", err)
+			continue
+		}
+
+		// Reminder times and days are in the user's time zone
+		pending, err := s.pendingReminders(userID, now.In(user.Location()))
+		if err != nil {
+			s.Logger.Error("failed to get reminders", "user", userID, "error", err)
+			continue
+		}
+
+		for _, r := range pending {
+			if err := s.deliver(ctx, r, now); err != nil {
+				s.Logger.Error("failed to deliver reminder", "trackerType", r.trackerType, "trackerId", r.trackerID, "error", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// pendingReminders returns the reminders of a user's due and unfinished trackers
+// whose time has come within the catch up window. Paused and skipped days
+// have no reminders.
+func (s *Scheduler) pendingReminders(userID int, now time.Time) ([]reminder, error) {
+	pending := make([]reminder, 0)
+
+	pauses, err := s.Store.GetPauses(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	habits, err := s.Store.GetAllHabitTrackers(userID, models.ACTIVE_TRACKERS)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, h := range habits {
+		if !h.Reminders.Enabled || !trackers.IsTrackerDueToday(h.Due, h.StartDate, now) {
+			continue
+		}
+
+		entries, err := s.Store.GetEntriesByTracker(userID, h.ID, string(models.HABIT))
+		if err != nil {
+			return nil, err
+		}
+		daysOff := models.NewDaysOff(pauses, entries, now.Location())
+		if daysOff.Contains(models.HABIT, h.ID, now) || isHabitCompleted(h, entries, daysOff, now) {
+			continue
+		}
+
+		for _, at := range s.dueTimes(h.Reminders.Times, now) {
+			pending = append(pending, reminder{userID, models.HABIT, h.ID, h.TrackerName, at})
+		}
+	}
+
+	targets, err := s.Store.GetAllTargetTrackers(userID, models.ACTIVE_TRACKERS)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range targets {
+		if !t.Reminders.Enabled || !trackers.IsTrackerDueToday(t.Due, t.StartDate, now) {
+			continue
+		}
+
+		entries, err := s.Store.GetEntriesByTracker(userID, t.ID, string(models.TARGET))
+		if err != nil {
+			return nil, err
+		}
+		if hasEntryOn(entries, now) || models.NewDaysOff(pauses, nil, now.Location()).Contains(models.TARGET, t.ID, now) {
+			continue
+		}
+
+		for _, at := range s.dueTimes(t.Reminders.Times, now) {
+			pending = append(pending, reminder{userID, models.TARGET, t.ID, t.TrackerName, at})
+		}
+	}
+
+	durations, err := s.Store.GetAllDurationTrackers(userID, models.ACTIVE_TRACKERS)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, d := range durations {
+		// A running timer means the user is already at it
+		if !d.Reminders.Enabled || d.Timer != nil || !trackers.IsTrackerDueToday(d.Due, d.StartDate, now) {
+			continue
+		}
+
+		entries, err := s.Store.GetEntriesByTracker(userID, d.ID, string(models.DURATION))
+		if err != nil {
+			return nil, err
+		}
+		daysOff := models.NewDaysOff(pauses, entries, now.Location())
+		if daysOff.Contains(models.DURATION, d.ID, now) || isDurationCompleted(d, entries, daysOff, now) {
+			continue
+		}
+
+		for _, at := range s.dueTimes(d.Reminders.Times, now) {
+			pending = append(pending, reminder{userID, models.DURATION, d.ID, d.TrackerName, at})
+		}
+	}
+
+	return pending, nil
+}
+
+// dueTimes turns "HH:MM" reminder times into today's times that have passed
+// at most CatchUp ago
+func (s *Scheduler) dueTimes(times []string, now time.Time) []time.Time {
+	due := make([]time.Time, 0)
+	for _, t := range times {
+		parsed, err := time.Parse("15:04", t)
+		if err != nil {
+			s.Logger.Warn("ignoring invalid reminder time", "time", t)
+			continue
+		}
+
+		at := time.Date(now.Year(), now.Month(), now.Day(), parsed.Hour(), parsed.Minute(), 0, 0, now.Location())
+		if !at.After(now) && now.Sub(at) <= s.CatchUp {
+			due = append(due, at)
+		}
+	}
+	return due
+}
+
+func (s *Scheduler) deliver(ctx context.Context, r reminder, now time.Time) error {
+	claimed, err := s.Store.ClaimReminderDelivery(r.userID, string(r.trackerType), r.trackerID, r.scheduledFor, now)
+	if err != nil || !claimed {
+		return err
+	}
+
+	msg := notify.Message{
+		UserID:       r.userID,
+		TrackerType:  r.trackerType,
+		TrackerID:    r.trackerID,
+		TrackerName:  r.trackerName,
+		Title:        "Reminder: " + r.trackerName,
+		Body:         r.trackerName + " is due today and has not been completed yet",
+		ScheduledFor: r.scheduledFor,
+	}
+
+	if err := s.Notifier.Notify(ctx, msg); err != nil {
+		// Forget the delivery so that the next tick retries it
+		if releaseErr := s.Store.ReleaseReminderDelivery(string(r.trackerType), r.trackerID, r.scheduledFor); releaseErr != nil {
+			s.Logger.Error("failed to release reminder delivery, it will not be retried",
+				"trackerType", r.trackerType, "trackerId", r.trackerID, "error", releaseErr)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// isHabitCompleted reports whether the habit's goal is already met for the
+// current period. Bad habits only need a check-in for the day.
+func isHabitCompleted(h habit.HabitTracker, entries []models.Entry, daysOff models.DaysOff, now time.Time) bool {
+	if h.BadHabit {
+		return hasEntryOn(entries, now)
+	}
+
+	periods := habit.CalculatePeriods(h, entries, daysOff, now)
+	if len(periods) == 0 {
+		return false
+	}
+
+	current := periods[len(periods)-1]
+	return current.IsCurrent && current.GoalMet
+}
+
+// isDurationCompleted reports whether enough time was spent in the current
+// period to meet the goal
+func isDurationCompleted(d duration.DurationTracker, entries []models.Entry, daysOff models.DaysOff, now time.Time) bool {
+	periods := duration.CalculatePeriods(d, entries, daysOff, now)
+	if len(periods) == 0 {
+		return false
+	}
+
+	current := periods[len(periods)-1]
+	return current.IsCurrent && current.GoalMet
+}
+
+func hasEntryOn(entries []models.Entry, day time.Time) bool {
+	for _, entry := range entries {
+		if entry.Date.In(day.Location()).Format("2006-01-02") == day.Format("2006-01-02") {
+			return true
+		}
+	}
+	return false
+}
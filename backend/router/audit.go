@@ -0,0 +1,11 @@
+package router
+
+import (
+	"github.com/gorilla/mux"
+	"routine-tracker/handlers"
+)
+
+// SetupAuditRoutes configures the audit log routes
+func SetupAuditRoutes(routes *Router, h *handlers.Handler, api *mux.Router) {
+	routes.RegisterAndHandle(api, "GET", "/audit", "Get audit log", h.GetAuditEvents)
+}
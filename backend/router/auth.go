@@ -0,0 +1,24 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"routine-tracker/handlers"
+)
+
+// SetupAuthRoutes configures account routes, login and registration stay public
+func SetupAuthRoutes(routes *Router, h *handlers.Handler, public *mux.Router, protected *mux.Router) {
+	register := h.Register
+	if !h.Config.Features.Registration {
+		register = func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "Registration is disabled", http.StatusForbidden)
+		}
+	}
+
+	routes.RegisterAndHandle(public, "POST", "/auth/register", "Register a new user", register)
+	routes.RegisterAndHandle(public, "POST", "/auth/login", "Login and get a bearer token", h.Login)
+	routes.RegisterAndHandle(protected, "POST", "/auth/logout", "Revoke the current bearer token", h.Logout)
+	routes.RegisterAndHandle(protected, "GET", "/auth/me", "Get the current user", h.GetCurrentUser)
+	routes.RegisterAndHandle(protected, "PUT", "/auth/me", "Update the current user's time zone", h.UpdateCurrentUser)
+}
@@ -0,0 +1,11 @@
+package router
+
+import (
+	"github.com/gorilla/mux"
+	"routine-tracker/handlers"
+)
+
+// SetupCalendarRoutes configures the calendar feed routes
+func SetupCalendarRoutes(routes *Router, h *handlers.Handler, feeds *mux.Router) {
+	routes.RegisterAndHandle(feeds, "GET", "/calendar.ics", "Get iCalendar feed of due dates and goal dates", h.GetCalendar)
+}
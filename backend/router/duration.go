@@ -0,0 +1,32 @@
+package router
+
+import (
+	"github.com/gorilla/mux"
+	"net/http"
+	"routine-tracker/handlers"
+)
+
+func SetupDurationRoutes(routes *Router, h *handlers.Handler, api *mux.Router) {
+	// Duration tracker routes
+	routes.RegisterAndHandle(api, "GET", "/duration-trackers", "Get all duration trackers", h.GetDurationTrackers)
+	routes.RegisterAndHandle(api, "POST", "/duration-trackers", "Create new duration tracker", h.CreateDurationTracker)
+	routes.RegisterAndHandle(api, "GET", "/duration-trackers/{id}", "Get specific duration tracker", h.GetDurationTracker)
+	routes.RegisterAndHandle(api, "PUT", "/duration-trackers/{id}", "Update duration tracker", h.UpdateDurationTracker)
+	routes.RegisterAndHandle(api, "DELETE", "/duration-trackers/{id}", "Delete duration tracker", h.DeleteDurationTracker)
+	routes.RegisterAndHandle(api, "POST", "/duration-trackers/{id}/archive", "Archive duration tracker", h.ArchiveDurationTracker)
+	routes.RegisterAndHandle(api, "POST", "/duration-trackers/{id}/unarchive", "Unarchive duration tracker", h.UnarchiveDurationTracker)
+	routes.RegisterAndHandle(api, "GET", "/duration-trackers/{id}/stats", "Get duration tracker per-period totals", h.GetDurationTrackerStats)
+	routes.RegisterAndHandle(api, "GET", "/duration-trackers/{id}/history", "Get duration tracker settings history", h.GetDurationTrackerHistory)
+	routes.RegisterAndHandle(api, "GET", "/duration-trackers/{id}/next-due", "Get next due dates of a duration tracker", h.GetDurationNextDue)
+	routes.RegisterAndHandle(api, "POST", "/duration-trackers/{id}/timer/start", "Start timer", h.StartDurationTimer)
+	routes.RegisterAndHandle(api, "POST", "/duration-trackers/{id}/timer/stop", "Stop timer and log its duration", h.StopDurationTimer)
+	routes.RegisterAndHandle(api, "POST", "/duration-trackers/{id}/entries", "Add duration entry", h.AddDurationEntry)
+	routes.RegisterAndHandle(api, "GET", "/duration-trackers/{id}/entries.csv", "Export duration entries as CSV", h.ExportDurationEntriesCSV)
+	routes.RegisterAndHandle(api, "POST", "/duration-trackers/{id}/entries.csv", "Import duration entries from CSV", h.ImportDurationEntriesCSV)
+	routes.RegisterAndHandle(api, "GET", "/duration-trackers/{id}/entries", "Get duration entries",
+		func(w http.ResponseWriter, r *http.Request) {
+			vars := mux.Vars(r)
+			vars["type"] = "duration"
+			h.GetTrackerEntries(w, r.WithContext(r.Context()))
+		})
+}
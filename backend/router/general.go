@@ -1,22 +1,29 @@
 package router
 
 import (
-    "github.com/gorilla/mux"
-    "routine-tracker/handlers"
+	"github.com/gorilla/mux"
+	"routine-tracker/handlers"
 )
 
 // SetupGeneralRoutes configures general/shared routes
-func SetupGeneralRoutes(api *mux.Router) {
-    // Dashboard and overview routes
-    RegisterAndHandle(api, "GET", "/dashboard", "Get today's due trackers", handlers.GetDashboard)
-    
-    // Combined data routes
-    RegisterAndHandle(api, "GET", "/trackers", "Get all trackers combined", handlers.GetAllTrackers)
-    RegisterAndHandle(api, "GET", "/entries", "Get all entries", handlers.GetAllEntries)
-    RegisterAndHandle(api, "PUT", "/entries/{id}", "Update entry by ID", handlers.UpdateEntry)
-    RegisterAndHandle(api, "DELETE", "/entries/{id}", "Delete entry by ID", handlers.DeleteEntry)
-    RegisterAndHandle(api, "DELETE", "/entries", "Bulk delete entries", handlers.BulkDeleteEntries)
+func SetupGeneralRoutes(routes *Router, h *handlers.Handler, api *mux.Router) {
+	// Dashboard and overview routes
+	routes.RegisterAndHandle(api, "GET", "/dashboard", "Get today's due trackers", h.GetDashboard)
+	routes.RegisterAndHandle(api, "GET", "/agenda", "Get due trackers for each day of a range", h.GetAgenda)
 
-    // Health check or status routes (optional)
-    // api.HandleFunc("/health", handlers.HealthCheck).Methods("GET")
+	// Combined data routes
+	routes.RegisterAndHandle(api, "GET", "/trackers", "Get all trackers combined", h.GetAllTrackers)
+	routes.RegisterAndHandle(api, "PUT", "/trackers/order", "Reorder and pin trackers", h.UpdateTrackerOrder)
+	routes.RegisterAndHandle(api, "GET", "/entries", "Get all entries", h.GetAllEntries)
+	routes.RegisterAndHandle(api, "PUT", "/entries/{id}", "Update entry by ID", h.UpdateEntry)
+	routes.RegisterAndHandle(api, "DELETE", "/entries/{id}", "Delete entry by ID", h.DeleteEntry)
+	routes.RegisterAndHandle(api, "DELETE", "/entries", "Bulk delete entries", h.BulkDeleteEntries)
+
+	// Backup and migration routes
+	routes.RegisterAndHandle(api, "GET", "/export", "Export all data as JSON", h.ExportData)
+	routes.RegisterAndHandle(api, "POST", "/import", "Import data from JSON", h.ImportData)
+	routes.RegisterAndHandle(api, "POST", "/import/loop", "Import Loop Habit Tracker backup", h.ImportLoopBackup)
+
+	// Health check or status routes (optional)
+	// api.HandleFunc("/health", h.HealthCheck).Methods("GET")
 }
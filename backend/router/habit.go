@@ -7,18 +7,25 @@ import (
 )
 
 // SetupHabitRoutes configures all habit tracker routes
-func SetupHabitRoutes(api *mux.Router) {
+func SetupHabitRoutes(routes *Router, h *handlers.Handler, api *mux.Router) {
 
-	RegisterAndHandle(api, "GET", "/habit-trackers", "Get all habit trackers", handlers.GetHabitTrackers)
-	RegisterAndHandle(api, "POST", "/habit-trackers", "Create new habit tracker", handlers.CreateHabitTracker)
-	RegisterAndHandle(api, "GET", "/habit-trackers/{id}", "Get specific habit tracker", handlers.GetHabitTracker)
-	RegisterAndHandle(api, "PUT", "/habit-trackers/{id}", "Update habit tracker", handlers.UpdateHabitTracker)
-	RegisterAndHandle(api, "DELETE", "/habit-trackers/{id}", "Delete habit tracker", handlers.DeleteHabitTracker)
-	RegisterAndHandle(api, "POST", "/habit-trackers/{id}/entries", "Add habit entry", handlers.AddHabitEntry)
-	RegisterAndHandle(api, "GET", "/habit-trackers/{id}/entries", "Get habit entries",
+	routes.RegisterAndHandle(api, "GET", "/habit-trackers", "Get all habit trackers", h.GetHabitTrackers)
+	routes.RegisterAndHandle(api, "POST", "/habit-trackers", "Create new habit tracker", h.CreateHabitTracker)
+	routes.RegisterAndHandle(api, "GET", "/habit-trackers/{id}", "Get specific habit tracker", h.GetHabitTracker)
+	routes.RegisterAndHandle(api, "PUT", "/habit-trackers/{id}", "Update habit tracker", h.UpdateHabitTracker)
+	routes.RegisterAndHandle(api, "DELETE", "/habit-trackers/{id}", "Delete habit tracker", h.DeleteHabitTracker)
+	routes.RegisterAndHandle(api, "POST", "/habit-trackers/{id}/archive", "Archive habit tracker", h.ArchiveHabitTracker)
+	routes.RegisterAndHandle(api, "POST", "/habit-trackers/{id}/unarchive", "Unarchive habit tracker", h.UnarchiveHabitTracker)
+	routes.RegisterAndHandle(api, "GET", "/habit-trackers/{id}/stats", "Get habit streaks and period stats", h.GetHabitTrackerStats)
+	routes.RegisterAndHandle(api, "GET", "/habit-trackers/{id}/history", "Get habit tracker settings history", h.GetHabitTrackerHistory)
+	routes.RegisterAndHandle(api, "GET", "/habit-trackers/{id}/next-due", "Get next due dates of a habit tracker", h.GetHabitNextDue)
+	routes.RegisterAndHandle(api, "POST", "/habit-trackers/{id}/entries", "Add habit entry", h.AddHabitEntry)
+	routes.RegisterAndHandle(api, "GET", "/habit-trackers/{id}/entries", "Get habit entries",
 		func(w http.ResponseWriter, r *http.Request) {
 			vars := mux.Vars(r)
 			vars["type"] = "habit"
-			handlers.GetTrackerEntries(w, r.WithContext(r.Context()))
+			h.GetTrackerEntries(w, r.WithContext(r.Context()))
 		})
+	routes.RegisterAndHandle(api, "GET", "/habit-trackers/{id}/entries.csv", "Export habit entries as CSV", h.ExportHabitEntriesCSV)
+	routes.RegisterAndHandle(api, "POST", "/habit-trackers/{id}/entries.csv", "Import habit entries from CSV", h.ImportHabitEntriesCSV)
 }
@@ -0,0 +1,125 @@
+package router
+
+import (
+	"log/slog"
+	"net/http"
+	"routine-tracker/app"
+	"routine-tracker/auth"
+	"routine-tracker/database"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// RequireAuth rejects requests without a valid bearer token and stores the
+// authenticated user's ID in the request context. Personal API tokens also
+// restrict the request to the token's scopes. Sessions expire by the clock.
+func RequireAuth(store database.Store, clock app.Clock) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := auth.BearerToken(r)
+			if token == "" {
+				http.Error(w, "Missing bearer token", http.StatusUnauthorized)
+				return
+			}
+
+			ctx := r.Context()
+			if strings.HasPrefix(token, auth.APITokenPrefix) {
+				userID, tokenID, scopes, err := store.AuthenticateAPIToken(auth.HashToken(token), clock.Now())
+				if err != nil {
+					http.Error(w, "Invalid or revoked API token", http.StatusUnauthorized)
+					return
+				}
+				ctx = auth.WithScopes(auth.WithUserID(ctx, userID), scopes)
+				ctx = auth.WithAPITokenID(ctx, tokenID)
+			} else {
+				userID, err := store.GetSessionUserID(auth.HashToken(token), clock.Now())
+				if err != nil {
+					http.Error(w, "Invalid or expired token", http.StatusUnauthorized)
+					return
+				}
+				ctx = auth.WithUserID(ctx, userID)
+			}
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// AllowQueryToken lets clients that cannot set headers, like calendar apps,
+// pass a personal API token in the "token" query parameter. Login session
+// tokens are not accepted there because URLs end up in logs and histories.
+func AllowQueryToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Query().Get("token")
+		if token != "" && auth.BearerToken(r) == "" {
+			if !strings.HasPrefix(token, auth.APITokenPrefix) {
+				http.Error(w, "Only personal API tokens can be passed in the URL", http.StatusUnauthorized)
+				return
+			}
+			r = r.Clone(r.Context())
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequireScope rejects requests whose API token was not granted the scope
+func RequireScope(scope string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !auth.HasScope(r.Context(), scope) {
+			http.Error(w, "API token is missing the '"+scope+"' scope", http.StatusForbidden)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// routeScope returns the scope an API token needs to call a route
+func routeScope(method, path string) string {
+	read := method == "GET"
+
+	switch {
+	case path == "/auth/register", path == "/auth/login", path == "/auth/logout":
+		return ""
+	case strings.HasPrefix(path, "/auth/"), strings.HasPrefix(path, "/tokens"), strings.HasPrefix(path, "/notifications"),
+		path == "/export", strings.HasPrefix(path, "/import"), path == "/audit":
+		return auth.SCOPE_ADMIN
+	// Stopping a timer logs an entry
+	case strings.Contains(path, "entries"), strings.Contains(path, "/timer/"):
+		if read {
+			return auth.SCOPE_ENTRIES_READ
+		}
+		return auth.SCOPE_ENTRIES_WRITE
+	default:
+		if read {
+			return auth.SCOPE_TRACKERS_READ
+		}
+		return auth.SCOPE_TRACKERS_WRITE
+	}
+}
+
+// statusRecorder remembers the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	s.status = status
+	s.ResponseWriter.WriteHeader(status)
+}
+
+// LogRequests logs every request at debug level
+func LogRequests(logger *slog.Logger) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+			logger.Debug("request", "method", r.Method, "path", r.URL.Path,
+				"status", rec.status, "duration", time.Since(start))
+		})
+	}
+}
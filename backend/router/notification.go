@@ -0,0 +1,15 @@
+package router
+
+import (
+	"github.com/gorilla/mux"
+	"routine-tracker/handlers"
+)
+
+// SetupNotificationRoutes configures notification channel routes
+func SetupNotificationRoutes(routes *Router, h *handlers.Handler, api *mux.Router) {
+	routes.RegisterAndHandle(api, "GET", "/notifications/channels", "Get notification channels", h.GetNotificationChannels)
+	routes.RegisterAndHandle(api, "POST", "/notifications/channels", "Create notification channel", h.CreateNotificationChannel)
+	routes.RegisterAndHandle(api, "PUT", "/notifications/channels/{id}", "Update notification channel", h.UpdateNotificationChannel)
+	routes.RegisterAndHandle(api, "DELETE", "/notifications/channels/{id}", "Delete notification channel", h.DeleteNotificationChannel)
+	routes.RegisterAndHandle(api, "POST", "/notifications/test", "Send test notification", h.SendTestNotification)
+}
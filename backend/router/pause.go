@@ -0,0 +1,14 @@
+package router
+
+import (
+	"github.com/gorilla/mux"
+	"routine-tracker/handlers"
+)
+
+// SetupPauseRoutes configures the routes of pauses and vacations
+func SetupPauseRoutes(routes *Router, h *handlers.Handler, api *mux.Router) {
+	routes.RegisterAndHandle(api, "GET", "/pauses", "Get pauses", h.GetPauses)
+	routes.RegisterAndHandle(api, "POST", "/pauses", "Create pause", h.CreatePause)
+	routes.RegisterAndHandle(api, "PUT", "/pauses/{id}", "Update pause", h.UpdatePause)
+	routes.RegisterAndHandle(api, "DELETE", "/pauses/{id}", "Delete pause", h.DeletePause)
+}
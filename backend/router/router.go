@@ -1,27 +1,64 @@
 package router
 
 import (
-    "github.com/gorilla/mux"
-    httpSwagger "github.com/swaggo/http-swagger"
+	"net/http"
+	"routine-tracker/app"
+	"routine-tracker/config"
+	"routine-tracker/handlers"
+
+	"github.com/gorilla/mux"
+	httpSwagger "github.com/swaggo/http-swagger"
 )
 
 const APIPrefix = "/api"
 
-// Setup creates and configures the main router with all routes
-func Setup() *mux.Router {
-    // Initialize main router
-    r := mux.NewRouter()
-    
-    // Add Swagger documentation
-    r.PathPrefix("/swagger/").Handler(httpSwagger.WrapHandler)
-    
-    // API routes subrouter
-    api := r.PathPrefix(APIPrefix).Subrouter()
-    
-    // Setup route groups
-    SetupHabitRoutes(api)
-    SetupTargetRoutes(api)
-    SetupGeneralRoutes(api)
-    
-    return r
+// Router serves the API of an app and keeps its routes for PrintRoutes
+type Router struct {
+	mux    *mux.Router
+	cfg    *config.Config
+	routes []Route
+}
+
+// Setup creates and configures the main router with all routes of the app
+func Setup(a *app.App) *Router {
+	// Initialize main router
+	r := &Router{mux: mux.NewRouter(), cfg: a.Config}
+	r.mux.Use(LogRequests(a.Logger))
+	h := handlers.New(a)
+
+	// Add Swagger documentation
+	if a.Config.Features.Swagger {
+		r.mux.PathPrefix("/swagger/").Handler(httpSwagger.WrapHandler)
+	}
+
+	// API routes subrouter
+	api := r.mux.PathPrefix(APIPrefix).Subrouter()
+
+	// The calendar feed also takes an API token from the URL
+	feeds := api.NewRoute().Subrouter()
+	feeds.Use(AllowQueryToken, RequireAuth(a.Store, a))
+
+	// Everything except login and registration requires a bearer token
+	protected := api.NewRoute().Subrouter()
+	protected.Use(RequireAuth(a.Store, a))
+
+	// Setup route groups
+	SetupAuthRoutes(r, h, api, protected)
+	SetupTokenRoutes(r, h, protected)
+	SetupNotificationRoutes(r, h, protected)
+	SetupHabitRoutes(r, h, protected)
+	SetupTargetRoutes(r, h, protected)
+	SetupDurationRoutes(r, h, protected)
+	SetupGeneralRoutes(r, h, protected)
+	SetupPauseRoutes(r, h, protected)
+	SetupTagRoutes(r, h, protected)
+	SetupTrashRoutes(r, h, protected)
+	SetupAuditRoutes(r, h, protected)
+	SetupCalendarRoutes(r, h, feeds)
+
+	return r
+}
+
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mux.ServeHTTP(w, req)
 }
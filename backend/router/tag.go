@@ -0,0 +1,18 @@
+package router
+
+import (
+	"github.com/gorilla/mux"
+	"routine-tracker/handlers"
+)
+
+// SetupTagRoutes configures the routes of tags and categories
+func SetupTagRoutes(routes *Router, h *handlers.Handler, api *mux.Router) {
+	routes.RegisterAndHandle(api, "GET", "/tags", "Get tags", h.GetTags)
+	routes.RegisterAndHandle(api, "POST", "/tags", "Create tag", h.CreateTag)
+	routes.RegisterAndHandle(api, "PUT", "/tags/{id}", "Update tag", h.UpdateTag)
+	routes.RegisterAndHandle(api, "DELETE", "/tags/{id}", "Delete tag", h.DeleteTag)
+	routes.RegisterAndHandle(api, "GET", "/categories", "Get categories", h.GetCategories)
+	routes.RegisterAndHandle(api, "POST", "/categories", "Create category", h.CreateCategory)
+	routes.RegisterAndHandle(api, "PUT", "/categories/{id}", "Update category", h.UpdateCategory)
+	routes.RegisterAndHandle(api, "DELETE", "/categories/{id}", "Delete category", h.DeleteCategory)
+}
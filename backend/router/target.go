@@ -6,18 +6,26 @@ import (
 	"routine-tracker/handlers"
 )
 
-func SetupTargetRoutes(api *mux.Router) {
+func SetupTargetRoutes(routes *Router, h *handlers.Handler, api *mux.Router) {
 	// Target tracker routes
-	RegisterAndHandle(api, "GET", "/target-trackers", "Get all target trackers", handlers.GetTargetTrackers)
-	RegisterAndHandle(api, "POST", "/target-trackers", "Create new target tracker", handlers.CreateTargetTracker)
-	RegisterAndHandle(api, "GET", "/target-trackers/{id}", "Get specific target tracker", handlers.GetTargetTracker)
-	RegisterAndHandle(api, "PUT", "/target-trackers/{id}", "Update target tracker", handlers.UpdateTargetTracker)
-	RegisterAndHandle(api, "DELETE", "/target-trackers/{id}", "Delete target tracker", handlers.DeleteTargetTracker)
-	RegisterAndHandle(api, "POST", "/target-trackers/{id}/entries", "Add target entry", handlers.AddTargetEntry)
-	RegisterAndHandle(api, "GET", "/target-trackers/{id}/entries", "Get target entries",
+	routes.RegisterAndHandle(api, "GET", "/target-trackers", "Get all target trackers", h.GetTargetTrackers)
+	routes.RegisterAndHandle(api, "POST", "/target-trackers", "Create new target tracker", h.CreateTargetTracker)
+	routes.RegisterAndHandle(api, "GET", "/target-trackers/{id}", "Get specific target tracker", h.GetTargetTracker)
+	routes.RegisterAndHandle(api, "PUT", "/target-trackers/{id}", "Update target tracker", h.UpdateTargetTracker)
+	routes.RegisterAndHandle(api, "DELETE", "/target-trackers/{id}", "Delete target tracker", h.DeleteTargetTracker)
+	routes.RegisterAndHandle(api, "POST", "/target-trackers/{id}/archive", "Archive target tracker", h.ArchiveTargetTracker)
+	routes.RegisterAndHandle(api, "POST", "/target-trackers/{id}/unarchive", "Unarchive target tracker", h.UnarchiveTargetTracker)
+	routes.RegisterAndHandle(api, "GET", "/target-trackers/{id}/projection", "Get target pace, trend and projection", h.GetTargetTrackerProjection)
+	routes.RegisterAndHandle(api, "GET", "/target-trackers/{id}/history", "Get target tracker settings history", h.GetTargetTrackerHistory)
+	routes.RegisterAndHandle(api, "GET", "/target-trackers/{id}/segments", "Get target goal configurations over time", h.GetTargetTrackerSegments)
+	routes.RegisterAndHandle(api, "GET", "/target-trackers/{id}/next-due", "Get next due dates of a target tracker", h.GetTargetNextDue)
+	routes.RegisterAndHandle(api, "POST", "/target-trackers/{id}/entries", "Add target entry", h.AddTargetEntry)
+	routes.RegisterAndHandle(api, "GET", "/target-trackers/{id}/entries", "Get target entries",
 		func(w http.ResponseWriter, r *http.Request) {
 			vars := mux.Vars(r)
 			vars["type"] = "target"
-			handlers.GetTrackerEntries(w, r.WithContext(r.Context()))
+			h.GetTrackerEntries(w, r.WithContext(r.Context()))
 		})
+	routes.RegisterAndHandle(api, "GET", "/target-trackers/{id}/entries.csv", "Export target entries as CSV", h.ExportTargetEntriesCSV)
+	routes.RegisterAndHandle(api, "POST", "/target-trackers/{id}/entries.csv", "Import target entries from CSV", h.ImportTargetEntriesCSV)
 }
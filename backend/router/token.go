@@ -0,0 +1,13 @@
+package router
+
+import (
+	"github.com/gorilla/mux"
+	"routine-tracker/handlers"
+)
+
+// SetupTokenRoutes configures personal API token management routes
+func SetupTokenRoutes(routes *Router, h *handlers.Handler, api *mux.Router) {
+	routes.RegisterAndHandle(api, "GET", "/tokens", "Get personal API tokens", h.GetAPITokens)
+	routes.RegisterAndHandle(api, "POST", "/tokens", "Create personal API token", h.CreateAPIToken)
+	routes.RegisterAndHandle(api, "DELETE", "/tokens/{id}", "Revoke personal API token", h.RevokeAPIToken)
+}
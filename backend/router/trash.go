@@ -0,0 +1,16 @@
+package router
+
+import (
+	"github.com/gorilla/mux"
+	"routine-tracker/handlers"
+)
+
+// SetupTrashRoutes configures the routes of deleted trackers and entries
+func SetupTrashRoutes(routes *Router, h *handlers.Handler, api *mux.Router) {
+	routes.RegisterAndHandle(api, "GET", "/trash", "Get deleted trackers and entries", h.GetTrash)
+	routes.RegisterAndHandle(api, "DELETE", "/trash", "Empty the trash", h.EmptyTrash)
+	routes.RegisterAndHandle(api, "POST", "/trash/entries/restore", "Restore deleted entries", h.RestoreEntries)
+	routes.RegisterAndHandle(api, "POST", "/trash/habit-trackers/{id}/restore", "Restore deleted habit tracker", h.RestoreHabitTracker)
+	routes.RegisterAndHandle(api, "POST", "/trash/target-trackers/{id}/restore", "Restore deleted target tracker", h.RestoreTargetTracker)
+	routes.RegisterAndHandle(api, "POST", "/trash/duration-trackers/{id}/restore", "Restore deleted duration tracker", h.RestoreDurationTracker)
+}
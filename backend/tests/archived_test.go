@@ -0,0 +1,171 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"routine-tracker/auth"
+	"routine-tracker/models"
+	"routine-tracker/trackers"
+	"routine-tracker/trackers/habit"
+	"routine-tracker/trackers/target"
+)
+
+func TestArchiveTrackers(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	srv.app.Clock = fixedClock{time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)}
+	daily := models.Due{Type: models.INTERVAL, IntervalType: "day", IntervalValue: 1}
+	retired := srv.createDatedHabit(t, "Retired", "2024-01-01", daily)
+	srv.createDatedHabit(t, "Active", "2024-01-01", daily)
+	rr, _ := srv.makeRequest("POST", "/api/target-trackers", target.CreateTargetRequest{
+		TrackerName: "Weight", StartValue: 80, GoalValue: 70, StartDate: "2024-01-01", GoalDate: "2024-12-31", Due: daily,
+	})
+	var weight target.TargetTracker
+	json.Unmarshal(rr.Body.Bytes(), &weight)
+	srv.makeRequest("POST", fmt.Sprintf("/api/habit-trackers/%d/entries", retired.ID), models.AddEntryRequest{Date: "2024-05-31"})
+	srv.makeRequest("POST", fmt.Sprintf("/api/target-trackers/%d/entries", weight.ID), models.AddEntryRequest{Value: 75, Date: "2024-05-31"})
+
+	rr, _ = srv.makeRequest("POST", fmt.Sprintf("/api/habit-trackers/%d/archive", retired.ID), nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	var archived habit.HabitTracker
+	json.Unmarshal(rr.Body.Bytes(), &archived)
+	if archived.ArchivedAt == nil || !archived.ArchivedAt.Equal(srv.app.Now()) {
+		t.Errorf("Expected archivedAt %s, got %v", srv.app.Now(), archived.ArchivedAt)
+	}
+
+	rr, _ = srv.makeRequest("POST", fmt.Sprintf("/api/target-trackers/%d/archive", weight.ID), nil)
+	var archivedTarget target.TargetTracker
+	json.Unmarshal(rr.Body.Bytes(), &archivedTarget)
+	if rr.Code != http.StatusOK || archivedTarget.ArchivedAt == nil || archivedTarget.CurrentValue == nil || *archivedTarget.CurrentValue != 75 {
+		t.Errorf("Expected an archived target with its current value, got %d %+v", rr.Code, archivedTarget)
+	}
+
+	// Archiving again keeps the original time
+	srv.app.Clock = fixedClock{time.Date(2024, 6, 2, 10, 0, 0, 0, time.UTC)}
+	rr, _ = srv.makeRequest("POST", fmt.Sprintf("/api/habit-trackers/%d/archive", retired.ID), nil)
+	json.Unmarshal(rr.Body.Bytes(), &archived)
+	if archived.ArchivedAt == nil || archived.ArchivedAt.Day() != 1 {
+		t.Errorf("Expected the first archive time to be kept, got %v", archived.ArchivedAt)
+	}
+
+	// Archived trackers are left out of the dashboard, agenda and lists
+	if _, names := srv.dashboard(t, ""); strings.Join(names, " ") != "Active" {
+		t.Errorf("Expected only the active habit on the dashboard, got %v", names)
+	}
+	rr, _ = srv.makeRequest("GET", "/api/dashboard", nil)
+	var dashboard trackers.DashboardResponse
+	json.Unmarshal(rr.Body.Bytes(), &dashboard)
+	if len(dashboard.TargetTrackers) != 0 {
+		t.Errorf("Expected no target trackers on the dashboard, got %d", len(dashboard.TargetTrackers))
+	}
+	if agenda := srv.agenda(t, ""); len(agenda.Days[0].HabitTrackers) != 1 || len(agenda.Days[0].TargetTrackers) != 0 {
+		t.Errorf("Expected archived trackers out of the agenda, got %+v", agenda.Days[0])
+	}
+
+	for _, tt := range []struct {
+		query   string
+		habits  int
+		targets int
+	}{
+		{"", 1, 0},
+		{"?archived=false", 1, 0},
+		{"?archived=true", 1, 1},
+		{"?archived=all", 2, 1},
+	} {
+		rr, _ := srv.makeRequest("GET", "/api/trackers"+tt.query, nil)
+		var all trackers.TrackersResponse
+		json.Unmarshal(rr.Body.Bytes(), &all)
+		if len(all.HabitTrackers) != tt.habits || len(all.TargetTrackers) != tt.targets {
+			t.Errorf("%q: expected %d habits and %d targets, got %d and %d", tt.query, tt.habits, tt.targets, len(all.HabitTrackers), len(all.TargetTrackers))
+		}
+
+		rr, _ = srv.makeRequest("GET", "/api/habit-trackers"+tt.query, nil)
+		var habits []habit.HabitTracker
+		json.Unmarshal(rr.Body.Bytes(), &habits)
+		rr, _ = srv.makeRequest("GET", "/api/target-trackers"+tt.query, nil)
+		var targets []target.TargetTracker
+		json.Unmarshal(rr.Body.Bytes(), &targets)
+		if len(habits) != tt.habits || len(targets) != tt.targets {
+			t.Errorf("%q: expected %d habits and %d targets in the lists, got %d and %d", tt.query, tt.habits, tt.targets, len(habits), len(targets))
+		}
+	}
+	for _, url := range []string{"/api/trackers?archived=maybe", "/api/habit-trackers?archived=1", "/api/target-trackers?archived=yes"} {
+		if rr, _ := srv.makeRequest("GET", url, nil); rr.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %s, got %d", http.StatusBadRequest, url, rr.Code)
+		}
+	}
+
+	// The history stays readable
+	rr, _ = srv.makeRequest("GET", fmt.Sprintf("/api/habit-trackers/%d", retired.ID), nil)
+	var fetched habit.HabitTracker
+	json.Unmarshal(rr.Body.Bytes(), &fetched)
+	if rr.Code != http.StatusOK || fetched.ArchivedAt == nil {
+		t.Errorf("Expected the archived habit, got %d %+v", rr.Code, fetched)
+	}
+	if stats := srv.habitStats(t, retired.ID, "2024-05-31"); stats.CurrentStreak != 1 {
+		t.Errorf("Expected the stats of the archived habit, got current streak %d", stats.CurrentStreak)
+	}
+	rr, _ = srv.makeRequest("GET", fmt.Sprintf("/api/target-trackers/%d/entries", weight.ID), nil)
+	var entries []models.Entry
+	json.Unmarshal(rr.Body.Bytes(), &entries)
+	if len(entries) != 1 {
+		t.Errorf("Expected the entries of the archived target, got %d", len(entries))
+	}
+
+	// Restoring puts the tracker back on the dashboard
+	rr, _ = srv.makeRequest("POST", fmt.Sprintf("/api/habit-trackers/%d/unarchive", retired.ID), nil)
+	var restored habit.HabitTracker
+	json.Unmarshal(rr.Body.Bytes(), &restored)
+	if rr.Code != http.StatusOK || restored.ArchivedAt != nil {
+		t.Errorf("Expected a restored habit, got %d %+v", rr.Code, restored)
+	}
+	if _, names := srv.dashboard(t, ""); strings.Join(names, " ") != "Active Retired" {
+		t.Errorf("Expected both habits on the dashboard, got %v", names)
+	}
+
+	for _, url := range []string{"/api/habit-trackers/9999/archive", "/api/target-trackers/9999/unarchive"} {
+		if rr, _ := srv.makeRequest("POST", url, nil); rr.Code != http.StatusNotFound {
+			t.Errorf("Expected status %d for %s, got %d", http.StatusNotFound, url, rr.Code)
+		}
+	}
+}
+
+func TestArchivedTrackersInFeedsAndExports(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	daily := models.Due{Type: models.INTERVAL, IntervalType: "day", IntervalValue: 1}
+	retired := srv.createDatedHabit(t, "Retired", "2024-01-01", daily)
+	srv.createDatedHabit(t, "Active", "2024-01-01", daily)
+	srv.makeRequest("POST", fmt.Sprintf("/api/habit-trackers/%d/archive", retired.ID), nil)
+
+	feed := srv.calendarFeed(t, srv.createAPIToken(t, "Calendar", auth.SCOPE_TRACKERS_READ).Token)
+	if strings.Contains(feed, "SUMMARY:Retired") || !strings.Contains(feed, "SUMMARY:Active") {
+		t.Errorf("Expected only the active habit in the calendar feed:\n%s", feed)
+	}
+
+	// Exports keep archived trackers and imports restore their state
+	archive := srv.exportArchive(t, srv.token)
+	if len(archive.HabitTrackers) != 2 {
+		t.Fatalf("Expected both habits in the export, got %d", len(archive.HabitTrackers))
+	}
+	destToken, err := srv.registerAndLogin("dest", "password123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status, _ := srv.importArchive(t, destToken, "?mode=merge", archive); status != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, status)
+	}
+	rr, _ := srv.makeRequestWithToken(destToken, "GET", "/api/habit-trackers?archived=true", nil)
+	var habits []habit.HabitTracker
+	json.Unmarshal(rr.Body.Bytes(), &habits)
+	if len(habits) != 1 || habits[0].TrackerName != "Retired" {
+		t.Errorf("Expected the retired habit to stay archived, got %+v", habits)
+	}
+}
@@ -0,0 +1,153 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"routine-tracker/auth"
+	"routine-tracker/models"
+	"routine-tracker/trackers/target"
+)
+
+func (srv *testServer) auditEvents(t *testing.T, token, url string) []models.AuditEvent {
+	t.Helper()
+
+	rr, _ := srv.makeRequestWithToken(token, "GET", url, nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	var events []models.AuditEvent
+	if err := json.Unmarshal(rr.Body.Bytes(), &events); err != nil {
+		t.Fatal(err)
+	}
+	return events
+}
+
+func TestAuditTrackerHistory(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	srv.app.Clock = fixedClock{time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)}
+
+	rr, _ := srv.makeRequest("POST", "/api/target-trackers", target.CreateTargetRequest{
+		TrackerName: "Weight", StartValue: 90, GoalValue: 80, StartDate: "2024-01-01", GoalDate: "2024-12-31",
+		Due: models.Due{Type: models.INTERVAL, IntervalType: "day", IntervalValue: 1},
+	})
+	var weight target.TargetTracker
+	json.Unmarshal(rr.Body.Bytes(), &weight)
+
+	goal := 75.0
+	rr, _ = srv.makeRequest("PUT", fmt.Sprintf("/api/target-trackers/%d", weight.ID), target.UpdateTargetRequest{GoalValue: &goal})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	srv.makeRequest("POST", fmt.Sprintf("/api/target-trackers/%d/archive", weight.ID), nil)
+	srv.addEntry(t, models.TARGET, weight.ID, models.AddEntryRequest{Value: 89, Date: "2024-05-01"})
+
+	history := srv.auditEvents(t, srv.token, fmt.Sprintf("/api/target-trackers/%d/history", weight.ID))
+	if len(history) != 3 {
+		t.Fatalf("Expected create, update and archive in the history, got %+v", history)
+	}
+	archive, update, create := history[0], history[1], history[2]
+
+	if create.Action != models.AUDIT_CREATE || create.Actor != models.ACTOR_SESSION || create.EntityID != weight.ID {
+		t.Errorf("Unexpected create event %+v", create)
+	}
+	if name := create.Changes["trackerName"]; name.Before != nil || name.After != "Weight" {
+		t.Errorf("Expected the created tracker name, got %+v", name)
+	}
+
+	if update.Action != models.AUDIT_UPDATE {
+		t.Errorf("Expected an update event, got %+v", update)
+	}
+	if len(update.Changes) != 1 || update.Changes["goalValue"].Before != 80.0 || update.Changes["goalValue"].After != 75.0 {
+		t.Errorf("Expected only the goal value to change from 80 to 75, got %+v", update.Changes)
+	}
+
+	if archive.Action != models.AUDIT_ARCHIVE {
+		t.Errorf("Expected an archive event, got %+v", archive)
+	}
+	if archived := archive.Changes["archivedAt"]; archived.Before != nil || archived.After != "2024-06-01T10:00:00Z" {
+		t.Errorf("Expected archivedAt to be set, got %+v", archived)
+	}
+
+	rr, _ = srv.makeRequest("GET", "/api/habit-trackers/999/history", nil)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d for an unknown tracker, got %d", http.StatusNotFound, rr.Code)
+	}
+}
+
+func TestAuditLog(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	otherToken, err := srv.registerAndLogin("other", "password123")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	habit := srv.createDatedHabit(t, "Read", "2024-01-01", models.Due{Type: models.INTERVAL, IntervalType: "day", IntervalValue: 1})
+	entry := srv.addEntry(t, models.HABIT, habit.ID, models.AddEntryRequest{Date: "2024-05-01"})
+
+	// Entries written with an API token name the token
+	token := srv.createAPIToken(t, "Shortcut", auth.SCOPE_ENTRIES_WRITE)
+	note := "Chapter 3"
+	rr, _ := srv.makeRequestWithToken(token.Token, "PUT", fmt.Sprintf("/api/entries/%d", entry.ID), models.UpdateEntryRequest{Note: &note})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	srv.makeRequest("DELETE", fmt.Sprintf("/api/entries/%d", entry.ID), nil)
+
+	events := srv.auditEvents(t, srv.token, fmt.Sprintf("/api/audit?entity=entry&id=%d", entry.ID))
+	if len(events) != 3 {
+		t.Fatalf("Expected create, update and delete of the entry, got %+v", events)
+	}
+	deleted, updated, created := events[0], events[1], events[2]
+	if created.Action != models.AUDIT_CREATE || deleted.Action != models.AUDIT_DELETE {
+		t.Errorf("Expected create and delete events, got %s and %s", created.Action, deleted.Action)
+	}
+	if updated.Actor != models.ACTOR_API_TOKEN || updated.APITokenID == nil || *updated.APITokenID != token.ID {
+		t.Errorf("Expected the update to name API token %d, got %+v", token.ID, updated)
+	}
+	if change := updated.Changes["note"]; change.Before != nil || change.After != "Chapter 3" {
+		t.Errorf("Expected the note to be added, got %+v", updated.Changes)
+	}
+	if change := deleted.Changes["id"]; change.Before != float64(entry.ID) || change.After != nil {
+		t.Errorf("Expected the deleted entry as before, got %+v", deleted.Changes)
+	}
+
+	// Token values are never written to the log
+	events = srv.auditEvents(t, srv.token, "/api/audit?entity=api_token")
+	if len(events) != 1 || events[0].Changes["name"].After != "Shortcut" {
+		t.Errorf("Expected the created token, got %+v", events)
+	}
+	for _, event := range srv.auditEvents(t, srv.token, "/api/audit") {
+		if change, ok := event.Changes["token"]; ok && change.After != "[redacted]" && change.After != nil {
+			t.Errorf("Expected the token to be redacted, got %+v", change)
+		}
+	}
+
+	if events := srv.auditEvents(t, srv.token, "/api/audit?limit=2"); len(events) != 2 {
+		t.Errorf("Expected 2 events with limit=2, got %d", len(events))
+	}
+
+	// Other users only see their own events
+	events = srv.auditEvents(t, otherToken, "/api/audit")
+	if len(events) != 1 || events[0].Entity != models.AUDIT_USER || events[0].Action != models.AUDIT_CREATE {
+		t.Errorf("Expected only the registration of the other user, got %+v", events)
+	}
+
+	for _, query := range []string{"entity=tracker", "id=1", "entity=entry&id=x", "limit=0"} {
+		rr, _ := srv.makeRequest("GET", "/api/audit?"+query, nil)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %s, got %d", http.StatusBadRequest, query, rr.Code)
+		}
+	}
+
+	// Reading the log needs the admin scope
+	rr, _ = srv.makeRequestWithToken(token.Token, "GET", "/api/audit", nil)
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("Expected status %d without the admin scope, got %d", http.StatusForbidden, rr.Code)
+	}
+}
@@ -0,0 +1,270 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"routine-tracker/models"
+	"routine-tracker/trackers"
+	"routine-tracker/trackers/habit"
+	"routine-tracker/trackers/target"
+)
+
+func TestRegisterAndLogin(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	token, err := srv.registerAndLogin("auth-user", "auth-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr, err := srv.makeRequestWithToken(token, "GET", "/api/auth/me", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	var user models.User
+	if err := json.Unmarshal(rr.Body.Bytes(), &user); err != nil {
+		t.Fatalf("Failed to parse user response: %v", err)
+	}
+
+	if user.Username != "auth-user" {
+		t.Errorf("Expected username 'auth-user', got '%s'", user.Username)
+	}
+	if user.PasswordHash != "" {
+		t.Error("Expected password hash not to be exposed")
+	}
+}
+
+func TestRegisterValidation(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	tests := []struct {
+		name       string
+		request    models.RegisterRequest
+		wantStatus int
+	}{
+		{"missing username", models.RegisterRequest{Username: " ", Password: "long-enough"}, http.StatusBadRequest},
+		{"short password", models.RegisterRequest{Username: "short-password", Password: "short"}, http.StatusBadRequest},
+		{"duplicate username", models.RegisterRequest{Username: "testuser", Password: "long-enough"}, http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr, err := srv.makeRequestWithToken("", "POST", "/api/auth/register", tt.request)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rr.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d. Body: %s", tt.wantStatus, rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestLoginWithWrongPassword(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	rr, err := srv.makeRequestWithToken("", "POST", "/api/auth/login", models.LoginRequest{Username: "testuser", Password: "wrong-password"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d, got %d", http.StatusUnauthorized, rr.Code)
+	}
+}
+
+func TestRequestsWithoutValidTokenAreRejected(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	for _, token := range []string{"", "not-a-real-token"} {
+		rr, err := srv.makeRequestWithToken(token, "GET", "/api/habit-trackers", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rr.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status %d for token %q, got %d", http.StatusUnauthorized, token, rr.Code)
+		}
+	}
+}
+
+func TestLogout(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	token, err := srv.registerAndLogin("logout-user", "logout-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr, err := srv.makeRequestWithToken(token, "POST", "/api/auth/logout", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("Expected status %d, got %d", http.StatusNoContent, rr.Code)
+	}
+
+	rr, err = srv.makeRequestWithToken(token, "GET", "/api/auth/me", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d after logout, got %d", http.StatusUnauthorized, rr.Code)
+	}
+}
+
+func TestSessionExpiresByClock(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	srv.app.Clock = fixedClock{time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)}
+	token, err := srv.registerAndLogin("expiring-user", "expiring-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr, _ := srv.makeRequestWithToken(token, "GET", "/api/auth/me", nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+
+	srv.app.Clock = fixedClock{time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC).Add(31 * 24 * time.Hour)}
+	rr, _ = srv.makeRequestWithToken(token, "GET", "/api/auth/me", nil)
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d after the session expired, got %d", http.StatusUnauthorized, rr.Code)
+	}
+}
+
+func TestUserIsolation(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	otherToken, err := srv.registerAndLogin("other-user", "other-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The default test user owns a habit and a target with entries
+	habitRequest := habit.CreateHabitRequest{
+		TrackerName: "Private Habit",
+		Goal:        1,
+		TimePeriod:  models.PER_DAY,
+		StartDate:   time.Now().AddDate(0, 0, -1).Format("2006-01-02"),
+		Due: models.Due{
+			Type:          models.INTERVAL,
+			IntervalType:  "day",
+			IntervalValue: 1,
+		},
+	}
+	createRr, _ := srv.makeRequest("POST", "/api/habit-trackers", habitRequest)
+	var privateHabit habit.HabitTracker
+	json.Unmarshal(createRr.Body.Bytes(), &privateHabit)
+
+	targetRequest := target.CreateTargetRequest{
+		TrackerName: "Private Target",
+		StartValue:  0,
+		GoalValue:   100,
+		StartDate:   time.Now().AddDate(0, 0, -1).Format("2006-01-02"),
+		GoalDate:    time.Now().AddDate(0, 1, 0).Format("2006-01-02"),
+		AddToTotal:  true,
+		Due: models.Due{
+			Type:          models.INTERVAL,
+			IntervalType:  "day",
+			IntervalValue: 1,
+		},
+	}
+	createRr, _ = srv.makeRequest("POST", "/api/target-trackers", targetRequest)
+	var privateTarget target.TargetTracker
+	json.Unmarshal(createRr.Body.Bytes(), &privateTarget)
+
+	entryRr, _ := srv.makeRequest("POST", fmt.Sprintf("/api/habit-trackers/%d/entries", privateHabit.ID), models.AddEntryRequest{})
+	var privateEntry models.Entry
+	json.Unmarshal(entryRr.Body.Bytes(), &privateEntry)
+	srv.makeRequest("POST", fmt.Sprintf("/api/target-trackers/%d/entries", privateTarget.ID), models.AddEntryRequest{Value: 10})
+
+	newNote := "changed by someone else"
+	notFoundRequests := []struct {
+		method string
+		url    string
+		body   interface{}
+	}{
+		{"GET", fmt.Sprintf("/api/habit-trackers/%d", privateHabit.ID), nil},
+		{"PUT", fmt.Sprintf("/api/habit-trackers/%d", privateHabit.ID), habit.UpdateHabitRequest{TrackerName: &newNote}},
+		{"DELETE", fmt.Sprintf("/api/habit-trackers/%d", privateHabit.ID), nil},
+		{"GET", fmt.Sprintf("/api/habit-trackers/%d/entries", privateHabit.ID), nil},
+		{"POST", fmt.Sprintf("/api/habit-trackers/%d/entries", privateHabit.ID), models.AddEntryRequest{}},
+		{"GET", fmt.Sprintf("/api/habit-trackers/%d/stats", privateHabit.ID), nil},
+		{"GET", fmt.Sprintf("/api/target-trackers/%d", privateTarget.ID), nil},
+		{"PUT", fmt.Sprintf("/api/target-trackers/%d", privateTarget.ID), target.UpdateTargetRequest{TrackerName: &newNote}},
+		{"DELETE", fmt.Sprintf("/api/target-trackers/%d", privateTarget.ID), nil},
+		{"GET", fmt.Sprintf("/api/target-trackers/%d/entries", privateTarget.ID), nil},
+		{"POST", fmt.Sprintf("/api/target-trackers/%d/entries", privateTarget.ID), models.AddEntryRequest{Value: 5}},
+		{"GET", fmt.Sprintf("/api/target-trackers/%d/projection", privateTarget.ID), nil},
+		{"PUT", fmt.Sprintf("/api/entries/%d", privateEntry.ID), models.UpdateEntryRequest{Note: &newNote}},
+		{"DELETE", fmt.Sprintf("/api/entries/%d", privateEntry.ID), nil},
+	}
+
+	for _, req := range notFoundRequests {
+		rr, err := srv.makeRequestWithToken(otherToken, req.method, req.url, req.body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("%s %s: expected status %d for another user's data, got %d", req.method, req.url, http.StatusNotFound, rr.Code)
+		}
+	}
+
+	// Bulk delete silently ignores entries of other users
+	srv.makeRequestWithToken(otherToken, "DELETE", "/api/entries", []int{privateEntry.ID})
+
+	// Lists of the other user must not contain any of the private data
+	rr, _ := srv.makeRequestWithToken(otherToken, "GET", "/api/trackers", nil)
+	var otherTrackers trackers.TrackersResponse
+	json.Unmarshal(rr.Body.Bytes(), &otherTrackers)
+	if len(otherTrackers.HabitTrackers) != 0 || len(otherTrackers.TargetTrackers) != 0 {
+		t.Errorf("Expected other user to have no trackers, got %d habits and %d targets",
+			len(otherTrackers.HabitTrackers), len(otherTrackers.TargetTrackers))
+	}
+
+	rr, _ = srv.makeRequestWithToken(otherToken, "GET", "/api/entries", nil)
+	var otherEntries []models.Entry
+	json.Unmarshal(rr.Body.Bytes(), &otherEntries)
+	if len(otherEntries) != 0 {
+		t.Errorf("Expected other user to have no entries, got %d", len(otherEntries))
+	}
+
+	rr, _ = srv.makeRequestWithToken(otherToken, "GET", "/api/dashboard", nil)
+	var otherDashboard trackers.DashboardResponse
+	json.Unmarshal(rr.Body.Bytes(), &otherDashboard)
+	if len(otherDashboard.HabitTrackers) != 0 || len(otherDashboard.TargetTrackers) != 0 {
+		t.Error("Expected other user's dashboard to be empty")
+	}
+
+	// The owner still sees everything untouched
+	rr, _ = srv.makeRequest("GET", fmt.Sprintf("/api/habit-trackers/%d", privateHabit.ID), nil)
+	var ownHabit habit.HabitTracker
+	json.Unmarshal(rr.Body.Bytes(), &ownHabit)
+	if rr.Code != http.StatusOK || ownHabit.TrackerName != "Private Habit" {
+		t.Errorf("Expected owner to still see the unchanged habit, got status %d and name '%s'", rr.Code, ownHabit.TrackerName)
+	}
+
+	rr, _ = srv.makeRequest("GET", fmt.Sprintf("/api/habit-trackers/%d/entries", privateHabit.ID), nil)
+	var ownEntries []models.Entry
+	json.Unmarshal(rr.Body.Bytes(), &ownEntries)
+	if len(ownEntries) != 1 || ownEntries[0].Note != "" {
+		t.Errorf("Expected owner to still have the unchanged entry, got %+v", ownEntries)
+	}
+
+	rr, _ = srv.makeRequest("GET", fmt.Sprintf("/api/target-trackers/%d", privateTarget.ID), nil)
+	var ownTarget target.TargetTracker
+	json.Unmarshal(rr.Body.Bytes(), &ownTarget)
+	if ownTarget.CurrentValue == nil || *ownTarget.CurrentValue != 10 {
+		t.Errorf("Expected owner's target current value to stay 10, got %v", ownTarget.CurrentValue)
+	}
+}
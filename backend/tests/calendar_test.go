@@ -0,0 +1,213 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"routine-tracker/auth"
+	"routine-tracker/models"
+	"routine-tracker/trackers/habit"
+	"routine-tracker/trackers/target"
+)
+
+// Helper function to fetch the calendar feed the way calendar apps do, with
+// the token in the URL
+func (srv *testServer) calendarFeed(t *testing.T, token string) string {
+	t.Helper()
+
+	req, _ := http.NewRequest("GET", "/api/calendar.ics?token="+token, nil)
+	rr := httptest.NewRecorder()
+	srv.router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if !strings.HasPrefix(rr.Header().Get("Content-Type"), "text/calendar") {
+		t.Errorf("Expected text/calendar, got %q", rr.Header().Get("Content-Type"))
+	}
+
+	// Unfold the content lines
+	return strings.ReplaceAll(rr.Body.String(), "\r\n ", "")
+}
+
+// Helper function to return the lines of the event with the given UID
+func calendarEvent(t *testing.T, feed, uid string) []string {
+	t.Helper()
+
+	for _, event := range strings.Split(feed, "BEGIN:VEVENT\r\n")[1:] {
+		if strings.Contains(event, "UID:"+uid+"\r\n") {
+			return strings.Split(strings.TrimSpace(event), "\r\n")
+		}
+	}
+	t.Fatalf("Event %s not found in feed:\n%s", uid, feed)
+	return nil
+}
+
+func hasLine(lines []string, line string) bool {
+	for _, l := range lines {
+		if l == line {
+			return true
+		}
+	}
+	return false
+}
+
+func TestCalendarFeed(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	token := srv.createAPIToken(t, "Calendar", auth.SCOPE_TRACKERS_READ).Token
+
+	rr, _ := srv.makeRequest("POST", "/api/habit-trackers", habit.CreateHabitRequest{
+		TrackerName: "Read, then write; repeat",
+		Goal:        1,
+		TimePeriod:  models.PER_DAY,
+		StartDate:   "2024-01-03",
+		Due:         models.Due{Type: models.RRULE, RRule: "FREQ=MONTHLY;BYDAY=1MO"},
+		Reminders:   models.Reminder{Times: []string{"08:00", "18:30"}, Enabled: true},
+	})
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusCreated, rr.Code, rr.Body.String())
+	}
+	srv.createDatedHabit(t, "Gym", "2024-01-01", models.Due{Type: models.SPECIFIC_DAYS, SpecificDays: []string{"monday", "friday"}})
+	rr, _ = srv.makeRequest("POST", "/api/target-trackers", target.CreateTargetRequest{
+		TrackerName: "Weight",
+		StartValue:  80,
+		GoalValue:   72.5,
+		StartDate:   "2024-01-01",
+		GoalDate:    "2024-06-30",
+		Due:         models.Due{Type: models.INTERVAL, IntervalType: "week", IntervalValue: 2},
+	})
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusCreated, rr.Code, rr.Body.String())
+	}
+
+	feed := srv.calendarFeed(t, token)
+	if !strings.HasPrefix(feed, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n") || !strings.HasSuffix(feed, "END:VCALENDAR\r\n") {
+		t.Fatalf("Expected a calendar, got:\n%s", feed)
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(feed, "\r\n"), "\r\n") {
+		if strings.Contains(line, "\n") {
+			t.Errorf("Expected CRLF line endings, got %q", line)
+		}
+	}
+
+	// The event starts on the first due day, reminders become alarms
+	monthly := calendarEvent(t, feed, "habit-1@progress")
+	for _, line := range []string{
+		"DTSTART;VALUE=DATE:20240205",
+		"RRULE:FREQ=MONTHLY;BYDAY=1MO",
+		`SUMMARY:Read\, then write\; repeat`,
+		"TRIGGER:PT8H",
+		"TRIGGER:PT18H30M",
+	} {
+		if !hasLine(monthly, line) {
+			t.Errorf("Expected %q in event:\n%s", line, strings.Join(monthly, "\n"))
+		}
+	}
+
+	gym := calendarEvent(t, feed, "habit-2@progress")
+	if !hasLine(gym, "RRULE:FREQ=WEEKLY;BYDAY=MO,FR") || hasLine(gym, "BEGIN:VALARM") {
+		t.Errorf("Unexpected specific days event:\n%s", strings.Join(gym, "\n"))
+	}
+
+	weight := calendarEvent(t, feed, "target-1@progress")
+	if !hasLine(weight, "RRULE:FREQ=WEEKLY;INTERVAL=2") || !hasLine(weight, "DTSTART;VALUE=DATE:20240101") {
+		t.Errorf("Unexpected interval event:\n%s", strings.Join(weight, "\n"))
+	}
+
+	// The goal date is a single all-day event
+	goal := calendarEvent(t, feed, "target-1-goal@progress")
+	if !hasLine(goal, "DTSTART;VALUE=DATE:20240630") || !hasLine(goal, "DTEND;VALUE=DATE:20240701") || !hasLine(goal, "DESCRIPTION:Reach 72.5") {
+		t.Errorf("Unexpected goal event:\n%s", strings.Join(goal, "\n"))
+	}
+	for _, line := range goal {
+		if strings.HasPrefix(line, "RRULE:") {
+			t.Errorf("Expected the goal event not to repeat, got %s", line)
+		}
+	}
+
+	// Edits show up the next time the feed is fetched
+	name := "Monthly Review"
+	rr, _ = srv.makeRequest("PUT", "/api/habit-trackers/1", habit.UpdateHabitRequest{
+		TrackerName: &name,
+		Due:         &models.Due{Type: models.RRULE, RRule: "FREQ=MONTHLY;BYMONTHDAY=-1"},
+		Reminders:   &models.Reminder{Times: []string{"08:00"}, Enabled: false},
+	})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	goalDate := "2024-12-31"
+	rr, _ = srv.makeRequest("PUT", "/api/target-trackers/1", target.UpdateTargetRequest{GoalDate: &goalDate})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	feed = srv.calendarFeed(t, token)
+	monthly = calendarEvent(t, feed, "habit-1@progress")
+	if !hasLine(monthly, "SUMMARY:Monthly Review") || !hasLine(monthly, "RRULE:FREQ=MONTHLY;BYMONTHDAY=-1") ||
+		!hasLine(monthly, "DTSTART;VALUE=DATE:20240131") || hasLine(monthly, "BEGIN:VALARM") {
+		t.Errorf("Expected updated event, got:\n%s", strings.Join(monthly, "\n"))
+	}
+	if !hasLine(calendarEvent(t, feed, "target-1-goal@progress"), "DTSTART;VALUE=DATE:20241231") {
+		t.Error("Expected updated goal date")
+	}
+}
+
+func TestCalendarFeedLongLines(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	name := strings.Repeat("Übung ", 20)
+	srv.createDatedHabit(t, name, "2024-01-01", models.Due{Type: models.INTERVAL, IntervalType: "day", IntervalValue: 1})
+
+	rr, _ := srv.makeRequest("GET", "/api/calendar.ics", nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d with a bearer token, got %d", http.StatusOK, rr.Code)
+	}
+	for _, line := range strings.Split(rr.Body.String(), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("Expected lines of at most 75 octets, got %d: %q", len(line), line)
+		}
+		if !utf8.ValidString(line) {
+			t.Errorf("Expected folding to keep characters whole, got %q", line)
+		}
+	}
+	if !strings.Contains(strings.ReplaceAll(rr.Body.String(), "\r\n ", ""), "SUMMARY:"+name) {
+		t.Error("Expected the whole name after unfolding")
+	}
+}
+
+func TestCalendarFeedAuth(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	entriesOnly := srv.createAPIToken(t, "Entries", auth.SCOPE_ENTRIES_READ).Token
+
+	for _, tt := range []struct {
+		name   string
+		url    string
+		status int
+	}{
+		{"no token", "/api/calendar.ics", http.StatusUnauthorized},
+		{"session token in URL", "/api/calendar.ics?token=" + srv.token, http.StatusUnauthorized},
+		{"unknown API token", "/api/calendar.ics?token=" + auth.APITokenPrefix + "unknown", http.StatusUnauthorized},
+		{"missing scope", "/api/calendar.ics?token=" + entriesOnly, http.StatusForbidden},
+	} {
+		req, _ := http.NewRequest("GET", tt.url, nil)
+		rr := httptest.NewRecorder()
+		srv.router.ServeHTTP(rr, req)
+		if rr.Code != tt.status {
+			t.Errorf("%s: expected status %d, got %d", tt.name, tt.status, rr.Code)
+		}
+	}
+
+	// Other routes keep ignoring the query parameter
+	token := srv.createAPIToken(t, "Calendar", auth.SCOPE_TRACKERS_READ).Token
+	req, _ := http.NewRequest("GET", fmt.Sprintf("/api/trackers?token=%s", token), nil)
+	rr := httptest.NewRecorder()
+	srv.router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d for token in URL of another route, got %d", http.StatusUnauthorized, rr.Code)
+	}
+}
@@ -0,0 +1,243 @@
+package tests
+
+import (
+	"bytes"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"routine-tracker/config"
+)
+
+// Helper function to look up environment variables from a map
+func envFrom(values map[string]string) func(string) string {
+	return func(name string) string {
+		return values[name]
+	}
+}
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestConfigDefaults(t *testing.T) {
+	t.Parallel()
+	cfg, args, err := config.Load([]string{"migrate", "status"}, envFrom(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Server.Addr != ":8080" || cfg.Database.DSN != "./tracker.db" || cfg.Log.Level != "info" || cfg.TLS() {
+		t.Errorf("Unexpected defaults %+v", cfg)
+	}
+	if !cfg.Features.Registration || !cfg.Features.Reminders || !cfg.Features.Swagger {
+		t.Errorf("Expected all features to be enabled by default, got %+v", cfg.Features)
+	}
+	if cfg.Trash.RetentionDays != 30 || cfg.TrashRetention() != 30*24*time.Hour {
+		t.Errorf("Expected a trash retention of 30 days, got %d", cfg.Trash.RetentionDays)
+	}
+	if strings.Join(args, " ") != "migrate status" {
+		t.Errorf("Expected remaining arguments 'migrate status', got %v", args)
+	}
+}
+
+func TestConfigPrecedence(t *testing.T) {
+	t.Parallel()
+	yamlFile := writeConfigFile(t, "config.yaml", `
+server:
+  addr: ":9000"
+database:
+  dsn: /data/from-file.db
+cors:
+  allowed_origins:
+    - https://progress.example.com
+log:
+  level: warn
+features:
+  reminders: false
+`)
+
+	// The file overrides the defaults
+	cfg, _, err := config.Load([]string{"-config", yamlFile}, envFrom(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Server.Addr != ":9000" || cfg.Database.DSN != "/data/from-file.db" || cfg.Log.Level != "warn" {
+		t.Errorf("Expected values from file, got %+v", cfg)
+	}
+	if len(cfg.CORS.AllowedOrigins) != 1 || cfg.Features.Reminders || !cfg.Features.Swagger {
+		t.Errorf("Expected origins and features from file, got %+v %+v", cfg.CORS, cfg.Features)
+	}
+
+	// Environment variables override the file
+	env := envFrom(map[string]string{
+		"PROGRESS_CONFIG":            yamlFile,
+		"PROGRESS_ADDR":              "127.0.0.1:9100",
+		"PROGRESS_CORS_ORIGINS":      "https://a.example.com, https://b.example.com",
+		"PROGRESS_FEATURE_REMINDERS": "true",
+	})
+	cfg, _, err = config.Load(nil, env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Server.Addr != "127.0.0.1:9100" || cfg.Database.DSN != "/data/from-file.db" || !cfg.Features.Reminders {
+		t.Errorf("Expected environment to override file, got %+v", cfg)
+	}
+	if strings.Join(cfg.CORS.AllowedOrigins, " ") != "https://a.example.com https://b.example.com" {
+		t.Errorf("Unexpected origins %v", cfg.CORS.AllowedOrigins)
+	}
+
+	// Flags override everything
+	cfg, _, err = config.Load([]string{"-addr", ":9200", "-reminders=false", "-log-level", "debug"}, env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Server.Addr != ":9200" || cfg.Features.Reminders || cfg.Log.Level != "debug" {
+		t.Errorf("Expected flags to override environment, got %+v", cfg)
+	}
+}
+
+func TestConfigTOMLFile(t *testing.T) {
+	t.Parallel()
+	path := writeConfigFile(t, "config.toml", `
+[server]
+addr = ":7000"
+
+[cors]
+allowed_origins = ["https://progress.example.com"]
+
+[features]
+swagger = false
+`)
+
+	cfg, _, err := config.Load([]string{"-config", path}, envFrom(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Server.Addr != ":7000" || cfg.Features.Swagger || cfg.CORS.AllowedOrigins[0] != "https://progress.example.com" {
+		t.Errorf("Unexpected config from TOML %+v", cfg)
+	}
+}
+
+func TestConfigValidation(t *testing.T) {
+	t.Parallel()
+	cert := writeConfigFile(t, "cert.pem", "cert")
+
+	tests := []struct {
+		name string
+		args []string
+		env  map[string]string
+		file string
+	}{
+		{"invalid address", []string{"-addr", "8080"}, nil, ""},
+		{"certificate without key", []string{"-tls-cert", cert}, nil, ""},
+		{"missing certificate", []string{"-tls-cert", "/missing/cert.pem", "-tls-key", cert}, nil, ""},
+		{"empty DSN", []string{"-db", ""}, nil, ""},
+		{"origin without scheme", []string{"-cors-origins", "progress.example.com"}, nil, ""},
+		{"origin with path", []string{"-cors-origins", "https://example.com/app"}, nil, ""},
+		{"unknown log level", nil, map[string]string{"PROGRESS_LOG_LEVEL": "verbose"}, ""},
+		{"invalid feature toggle", nil, map[string]string{"PROGRESS_FEATURE_SWAGGER": "maybe"}, ""},
+		{"no trash retention", []string{"-trash-retention-days", "0"}, nil, ""},
+		{"invalid trash retention", nil, map[string]string{"PROGRESS_TRASH_RETENTION_DAYS": "a month"}, ""},
+		{"unknown flag", []string{"-port", "8080"}, nil, ""},
+		{"unknown file field", nil, nil, "server:\n  port: 8080\n"},
+		{"missing file", []string{"-config", "/missing/config.yaml"}, nil, ""},
+		{"SMTP without from address", nil, map[string]string{"PROGRESS_SMTP_HOST": "mail.example.com"}, ""},
+		{"invalid SMTP port", nil, map[string]string{"PROGRESS_SMTP_HOST": "mail.example.com", "PROGRESS_SMTP_FROM": "progress@example.com", "PROGRESS_SMTP_PORT": "smtp"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := tt.args
+			if tt.file != "" {
+				args = append(args, "-config", writeConfigFile(t, "config.yaml", tt.file))
+			}
+			if _, _, err := config.Load(args, envFrom(tt.env)); err == nil {
+				t.Error("Expected configuration to be rejected")
+			}
+		})
+	}
+
+	// TLS with both files is accepted
+	cfg, _, err := config.Load([]string{"-tls-cert", cert, "-tls-key", cert}, envFrom(nil))
+	if err != nil || !cfg.TLS() {
+		t.Errorf("Expected TLS config to be accepted, got %v", err)
+	}
+}
+
+func TestConfigNotifications(t *testing.T) {
+	t.Parallel()
+	path := writeConfigFile(t, "config.toml", `
+[notifications]
+allow_internal_hosts = true
+
+[notifications.smtp]
+host = "mail.example.com"
+from = "progress@example.com"
+`)
+
+	env := envFrom(map[string]string{"PROGRESS_SMTP_PORT": "2525", "PROGRESS_SMTP_PASSWORD": "secret"})
+	cfg, _, err := config.Load([]string{"-config", path}, env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	smtp := cfg.Notifications.SMTP
+	if smtp.Host != "mail.example.com" || smtp.Port != 2525 || smtp.Password != "secret" || !cfg.Notifications.AllowInternalHosts {
+		t.Errorf("Unexpected notification config %+v", cfg.Notifications)
+	}
+
+	// The app sends email through the configured server
+	srv := newTestServerWithConfig(t, cfg)
+	if srv.app.Notifier.SMTP.Host != "mail.example.com" || srv.app.Notifier.SMTP.Port != 2525 {
+		t.Errorf("Expected the dispatcher to use the configured SMTP server, got %+v", srv.app.Notifier.SMTP)
+	}
+
+	cfg, _, err = config.Load([]string{"-notify-internal-hosts=false", "-config", path}, env)
+	if err != nil || cfg.Notifications.AllowInternalHosts {
+		t.Errorf("Expected the flag to disallow internal hosts, got %v", err)
+	}
+	if config.Default().Notifications.AllowInternalHosts {
+		t.Error("Expected internal hosts to be refused by default")
+	}
+}
+
+func TestConfigPrint(t *testing.T) {
+	t.Parallel()
+	cfg := config.Default()
+	cfg.Database.DSN = "postgres://progress:secret@db:5432/progress"
+
+	var out bytes.Buffer
+	cfg.Print(&out)
+	if strings.Contains(out.String(), "secret") {
+		t.Errorf("Expected password to be hidden:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), ":8080") || !strings.Contains(out.String(), "http://localhost:5173") {
+		t.Errorf("Expected address and origins in output:\n%s", out.String())
+	}
+}
+
+func TestConfigFeatureToggles(t *testing.T) {
+	t.Parallel()
+	cfg := config.Default()
+	cfg.Features.Registration = false
+	cfg.Features.Swagger = false
+	srv := newTestServerWithConfig(t, cfg)
+
+	rr, _ := srv.makeRequestWithToken("", "POST", "/api/auth/register", map[string]string{"username": "disabled", "password": "password123"})
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("Expected status %d when registration is disabled, got %d", http.StatusForbidden, rr.Code)
+	}
+
+	rr, _ = srv.makeRequestWithToken("", "GET", "/swagger/index.html", nil)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d when Swagger is disabled, got %d", http.StatusNotFound, rr.Code)
+	}
+}
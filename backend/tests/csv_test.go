@@ -0,0 +1,230 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"routine-tracker/models"
+	"routine-tracker/trackers/target"
+)
+
+// Helper function to send a raw body, e.g. a CSV file
+func (srv *testServer) makeRawRequest(token, method, url, contentType string, body []byte) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, url, bytes.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	rr := httptest.NewRecorder()
+	srv.router.ServeHTTP(rr, req)
+	return rr
+}
+
+func (srv *testServer) uploadCSV(t *testing.T, token, url, body string) models.CSVImportResult {
+	t.Helper()
+
+	rr := srv.makeRawRequest(token, "POST", url, "text/csv", []byte(body))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	var result models.CSVImportResult
+	json.Unmarshal(rr.Body.Bytes(), &result)
+	return result
+}
+
+func TestHabitEntriesCSV(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	token, _ := srv.registerUser(t, "csv-habit-user")
+	h := srv.createReminderHabit(t, token, "CSV Habit", models.Due{Type: models.INTERVAL, IntervalType: "day", IntervalValue: 1})
+	url := fmt.Sprintf("/api/habit-trackers/%d/entries.csv", h.ID)
+
+	file := "date,value,done,note\n" +
+		"2024-03-01T08:00:00Z,,true,morning run\n" +
+		"2024-03-02,,,\n" +
+		"2024-03-03T08:00:00+02:00,,false,\"skipped, raining\"\n" +
+		"03/04/2024,,true,\n" +
+		"2024-03-05,,maybe,\n"
+
+	result := srv.uploadCSV(t, token, url, file)
+	if result.Created != 3 || result.Duplicates != 0 {
+		t.Errorf("Expected 3 created entries, got %+v", result)
+	}
+	if len(result.Errors) != 2 || result.Errors[0].Line != 5 || result.Errors[1].Line != 6 {
+		t.Errorf("Expected errors on lines 5 and 6, got %+v", result.Errors)
+	}
+
+	// Uploading the same file again does not create duplicates
+	result = srv.uploadCSV(t, token, url, file)
+	if result.Created != 0 || result.Duplicates != 3 || len(result.Errors) != 2 {
+		t.Errorf("Expected only duplicates on second upload, got %+v", result)
+	}
+
+	rr, _ := srv.makeRequestWithToken(token, "GET", url, nil)
+	exported := rr.Body.String()
+	if rr.Code != http.StatusOK || !strings.HasPrefix(rr.Header().Get("Content-Type"), "text/csv") {
+		t.Fatalf("Expected CSV response, got status %d and type '%s'", rr.Code, rr.Header().Get("Content-Type"))
+	}
+
+	records, err := csv.NewReader(strings.NewReader(exported)).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 4 || strings.Join(records[0], ",") != "date,value,done,note,skipped,duration" {
+		t.Fatalf("Expected header and 3 rows, got %v", records)
+	}
+	if records[1][0] != "2024-03-01T08:00:00Z" || records[1][2] != "true" || records[1][3] != "morning run" {
+		t.Errorf("Unexpected first row %v", records[1])
+	}
+	if records[3][0] != "2024-03-03T06:00:00Z" || records[3][2] != "false" || records[3][3] != "skipped, raining" {
+		t.Errorf("Unexpected last row %v", records[3])
+	}
+
+	// The exported file can be uploaded again without changes
+	result = srv.uploadCSV(t, token, url, exported)
+	if result.Created != 0 || result.Duplicates != 3 {
+		t.Errorf("Expected exported rows to be recognised as duplicates, got %+v", result)
+	}
+}
+
+func TestEntriesCSVRoundTrip(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	daily := models.Due{Type: models.INTERVAL, IntervalType: "day", IntervalValue: 1}
+	source := srv.createReminderHabit(t, srv.token, "Source Habit", daily)
+	copied := srv.createReminderHabit(t, srv.token, "Copied Habit", daily)
+
+	done, missed, skipped := true, false, true
+	srv.addEntry(t, models.HABIT, source.ID, models.AddEntryRequest{Date: "2024-04-01", Done: &done, Note: "done"})
+	srv.addEntry(t, models.HABIT, source.ID, models.AddEntryRequest{Date: "2024-04-02", Done: &missed})
+	srv.addEntry(t, models.HABIT, source.ID, models.AddEntryRequest{Date: "2024-04-03", Skipped: &skipped, Note: "sick"})
+
+	export := func(id int) string {
+		rr, _ := srv.makeRequest("GET", fmt.Sprintf("/api/habit-trackers/%d/entries.csv", id), nil)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, rr.Code, rr.Body.String())
+		}
+		return rr.Body.String()
+	}
+
+	exported := export(source.ID)
+	records, _ := csv.NewReader(strings.NewReader(exported)).ReadAll()
+	if len(records) != 4 || records[2][2] != "false" || records[2][4] != "false" || records[3][2] != "false" || records[3][4] != "true" {
+		t.Fatalf("Expected missed and skipped days to be told apart, got %v", records)
+	}
+
+	// Importing the file into another tracker recreates the same entries
+	result := srv.uploadCSV(t, srv.token, fmt.Sprintf("/api/habit-trackers/%d/entries.csv", copied.ID), exported)
+	if result.Created != 3 || len(result.Errors) != 0 {
+		t.Fatalf("Expected 3 created entries, got %+v", result)
+	}
+	if reexported := export(copied.ID); reexported != exported {
+		t.Errorf("Expected the round trip to keep the entries, got\n%s\ninstead of\n%s", reexported, exported)
+	}
+
+	// Skipped days cannot be done, and only habit days can be skipped
+	result = srv.uploadCSV(t, srv.token, fmt.Sprintf("/api/habit-trackers/%d/entries.csv", copied.ID), "date,done,skipped\n2024-04-05,true,true\n2024-04-06,,maybe\n")
+	if result.Created != 0 || len(result.Errors) != 2 {
+		t.Errorf("Expected both rows to be rejected, got %+v", result)
+	}
+}
+
+func TestDurationEntriesCSV(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	practice := srv.createDuration(t, "Practice", 1800, models.PER_DAY, "2024-05-01")
+	url := fmt.Sprintf("/api/duration-trackers/%d/entries.csv", practice.ID)
+
+	file := "date,duration,note\n" +
+		"2024-05-01T18:00:00Z,1200,scales\n" +
+		"2024-05-02,600,\n" +
+		"2024-05-03,,forgot\n" +
+		"2024-05-04,-60,\n"
+	result := srv.uploadCSV(t, srv.token, url, file)
+	if result.Created != 2 || len(result.Errors) != 2 || result.Errors[0].Line != 4 || result.Errors[1].Line != 5 {
+		t.Fatalf("Expected 2 entries and errors on lines 4 and 5, got %+v", result)
+	}
+
+	rr, _ := srv.makeRequest("GET", url, nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	records, _ := csv.NewReader(strings.NewReader(rr.Body.String())).ReadAll()
+	if len(records) != 3 || records[1][5] != "1200" || records[1][3] != "scales" || records[2][5] != "600" || records[1][4] != "" {
+		t.Errorf("Unexpected export %v", records)
+	}
+
+	// The export is recognised when uploaded again
+	result = srv.uploadCSV(t, srv.token, url, rr.Body.String())
+	if result.Created != 0 || result.Duplicates != 2 {
+		t.Errorf("Expected exported rows to be duplicates, got %+v", result)
+	}
+}
+
+func TestTargetEntriesCSV(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	token, _ := srv.registerUser(t, "csv-target-user")
+
+	rr, _ := srv.makeRequestWithToken(token, "POST", "/api/target-trackers", target.CreateTargetRequest{
+		TrackerName: "CSV Target",
+		StartValue:  80,
+		GoalValue:   70,
+		StartDate:   "2024-01-01",
+		GoalDate:    "2024-12-31",
+		Due:         models.Due{Type: models.INTERVAL, IntervalType: "day", IntervalValue: 1},
+	})
+	var tg target.TargetTracker
+	json.Unmarshal(rr.Body.Bytes(), &tg)
+	url := fmt.Sprintf("/api/target-trackers/%d/entries.csv", tg.ID)
+
+	// Without a header row the export column order is used
+	result := srv.uploadCSV(t, token, url, "2024-02-01T07:00:00Z,79.5\n2024-02-02T07:00:00Z,,,forgot to weigh\n2024-02-03T07:00:00Z,abc\n")
+	if result.Created != 1 || len(result.Errors) != 2 {
+		t.Fatalf("Expected 1 entry and 2 errors, got %+v", result)
+	}
+	if result.Errors[0].Line != 2 || result.Errors[1].Line != 3 {
+		t.Errorf("Expected errors on lines 2 and 3, got %+v", result.Errors)
+	}
+
+	// Browsers upload files as multipart forms
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, _ := form.CreateFormFile("file", "weights.csv")
+	part.Write([]byte("note,date,value\nafter holiday,2024-02-10T07:00:00Z,78.2\n"))
+	form.Close()
+
+	rr = srv.makeRawRequest(token, "POST", url, form.FormDataContentType(), body.Bytes())
+	json.Unmarshal(rr.Body.Bytes(), &result)
+	if rr.Code != http.StatusOK || result.Created != 1 {
+		t.Errorf("Expected multipart upload to create 1 entry, got status %d and %+v", rr.Code, result)
+	}
+
+	rr, _ = srv.makeRequestWithToken(token, "GET", fmt.Sprintf("/api/target-trackers/%d", tg.ID), nil)
+	json.Unmarshal(rr.Body.Bytes(), &tg)
+	if tg.CurrentValue == nil || *tg.CurrentValue != 78.2 {
+		t.Errorf("Expected current value 78.2 after import, got %v", tg.CurrentValue)
+	}
+
+	result = srv.uploadCSV(t, token, url, "date,value,skipped\n2024-02-11T07:00:00Z,78,true\n")
+	if result.Created != 0 || len(result.Errors) != 1 {
+		t.Errorf("Expected skipped target entries to be rejected, got %+v", result)
+	}
+
+	// Other users cannot read or write the tracker's entries
+	rr, _ = srv.makeRequest("GET", url, nil)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d for another user's tracker, got %d", http.StatusNotFound, rr.Code)
+	}
+	rr = srv.makeRawRequest(srv.token, "POST", url, "text/csv", []byte("2024-02-01,1\n"))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d for another user's tracker, got %d", http.StatusNotFound, rr.Code)
+	}
+}
@@ -14,7 +14,9 @@ import (
 )
 
 func TestDashboardSpecificDays(t *testing.T) {
-	today := time.Now()
+	t.Parallel()
+	srv := newTestServer(t)
+	today := time.Now().UTC()
 	todayWeekday := strings.ToLower(today.Weekday().String())
 
 	// Create a habit that should appear today
@@ -22,7 +24,7 @@ func TestDashboardSpecificDays(t *testing.T) {
 		TrackerName: "Dashboard Test Habit",
 		Goal:        1,
 		TimePeriod:  "perDay",
-		StartDate:   time.Now().AddDate(0, 0, -1).Format("2006-01-02"), // Started yesterday
+		StartDate:   time.Now().UTC().AddDate(0, 0, -1).Format("2006-01-02"), // Started yesterday
 		Due: models.Due{
 			Type:         "specificDays",
 			SpecificDays: []string{todayWeekday}, // Should appear today
@@ -39,8 +41,8 @@ func TestDashboardSpecificDays(t *testing.T) {
 		TrackerName: "Dashboard Test Target",
 		StartValue:  0,
 		GoalValue:   100,
-		StartDate:   time.Now().AddDate(0, 0, -1).Format("2006-01-02"),
-		GoalDate:    time.Now().AddDate(0, 1, 0).Format("2006-01-02"),
+		StartDate:   time.Now().UTC().AddDate(0, 0, -1).Format("2006-01-02"),
+		GoalDate:    time.Now().UTC().AddDate(0, 1, 0).Format("2006-01-02"),
 		AddToTotal:  true,
 		Due: models.Due{
 			Type:         "specificDays",
@@ -49,11 +51,11 @@ func TestDashboardSpecificDays(t *testing.T) {
 	}
 
 	// Create both trackers
-	makeRequest("POST", "/api/habit-trackers", habitRequest)
-	makeRequest("POST", "/api/target-trackers", targetRequest)
+	srv.makeRequest("POST", "/api/habit-trackers", habitRequest)
+	srv.makeRequest("POST", "/api/target-trackers", targetRequest)
 
 	// Get dashboard
-	dashboardRr, err := makeRequest("GET", "/api/dashboard", nil)
+	dashboardRr, err := srv.makeRequest("GET", "/api/dashboard", nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -99,12 +101,14 @@ func TestDashboardSpecificDays(t *testing.T) {
 }
 
 func TestDashboardInterval(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
 	// Create a habit with interval: every 1 day (should appear today)
 	habitRequest := habit.CreateHabitRequest{
 		TrackerName: "Interval Test Habit",
 		Goal:        1,
 		TimePeriod:  "perDay",
-		StartDate:   time.Now().Format("2006-01-02"), // Started today
+		StartDate:   time.Now().UTC().Format("2006-01-02"), // Started today
 		Due: models.Due{
 			Type:          "interval",
 			IntervalType:  "day",
@@ -117,8 +121,8 @@ func TestDashboardInterval(t *testing.T) {
 		TrackerName: "Interval Test Target",
 		StartValue:  0,
 		GoalValue:   50,
-		StartDate:   time.Now().AddDate(0, 0, -7).Format("2006-01-02"), // Started 7 days ago
-		GoalDate:    time.Now().AddDate(0, 1, 0).Format("2006-01-02"),
+		StartDate:   time.Now().UTC().AddDate(0, 0, -7).Format("2006-01-02"), // Started 7 days ago
+		GoalDate:    time.Now().UTC().AddDate(0, 1, 0).Format("2006-01-02"),
 		AddToTotal:  true,
 		Due: models.Due{
 			Type:          "interval",
@@ -128,11 +132,11 @@ func TestDashboardInterval(t *testing.T) {
 	}
 
 	// Create both trackers
-	makeRequest("POST", "/api/habit-trackers", habitRequest)
-	makeRequest("POST", "/api/target-trackers", targetRequest)
+	srv.makeRequest("POST", "/api/habit-trackers", habitRequest)
+	srv.makeRequest("POST", "/api/target-trackers", targetRequest)
 
 	// Get dashboard
-	dashboardRr, err := makeRequest("GET", "/api/dashboard", nil)
+	dashboardRr, err := srv.makeRequest("GET", "/api/dashboard", nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -166,22 +170,24 @@ func TestDashboardInterval(t *testing.T) {
 }
 
 func TestDashboardFutureStartDate(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
 	// Create a habit that starts in the future (should NOT appear)
 	habitRequest := habit.CreateHabitRequest{
 		TrackerName: "Future Habit",
 		Goal:        1,
 		TimePeriod:  "perDay",
-		StartDate:   time.Now().AddDate(0, 0, 1).Format("2006-01-02"), // Starts tomorrow
+		StartDate:   time.Now().UTC().AddDate(0, 0, 1).Format("2006-01-02"), // Starts tomorrow
 		Due: models.Due{
 			Type:         "specificDays",
-			SpecificDays: []string{strings.ToLower(time.Now().Weekday().String())},
+			SpecificDays: []string{strings.ToLower(time.Now().UTC().Weekday().String())},
 		},
 	}
 
-	makeRequest("POST", "/api/habit-trackers", habitRequest)
+	srv.makeRequest("POST", "/api/habit-trackers", habitRequest)
 
 	// Get dashboard
-	dashboardRr, err := makeRequest("GET", "/api/dashboard", nil)
+	dashboardRr, err := srv.makeRequest("GET", "/api/dashboard", nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -199,6 +205,8 @@ func TestDashboardFutureStartDate(t *testing.T) {
 
 // TestDashboardWithDateParameter tests the dashboard with a specific date parameter
 func TestDashboardWithDateParameter(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
 	// Use a specific date for testing (e.g., a Monday)
 	testDate := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC) // Monday, January 15, 2024
 	testDateStr := testDate.Format("2006-01-02")
@@ -231,11 +239,11 @@ func TestDashboardWithDateParameter(t *testing.T) {
 	}
 
 	// Create both trackers
-	makeRequest("POST", "/api/habit-trackers", habitRequest)
-	makeRequest("POST", "/api/target-trackers", targetRequest)
+	srv.makeRequest("POST", "/api/habit-trackers", habitRequest)
+	srv.makeRequest("POST", "/api/target-trackers", targetRequest)
 
 	// Get dashboard for the specific date
-	dashboardRr, err := makeRequest("GET", "/api/dashboard?date="+testDateStr, nil)
+	dashboardRr, err := srv.makeRequest("GET", "/api/dashboard?date="+testDateStr, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -281,23 +289,25 @@ func TestDashboardWithDateParameter(t *testing.T) {
 
 // TestDashboardWithInvalidDateFormat tests error handling for invalid date formats
 func TestDashboardWithInvalidDateFormat(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
 	invalidDates := []string{
-		"2024/01/15",    // Wrong separator
-		"15-01-2024",    // Wrong order
-		"2024-1-15",     // Missing zero padding
-		"invalid-date",  // Completely invalid
-		"2024-13-01",    // Invalid month
-		"2024-01-32",    // Invalid day
+		"2024/01/15",   // Wrong separator
+		"15-01-2024",   // Wrong order
+		"2024-1-15",    // Missing zero padding
+		"invalid-date", // Completely invalid
+		"2024-13-01",   // Invalid month
+		"2024-01-32",   // Invalid day
 	}
 
 	for _, invalidDate := range invalidDates {
-		dashboardRr, err := makeRequest("GET", "/api/dashboard?date="+invalidDate, nil)
+		dashboardRr, err := srv.makeRequest("GET", "/api/dashboard?date="+invalidDate, nil)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		if dashboardRr.Code != http.StatusBadRequest {
-			t.Errorf("Expected status %d for invalid date %s, got %d", 
+			t.Errorf("Expected status %d for invalid date %s, got %d",
 				http.StatusBadRequest, invalidDate, dashboardRr.Code)
 		}
 
@@ -311,6 +321,8 @@ func TestDashboardWithInvalidDateFormat(t *testing.T) {
 
 // TestDashboardDateVsTodayComparison tests that different dates return different results
 func TestDashboardDateVsTodayComparison(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
 	// Create a habit that appears only on Sundays
 	habitRequest := habit.CreateHabitRequest{
 		TrackerName: "Sunday Only Habit",
@@ -323,11 +335,11 @@ func TestDashboardDateVsTodayComparison(t *testing.T) {
 		},
 	}
 
-	makeRequest("POST", "/api/habit-trackers", habitRequest)
+	srv.makeRequest("POST", "/api/habit-trackers", habitRequest)
 
 	// Test Sunday date (should appear)
 	sundayDate := "2024-01-14" // A Sunday
-	sundayRr, err := makeRequest("GET", "/api/dashboard?date="+sundayDate, nil)
+	sundayRr, err := srv.makeRequest("GET", "/api/dashboard?date="+sundayDate, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -337,7 +349,7 @@ func TestDashboardDateVsTodayComparison(t *testing.T) {
 
 	// Test Monday date (should NOT appear)
 	mondayDate := "2024-01-15" // A Monday
-	mondayRr, err := makeRequest("GET", "/api/dashboard?date="+mondayDate, nil)
+	mondayRr, err := srv.makeRequest("GET", "/api/dashboard?date="+mondayDate, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -372,6 +384,8 @@ func TestDashboardDateVsTodayComparison(t *testing.T) {
 
 // TestDashboardWithIntervalAndSpecificDate tests interval-based trackers with specific dates
 func TestDashboardWithIntervalAndSpecificDate(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
 	startDate := "2024-01-01" // Start date
 
 	// Create habit with 3-day interval
@@ -387,7 +401,7 @@ func TestDashboardWithIntervalAndSpecificDate(t *testing.T) {
 		},
 	}
 
-	makeRequest("POST", "/api/habit-trackers", habitRequest)
+	srv.makeRequest("POST", "/api/habit-trackers", habitRequest)
 
 	// Test dates that should match the interval
 	testCases := []struct {
@@ -403,7 +417,7 @@ func TestDashboardWithIntervalAndSpecificDate(t *testing.T) {
 	}
 
 	for _, tc := range testCases {
-		dashboardRr, err := makeRequest("GET", "/api/dashboard?date="+tc.date, nil)
+		dashboardRr, err := srv.makeRequest("GET", "/api/dashboard?date="+tc.date, nil)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -420,7 +434,7 @@ func TestDashboardWithIntervalAndSpecificDate(t *testing.T) {
 		}
 
 		if habitFound != tc.shouldAppear {
-			t.Errorf("For date %s (%s): expected habit found=%t, got %t", 
+			t.Errorf("For date %s (%s): expected habit found=%t, got %t",
 				tc.date, tc.description, tc.shouldAppear, habitFound)
 		}
 	}
@@ -428,11 +442,13 @@ func TestDashboardWithIntervalAndSpecificDate(t *testing.T) {
 
 // TestDashboardDefaultsToToday tests that dashboard without date parameter uses today
 func TestDashboardDefaultsToToday(t *testing.T) {
-	today := time.Now()
+	t.Parallel()
+	srv := newTestServer(t)
+	today := time.Now().UTC()
 	todayStr := today.Format("2006-01-02")
 
 	// Get dashboard without date parameter
-	dashboardRr, err := makeRequest("GET", "/api/dashboard", nil)
+	dashboardRr, err := srv.makeRequest("GET", "/api/dashboard", nil)
 	if err != nil {
 		t.Fatal(err)
 	}
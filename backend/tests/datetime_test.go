@@ -4,13 +4,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"routine-tracker/models"
+	"routine-tracker/trackers"
 	"routine-tracker/trackers/habit"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 )
 
 func TestAddEntryWithDatetime(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
 	// Create a habit tracker first
 	createRequest := habit.CreateHabitRequest{
 		TrackerName: "Test Datetime Habit",
@@ -27,7 +33,7 @@ func TestAddEntryWithDatetime(t *testing.T) {
 		},
 	}
 
-	rr, err := makeRequest("POST", "/api/habit-trackers", createRequest)
+	rr, err := srv.makeRequest("POST", "/api/habit-trackers", createRequest)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -48,7 +54,7 @@ func TestAddEntryWithDatetime(t *testing.T) {
 		Note: "Added with datetime",
 	}
 
-	entryRr, err := makeRequest("POST", fmt.Sprintf("/api/habit-trackers/%d/entries", createdHabit.ID), datetimeEntry)
+	entryRr, err := srv.makeRequest("POST", fmt.Sprintf("/api/habit-trackers/%d/entries", createdHabit.ID), datetimeEntry)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -74,6 +80,8 @@ func TestAddEntryWithDatetime(t *testing.T) {
 }
 
 func TestAddEntryWithDateOnly(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
 	// Create a habit tracker first
 	createRequest := habit.CreateHabitRequest{
 		TrackerName: "Test Date Only Habit",
@@ -90,7 +98,7 @@ func TestAddEntryWithDateOnly(t *testing.T) {
 		},
 	}
 
-	rr, err := makeRequest("POST", "/api/habit-trackers", createRequest)
+	rr, err := srv.makeRequest("POST", "/api/habit-trackers", createRequest)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -111,7 +119,7 @@ func TestAddEntryWithDateOnly(t *testing.T) {
 		Note: "Added with date only",
 	}
 
-	entryRr, err := makeRequest("POST", fmt.Sprintf("/api/habit-trackers/%d/entries", createdHabit.ID), dateEntry)
+	entryRr, err := srv.makeRequest("POST", fmt.Sprintf("/api/habit-trackers/%d/entries", createdHabit.ID), dateEntry)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -137,6 +145,8 @@ func TestAddEntryWithDateOnly(t *testing.T) {
 }
 
 func TestAddEntryWithInvalidDateTime(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
 	// Create a habit tracker first
 	createRequest := habit.CreateHabitRequest{
 		TrackerName: "Test Invalid DateTime Habit",
@@ -153,7 +163,7 @@ func TestAddEntryWithInvalidDateTime(t *testing.T) {
 		},
 	}
 
-	rr, err := makeRequest("POST", "/api/habit-trackers", createRequest)
+	rr, err := srv.makeRequest("POST", "/api/habit-trackers", createRequest)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -170,7 +180,7 @@ func TestAddEntryWithInvalidDateTime(t *testing.T) {
 		Note: "Should fail",
 	}
 
-	entryRr, err := makeRequest("POST", fmt.Sprintf("/api/habit-trackers/%d/entries", createdHabit.ID), invalidEntry)
+	entryRr, err := srv.makeRequest("POST", fmt.Sprintf("/api/habit-trackers/%d/entries", createdHabit.ID), invalidEntry)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -178,4 +188,248 @@ func TestAddEntryWithInvalidDateTime(t *testing.T) {
 	if entryRr.Code != http.StatusBadRequest {
 		t.Errorf("Expected status %d for invalid date, got %d. Body: %s", http.StatusBadRequest, entryRr.Code, entryRr.Body.String())
 	}
-}
\ No newline at end of file
+}
+func TestCreatedAtUsesClock(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	now := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	srv.app.Clock = fixedClock{now}
+
+	rr, err := srv.makeRequest("POST", "/api/habit-trackers", habit.CreateHabitRequest{
+		TrackerName: "Stretch",
+		Goal:        1,
+		TimePeriod:  "perDay",
+		StartDate:   "2024-03-01",
+		Due:         models.Due{Type: "specificDays", SpecificDays: []string{"friday"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var created habit.HabitTracker
+	json.Unmarshal(rr.Body.Bytes(), &created)
+
+	rr, _ = srv.makeRequest("GET", fmt.Sprintf("/api/habit-trackers/%d", created.ID), nil)
+	var stored habit.HabitTracker
+	json.Unmarshal(rr.Body.Bytes(), &stored)
+	if !stored.CreatedAt.Equal(now) {
+		t.Errorf("Expected createdAt %v from the clock, got %v", now, stored.CreatedAt)
+	}
+}
+
+// fixedClock always tells the same time
+type fixedClock struct {
+	now time.Time
+}
+
+func (c fixedClock) Now() time.Time {
+	return c.now
+}
+
+// Helper function to set the default user's time zone
+func (srv *testServer) setTimeZone(t *testing.T, timeZone string) {
+	t.Helper()
+
+	rr, _ := srv.makeRequest("PUT", "/api/auth/me", models.UpdateUserRequest{TimeZone: &timeZone})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d when setting time zone, got %d. Body: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+}
+
+// Helper function to create a habit starting on a given date
+func (srv *testServer) createDatedHabit(t *testing.T, name, startDate string, due models.Due) habit.HabitTracker {
+	t.Helper()
+
+	rr, _ := srv.makeRequest("POST", "/api/habit-trackers", habit.CreateHabitRequest{
+		TrackerName: name,
+		Goal:        1,
+		TimePeriod:  models.PER_DAY,
+		StartDate:   startDate,
+		Due:         due,
+	})
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusCreated, rr.Code, rr.Body.String())
+	}
+
+	var created habit.HabitTracker
+	json.Unmarshal(rr.Body.Bytes(), &created)
+	return created
+}
+
+// Helper function to request the dashboard, optionally in another time zone
+func (srv *testServer) dashboard(t *testing.T, timeZone string) (string, []string) {
+	t.Helper()
+
+	req, _ := http.NewRequest("GET", "/api/dashboard", nil)
+	req.Header.Set("Authorization", "Bearer "+srv.token)
+	if timeZone != "" {
+		req.Header.Set("X-Time-Zone", timeZone)
+	}
+	rr := httptest.NewRecorder()
+	srv.router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	var dashboard trackers.DashboardResponse
+	json.Unmarshal(rr.Body.Bytes(), &dashboard)
+	names := make([]string, 0)
+	for _, h := range dashboard.HabitTrackers {
+		names = append(names, h.TrackerName)
+	}
+	sort.Strings(names)
+	return dashboard.Date, names
+}
+
+func TestUserTimeZoneSetting(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+
+	rr, _ := srv.makeRequest("GET", "/api/auth/me", nil)
+	var user models.User
+	json.Unmarshal(rr.Body.Bytes(), &user)
+	if user.TimeZone != "UTC" {
+		t.Errorf("Expected new users to be in UTC, got '%s'", user.TimeZone)
+	}
+
+	srv.setTimeZone(t, "Pacific/Auckland")
+	rr, _ = srv.makeRequest("GET", "/api/auth/me", nil)
+	json.Unmarshal(rr.Body.Bytes(), &user)
+	if user.TimeZone != "Pacific/Auckland" {
+		t.Errorf("Expected time zone Pacific/Auckland, got '%s'", user.TimeZone)
+	}
+
+	for _, invalid := range []string{"", "Local", "Mars/Olympus_Mons"} {
+		rr, _ := srv.makeRequest("PUT", "/api/auth/me", models.UpdateUserRequest{TimeZone: &invalid})
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for time zone '%s', got %d", http.StatusBadRequest, invalid, rr.Code)
+		}
+	}
+
+	req, _ := http.NewRequest("GET", "/api/dashboard", nil)
+	req.Header.Set("Authorization", "Bearer "+srv.token)
+	req.Header.Set("X-Time-Zone", "Not/A_Zone")
+	rr = httptest.NewRecorder()
+	srv.router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for an invalid time zone header, got %d", http.StatusBadRequest, rr.Code)
+	}
+}
+
+func TestDashboardOnOtherSideOfUTC(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	srv.createDatedHabit(t, "Tuesday Habit", "2024-01-01", models.Due{Type: models.SPECIFIC_DAYS, SpecificDays: []string{"tuesday"}})
+	srv.createDatedHabit(t, "Monday Habit", "2024-01-01", models.Due{Type: models.SPECIFIC_DAYS, SpecificDays: []string{"monday"}})
+
+	// Monday evening in UTC is already Tuesday morning in Auckland (UTC+13)
+	// and still Monday noon in Los Angeles (UTC-8)
+	srv.app.Clock = fixedClock{time.Date(2024, 1, 15, 20, 0, 0, 0, time.UTC)}
+
+	date, names := srv.dashboard(t, "")
+	if date != "2024-01-15" || strings.Join(names, ",") != "Monday Habit" {
+		t.Errorf("Expected Monday Habit on 2024-01-15 in UTC, got %v on %s", names, date)
+	}
+
+	srv.setTimeZone(t, "Pacific/Auckland")
+	date, names = srv.dashboard(t, "")
+	if date != "2024-01-16" || strings.Join(names, ",") != "Tuesday Habit" {
+		t.Errorf("Expected Tuesday Habit on 2024-01-16 in Auckland, got %v on %s", names, date)
+	}
+
+	// The header overrides the user's time zone for a single request
+	date, names = srv.dashboard(t, "America/Los_Angeles")
+	if date != "2024-01-15" || strings.Join(names, ",") != "Monday Habit" {
+		t.Errorf("Expected Monday Habit on 2024-01-15 in Los Angeles, got %v on %s", names, date)
+	}
+}
+
+func TestDateOnlyEntryInUserTimeZone(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	srv.setTimeZone(t, "Pacific/Auckland")
+	h := srv.createDatedHabit(t, "Auckland Habit", "2024-01-15", models.Due{Type: models.INTERVAL, IntervalType: "day", IntervalValue: 1})
+
+	rr, _ := srv.makeRequest("POST", fmt.Sprintf("/api/habit-trackers/%d/entries", h.ID), models.AddEntryRequest{Date: "2024-01-15"})
+	var entry models.Entry
+	json.Unmarshal(rr.Body.Bytes(), &entry)
+
+	// Midnight in Auckland is still the previous day in UTC
+	expected := time.Date(2024, 1, 14, 11, 0, 0, 0, time.UTC)
+	if !entry.Date.Equal(expected) {
+		t.Errorf("Expected date %s, got %s", expected.Format(time.RFC3339), entry.Date.Format(time.RFC3339))
+	}
+
+	// The entry belongs to the tracker's first day
+	rr, _ = srv.makeRequest("GET", fmt.Sprintf("/api/habit-trackers/%d/entries", h.ID), nil)
+	var entries []models.Entry
+	json.Unmarshal(rr.Body.Bytes(), &entries)
+	if len(entries) != 1 {
+		t.Fatalf("Expected the entry on the start date to be listed, got %d entries", len(entries))
+	}
+
+	rr, _ = srv.makeRequest("GET", fmt.Sprintf("/api/habit-trackers/%d/stats?date=2024-01-15", h.ID), nil)
+	var stats habit.Stats
+	json.Unmarshal(rr.Body.Bytes(), &stats)
+	if len(stats.Periods) != 1 || stats.Periods[0].Count != 1 || stats.CurrentStreak != 1 {
+		t.Errorf("Expected one completed day, got %+v", stats)
+	}
+
+	// Moving the entry to another day uses the time zone as well
+	date := "2024-01-16"
+	rr, _ = srv.makeRequest("PUT", fmt.Sprintf("/api/entries/%d", entry.ID), models.UpdateEntryRequest{Date: &date})
+	json.Unmarshal(rr.Body.Bytes(), &entry)
+	if expected = expected.AddDate(0, 0, 1); rr.Code != http.StatusOK || !entry.Date.Equal(expected) {
+		t.Errorf("Expected the updated date %s, got %d. Body: %s", expected.Format(time.RFC3339), rr.Code, rr.Body.String())
+	}
+	invalid := "16/01/2024"
+	rr, _ = srv.makeRequest("PUT", fmt.Sprintf("/api/entries/%d", entry.ID), models.UpdateEntryRequest{Date: &invalid})
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for an invalid date, got %d", http.StatusBadRequest, rr.Code)
+	}
+}
+
+func TestEntryDaysAcrossDSTTransitions(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+
+	// Clocks in New York jump from 02:00 to 03:00 on 2024-03-10
+	srv.setTimeZone(t, "America/New_York")
+	h := srv.createDatedHabit(t, "Spring Forward", "2024-03-09", models.Due{Type: models.INTERVAL, IntervalType: "day", IntervalValue: 1})
+	url := fmt.Sprintf("/api/habit-trackers/%d/entries", h.ID)
+
+	for _, date := range []string{"2024-03-10", "2024-03-10T23:30:00-04:00", "2024-03-11"} {
+		rr, _ := srv.makeRequest("POST", url, models.AddEntryRequest{Date: date})
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("Expected status %d for %s, got %d. Body: %s", http.StatusCreated, date, rr.Code, rr.Body.String())
+		}
+	}
+
+	rr, _ := srv.makeRequest("GET", fmt.Sprintf("/api/habit-trackers/%d/stats?date=2024-03-11", h.ID), nil)
+	var stats habit.Stats
+	json.Unmarshal(rr.Body.Bytes(), &stats)
+
+	counts := make([]int, 0)
+	for _, period := range stats.Periods {
+		counts = append(counts, period.Count)
+	}
+	if fmt.Sprint(counts) != "[0 2 1]" {
+		t.Errorf("Expected 0, 2 and 1 entries on March 9th to 11th, got %v", counts)
+	}
+
+	// Clocks in Berlin fall back from 03:00 to 02:00 on 2024-10-27, the day
+	// still counts as a single day for interval trackers
+	srv.setTimeZone(t, "Europe/Berlin")
+	srv.createDatedHabit(t, "Every Other Day", "2024-10-25", models.Due{Type: models.INTERVAL, IntervalType: "day", IntervalValue: 2})
+
+	srv.app.Clock = fixedClock{time.Date(2024, 10, 27, 22, 30, 0, 0, time.UTC)}
+	date, names := srv.dashboard(t, "")
+	if date != "2024-10-27" || strings.Join(names, ",") != "Every Other Day,Spring Forward" {
+		t.Errorf("Expected both habits on 2024-10-27 in Berlin, got %v on %s", names, date)
+	}
+
+	srv.app.Clock = fixedClock{time.Date(2024, 10, 27, 23, 30, 0, 0, time.UTC)}
+	date, names = srv.dashboard(t, "")
+	if date != "2024-10-28" || strings.Join(names, ",") != "Spring Forward" {
+		t.Errorf("Expected only the daily habit on 2024-10-28 in Berlin, got %v on %s", names, date)
+	}
+}
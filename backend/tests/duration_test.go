@@ -0,0 +1,317 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"routine-tracker/models"
+	"routine-tracker/trackers"
+	"routine-tracker/trackers/duration"
+)
+
+// Helper function to create a duration tracker with a goal in seconds
+func (srv *testServer) createDuration(t *testing.T, name string, goal int, timePeriod models.TimePeriod, startDate string) duration.DurationTracker {
+	t.Helper()
+
+	rr, _ := srv.makeRequest("POST", "/api/duration-trackers", duration.CreateDurationRequest{
+		TrackerName: name,
+		Goal:        goal,
+		TimePeriod:  timePeriod,
+		StartDate:   startDate,
+		Due:         models.Due{Type: models.INTERVAL, IntervalType: "day", IntervalValue: 1},
+	})
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusCreated, rr.Code, rr.Body.String())
+	}
+
+	var created duration.DurationTracker
+	json.Unmarshal(rr.Body.Bytes(), &created)
+	return created
+}
+
+func (srv *testServer) durationTracker(t *testing.T, id int) duration.DurationTracker {
+	t.Helper()
+
+	rr, _ := srv.makeRequest("GET", fmt.Sprintf("/api/duration-trackers/%d", id), nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	var tracker duration.DurationTracker
+	json.Unmarshal(rr.Body.Bytes(), &tracker)
+	return tracker
+}
+
+func TestDurationTimer(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	start := time.Date(2024, 6, 3, 10, 0, 0, 0, time.UTC)
+	srv.app.Clock = fixedClock{start}
+	guitar := srv.createDuration(t, "Guitar", 1800, models.PER_DAY, "2024-06-01")
+	reading := srv.createDuration(t, "Reading", 3600, models.PER_WEEK, "2024-06-01")
+
+	rr, _ := srv.makeRequest("POST", fmt.Sprintf("/api/duration-trackers/%d/timer/start", guitar.ID), duration.StartTimerRequest{Note: "Scales"})
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusCreated, rr.Code, rr.Body.String())
+	}
+	var timer duration.Timer
+	json.Unmarshal(rr.Body.Bytes(), &timer)
+	if timer.TrackerID != guitar.ID || !timer.StartedAt.Equal(start) {
+		t.Errorf("Expected a timer of Guitar started at %s, got %+v", start, timer)
+	}
+	if running := srv.durationTracker(t, guitar.ID).Timer; running == nil || running.ID != timer.ID {
+		t.Errorf("Expected Guitar to show its running timer, got %+v", running)
+	}
+	events := srv.auditEvents(t, srv.token, fmt.Sprintf("/api/audit?entity=timer&id=%d", timer.ID))
+	if len(events) != 1 || events[0].Action != models.AUDIT_CREATE || events[0].Changes["note"].After != "Scales" {
+		t.Errorf("Expected the started timer in the audit log, got %+v", events)
+	}
+
+	// Only one timer runs at a time
+	for _, id := range []int{guitar.ID, reading.ID} {
+		rr, _ = srv.makeRequest("POST", fmt.Sprintf("/api/duration-trackers/%d/timer/start", id), nil)
+		if rr.Code != http.StatusConflict {
+			t.Errorf("Expected status %d for a second timer, got %d", http.StatusConflict, rr.Code)
+		}
+	}
+	rr, _ = srv.makeRequest("POST", fmt.Sprintf("/api/duration-trackers/%d/timer/stop", reading.ID), nil)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d for a tracker without a timer, got %d", http.StatusNotFound, rr.Code)
+	}
+
+	// Stopping the timer logs the time since its start
+	srv.app.Clock = fixedClock{start.Add(20 * time.Minute)}
+	rr, _ = srv.makeRequest("POST", fmt.Sprintf("/api/duration-trackers/%d/timer/stop", guitar.ID), nil)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusCreated, rr.Code, rr.Body.String())
+	}
+	var entry models.Entry
+	json.Unmarshal(rr.Body.Bytes(), &entry)
+	if entry.Type != models.DURATION || entry.Duration == nil || *entry.Duration != 1200 || !entry.Date.Equal(start) || entry.Note != "Scales" {
+		t.Errorf("Expected a 1200 second entry dated at the start, got %+v", entry)
+	}
+	tracker := srv.durationTracker(t, guitar.ID)
+	if tracker.Timer != nil || tracker.CurrentTotal == nil || *tracker.CurrentTotal != 1200 {
+		t.Errorf("Expected a stopped timer and 1200 seconds today, got %+v", tracker)
+	}
+	rr, _ = srv.makeRequest("GET", "/api/entries", nil)
+	var stored []models.Entry
+	json.Unmarshal(rr.Body.Bytes(), &stored)
+	if len(stored) != 1 || !stored[0].CreatedAt.Equal(start.Add(20*time.Minute)) {
+		t.Errorf("Expected the entry to be created when the timer stopped, got %+v", stored)
+	}
+
+	// Archived trackers cannot be timed
+	srv.makeRequest("POST", fmt.Sprintf("/api/duration-trackers/%d/archive", guitar.ID), nil)
+	rr, _ = srv.makeRequest("POST", fmt.Sprintf("/api/duration-trackers/%d/timer/start", guitar.ID), nil)
+	if rr.Code != http.StatusConflict {
+		t.Errorf("Expected status %d for an archived tracker, got %d", http.StatusConflict, rr.Code)
+	}
+	srv.makeRequest("POST", fmt.Sprintf("/api/duration-trackers/%d/unarchive", guitar.ID), nil)
+
+	// Another timer can start once the first one stopped
+	rr, _ = srv.makeRequest("POST", fmt.Sprintf("/api/duration-trackers/%d/timer/start", reading.ID), nil)
+	if rr.Code != http.StatusCreated {
+		t.Errorf("Expected status %d, got %d. Body: %s", http.StatusCreated, rr.Code, rr.Body.String())
+	}
+
+	// Deleting a tracker discards its running timer
+	srv.makeRequest("DELETE", fmt.Sprintf("/api/duration-trackers/%d", reading.ID), nil)
+	rr, _ = srv.makeRequest("POST", fmt.Sprintf("/api/duration-trackers/%d/timer/start", guitar.ID), nil)
+	if rr.Code != http.StatusCreated {
+		t.Errorf("Expected status %d after deleting the timed tracker, got %d. Body: %s", http.StatusCreated, rr.Code, rr.Body.String())
+	}
+	rr, _ = srv.makeRequest("POST", fmt.Sprintf("/api/trash/duration-trackers/%d/restore", reading.ID), nil)
+	if rr.Code != http.StatusOK || srv.durationTracker(t, reading.ID).Timer != nil {
+		t.Errorf("Expected Reading to be restored without a timer, got %d. Body: %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestDurationEntryOverlaps(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	start := time.Date(2024, 6, 3, 10, 0, 0, 0, time.UTC)
+	srv.app.Clock = fixedClock{start}
+	guitar := srv.createDuration(t, "Guitar", 7200, models.PER_DAY, "2024-06-01")
+	piano := srv.createDuration(t, "Piano", 7200, models.PER_DAY, "2024-06-01")
+	srv.makeRequest("POST", fmt.Sprintf("/api/duration-trackers/%d/timer/start", guitar.ID), nil)
+
+	// Time the running timer will log cannot be added by hand as well
+	seconds := 3600
+	rr, _ := srv.makeRequest("POST", fmt.Sprintf("/api/duration-trackers/%d/entries", guitar.ID), models.AddEntryRequest{Duration: &seconds, Date: "2024-06-03T09:30:00Z"})
+	if rr.Code != http.StatusConflict {
+		t.Errorf("Expected status %d for an entry overlapping the timer, got %d", http.StatusConflict, rr.Code)
+	}
+	srv.addEntry(t, models.DURATION, piano.ID, models.AddEntryRequest{Duration: &seconds, Date: "2024-06-03T09:30:00Z"})
+
+	// Manual entries are not checked against each other and both count
+	seconds = 1800
+	srv.addEntry(t, models.DURATION, guitar.ID, models.AddEntryRequest{Duration: &seconds, Date: "2024-06-03T08:00:00Z"})
+	srv.addEntry(t, models.DURATION, guitar.ID, models.AddEntryRequest{Duration: &seconds, Date: "2024-06-03T08:00:00Z"})
+	if tracker := srv.durationTracker(t, guitar.ID); tracker.CurrentTotal == nil || *tracker.CurrentTotal != 3600 {
+		t.Errorf("Expected both overlapping entries to count, got %+v", tracker.CurrentTotal)
+	}
+}
+
+func TestDurationEntriesAndStats(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	srv.app.Clock = fixedClock{time.Date(2024, 6, 12, 10, 0, 0, 0, time.UTC)}
+	practice := srv.createDuration(t, "Practice", 3600, models.PER_WEEK, "2024-06-03")
+
+	// Weeks start on Monday: 2024-06-03, 2024-06-10
+	for _, e := range []struct {
+		date    string
+		seconds int
+	}{{"2024-06-03", 1800}, {"2024-06-09", 2400}, {"2024-06-11", 600}} {
+		seconds := e.seconds
+		rr, _ := srv.makeRequest("POST", fmt.Sprintf("/api/duration-trackers/%d/entries", practice.ID), models.AddEntryRequest{Date: e.date, Duration: &seconds})
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusCreated, rr.Code, rr.Body.String())
+		}
+	}
+
+	rr, _ := srv.makeRequest("GET", fmt.Sprintf("/api/duration-trackers/%d/stats", practice.ID), nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	var stats duration.Stats
+	json.Unmarshal(rr.Body.Bytes(), &stats)
+	if len(stats.Periods) != 2 || stats.Periods[0].Total != 4200 || !stats.Periods[0].GoalMet || stats.Periods[1].Total != 600 || !stats.Periods[1].IsCurrent {
+		t.Errorf("Expected a met week of 4200 seconds and a current one of 600, got %+v", stats.Periods)
+	}
+	if stats.TotalDuration != 4800 || stats.CurrentTotal != 600 || stats.CompletedPeriods != 1 || stats.TotalPeriods != 1 || stats.CompletionRate != 1 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+
+	// Entries of a duration tracker are listed with their durations
+	rr, _ = srv.makeRequest("GET", fmt.Sprintf("/api/duration-trackers/%d/entries", practice.ID), nil)
+	var entries []models.Entry
+	json.Unmarshal(rr.Body.Bytes(), &entries)
+	if len(entries) != 3 || entries[0].Duration == nil {
+		t.Fatalf("Expected 3 entries with durations, got %+v", entries)
+	}
+
+	// The duration of an entry can be corrected, but not set on other types
+	corrected := 900
+	rr, _ = srv.makeRequest("PUT", fmt.Sprintf("/api/entries/%d", entries[0].ID), models.UpdateEntryRequest{Duration: &corrected})
+	var updated models.Entry
+	json.Unmarshal(rr.Body.Bytes(), &updated)
+	if rr.Code != http.StatusOK || updated.Duration == nil || *updated.Duration != 900 {
+		t.Errorf("Expected the duration to be updated, got %d. Body: %s", rr.Code, rr.Body.String())
+	}
+	daily := models.Due{Type: models.INTERVAL, IntervalType: "day", IntervalValue: 1}
+	habit := srv.createDatedHabit(t, "Read", "2024-06-01", daily)
+	rr, _ = srv.makeRequest("POST", fmt.Sprintf("/api/habit-trackers/%d/entries", habit.ID), models.AddEntryRequest{})
+	var habitEntry models.Entry
+	json.Unmarshal(rr.Body.Bytes(), &habitEntry)
+	rr, _ = srv.makeRequest("PUT", fmt.Sprintf("/api/entries/%d", habitEntry.ID), models.UpdateEntryRequest{Duration: &corrected})
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for the duration of a habit entry, got %d", http.StatusBadRequest, rr.Code)
+	}
+
+	negative := -60
+	for name, request := range map[string]struct {
+		url  string
+		body interface{}
+	}{
+		"no goal":            {"/api/duration-trackers", duration.CreateDurationRequest{TrackerName: "Idle", TimePeriod: models.PER_DAY, StartDate: "2024-06-01"}},
+		"invalid period":     {"/api/duration-trackers", duration.CreateDurationRequest{TrackerName: "Idle", Goal: 60, TimePeriod: "perHour", StartDate: "2024-06-01"}},
+		"entry without time": {fmt.Sprintf("/api/duration-trackers/%d/entries", practice.ID), models.AddEntryRequest{}},
+		"negative duration":  {fmt.Sprintf("/api/duration-trackers/%d/entries", practice.ID), models.AddEntryRequest{Duration: &negative}},
+	} {
+		rr, _ := srv.makeRequest("POST", request.url, request.body)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %s, got %d", http.StatusBadRequest, name, rr.Code)
+		}
+	}
+}
+
+func TestDurationTrackersOnDashboard(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	srv.app.Clock = fixedClock{time.Date(2024, 6, 3, 10, 0, 0, 0, time.UTC)}
+	daily := models.Due{Type: models.INTERVAL, IntervalType: "day", IntervalValue: 1}
+	read := srv.createDatedHabit(t, "Read", "2024-06-01", daily)
+	practice := srv.createDuration(t, "Practice", 1800, models.PER_DAY, "2024-06-01")
+	seconds := 600
+	srv.makeRequest("POST", fmt.Sprintf("/api/duration-trackers/%d/entries", practice.ID), models.AddEntryRequest{Duration: &seconds})
+
+	rr, _ := srv.makeRequest("GET", "/api/dashboard", nil)
+	var dashboard trackers.DashboardResponse
+	json.Unmarshal(rr.Body.Bytes(), &dashboard)
+	if len(dashboard.DurationTrackers) != 1 || dashboard.DurationTrackers[0].CurrentTotal == nil || *dashboard.DurationTrackers[0].CurrentTotal != 600 {
+		t.Errorf("Expected Practice on the dashboard with 600 seconds today, got %+v", dashboard.DurationTrackers)
+	}
+
+	// Duration trackers are interleaved with the others in the user-defined order
+	srv.makeRequest("PUT", "/api/trackers/order", models.UpdateOrderRequest{Trackers: []models.TrackerPosition{
+		{Type: models.DURATION, ID: practice.ID, Position: 0},
+		{Type: models.HABIT, ID: read.ID, Position: 1},
+	}})
+	want := "[duration:Practice habit:Read]"
+	if got := fmt.Sprint(orderNames(srv.trackers(t, ""))); got != want {
+		t.Errorf("Expected order %s, got %s", want, got)
+	}
+
+	rr, _ = srv.makeRequest("GET", "/api/agenda?from=2024-06-03&to=2024-06-04", nil)
+	var agenda trackers.AgendaResponse
+	json.Unmarshal(rr.Body.Bytes(), &agenda)
+	if len(agenda.Days) != 2 || len(agenda.Days[1].DurationTrackers) != 1 {
+		t.Errorf("Expected Practice to be due on every day of the agenda, got %+v", agenda.Days)
+	}
+
+	// Days off are left out like for the other types
+	durationType := models.DURATION
+	rr, _ = srv.makeRequest("POST", "/api/pauses", models.CreatePauseRequest{
+		TrackerType: &durationType, TrackerID: &practice.ID, StartDate: "2024-06-03",
+	})
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusCreated, rr.Code, rr.Body.String())
+	}
+	rr, _ = srv.makeRequest("GET", "/api/dashboard", nil)
+	json.Unmarshal(rr.Body.Bytes(), &dashboard)
+	if len(dashboard.DurationTrackers) != 0 {
+		t.Errorf("Expected the paused tracker to be left out, got %+v", dashboard.DurationTrackers)
+	}
+}
+
+func TestDurationReminders(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	token, userID := srv.registerUser(t, "duration-reminder-user")
+
+	now := time.Now().UTC()
+	now = time.Date(now.Year(), now.Month(), now.Day(), 12, 0, 0, 0, time.UTC)
+	create := func(name string) duration.DurationTracker {
+		rr, _ := srv.makeRequestWithToken(token, "POST", "/api/duration-trackers", duration.CreateDurationRequest{
+			TrackerName: name,
+			Goal:        1800,
+			TimePeriod:  models.PER_DAY,
+			StartDate:   now.AddDate(0, 0, -7).Format("2006-01-02"),
+			Due:         models.Due{Type: models.INTERVAL, IntervalType: "day", IntervalValue: 1},
+			Reminders:   models.Reminder{Enabled: true, Times: []string{"11:30"}},
+		})
+		var created duration.DurationTracker
+		json.Unmarshal(rr.Body.Bytes(), &created)
+		return created
+	}
+
+	behind, met := create("Behind"), create("Met")
+	partly, fully := 600, 1800
+	srv.makeRequestWithToken(token, "POST", fmt.Sprintf("/api/duration-trackers/%d/entries", behind.ID), models.AddEntryRequest{Duration: &partly})
+	srv.makeRequestWithToken(token, "POST", fmt.Sprintf("/api/duration-trackers/%d/entries", met.ID), models.AddEntryRequest{Duration: &fully})
+
+	notifier := &recordingNotifier{userID: userID}
+	if err := srv.scheduler(notifier).Tick(context.Background(), now); err != nil {
+		t.Fatal(err)
+	}
+	sent := notifier.sent()
+	if len(sent) != 1 || sent[0].TrackerID != behind.ID || sent[0].TrackerType != models.DURATION {
+		t.Errorf("Expected a reminder for the tracker behind its goal only, got %+v", sent)
+	}
+}
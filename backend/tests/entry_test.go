@@ -13,6 +13,8 @@ import (
 )
 
 func TestGetEntriesForHabitTracker(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
 	// Create a habit tracker
 	habitRequest := habit.CreateHabitRequest{
 		TrackerName: "Daily Meditation",
@@ -25,7 +27,7 @@ func TestGetEntriesForHabitTracker(t *testing.T) {
 		},
 	}
 
-	createRr, err := makeRequest("POST", "/api/habit-trackers", habitRequest)
+	createRr, err := srv.makeRequest("POST", "/api/habit-trackers", habitRequest)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -41,14 +43,14 @@ func TestGetEntriesForHabitTracker(t *testing.T) {
 	}
 
 	for _, entryReq := range entries {
-		_, err := makeRequest("POST", fmt.Sprintf("/api/habit-trackers/%d/entries", createdHabit.ID), entryReq)
+		_, err := srv.makeRequest("POST", fmt.Sprintf("/api/habit-trackers/%d/entries", createdHabit.ID), entryReq)
 		if err != nil {
 			t.Fatal(err)
 		}
 	}
 
 	// Get entries for this tracker
-	entriesRr, err := makeRequest("GET", fmt.Sprintf("/api/habit-trackers/%d/entries", createdHabit.ID), nil)
+	entriesRr, err := srv.makeRequest("GET", fmt.Sprintf("/api/habit-trackers/%d/entries", createdHabit.ID), nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -81,6 +83,8 @@ func TestGetEntriesForHabitTracker(t *testing.T) {
 }
 
 func TestGetEntriesForTargetTracker(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
 	// Create a target tracker
 	targetRequest := target.CreateTargetRequest{
 		TrackerName: "Study Hours",
@@ -95,7 +99,7 @@ func TestGetEntriesForTargetTracker(t *testing.T) {
 		},
 	}
 
-	createRr, err := makeRequest("POST", "/api/target-trackers", targetRequest)
+	createRr, err := srv.makeRequest("POST", "/api/target-trackers", targetRequest)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -111,14 +115,14 @@ func TestGetEntriesForTargetTracker(t *testing.T) {
 	}
 
 	for _, entryReq := range entries {
-		_, err := makeRequest("POST", fmt.Sprintf("/api/target-trackers/%d/entries", createdTarget.ID), entryReq)
+		_, err := srv.makeRequest("POST", fmt.Sprintf("/api/target-trackers/%d/entries", createdTarget.ID), entryReq)
 		if err != nil {
 			t.Fatal(err)
 		}
 	}
 
 	// Get entries for this tracker
-	entriesRr, err := makeRequest("GET", fmt.Sprintf("/api/target-trackers/%d/entries", createdTarget.ID), nil)
+	entriesRr, err := srv.makeRequest("GET", fmt.Sprintf("/api/target-trackers/%d/entries", createdTarget.ID), nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -160,6 +164,8 @@ func TestGetEntriesForTargetTracker(t *testing.T) {
 }
 
 func TestGetAllEntries(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
 	// Create both habit and target trackers
 	habitRequest := habit.CreateHabitRequest{
 		TrackerName: "Test Habit",
@@ -180,8 +186,8 @@ func TestGetAllEntries(t *testing.T) {
 	}
 
 	// Create trackers
-	habitRr, _ := makeRequest("POST", "/api/habit-trackers", habitRequest)
-	targetRr, _ := makeRequest("POST", "/api/target-trackers", targetRequest)
+	habitRr, _ := srv.makeRequest("POST", "/api/habit-trackers", habitRequest)
+	targetRr, _ := srv.makeRequest("POST", "/api/target-trackers", targetRequest)
 
 	var createdHabit habit.HabitTracker
 	var createdTarget target.TargetTracker
@@ -192,11 +198,11 @@ func TestGetAllEntries(t *testing.T) {
 	habitEntry := models.AddEntryRequest{Done: func() *bool { b := true; return &b }()}
 	targetEntry := models.AddEntryRequest{Value: 10.0}
 
-	makeRequest("POST", fmt.Sprintf("/api/habit-trackers/%d/entries", createdHabit.ID), habitEntry)
-	makeRequest("POST", fmt.Sprintf("/api/target-trackers/%d/entries", createdTarget.ID), targetEntry)
+	srv.makeRequest("POST", fmt.Sprintf("/api/habit-trackers/%d/entries", createdHabit.ID), habitEntry)
+	srv.makeRequest("POST", fmt.Sprintf("/api/target-trackers/%d/entries", createdTarget.ID), targetEntry)
 
 	// Get all entries
-	allEntriesRr, err := makeRequest("GET", "/api/entries", nil)
+	allEntriesRr, err := srv.makeRequest("GET", "/api/entries", nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -237,6 +243,8 @@ func TestGetAllEntries(t *testing.T) {
 }
 
 func TestHabitEntryFilteringByStartDate(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
 	// Create a habit tracker with start date of yesterday
 	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
 	habitRequest := habit.CreateHabitRequest{
@@ -250,7 +258,7 @@ func TestHabitEntryFilteringByStartDate(t *testing.T) {
 		},
 	}
 
-	createRr, err := makeRequest("POST", "/api/habit-trackers", habitRequest)
+	createRr, err := srv.makeRequest("POST", "/api/habit-trackers", habitRequest)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -271,14 +279,14 @@ func TestHabitEntryFilteringByStartDate(t *testing.T) {
 
 	// Add all entries
 	for _, entryReq := range entries {
-		_, err := makeRequest("POST", fmt.Sprintf("/api/habit-trackers/%d/entries", createdHabit.ID), entryReq)
+		_, err := srv.makeRequest("POST", fmt.Sprintf("/api/habit-trackers/%d/entries", createdHabit.ID), entryReq)
 		if err != nil {
 			t.Fatal(err)
 		}
 	}
 
 	// Get entries for this tracker - should only return entries from start date onwards
-	entriesRr, err := makeRequest("GET", fmt.Sprintf("/api/habit-trackers/%d/entries", createdHabit.ID), nil)
+	entriesRr, err := srv.makeRequest("GET", fmt.Sprintf("/api/habit-trackers/%d/entries", createdHabit.ID), nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -306,6 +314,8 @@ func TestHabitEntryFilteringByStartDate(t *testing.T) {
 }
 
 func TestTargetEntryFilteringByStartDate(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
 	// Create a target tracker with start date of yesterday
 	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
 	targetRequest := target.CreateTargetRequest{
@@ -321,7 +331,7 @@ func TestTargetEntryFilteringByStartDate(t *testing.T) {
 		},
 	}
 
-	createRr, err := makeRequest("POST", "/api/target-trackers", targetRequest)
+	createRr, err := srv.makeRequest("POST", "/api/target-trackers", targetRequest)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -342,14 +352,14 @@ func TestTargetEntryFilteringByStartDate(t *testing.T) {
 
 	// Add all entries
 	for _, entryReq := range entries {
-		_, err := makeRequest("POST", fmt.Sprintf("/api/target-trackers/%d/entries", createdTarget.ID), entryReq)
+		_, err := srv.makeRequest("POST", fmt.Sprintf("/api/target-trackers/%d/entries", createdTarget.ID), entryReq)
 		if err != nil {
 			t.Fatal(err)
 		}
 	}
 
 	// Get entries for this tracker - should only return entries from start date onwards
-	entriesRr, err := makeRequest("GET", fmt.Sprintf("/api/target-trackers/%d/entries", createdTarget.ID), nil)
+	entriesRr, err := srv.makeRequest("GET", fmt.Sprintf("/api/target-trackers/%d/entries", createdTarget.ID), nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -397,6 +407,8 @@ func TestTargetEntryFilteringByStartDate(t *testing.T) {
 }
 
 func TestTargetCurrentValueCalculationWithFiltering(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
 	// Create a target tracker with start date of yesterday
 	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
 	targetRequest := target.CreateTargetRequest{
@@ -412,7 +424,7 @@ func TestTargetCurrentValueCalculationWithFiltering(t *testing.T) {
 		},
 	}
 
-	createRr, err := makeRequest("POST", "/api/target-trackers", targetRequest)
+	createRr, err := srv.makeRequest("POST", "/api/target-trackers", targetRequest)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -433,14 +445,14 @@ func TestTargetCurrentValueCalculationWithFiltering(t *testing.T) {
 
 	// Add all entries
 	for _, entryReq := range entries {
-		_, err := makeRequest("POST", fmt.Sprintf("/api/target-trackers/%d/entries", createdTarget.ID), entryReq)
+		_, err := srv.makeRequest("POST", fmt.Sprintf("/api/target-trackers/%d/entries", createdTarget.ID), entryReq)
 		if err != nil {
 			t.Fatal(err)
 		}
 	}
 
 	// Get the target tracker to check current value calculation
-	targetRr, err := makeRequest("GET", fmt.Sprintf("/api/target-trackers/%d", createdTarget.ID), nil)
+	targetRr, err := srv.makeRequest("GET", fmt.Sprintf("/api/target-trackers/%d", createdTarget.ID), nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -466,6 +478,8 @@ func TestTargetCurrentValueCalculationWithFiltering(t *testing.T) {
 }
 
 func TestTargetCurrentValueCalculationReplacementWithFiltering(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
 	// Create a replacement-type target tracker
 	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
 	targetRequest := target.CreateTargetRequest{
@@ -481,7 +495,7 @@ func TestTargetCurrentValueCalculationReplacementWithFiltering(t *testing.T) {
 		},
 	}
 
-	createRr, err := makeRequest("POST", "/api/target-trackers", targetRequest)
+	createRr, err := srv.makeRequest("POST", "/api/target-trackers", targetRequest)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -502,14 +516,14 @@ func TestTargetCurrentValueCalculationReplacementWithFiltering(t *testing.T) {
 
 	// Add all entries
 	for _, entryReq := range entries {
-		_, err := makeRequest("POST", fmt.Sprintf("/api/target-trackers/%d/entries", createdTarget.ID), entryReq)
+		_, err := srv.makeRequest("POST", fmt.Sprintf("/api/target-trackers/%d/entries", createdTarget.ID), entryReq)
 		if err != nil {
 			t.Fatal(err)
 		}
 	}
 
 	// Get the target tracker to check current value calculation
-	targetRr, err := makeRequest("GET", fmt.Sprintf("/api/target-trackers/%d", createdTarget.ID), nil)
+	targetRr, err := srv.makeRequest("GET", fmt.Sprintf("/api/target-trackers/%d", createdTarget.ID), nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -535,6 +549,8 @@ func TestTargetCurrentValueCalculationReplacementWithFiltering(t *testing.T) {
 }
 
 func TestDeleteEntry(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
 	// Create a habit tracker
 	habitRequest := habit.CreateHabitRequest{
 		TrackerName: "Delete Test Habit",
@@ -547,7 +563,7 @@ func TestDeleteEntry(t *testing.T) {
 		},
 	}
 
-	createRr, err := makeRequest("POST", "/api/habit-trackers", habitRequest)
+	createRr, err := srv.makeRequest("POST", "/api/habit-trackers", habitRequest)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -562,7 +578,7 @@ func TestDeleteEntry(t *testing.T) {
 		Date: time.Now().Format("2006-01-02T15:04:05Z"),
 	}
 
-	entryRr, err := makeRequest("POST", fmt.Sprintf("/api/habit-trackers/%d/entries", createdHabit.ID), entryRequest)
+	entryRr, err := srv.makeRequest("POST", fmt.Sprintf("/api/habit-trackers/%d/entries", createdHabit.ID), entryRequest)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -575,7 +591,7 @@ func TestDeleteEntry(t *testing.T) {
 	json.Unmarshal(entryRr.Body.Bytes(), &createdEntry)
 
 	// Delete the entry
-	deleteRr, err := makeRequest("DELETE", fmt.Sprintf("/api/entries/%d", createdEntry.ID), nil)
+	deleteRr, err := srv.makeRequest("DELETE", fmt.Sprintf("/api/entries/%d", createdEntry.ID), nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -585,7 +601,7 @@ func TestDeleteEntry(t *testing.T) {
 	}
 
 	// Verify entry is deleted by trying to get entries for the tracker
-	entriesRr, err := makeRequest("GET", fmt.Sprintf("/api/habit-trackers/%d/entries", createdHabit.ID), nil)
+	entriesRr, err := srv.makeRequest("GET", fmt.Sprintf("/api/habit-trackers/%d/entries", createdHabit.ID), nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -604,8 +620,10 @@ func TestDeleteEntry(t *testing.T) {
 }
 
 func TestDeleteNonExistentEntry(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
 	// Try to delete an entry that doesn't exist
-	deleteRr, err := makeRequest("DELETE", "/api/entries/99999", nil)
+	deleteRr, err := srv.makeRequest("DELETE", "/api/entries/99999", nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -616,8 +634,10 @@ func TestDeleteNonExistentEntry(t *testing.T) {
 }
 
 func TestDeleteEntryInvalidID(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
 	// Try to delete with invalid ID
-	deleteRr, err := makeRequest("DELETE", "/api/entries/invalid", nil)
+	deleteRr, err := srv.makeRequest("DELETE", "/api/entries/invalid", nil)
 	if err != nil {
 		t.Fatal(err)
 	}
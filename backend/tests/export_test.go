@@ -0,0 +1,262 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"routine-tracker/models"
+	"routine-tracker/trackers"
+	"routine-tracker/trackers/habit"
+	"routine-tracker/trackers/target"
+)
+
+// Helper function to fill an account with one habit, one target and entries
+func (srv *testServer) createArchiveData(t *testing.T, token string) {
+	t.Helper()
+
+	daily := models.Due{Type: models.INTERVAL, IntervalType: "day", IntervalValue: 1}
+	h := srv.createReminderHabit(t, token, "Exported Habit", daily)
+
+	targetRequest := target.CreateTargetRequest{
+		TrackerName: "Exported Target",
+		StartValue:  10,
+		GoalValue:   100,
+		StartDate:   "2024-01-01",
+		GoalDate:    "2024-12-31",
+		AddToTotal:  true,
+		Due:         daily,
+	}
+	rr, _ := srv.makeRequestWithToken(token, "POST", "/api/target-trackers", targetRequest)
+	var tg target.TargetTracker
+	json.Unmarshal(rr.Body.Bytes(), &tg)
+
+	done := true
+	srv.makeRequestWithToken(token, "POST", fmt.Sprintf("/api/habit-trackers/%d/entries", h.ID), models.AddEntryRequest{Done: &done, Date: "2024-03-01T08:00:00Z", Note: "morning"})
+	srv.makeRequestWithToken(token, "POST", fmt.Sprintf("/api/habit-trackers/%d/entries", h.ID), models.AddEntryRequest{Done: &done, Date: "2024-03-02T08:00:00Z"})
+	srv.makeRequestWithToken(token, "POST", fmt.Sprintf("/api/target-trackers/%d/entries", tg.ID), models.AddEntryRequest{Value: 15, Date: "2024-03-01T08:00:00Z"})
+}
+
+func (srv *testServer) exportArchive(t *testing.T, token string) trackers.Archive {
+	t.Helper()
+
+	rr, _ := srv.makeRequestWithToken(token, "GET", "/api/export", nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	var archive trackers.Archive
+	if err := json.Unmarshal(rr.Body.Bytes(), &archive); err != nil {
+		t.Fatalf("Failed to parse archive: %v", err)
+	}
+	return archive
+}
+
+func (srv *testServer) importArchive(t *testing.T, token, query string, archive interface{}) (int, trackers.ImportResult) {
+	t.Helper()
+
+	rr, _ := srv.makeRequestWithToken(token, "POST", "/api/import"+query, archive)
+	var result trackers.ImportResult
+	json.Unmarshal(rr.Body.Bytes(), &result)
+	return rr.Code, result
+}
+
+func (srv *testServer) countTrackers(token string) (int, int, int) {
+	rr, _ := srv.makeRequestWithToken(token, "GET", "/api/trackers", nil)
+	var all trackers.TrackersResponse
+	json.Unmarshal(rr.Body.Bytes(), &all)
+
+	rr, _ = srv.makeRequestWithToken(token, "GET", "/api/entries", nil)
+	var entries []models.Entry
+	json.Unmarshal(rr.Body.Bytes(), &entries)
+
+	return len(all.HabitTrackers), len(all.TargetTrackers), len(entries)
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	sourceToken, _ := srv.registerUser(t, "export-source")
+	srv.createArchiveData(t, sourceToken)
+
+	archive := srv.exportArchive(t, sourceToken)
+	if archive.Version != trackers.ArchiveVersion {
+		t.Errorf("Expected version %d, got %d", trackers.ArchiveVersion, archive.Version)
+	}
+	if len(archive.HabitTrackers) != 1 || len(archive.TargetTrackers) != 1 || len(archive.Entries) != 3 {
+		t.Fatalf("Expected 1 habit, 1 target and 3 entries, got %d, %d and %d",
+			len(archive.HabitTrackers), len(archive.TargetTrackers), len(archive.Entries))
+	}
+
+	destToken, _ := srv.registerUser(t, "export-destination")
+
+	// A dry run reports the changes without saving them
+	status, result := srv.importArchive(t, destToken, "?dryRun=true", archive)
+	if status != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, status)
+	}
+	if !result.DryRun || result.HabitTrackersCreated != 1 || result.TargetTrackersCreated != 1 || result.EntriesCreated != 3 {
+		t.Errorf("Unexpected dry run result %+v", result)
+	}
+	if habits, targets, entries := srv.countTrackers(destToken); habits+targets+entries != 0 {
+		t.Errorf("Expected dry run not to save anything, got %d habits, %d targets and %d entries", habits, targets, entries)
+	}
+
+	status, result = srv.importArchive(t, destToken, "", archive)
+	if status != http.StatusOK || result.Mode != trackers.IMPORT_MERGE || result.EntriesCreated != 3 {
+		t.Fatalf("Unexpected import result %d %+v", status, result)
+	}
+
+	imported := srv.exportArchive(t, destToken)
+	if len(imported.HabitTrackers) != 1 || len(imported.TargetTrackers) != 1 || len(imported.Entries) != 3 {
+		t.Fatalf("Expected imported data to match the export, got %+v", imported)
+	}
+	if imported.HabitTrackers[0].ID == archive.HabitTrackers[0].ID {
+		t.Error("Expected habit tracker to get a new ID")
+	}
+	for _, e := range imported.Entries {
+		ownerID := imported.HabitTrackers[0].ID
+		if e.Type == models.TARGET {
+			ownerID = imported.TargetTrackers[0].ID
+		}
+		if e.TrackerID != ownerID {
+			t.Errorf("Expected entry to point at remapped tracker %d, got %d", ownerID, e.TrackerID)
+		}
+	}
+
+	rr, _ := srv.makeRequestWithToken(destToken, "GET", fmt.Sprintf("/api/target-trackers/%d", imported.TargetTrackers[0].ID), nil)
+	var importedTarget target.TargetTracker
+	json.Unmarshal(rr.Body.Bytes(), &importedTarget)
+	if importedTarget.CurrentValue == nil || *importedTarget.CurrentValue != 25 {
+		t.Errorf("Expected imported target current value 25, got %v", importedTarget.CurrentValue)
+	}
+
+	// Merging the same archive again combines trackers and skips identical entries
+	_, result = srv.importArchive(t, destToken, "?mode=merge", archive)
+	if result.TrackersMerged != 2 || result.EntriesSkipped != 3 || result.EntriesCreated != 0 || result.HabitTrackersCreated != 0 {
+		t.Errorf("Unexpected merge result %+v", result)
+	}
+
+	// Replacing removes existing data first
+	srv.createReminderHabit(t, destToken, "Local Only Habit", models.Due{Type: models.INTERVAL, IntervalType: "day", IntervalValue: 1})
+	_, result = srv.importArchive(t, destToken, "?mode=replace", archive)
+	if result.TrackersDeleted != 3 || result.EntriesDeleted != 3 || result.HabitTrackersCreated != 1 || result.EntriesCreated != 3 {
+		t.Errorf("Unexpected replace result %+v", result)
+	}
+	if habits, targets, entries := srv.countTrackers(destToken); habits != 1 || targets != 1 || entries != 3 {
+		t.Errorf("Expected 1 habit, 1 target and 3 entries after replace, got %d, %d and %d", habits, targets, entries)
+	}
+}
+
+func TestExportImportLabels(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	daily := models.Due{Type: models.INTERVAL, IntervalType: "day", IntervalValue: 1}
+
+	health := srv.createCategory(t, "Health")
+	morning := srv.createTag(t, "morning")
+	outdoors := srv.createTag(t, "outdoors")
+	srv.makeRequest("POST", "/api/habit-trackers", habit.CreateHabitRequest{
+		TrackerName: "Walk", Goal: 1, TimePeriod: models.PER_DAY, StartDate: "2024-01-01", Due: daily,
+		CategoryID: &health.ID, TagIDs: []int{morning.ID, outdoors.ID},
+	})
+	srv.makeRequest("POST", "/api/target-trackers", target.CreateTargetRequest{
+		TrackerName: "Weight", StartValue: 80, GoalValue: 70, StartDate: "2024-01-01", GoalDate: "2024-12-31", Due: daily,
+		TagIDs: []int{morning.ID},
+	})
+
+	archive := srv.exportArchive(t, srv.token)
+	if len(archive.Categories) != 1 || len(archive.Tags) != 2 {
+		t.Fatalf("Expected 1 category and 2 tags, got %+v and %+v", archive.Categories, archive.Tags)
+	}
+
+	// Replacing keeps the categories and tags, which the trackers get back
+	_, result := srv.importArchive(t, srv.token, "?mode=replace", archive)
+	if result.HabitTrackersCreated != 1 || result.CategoriesCreated != 0 || result.TagsCreated != 0 {
+		t.Errorf("Unexpected replace result %+v", result)
+	}
+	response := srv.trackers(t, "")
+	if len(response.HabitTrackers) != 1 || len(response.TargetTrackers) != 1 {
+		t.Fatalf("Expected 1 habit and 1 target after replace, got %+v", response)
+	}
+	walk := response.HabitTrackers[0]
+	if walk.CategoryID == nil || *walk.CategoryID != health.ID || len(walk.Tags) != 2 {
+		t.Errorf("Expected the habit to keep its category and tags, got %v and %+v", walk.CategoryID, walk.Tags)
+	}
+	if weight := response.TargetTrackers[0]; len(weight.Tags) != 1 || weight.Tags[0].ID != morning.ID {
+		t.Errorf("Expected the target to keep its tag, got %+v", weight.Tags)
+	}
+
+	// Another user gets new IDs, labels with a known name are reused
+	destToken, _ := srv.registerUser(t, "labels-destination")
+	rr, _ := srv.makeRequestWithToken(destToken, "POST", "/api/tags", models.TagRequest{Name: "morning"})
+	var ownMorning models.Tag
+	json.Unmarshal(rr.Body.Bytes(), &ownMorning)
+
+	_, result = srv.importArchive(t, destToken, "", archive)
+	if result.CategoriesCreated != 1 || result.TagsCreated != 1 {
+		t.Errorf("Expected 1 new category and 1 new tag, got %+v", result)
+	}
+	imported := srv.exportArchive(t, destToken)
+	if len(imported.Categories) != 1 || len(imported.Tags) != 2 {
+		t.Fatalf("Expected 1 category and 2 tags, got %+v and %+v", imported.Categories, imported.Tags)
+	}
+	for _, h := range imported.HabitTrackers {
+		if h.CategoryID == nil || *h.CategoryID != imported.Categories[0].ID {
+			t.Errorf("Expected the habit to be in the imported category, got %v", h.CategoryID)
+		}
+	}
+	for _, tg := range imported.TargetTrackers {
+		if len(tg.Tags) != 1 || tg.Tags[0].ID != ownMorning.ID {
+			t.Errorf("Expected the target to get the existing morning tag, got %+v", tg.Tags)
+		}
+	}
+}
+
+func TestImportValidation(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	token, _ := srv.registerUser(t, "import-validation")
+
+	valid := trackers.Archive{
+		Version: trackers.ArchiveVersion,
+		HabitTrackers: []habit.HabitTracker{{ID: 1, TrackerName: "Habit", Goal: 1, TimePeriod: models.PER_DAY,
+			Due: models.Due{Type: models.INTERVAL, IntervalType: "day", IntervalValue: 1}}},
+	}
+
+	noDue := valid
+	noDue.HabitTrackers = []habit.HabitTracker{{ID: 1, TrackerName: "Habit", Goal: 1, TimePeriod: models.PER_DAY}}
+
+	unknownTracker := valid
+	unknownTracker.Entries = []models.Entry{{TrackerID: 2, Type: models.HABIT, Date: time.Now()}}
+
+	newerVersion := valid
+	newerVersion.Version = trackers.ArchiveVersion + 1
+
+	tests := []struct {
+		name    string
+		query   string
+		archive interface{}
+	}{
+		{"invalid mode", "?mode=overwrite", valid},
+		{"newer version", "", newerVersion},
+		{"entry of unknown tracker", "", unknownTracker},
+		{"tracker without a due", "", noDue},
+		{"not an archive", "", []int{1, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, _ := srv.importArchive(t, token, tt.query, tt.archive)
+			if status != http.StatusBadRequest {
+				t.Errorf("Expected status %d, got %d", http.StatusBadRequest, status)
+			}
+		})
+	}
+
+	if habits, _, _ := srv.countTrackers(token); habits != 0 {
+		t.Errorf("Expected rejected imports not to create trackers, got %d", habits)
+	}
+}
@@ -12,6 +12,8 @@ import (
 )
 
 func TestCreateHabitTracker(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
 	// Test data
 	habitRequest := habit.CreateHabitRequest{
 		TrackerName: "Drink Water",
@@ -27,7 +29,7 @@ func TestCreateHabitTracker(t *testing.T) {
 	}
 
 	// Make request
-	rr, err := makeRequest("POST", "/api/habit-trackers", habitRequest)
+	rr, err := srv.makeRequest("POST", "/api/habit-trackers", habitRequest)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -56,6 +58,8 @@ func TestCreateHabitTracker(t *testing.T) {
 }
 
 func TestGetHabitTrackers(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
 	// First create a habit tracker
 	habitRequest := habit.CreateHabitRequest{
 		TrackerName: "Exercise",
@@ -70,13 +74,13 @@ func TestGetHabitTrackers(t *testing.T) {
 	}
 
 	// Create the habit
-	_, err := makeRequest("POST", "/api/habit-trackers", habitRequest)
+	_, err := srv.makeRequest("POST", "/api/habit-trackers", habitRequest)
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	// Get all habits
-	rr, err := makeRequest("GET", "/api/habit-trackers", nil)
+	rr, err := srv.makeRequest("GET", "/api/habit-trackers", nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -96,6 +100,8 @@ func TestGetHabitTrackers(t *testing.T) {
 }
 
 func TestAddHabitEntry(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
 	// Create a habit first
 	habitRequest := habit.CreateHabitRequest{
 		TrackerName: "Read Book",
@@ -108,7 +114,7 @@ func TestAddHabitEntry(t *testing.T) {
 		},
 	}
 
-	createRr, err := makeRequest("POST", "/api/habit-trackers", habitRequest)
+	createRr, err := srv.makeRequest("POST", "/api/habit-trackers", habitRequest)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -122,7 +128,7 @@ func TestAddHabitEntry(t *testing.T) {
 		Note: "Finished chapter 1",
 	}
 
-	entryRr, err := makeRequest("POST", fmt.Sprintf("/api/habit-trackers/%d/entries", createdHabit.ID), entryRequest)
+	entryRr, err := srv.makeRequest("POST", fmt.Sprintf("/api/habit-trackers/%d/entries", createdHabit.ID), entryRequest)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -145,6 +151,8 @@ func TestAddHabitEntry(t *testing.T) {
 }
 
 func TestDeleteHabitTracker(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
 	// Create a habit first
 	habitRequest := habit.CreateHabitRequest{
 		TrackerName: "Test Delete",
@@ -157,7 +165,7 @@ func TestDeleteHabitTracker(t *testing.T) {
 		},
 	}
 
-	createRr, err := makeRequest("POST", "/api/habit-trackers", habitRequest)
+	createRr, err := srv.makeRequest("POST", "/api/habit-trackers", habitRequest)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -166,7 +174,7 @@ func TestDeleteHabitTracker(t *testing.T) {
 	json.Unmarshal(createRr.Body.Bytes(), &createdHabit)
 
 	// Delete the habit
-	deleteRr, err := makeRequest("DELETE", fmt.Sprintf("/api/habit-trackers/%d", createdHabit.ID), nil)
+	deleteRr, err := srv.makeRequest("DELETE", fmt.Sprintf("/api/habit-trackers/%d", createdHabit.ID), nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -176,7 +184,7 @@ func TestDeleteHabitTracker(t *testing.T) {
 	}
 
 	// Try to get the deleted habit (should fail)
-	getRr, err := makeRequest("GET", fmt.Sprintf("/api/habit-trackers/%d", createdHabit.ID), nil)
+	getRr, err := srv.makeRequest("GET", fmt.Sprintf("/api/habit-trackers/%d", createdHabit.ID), nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -186,8 +194,9 @@ func TestDeleteHabitTracker(t *testing.T) {
 	}
 }
 
-
 func TestGetHabitTracker(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
 	// Create a habit first
 	habitRequest := habit.CreateHabitRequest{
 		TrackerName: "Test Get Habit",
@@ -203,7 +212,7 @@ func TestGetHabitTracker(t *testing.T) {
 	}
 
 	// Create the habit
-	createRr, err := makeRequest("POST", "/api/habit-trackers", habitRequest)
+	createRr, err := srv.makeRequest("POST", "/api/habit-trackers", habitRequest)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -212,7 +221,7 @@ func TestGetHabitTracker(t *testing.T) {
 	json.Unmarshal(createRr.Body.Bytes(), &createdHabit)
 
 	// Get the specific habit
-	getRr, err := makeRequest("GET", fmt.Sprintf("/api/habit-trackers/%d", createdHabit.ID), nil)
+	getRr, err := srv.makeRequest("GET", fmt.Sprintf("/api/habit-trackers/%d", createdHabit.ID), nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -251,8 +260,10 @@ func TestGetHabitTracker(t *testing.T) {
 }
 
 func TestGetHabitTrackerNotFound(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
 	// Try to get a non-existent habit tracker
-	getRr, err := makeRequest("GET", "/api/habit-trackers/99999", nil)
+	getRr, err := srv.makeRequest("GET", "/api/habit-trackers/99999", nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -263,8 +274,10 @@ func TestGetHabitTrackerNotFound(t *testing.T) {
 }
 
 func TestGetHabitTrackerInvalidID(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
 	// Try to get a habit tracker with invalid ID
-	getRr, err := makeRequest("GET", "/api/habit-trackers/invalid", nil)
+	getRr, err := srv.makeRequest("GET", "/api/habit-trackers/invalid", nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -275,6 +288,8 @@ func TestGetHabitTrackerInvalidID(t *testing.T) {
 }
 
 func TestUpdateHabitTracker(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
 	// Create a habit first
 	habitRequest := habit.CreateHabitRequest{
 		TrackerName: "Original Habit",
@@ -288,7 +303,7 @@ func TestUpdateHabitTracker(t *testing.T) {
 		BadHabit: false,
 	}
 
-	createRr, err := makeRequest("POST", "/api/habit-trackers", habitRequest)
+	createRr, err := srv.makeRequest("POST", "/api/habit-trackers", habitRequest)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -309,7 +324,7 @@ func TestUpdateHabitTracker(t *testing.T) {
 		},
 	}
 
-	updateRr, err := makeRequest("PUT", fmt.Sprintf("/api/habit-trackers/%d", createdHabit.ID), updateRequest)
+	updateRr, err := srv.makeRequest("PUT", fmt.Sprintf("/api/habit-trackers/%d", createdHabit.ID), updateRequest)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -344,10 +359,36 @@ func TestUpdateHabitTracker(t *testing.T) {
 	}
 }
 
+func TestUpdateHabitTrackerInvalidStartDate(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	createRr, err := srv.makeRequest("POST", "/api/habit-trackers", habit.CreateHabitRequest{
+		TrackerName: "Read",
+		Goal:        1,
+		TimePeriod:  "perDay",
+		StartDate:   time.Now().Format("2006-01-02"),
+		Due:         models.Due{Type: "specificDays", SpecificDays: []string{"monday"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var createdHabit habit.HabitTracker
+	json.Unmarshal(createRr.Body.Bytes(), &createdHabit)
 
+	startDate := "2024-13-45"
+	rr, err := srv.makeRequest("PUT", fmt.Sprintf("/api/habit-trackers/%d", createdHabit.ID), habit.UpdateHabitRequest{StartDate: &startDate})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d. Body: %s", http.StatusBadRequest, rr.Code, rr.Body.String())
+	}
+}
 
 // Integration test to verify the complete flow
 func TestHabitTrackerDetailPageFlow(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
 	// 1. Create a habit
 	habitRequest := habit.CreateHabitRequest{
 		TrackerName: "Integration Test Habit",
@@ -362,7 +403,7 @@ func TestHabitTrackerDetailPageFlow(t *testing.T) {
 		GoalStreak: func() *int { i := 7; return &i }(),
 	}
 
-	createRr, err := makeRequest("POST", "/api/habit-trackers", habitRequest)
+	createRr, err := srv.makeRequest("POST", "/api/habit-trackers", habitRequest)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -379,14 +420,14 @@ func TestHabitTrackerDetailPageFlow(t *testing.T) {
 	}
 
 	for _, entryReq := range entries {
-		_, err := makeRequest("POST", fmt.Sprintf("/api/habit-trackers/%d/entries", createdHabit.ID), entryReq)
+		_, err := srv.makeRequest("POST", fmt.Sprintf("/api/habit-trackers/%d/entries", createdHabit.ID), entryReq)
 		if err != nil {
 			t.Fatal(err)
 		}
 	}
 
 	// 3. Get habit details (what the detail page would do)
-	getRr, err := makeRequest("GET", fmt.Sprintf("/api/habit-trackers/%d", createdHabit.ID), nil)
+	getRr, err := srv.makeRequest("GET", fmt.Sprintf("/api/habit-trackers/%d", createdHabit.ID), nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -399,7 +440,7 @@ func TestHabitTrackerDetailPageFlow(t *testing.T) {
 	json.Unmarshal(getRr.Body.Bytes(), &mhabit)
 
 	// 4. Get habit entries (what the detail page would do)
-	entriesRr, err := makeRequest("GET", fmt.Sprintf("/api/habit-trackers/%d/entries", createdHabit.ID), nil)
+	entriesRr, err := srv.makeRequest("GET", fmt.Sprintf("/api/habit-trackers/%d/entries", createdHabit.ID), nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -433,12 +474,12 @@ func TestHabitTrackerDetailPageFlow(t *testing.T) {
 	}
 
 	// 6. Update the habit (Settings tab functionality)
-	updateRequest := habit.UpdateHabitRequest {
+	updateRequest := habit.UpdateHabitRequest{
 		TrackerName: func() *string { s := "Updated Integration Test Habit"; return &s }(),
 		Goal:        func() *float64 { g := 2.0; return &g }(),
 	}
 
-	updateRr, err := makeRequest("PUT", fmt.Sprintf("/api/habit-trackers/%d", createdHabit.ID), updateRequest)
+	updateRr, err := srv.makeRequest("PUT", fmt.Sprintf("/api/habit-trackers/%d", createdHabit.ID), updateRequest)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -0,0 +1,280 @@
+package tests
+
+import (
+	"archive/zip"
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"routine-tracker/importer"
+	"routine-tracker/models"
+	"routine-tracker/trackers"
+	"routine-tracker/trackers/habit"
+)
+
+func TestLoopFrequencyMapping(t *testing.T) {
+	t.Parallel()
+	daily := models.Due{Type: models.INTERVAL, IntervalType: "day", IntervalValue: 1}
+	monWedFri := 1<<1 | 1<<3 | 1<<5
+
+	tests := []struct {
+		name         string
+		habit        importer.LoopHabit
+		wantGoal     float64
+		wantPeriod   models.TimePeriod
+		wantDue      models.Due
+		wantUnmapped bool
+	}{
+		{"every day", importer.LoopHabit{FreqNum: 1, FreqDen: 1, ReminderDays: 127}, 1, models.PER_DAY, daily, false},
+		{"three times a week on reminder days", importer.LoopHabit{FreqNum: 3, FreqDen: 7, ReminderDays: monWedFri}, 3, models.PER_WEEK,
+			models.Due{Type: models.SPECIFIC_DAYS, SpecificDays: []string{"monday", "wednesday", "friday"}}, false},
+		{"three times a week on any day", importer.LoopHabit{FreqNum: 3, FreqDen: 7, ReminderDays: 127}, 3, models.PER_WEEK, daily, false},
+		{"every third day", importer.LoopHabit{FreqNum: 1, FreqDen: 3, ReminderDays: 127}, 1, models.PER_DAY,
+			models.Due{Type: models.INTERVAL, IntervalType: "day", IntervalValue: 3}, false},
+		{"twice a month", importer.LoopHabit{FreqNum: 2, FreqDen: 30, ReminderDays: 127}, 2, models.PER_MONTH, daily, false},
+		{"twice every three days", importer.LoopHabit{FreqNum: 2, FreqDen: 3, ReminderDays: 127}, 5, models.PER_WEEK, daily, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.habit.Name = tt.name
+			archive, unmapped := importer.LoopArchive([]importer.LoopHabit{tt.habit}, time.Now(), time.UTC)
+			if len(archive.HabitTrackers) != 1 {
+				t.Fatalf("Expected 1 habit tracker, got %d", len(archive.HabitTrackers))
+			}
+
+			h := archive.HabitTrackers[0]
+			if h.Goal != tt.wantGoal || h.TimePeriod != tt.wantPeriod {
+				t.Errorf("Expected goal %v %s, got %v %s", tt.wantGoal, tt.wantPeriod, h.Goal, h.TimePeriod)
+			}
+			if !reflect.DeepEqual(h.Due, tt.wantDue) {
+				t.Errorf("Expected due %+v, got %+v", tt.wantDue, h.Due)
+			}
+			if (len(unmapped) > 0) != tt.wantUnmapped {
+				t.Errorf("Expected unmapped %v, got %+v", tt.wantUnmapped, unmapped)
+			}
+		})
+	}
+}
+
+func TestLoopArchiveTimeZone(t *testing.T) {
+	t.Parallel()
+	loc, _ := time.LoadLocation("Pacific/Auckland")
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	habits := []importer.LoopHabit{{
+		Name: "Meditate", FreqNum: 1, FreqDen: 1,
+		Checkmarks: []importer.LoopCheckmark{{Date: day, Value: importer.LOOP_YES_MANUAL}},
+	}}
+
+	archive, _ := importer.LoopArchive(habits, time.Now(), loc)
+	if len(archive.Entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(archive.Entries))
+	}
+	if want := time.Date(2024, 5, 1, 12, 0, 0, 0, loc); !archive.Entries[0].Date.Equal(want) {
+		t.Errorf("Expected entry at noon in the user's time zone %v, got %v", want, archive.Entries[0].Date)
+	}
+	if got := archive.HabitTrackers[0].StartDate.Format("2006-01-02"); got != "2024-05-01" {
+		t.Errorf("Expected start date 2024-05-01, got %s", got)
+	}
+}
+
+// Helper function to create a Loop Habit Tracker SQLite backup
+func createLoopDatabase(t *testing.T) []byte {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "Loop Habits Backup.db")
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	day := func(date string) int64 {
+		parsed, _ := time.Parse("2006-01-02", date)
+		return parsed.UnixMilli()
+	}
+
+	statements := []string{
+		`CREATE TABLE Habits (id INTEGER PRIMARY KEY AUTOINCREMENT, archived INTEGER, color INTEGER, description TEXT,
+			freq_den INTEGER, freq_num INTEGER, highlight INTEGER, name TEXT, position INTEGER, reminder_hour INTEGER,
+			reminder_min INTEGER, reminder_days INTEGER NOT NULL DEFAULT 127, type INTEGER NOT NULL DEFAULT 0,
+			target_type INTEGER NOT NULL DEFAULT 0, target_value REAL NOT NULL DEFAULT 0, unit TEXT NOT NULL DEFAULT '',
+			question TEXT, uuid TEXT)`,
+		`CREATE TABLE Repetitions (id INTEGER PRIMARY KEY AUTOINCREMENT, habit INTEGER NOT NULL REFERENCES Habits(id),
+			timestamp INTEGER NOT NULL, value INTEGER NOT NULL)`,
+		`INSERT INTO Habits (id, archived, name, position, freq_num, freq_den, reminder_hour, reminder_min, type)
+			VALUES (1, 0, 'Meditate', 0, 1, 1, 7, 30, 0)`,
+		`INSERT INTO Habits (id, archived, name, position, freq_num, freq_den, type, target_type, target_value, unit)
+			VALUES (2, 1, 'Run', 1, 1, 1, 1, 0, 5, 'km')`,
+	}
+	for _, statement := range statements {
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	repetitions := []struct {
+		habit int
+		date  string
+		value int
+	}{
+		{1, "2024-05-01", importer.LOOP_YES_MANUAL},
+		{1, "2024-05-02", importer.LOOP_SKIP},
+		{1, "2024-05-03", importer.LOOP_YES_AUTO},
+		{1, "2024-05-04", importer.LOOP_YES_MANUAL},
+		{2, "2024-05-02", 5200},
+		{2, "2024-05-05", 3000},
+	}
+	for _, r := range repetitions {
+		if _, err := db.Exec("INSERT INTO Repetitions (habit, timestamp, value) VALUES (?, ?, ?)", r.habit, day(r.date), r.value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	db.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func (srv *testServer) importLoop(t *testing.T, token, query string, data []byte) importer.LoopImportResult {
+	t.Helper()
+
+	rr := srv.makeRawRequest(token, "POST", "/api/import/loop"+query, "application/octet-stream", data)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	var result importer.LoopImportResult
+	json.Unmarshal(rr.Body.Bytes(), &result)
+	return result
+}
+
+func TestImportLoopDatabase(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	token, _ := srv.registerUser(t, "loop-database-user")
+	backup := createLoopDatabase(t)
+
+	result := srv.importLoop(t, token, "?dryRun=true", backup)
+	if !result.DryRun || result.HabitTrackersCreated != 1 || result.TargetTrackersCreated != 1 || result.EntriesCreated != 5 {
+		t.Errorf("Unexpected dry run result %+v", result)
+	}
+	if habits, targets, _ := srv.countTrackers(token); habits+targets != 0 {
+		t.Error("Expected dry run not to create trackers")
+	}
+
+	result = srv.importLoop(t, token, "", backup)
+	if result.EntriesCreated != 5 {
+		t.Fatalf("Expected 5 entries, got %+v", result)
+	}
+
+	reasons := make([]string, 0)
+	for _, u := range result.Unmapped {
+		reasons = append(reasons, u.Habit+": "+u.Reason)
+	}
+	joined := strings.Join(reasons, "\n")
+	for _, want := range []string{"Run: numerical habit"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("Expected unmapped report to mention '%s', got:\n%s", want, joined)
+		}
+	}
+
+	rr, _ := srv.makeRequestWithToken(token, "GET", "/api/trackers?archived=all", nil)
+	var all trackers.TrackersResponse
+	json.Unmarshal(rr.Body.Bytes(), &all)
+	if len(all.HabitTrackers) != 1 || len(all.TargetTrackers) != 1 {
+		t.Fatalf("Expected 1 habit and 1 target, got %d and %d", len(all.HabitTrackers), len(all.TargetTrackers))
+	}
+
+	meditate := all.HabitTrackers[0]
+	if meditate.TrackerName != "Meditate" || meditate.StartDate.Format("2006-01-02") != "2024-05-01" {
+		t.Errorf("Unexpected habit %+v", meditate)
+	}
+	if !meditate.Reminders.Enabled || len(meditate.Reminders.Times) != 1 || meditate.Reminders.Times[0] != "07:30" {
+		t.Errorf("Expected reminder at 07:30, got %+v", meditate.Reminders)
+	}
+
+	run := all.TargetTrackers[0]
+	if run.TrackerName != "Run (km)" || run.GoalValue != 5 || run.CurrentValue == nil || *run.CurrentValue != 3 {
+		t.Errorf("Unexpected target %+v", run)
+	}
+	if run.ArchivedAt == nil || meditate.ArchivedAt != nil {
+		t.Errorf("Expected only the habit archived in Loop to be archived, got %v and %v", run.ArchivedAt, meditate.ArchivedAt)
+	}
+
+	rr, _ = srv.makeRequestWithToken(token, "GET", "/api/entries", nil)
+	var entries []models.Entry
+	json.Unmarshal(rr.Body.Bytes(), &entries)
+	skipped := 0
+	for _, e := range entries {
+		if e.Type == models.TARGET && e.Value != 5.2 && e.Value != 3 {
+			t.Errorf("Expected numerical values in km, got %v", e.Value)
+		}
+		if e.Skipped {
+			skipped++
+			if e.Date.Format("2006-01-02") != "2024-05-02" || e.Done == nil || *e.Done {
+				t.Errorf("Expected the skipped day 2024-05-02 not to be done, got %+v", e)
+			}
+		}
+	}
+	if skipped != 1 {
+		t.Errorf("Expected 1 skipped entry, got %d", skipped)
+	}
+
+	// Importing the same backup again only merges
+	result = srv.importLoop(t, token, "", backup)
+	if result.TrackersMerged != 2 || result.EntriesCreated != 0 || result.EntriesSkipped != 5 {
+		t.Errorf("Expected repeated import to skip everything, got %+v", result)
+	}
+}
+
+func TestImportLoopCSVExport(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	token, _ := srv.registerUser(t, "loop-csv-user")
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	files := map[string]string{
+		"Habits.csv": "Position,Name,Type,Question,Description,FrequencyNumerator,FrequencyDenominator,Color,Unit,Target Type,Target Value,Archived?\n" +
+			"001,Read,YES_NO,Did you read today?,,3,7,#FF8F00,,AT_LEAST,0,false\n",
+		"001 Read/Checkmarks.csv": "2024-06-03,2\n2024-06-04,0\n2024-06-05,1\n2024-06-06,2\n",
+		"001 Read/Scores.csv":     "2024-06-03,0.1\n",
+	}
+	for name, content := range files {
+		f, _ := archive.Create(name)
+		f.Write([]byte(content))
+	}
+	archive.Close()
+
+	result := srv.importLoop(t, token, "", buf.Bytes())
+	if result.HabitTrackersCreated != 1 || result.EntriesCreated != 2 {
+		t.Errorf("Expected 1 habit with 2 entries, got %+v", result)
+	}
+
+	rr, _ := srv.makeRequestWithToken(token, "GET", "/api/habit-trackers", nil)
+	var habits []habit.HabitTracker
+	json.Unmarshal(rr.Body.Bytes(), &habits)
+	if len(habits) != 1 || habits[0].Goal != 3 || habits[0].TimePeriod != models.PER_WEEK {
+		t.Errorf("Expected a 3 times per week habit, got %+v", habits)
+	}
+}
+
+func TestImportLoopRejectsUnknownFiles(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	rr := srv.makeRawRequest(srv.token, "POST", "/api/import/loop", "application/octet-stream", []byte("not a backup"))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, rr.Code)
+	}
+}
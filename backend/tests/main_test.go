@@ -2,40 +2,117 @@ package tests
 
 import (
 	"bytes"
+	"database/sql"
 	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
+	"sync/atomic"
 	"testing"
 
-  "routine-tracker/router"
-	"github.com/gorilla/mux"
+	"routine-tracker/app"
+	"routine-tracker/config"
 	"routine-tracker/database"
+	"routine-tracker/models"
+	"routine-tracker/router"
 )
 
-var testRouter *mux.Router
+// testServer is an isolated server with its own database and a registered
+// default user, so tests can run in parallel
+type testServer struct {
+	app    *app.App
+	router *router.Router
+	token  string // bearer token of the default user
+}
+
+// testLogger discards the logs of the test servers
+var testLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// testSchemas numbers the PostgreSQL schemas of the test servers
+var testSchemas atomic.Int64
+
+// newTestServer starts a server with the default configuration
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+	return newTestServerWithConfig(t, config.Default())
+}
 
-func TestMain(m *testing.M) {
-	// Setup test database
-	os.Remove("./test.db") // Remove any existing test db
+// newTestServerWithConfig starts a server on an empty in-memory SQLite
+// database, or on a fresh schema of TEST_POSTGRES_DSN when it is set
+func newTestServerWithConfig(t *testing.T, cfg *config.Config) *testServer {
+	t.Helper()
 
-	// Initialize test database
-	if err := database.InitTest("./test.db"); err != nil {
-		panic("Failed to initialize test database: " + err.Error())
+	store := openTestStore(t)
+	a := app.New(cfg, store, testLogger)
+	// Test receivers listen on localhost
+	a.Notifier.AllowInternalHosts = true
+	srv := &testServer{app: a, router: router.Setup(a)}
+
+	if cfg.Features.Registration {
+		token, err := srv.registerAndLogin("testuser", "testpassword")
+		if err != nil {
+			t.Fatal("Failed to create test user: " + err.Error())
+		}
+		srv.token = token
 	}
-  testRouter = router.Setup()
-	// Run tests
-	code := m.Run()
+	return srv
+}
 
-	// Cleanup
-	database.Close()
-	os.Remove("./test.db")
+func openTestStore(t *testing.T) database.Store {
+	t.Helper()
 
-	os.Exit(code)
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		store, err := database.Init(":memory:", testLogger)
+		if err != nil {
+			t.Fatal("Failed to initialize test database: " + err.Error())
+		}
+		t.Cleanup(func() { store.Close() })
+		return store
+	}
+
+	// Every test gets its own schema of the PostgreSQL database
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema := fmt.Sprintf("test_%d_%d", os.Getpid(), testSchemas.Add(1))
+	if _, err := db.Exec("DROP SCHEMA IF EXISTS " + schema + " CASCADE; CREATE SCHEMA " + schema); err != nil {
+		db.Close()
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		db.Exec("DROP SCHEMA " + schema + " CASCADE")
+		db.Close()
+	})
+
+	u, err := url.Parse(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := u.Query()
+	query.Set("search_path", schema)
+	u.RawQuery = query.Encode()
+
+	store, err := database.Init(u.String(), testLogger)
+	if err != nil {
+		t.Fatal("Failed to initialize test database: " + err.Error())
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+// Helper function to make HTTP requests as the default test user
+func (srv *testServer) makeRequest(method, url string, body interface{}) (*httptest.ResponseRecorder, error) {
+	return srv.makeRequestWithToken(srv.token, method, url, body)
 }
 
-// Helper function to make HTTP requests
-func makeRequest(method, url string, body interface{}) (*httptest.ResponseRecorder, error) {
+// Helper function to make HTTP requests with a specific bearer token, or none when empty
+func (srv *testServer) makeRequestWithToken(token, method, url string, body interface{}) (*httptest.ResponseRecorder, error) {
 	var reqBody *bytes.Buffer
 
 	if body != nil {
@@ -54,9 +131,39 @@ func makeRequest(method, url string, body interface{}) (*httptest.ResponseRecord
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
 
 	rr := httptest.NewRecorder()
-	testRouter.ServeHTTP(rr, req)
+	srv.router.ServeHTTP(rr, req)
 
 	return rr, nil
 }
+
+// Helper function to register a user and return a bearer token for it
+func (srv *testServer) registerAndLogin(username, password string) (string, error) {
+	credentials := models.RegisterRequest{Username: username, Password: password}
+
+	rr, err := srv.makeRequestWithToken("", "POST", "/api/auth/register", credentials)
+	if err != nil {
+		return "", err
+	}
+	if rr.Code != http.StatusCreated {
+		return "", fmt.Errorf("register failed with status %d: %s", rr.Code, rr.Body.String())
+	}
+
+	rr, err = srv.makeRequestWithToken("", "POST", "/api/auth/login", models.LoginRequest(credentials))
+	if err != nil {
+		return "", err
+	}
+	if rr.Code != http.StatusOK {
+		return "", fmt.Errorf("login failed with status %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var response models.LoginResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		return "", err
+	}
+	return response.Token, nil
+}
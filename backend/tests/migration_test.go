@@ -0,0 +1,231 @@
+package tests
+
+import (
+	"bytes"
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"routine-tracker/database"
+	"routine-tracker/models"
+	"routine-tracker/trackers"
+)
+
+// Helper function to open an empty SQLite store and a plain connection to
+// the same database file for inspecting the schema
+func openMigrationDB(t *testing.T) (database.Store, *sql.DB) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "migrations.db")
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	store, err := database.NewSQLiteStore(path, testLogger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store, db
+}
+
+func tableNames(t *testing.T, db *sql.DB) []string {
+	t.Helper()
+
+	rows, err := db.Query("SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	names := make([]string, 0)
+	for rows.Next() {
+		var name string
+		rows.Scan(&name)
+		names = append(names, name)
+	}
+	return names
+}
+
+func TestMigrationsUpAndDown(t *testing.T) {
+	t.Parallel()
+	store, db := openMigrationDB(t)
+
+	migrations, err := store.GetMigrationStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			t.Errorf("Expected migration %d to have version %d, got %d", i, i+1, m.Version)
+		}
+	}
+
+	count, err := store.MigrateUp(0)
+	if err != nil || count != len(migrations) {
+		t.Fatalf("Expected %d migrations to be applied, got %d and error %v", len(migrations), count, err)
+	}
+	if count, _ := store.MigrateUp(0); count != 0 {
+		t.Errorf("Expected no pending migrations, got %d", count)
+	}
+
+	statuses, _ := store.GetMigrationStatus()
+	for _, s := range statuses {
+		if s.AppliedAt == nil {
+			t.Errorf("Expected migration %d to be applied", s.Version)
+		}
+	}
+
+	// Reverting everything leaves only the version table
+	count, err = store.MigrateDown(len(migrations))
+	if err != nil || count != len(migrations) {
+		t.Fatalf("Expected %d migrations to be reverted, got %d and error %v", len(migrations), count, err)
+	}
+	if names := tableNames(t, db); strings.Join(names, ",") != "schema_migrations" {
+		t.Errorf("Expected only schema_migrations after reverting, got %v", names)
+	}
+
+	// Migrating to a specific version stops there
+	if count, err := store.MigrateUp(3); err != nil || count != 3 {
+		t.Fatalf("Expected 3 migrations to be applied, got %d and error %v", count, err)
+	}
+	if _, err := db.Exec("SELECT trend_weight_type FROM target_trackers"); err != nil {
+		t.Errorf("Expected trend_weight_type column after migration 3: %v", err)
+	}
+	if _, err := db.Exec("SELECT user_id FROM entries"); err == nil {
+		t.Error("Expected user_id column to be missing before migration 5")
+	}
+
+	if _, err := store.MigrateUp(0); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMigrationsOnLegacyDatabase(t *testing.T) {
+	t.Parallel()
+	store, db := openMigrationDB(t)
+
+	// Databases created before schema_migrations existed already have some
+	// of the columns the migrations add
+	legacy := []string{
+		`CREATE TABLE target_trackers (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, tracker_name TEXT NOT NULL, start_value REAL NOT NULL,
+			goal_value REAL NOT NULL, start_date DATETIME NOT NULL, goal_date DATETIME NOT NULL,
+			add_to_total BOOLEAN DEFAULT FALSE, use_actual_bounds BOOLEAN DEFAULT FALSE, due_type TEXT NOT NULL,
+			due_specific_days TEXT, due_interval_type TEXT, due_interval_value INTEGER, reminder_times TEXT,
+			reminder_enabled BOOLEAN DEFAULT TRUE, created_at DATETIME DEFAULT CURRENT_TIMESTAMP)`,
+		`INSERT INTO target_trackers (tracker_name, start_value, goal_value, start_date, goal_date, due_type)
+			VALUES ('Weight', 80, 70, '2024-01-01', '2024-12-31', 'interval')`,
+	}
+	for _, statement := range legacy {
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := store.MigrateUp(0); err != nil {
+		t.Fatalf("Expected migrations to run on a legacy database: %v", err)
+	}
+
+	var name, trendWeightType string
+	var userID sql.NullInt64
+	err := db.QueryRow("SELECT tracker_name, trend_weight_type, user_id FROM target_trackers").Scan(&name, &trendWeightType, &userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "Weight" || trendWeightType != "none" || userID.Valid {
+		t.Errorf("Unexpected migrated tracker: %s %s %v", name, trendWeightType, userID)
+	}
+}
+
+func TestIntervalDuesMigration(t *testing.T) {
+	t.Parallel()
+	store, db := openMigrationDB(t)
+	if _, err := store.MigrateUp(18); err != nil {
+		t.Fatal(err)
+	}
+
+	// Every day of every second week or month was due before rules existed
+	statements := []struct {
+		query string
+		args  []interface{}
+	}{
+		{"INSERT INTO users (id, username, password_hash) VALUES (1, 'legacy', 'x')", nil},
+		{`INSERT INTO habit_trackers (user_id, tracker_name, goal, time_period, start_date, due_type, due_specific_days, due_interval_type, due_interval_value, reminder_times)
+			VALUES (1, 'Weekly', 1, 'perDay', ?, 'interval', 'null', 'week', 2, '[]')`, []interface{}{time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)}},
+		{`INSERT INTO habit_trackers (user_id, tracker_name, goal, time_period, start_date, due_type, due_specific_days, due_interval_type, due_interval_value, reminder_times)
+			VALUES (1, 'Monthly', 1, 'perDay', ?, 'interval', 'null', 'month', 2, '[]')`, []interface{}{time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)}},
+	}
+	for _, statement := range statements {
+		if _, err := db.Exec(statement.query, statement.args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := store.MigrateUp(0); err != nil {
+		t.Fatal(err)
+	}
+
+	habits, err := store.GetAllHabitTrackers(1, models.ALL_TRACKERS)
+	if err != nil || len(habits) != 2 {
+		t.Fatalf("Expected 2 habit trackers, got %d and error %v", len(habits), err)
+	}
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	want := map[string]struct {
+		to   time.Time
+		days int
+	}{
+		// 2024-01-03 to 2024-01-09 and 2024-01-17 to 2024-01-23
+		"Weekly": {time.Date(2024, 1, 23, 0, 0, 0, 0, time.UTC), 14},
+		// 2024-01-15 to 2024-01-31 and all of March
+		"Monthly": {time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC), 48},
+	}
+	for _, h := range habits {
+		if h.Due.Type != models.RRULE {
+			t.Errorf("Expected %s to be migrated to a rule, got %+v", h.TrackerName, h.Due)
+		}
+		w := want[h.TrackerName]
+		if days := trackers.DueDates(h.Due, h.StartDate, from, w.to); len(days) != w.days {
+			t.Errorf("Expected %s to be due on %d days, got %d: %v", h.TrackerName, w.days, len(days), days)
+		}
+	}
+
+	if _, err := store.MigrateDown(1); err != nil {
+		t.Fatal(err)
+	}
+	var intervals int
+	db.QueryRow("SELECT COUNT(*) FROM habit_trackers WHERE due_type = 'interval' AND due_rrule = ''").Scan(&intervals)
+	if intervals != 2 {
+		t.Errorf("Expected both trackers to be interval trackers again, got %d", intervals)
+	}
+}
+
+func TestMigrateCommand(t *testing.T) {
+	t.Parallel()
+	store, _ := openMigrationDB(t)
+	var out bytes.Buffer
+
+	if err := database.MigrateCommand(store, []string{"status"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "0001  initial_schema") || !strings.Contains(out.String(), "pending") {
+		t.Errorf("Unexpected status output:\n%s", out.String())
+	}
+
+	out.Reset()
+	database.MigrateCommand(store, []string{"up"}, &out)
+	database.MigrateCommand(store, []string{"down"}, &out)
+	if !strings.Contains(out.String(), "Reverted 1 migration(s)") {
+		t.Errorf("Expected one migration to be reverted by default, got:\n%s", out.String())
+	}
+
+	for _, args := range [][]string{{}, {"sideways"}, {"down", "two"}} {
+		if err := database.MigrateCommand(store, args, &out); err == nil {
+			t.Errorf("Expected error for arguments %v", args)
+		}
+	}
+}
@@ -0,0 +1,466 @@
+package tests
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"routine-tracker/models"
+	"routine-tracker/notify"
+)
+
+// receivedRequest is a request captured by a test receiver
+type receivedRequest struct {
+	Method string
+	Path   string
+	Header http.Header
+	Body   string
+}
+
+// Helper function to start an HTTP receiver recording every request
+func startReceiver(t *testing.T, status int) (*httptest.Server, func() []receivedRequest) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var received []receivedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		received = append(received, receivedRequest{r.Method, r.URL.Path, r.Header.Clone(), string(body)})
+		mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []receivedRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]receivedRequest(nil), received...)
+	}
+}
+
+// receivedMail is an email accepted by the test SMTP server
+type receivedMail struct {
+	From string
+	To   []string
+	Data string
+}
+
+// Helper function to start a minimal in-process SMTP server
+func startSMTPServer(t *testing.T) (notify.SMTPConfig, func() []receivedMail) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	var mu sync.Mutex
+	var mails []receivedMail
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				reply := func(line string) { fmt.Fprintf(conn, "%s\r\n", line) }
+
+				var current receivedMail
+				reply("220 localhost test server")
+				for {
+					line, err := reader.ReadString('\n')
+					if err != nil {
+						return
+					}
+					command := strings.ToUpper(strings.TrimSpace(line))
+					switch {
+					case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+						reply("250 localhost")
+					case strings.HasPrefix(command, "MAIL FROM:"):
+						current = receivedMail{From: strings.Trim(strings.TrimSpace(line)[10:], "<>")}
+						reply("250 OK")
+					case strings.HasPrefix(command, "RCPT TO:"):
+						current.To = append(current.To, strings.Trim(strings.TrimSpace(line)[8:], "<>"))
+						reply("250 OK")
+					case command == "DATA":
+						reply("354 End data with <CR><LF>.<CR><LF>")
+						var data strings.Builder
+						for {
+							dataLine, err := reader.ReadString('\n')
+							if err != nil {
+								return
+							}
+							if dataLine == ".\r\n" {
+								break
+							}
+							data.WriteString(dataLine)
+						}
+						current.Data = data.String()
+						mu.Lock()
+						mails = append(mails, current)
+						mu.Unlock()
+						reply("250 OK")
+					case command == "QUIT":
+						reply("221 Bye")
+						return
+					default:
+						reply("250 OK")
+					}
+				}
+			}(conn)
+		}
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+	config := notify.SMTPConfig{Host: "127.0.0.1", Port: addr.Port, From: "tracker@example.com"}
+	return config, func() []receivedMail {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]receivedMail(nil), mails...)
+	}
+}
+
+func testMessage() notify.Message {
+	return notify.Message{
+		TrackerType:  models.HABIT,
+		TrackerID:    7,
+		TrackerName:  "Drink Water",
+		Title:        "Reminder: Drink Water",
+		Body:         "Drink Water is due today",
+		ScheduledFor: time.Date(2024, 1, 1, 18, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	t.Parallel()
+	server, received := startReceiver(t, http.StatusOK)
+
+	notifier := notify.WebhookNotifier{URL: server.URL + "/hook", Token: "secret"}
+	if err := notifier.Notify(context.Background(), testMessage()); err != nil {
+		t.Fatal(err)
+	}
+
+	requests := received()
+	if len(requests) != 1 {
+		t.Fatalf("Expected 1 request, got %d", len(requests))
+	}
+	if requests[0].Method != "POST" || requests[0].Path != "/hook" {
+		t.Errorf("Expected POST /hook, got %s %s", requests[0].Method, requests[0].Path)
+	}
+	if requests[0].Header.Get("Authorization") != "Bearer secret" {
+		t.Errorf("Expected bearer token, got '%s'", requests[0].Header.Get("Authorization"))
+	}
+
+	var payload notify.WebhookPayload
+	if err := json.Unmarshal([]byte(requests[0].Body), &payload); err != nil {
+		t.Fatalf("Expected JSON payload: %v", err)
+	}
+	if payload.Event != "reminder" || payload.TrackerID != 7 || payload.Title != "Reminder: Drink Water" {
+		t.Errorf("Unexpected payload %+v", payload)
+	}
+
+	failing, _ := startReceiver(t, http.StatusInternalServerError)
+	notifier.URL = failing.URL
+	if err := notifier.Notify(context.Background(), testMessage()); err == nil {
+		t.Error("Expected error for a failing webhook")
+	}
+
+	notifier.URL = "file:///etc/passwd"
+	if err := notifier.Notify(context.Background(), testMessage()); err == nil {
+		t.Error("Expected error for a URL other than http(s)")
+	}
+}
+
+func TestPushNotifiers(t *testing.T) {
+	t.Parallel()
+	server, received := startReceiver(t, http.StatusOK)
+
+	ntfy := notify.NtfyNotifier{URL: server.URL + "/my-habits", Token: "tk_ntfy"}
+	if err := ntfy.Notify(context.Background(), testMessage()); err != nil {
+		t.Fatal(err)
+	}
+
+	gotify := notify.GotifyNotifier{URL: server.URL + "/", Token: "app-token"}
+	if err := gotify.Notify(context.Background(), testMessage()); err != nil {
+		t.Fatal(err)
+	}
+
+	requests := received()
+	if len(requests) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(requests))
+	}
+
+	ntfyRequest := requests[0]
+	if ntfyRequest.Path != "/my-habits" || ntfyRequest.Body != "Drink Water is due today" {
+		t.Errorf("Unexpected ntfy request to %s with body '%s'", ntfyRequest.Path, ntfyRequest.Body)
+	}
+	if ntfyRequest.Header.Get("Title") != "Reminder: Drink Water" || ntfyRequest.Header.Get("Authorization") != "Bearer tk_ntfy" {
+		t.Errorf("Unexpected ntfy headers %v", ntfyRequest.Header)
+	}
+
+	gotifyRequest := requests[1]
+	if gotifyRequest.Path != "/message" || gotifyRequest.Header.Get("X-Gotify-Key") != "app-token" {
+		t.Errorf("Unexpected gotify request to %s with headers %v", gotifyRequest.Path, gotifyRequest.Header)
+	}
+	var body map[string]interface{}
+	json.Unmarshal([]byte(gotifyRequest.Body), &body)
+	if body["title"] != "Reminder: Drink Water" || body["message"] != "Drink Water is due today" {
+		t.Errorf("Unexpected gotify body %v", body)
+	}
+}
+
+func TestEmailNotifier(t *testing.T) {
+	t.Parallel()
+	smtpConfig, mails := startSMTPServer(t)
+
+	notifier := notify.EmailNotifier{SMTP: smtpConfig, To: "me@example.com"}
+	if err := notifier.Notify(context.Background(), testMessage()); err != nil {
+		t.Fatal(err)
+	}
+
+	received := mails()
+	if len(received) != 1 {
+		t.Fatalf("Expected 1 email, got %d", len(received))
+	}
+	if received[0].From != "tracker@example.com" || len(received[0].To) != 1 || received[0].To[0] != "me@example.com" {
+		t.Errorf("Unexpected envelope from %s to %v", received[0].From, received[0].To)
+	}
+	if !strings.Contains(received[0].Data, "Subject: Reminder: Drink Water") || !strings.Contains(received[0].Data, "Drink Water is due today") {
+		t.Errorf("Unexpected email:\n%s", received[0].Data)
+	}
+
+	if err := (notify.EmailNotifier{To: "me@example.com"}).Notify(context.Background(), testMessage()); err == nil {
+		t.Error("Expected error without SMTP configuration")
+	}
+}
+
+func TestNotificationChannelValidation(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	habitType := models.HABIT
+	missingID := 999999
+
+	tests := []struct {
+		name    string
+		request models.CreateNotificationChannelRequest
+	}{
+		{"missing name", models.CreateNotificationChannelRequest{Type: models.CHANNEL_WEBHOOK, Target: "https://example.com"}},
+		{"unknown type", models.CreateNotificationChannelRequest{Name: "Pager", Type: "pager", Target: "https://example.com"}},
+		{"invalid email", models.CreateNotificationChannelRequest{Name: "Mail", Type: models.CHANNEL_EMAIL, Target: "not-an-email"}},
+		{"invalid url", models.CreateNotificationChannelRequest{Name: "Hook", Type: models.CHANNEL_WEBHOOK, Target: "ftp://example.com"}},
+		{"tracker type without id", models.CreateNotificationChannelRequest{Name: "Hook", Type: models.CHANNEL_NTFY, Target: "https://ntfy.sh/x", TrackerType: &habitType}},
+		{"unknown tracker", models.CreateNotificationChannelRequest{Name: "Hook", Type: models.CHANNEL_NTFY, Target: "https://ntfy.sh/x", TrackerType: &habitType, TrackerID: &missingID}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr, err := srv.makeRequest("POST", "/api/notifications/channels", tt.request)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rr.Code != http.StatusBadRequest {
+				t.Errorf("Expected status %d, got %d. Body: %s", http.StatusBadRequest, rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestNotificationChannelsRejectInternalHosts(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	token, _ := srv.registerUser(t, "internal-hosts-user")
+	server, received := startReceiver(t, http.StatusOK)
+
+	rr, _ := srv.makeRequestWithToken(token, "POST", "/api/notifications/channels", models.CreateNotificationChannelRequest{
+		Name: "Local hook", Type: models.CHANNEL_WEBHOOK, Target: server.URL,
+	})
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d while internal hosts are allowed, got %d", http.StatusCreated, rr.Code)
+	}
+
+	srv.app.Notifier.AllowInternalHosts = false
+	for _, target := range []string{
+		server.URL,
+		"http://localhost:8080/hook",
+		"http://10.0.0.5/hook",
+		"https://192.168.1.10",
+		"http://169.254.169.254/latest/meta-data",
+		"http://[::1]/hook",
+		"http://0.0.0.0/",
+	} {
+		rr, _ := srv.makeRequestWithToken(token, "POST", "/api/notifications/channels", models.CreateNotificationChannelRequest{
+			Name: "Internal", Type: models.CHANNEL_NTFY, Target: target,
+		})
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status %d, got %d", target, http.StatusBadRequest, rr.Code)
+		}
+	}
+
+	// Channels created before are not sent to either
+	rr, _ = srv.makeRequestWithToken(token, "POST", "/api/notifications/test", nil)
+	var results []models.TestNotificationResult
+	json.Unmarshal(rr.Body.Bytes(), &results)
+	if len(results) != 1 || results[0].Success || len(received()) != 0 {
+		t.Errorf("Expected the internal host to be refused, got %+v and %d requests", results, len(received()))
+	}
+
+	// The dispatcher's client refuses internal addresses however the URL was checked
+	err := notify.WebhookNotifier{Client: srv.app.Notifier.Client, URL: server.URL}.Notify(context.Background(), testMessage())
+	if !errors.Is(err, notify.ErrInternalHost) || len(received()) != 0 {
+		t.Errorf("Expected the connection to be refused, got %v", err)
+	}
+}
+
+func TestNotificationChannelsAndTestEndpoint(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	token, _ := srv.registerUser(t, "notification-user")
+
+	rr, _ := srv.makeRequestWithToken(token, "POST", "/api/notifications/test", nil)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d without channels, got %d", http.StatusBadRequest, rr.Code)
+	}
+
+	server, received := startReceiver(t, http.StatusOK)
+	failing, _ := startReceiver(t, http.StatusBadGateway)
+	smtpConfig, mails := startSMTPServer(t)
+
+	srv.app.Notifier.SMTP = smtpConfig
+
+	requests := []models.CreateNotificationChannelRequest{
+		{Name: "Home Assistant", Type: models.CHANNEL_WEBHOOK, Target: server.URL + "/hook"},
+		{Name: "Broken", Type: models.CHANNEL_GOTIFY, Target: failing.URL, Token: "app-token"},
+		{Name: "Inbox", Type: models.CHANNEL_EMAIL, Target: "me@example.com"},
+	}
+
+	channels := make([]models.NotificationChannel, 0)
+	for _, req := range requests {
+		rr, _ := srv.makeRequestWithToken(token, "POST", "/api/notifications/channels", req)
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusCreated, rr.Code, rr.Body.String())
+		}
+		var created models.NotificationChannel
+		json.Unmarshal(rr.Body.Bytes(), &created)
+		if !created.Enabled {
+			t.Error("Expected channel to be enabled by default")
+		}
+		channels = append(channels, created)
+	}
+
+	rr, _ = srv.makeRequestWithToken(token, "POST", "/api/notifications/test", nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	var results []models.TestNotificationResult
+	json.Unmarshal(rr.Body.Bytes(), &results)
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(results))
+	}
+	for _, result := range results {
+		wantSuccess := result.ChannelID != channels[1].ID
+		if result.Success != wantSuccess {
+			t.Errorf("Channel '%s': expected success %v, got %v (%s)", result.Name, wantSuccess, result.Success, result.Error)
+		}
+	}
+	if len(received()) != 1 || len(mails()) != 1 {
+		t.Errorf("Expected 1 webhook call and 1 email, got %d and %d", len(received()), len(mails()))
+	}
+
+	// A single channel can be tested on its own
+	rr, _ = srv.makeRequestWithToken(token, "POST", "/api/notifications/test", models.TestNotificationRequest{ChannelID: &channels[0].ID})
+	json.Unmarshal(rr.Body.Bytes(), &results)
+	if len(results) != 1 || !results[0].Success || len(received()) != 2 {
+		t.Errorf("Expected a single successful test, got %+v", results)
+	}
+
+	// Channels are private to their owner
+	for _, req := range []struct{ method, url string }{
+		{"PUT", fmt.Sprintf("/api/notifications/channels/%d", channels[0].ID)},
+		{"DELETE", fmt.Sprintf("/api/notifications/channels/%d", channels[0].ID)},
+	} {
+		rr, _ := srv.makeRequest(req.method, req.url, models.UpdateNotificationChannelRequest{})
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("%s %s: expected status %d, got %d", req.method, req.url, http.StatusNotFound, rr.Code)
+		}
+	}
+	rr, _ = srv.makeRequest("POST", "/api/notifications/test", models.TestNotificationRequest{ChannelID: &channels[0].ID})
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d when testing another user's channel, got %d", http.StatusNotFound, rr.Code)
+	}
+
+	disabled := false
+	rr, _ = srv.makeRequestWithToken(token, "PUT", fmt.Sprintf("/api/notifications/channels/%d", channels[1].ID), models.UpdateNotificationChannelRequest{Enabled: &disabled})
+	var updated models.NotificationChannel
+	json.Unmarshal(rr.Body.Bytes(), &updated)
+	if rr.Code != http.StatusOK || updated.Enabled {
+		t.Errorf("Expected channel to be disabled, got status %d and %+v", rr.Code, updated)
+	}
+
+	rr, _ = srv.makeRequestWithToken(token, "DELETE", fmt.Sprintf("/api/notifications/channels/%d", channels[2].ID), nil)
+	if rr.Code != http.StatusNoContent {
+		t.Errorf("Expected status %d, got %d", http.StatusNoContent, rr.Code)
+	}
+
+	rr, _ = srv.makeRequestWithToken(token, "GET", "/api/notifications/channels", nil)
+	var listed []models.NotificationChannel
+	json.Unmarshal(rr.Body.Bytes(), &listed)
+	if len(listed) != 2 {
+		t.Errorf("Expected 2 channels after delete, got %d", len(listed))
+	}
+}
+
+func TestDispatcherUsesTrackerChannels(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	token, userID := srv.registerUser(t, "dispatcher-user")
+	daily := models.Due{Type: models.INTERVAL, IntervalType: "day", IntervalValue: 1}
+	special := srv.createReminderHabit(t, token, "Special Habit", daily, "08:00")
+	regular := srv.createReminderHabit(t, token, "Regular Habit", daily, "08:00")
+
+	allServer, allReceived := startReceiver(t, http.StatusOK)
+	ownServer, ownReceived := startReceiver(t, http.StatusOK)
+
+	habitType := models.HABIT
+	srv.makeRequestWithToken(token, "POST", "/api/notifications/channels", models.CreateNotificationChannelRequest{
+		Name: "All trackers", Type: models.CHANNEL_NTFY, Target: allServer.URL + "/all",
+	})
+	rr, _ := srv.makeRequestWithToken(token, "POST", "/api/notifications/channels", models.CreateNotificationChannelRequest{
+		Name: "Special only", Type: models.CHANNEL_WEBHOOK, Target: ownServer.URL, TrackerType: &habitType, TrackerID: &special.ID,
+	})
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusCreated, rr.Code, rr.Body.String())
+	}
+
+	dispatcher := notify.NewDispatcher(srv.app.Store, notify.SMTPConfig{}, true, testLogger)
+	for _, h := range []int{special.ID, regular.ID} {
+		msg := testMessage()
+		msg.UserID = userID
+		msg.TrackerID = h
+		if err := dispatcher.Notify(context.Background(), msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(ownReceived()) != 1 {
+		t.Errorf("Expected tracker channel to receive 1 message, got %d", len(ownReceived()))
+	}
+	if len(allReceived()) != 1 {
+		t.Errorf("Expected general channel to receive only the other tracker's message, got %d", len(allReceived()))
+	}
+}
@@ -0,0 +1,118 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"routine-tracker/models"
+	"routine-tracker/trackers"
+	"routine-tracker/trackers/habit"
+	"routine-tracker/trackers/target"
+)
+
+// orderNames describes an ordered list of trackers as "type:name"
+func orderNames(response trackers.TrackersResponse) []string {
+	names := make(map[trackers.TrackerRef]string)
+	for _, h := range response.HabitTrackers {
+		names[trackers.TrackerRef{Type: models.HABIT, ID: h.ID}] = h.TrackerName
+	}
+	for _, t := range response.TargetTrackers {
+		names[trackers.TrackerRef{Type: models.TARGET, ID: t.ID}] = t.TrackerName
+	}
+	for _, d := range response.DurationTrackers {
+		names[trackers.TrackerRef{Type: models.DURATION, ID: d.ID}] = d.TrackerName
+	}
+
+	var ordered []string
+	for _, ref := range response.Order {
+		ordered = append(ordered, string(ref.Type)+":"+names[ref])
+	}
+	return ordered
+}
+
+func TestTrackerOrder(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	daily := models.Due{Type: models.INTERVAL, IntervalType: "day", IntervalValue: 1}
+
+	read := srv.createDatedHabit(t, "Read", "2024-01-01", daily)
+	walk := srv.createDatedHabit(t, "Walk", "2024-01-01", daily)
+	rr, _ := srv.makeRequest("POST", "/api/target-trackers", target.CreateTargetRequest{
+		TrackerName: "Savings", StartValue: 0, GoalValue: 1000, AddToTotal: true, StartDate: "2024-01-01", GoalDate: "2099-12-31", Due: daily,
+	})
+	var savings target.TargetTracker
+	json.Unmarshal(rr.Body.Bytes(), &savings)
+
+	rr, _ = srv.makeRequest("PUT", "/api/trackers/order", models.UpdateOrderRequest{Trackers: []models.TrackerPosition{
+		{Type: models.HABIT, ID: walk.ID, Position: 0},
+		{Type: models.TARGET, ID: savings.ID, Position: 1},
+		{Type: models.HABIT, ID: read.ID, Position: 2},
+	}})
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusNoContent, rr.Code, rr.Body.String())
+	}
+
+	// Habits and targets are interleaved in the combined order
+	want := "[habit:Walk target:Savings habit:Read]"
+	response := srv.trackers(t, "")
+	if got := fmt.Sprint(orderNames(response)); got != want {
+		t.Errorf("Expected order %s, got %s", want, got)
+	}
+	if len(response.HabitTrackers) != 2 || response.HabitTrackers[0].ID != walk.ID {
+		t.Errorf("Expected Walk to be the first habit, got %+v", response.HabitTrackers)
+	}
+	if len(response.Groups) != 1 || fmt.Sprint(response.Groups[0].Order) != fmt.Sprint(response.Order) {
+		t.Errorf("Expected the group to keep the order, got %+v", response.Groups)
+	}
+
+	// Pinned trackers come first
+	pinned := true
+	rr, _ = srv.makeRequest("PUT", fmt.Sprintf("/api/habit-trackers/%d", read.ID), habit.UpdateHabitRequest{Pinned: &pinned})
+	json.Unmarshal(rr.Body.Bytes(), &read)
+	if !read.Pinned || read.Position != 2 {
+		t.Errorf("Expected Read to be pinned at position 2, got %+v", read)
+	}
+	rr, _ = srv.makeRequest("GET", "/api/dashboard", nil)
+	var dashboard trackers.DashboardResponse
+	json.Unmarshal(rr.Body.Bytes(), &dashboard)
+	want = "[habit:Read habit:Walk target:Savings]"
+	if got := fmt.Sprint(orderNames(trackers.TrackersResponse{HabitTrackers: dashboard.HabitTrackers, TargetTrackers: dashboard.TargetTrackers, Order: dashboard.Order})); got != want {
+		t.Errorf("Expected dashboard order %s, got %s", want, got)
+	}
+
+	// A batch with an unknown tracker changes nothing
+	rr, _ = srv.makeRequest("PUT", "/api/trackers/order", models.UpdateOrderRequest{Trackers: []models.TrackerPosition{
+		{Type: models.HABIT, ID: walk.ID, Position: 5},
+		{Type: models.TARGET, ID: 999, Position: 0},
+	}})
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d for an unknown tracker, got %d", http.StatusNotFound, rr.Code)
+	}
+	rr, _ = srv.makeRequest("GET", fmt.Sprintf("/api/habit-trackers/%d", walk.ID), nil)
+	json.Unmarshal(rr.Body.Bytes(), &walk)
+	if walk.Position != 0 {
+		t.Errorf("Expected Walk to keep position 0, got %d", walk.Position)
+	}
+
+	for name, positions := range map[string][]models.TrackerPosition{
+		"no trackers":  {},
+		"invalid type": {{Type: "counter", ID: walk.ID}},
+		"duplicate":    {{Type: models.HABIT, ID: walk.ID}, {Type: models.HABIT, ID: walk.ID, Position: 1}},
+	} {
+		rr, _ := srv.makeRequest("PUT", "/api/trackers/order", models.UpdateOrderRequest{Trackers: positions})
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %s, got %d", http.StatusBadRequest, name, rr.Code)
+		}
+	}
+
+	// Only trackers that moved are in the audit log
+	if history := srv.auditEvents(t, srv.token, fmt.Sprintf("/api/habit-trackers/%d/history", walk.ID)); len(history) != 1 {
+		t.Errorf("Expected only the creation of Walk, which kept position 0, got %+v", history)
+	}
+	history := srv.auditEvents(t, srv.token, fmt.Sprintf("/api/target-trackers/%d/history", savings.ID))
+	if len(history) != 2 || history[0].Changes["position"].Before != 0.0 || history[0].Changes["position"].After != 1.0 {
+		t.Errorf("Expected the move of Savings from position 0 to 1, got %+v", history)
+	}
+}
@@ -0,0 +1,376 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"routine-tracker/models"
+	"routine-tracker/trackers"
+	"routine-tracker/trackers/habit"
+	"routine-tracker/trackers/target"
+)
+
+// Helper function to create a pause
+func (srv *testServer) createPause(t *testing.T, req models.CreatePauseRequest) models.Pause {
+	t.Helper()
+
+	rr, _ := srv.makeRequest("POST", "/api/pauses", req)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusCreated, rr.Code, rr.Body.String())
+	}
+
+	var pause models.Pause
+	json.Unmarshal(rr.Body.Bytes(), &pause)
+	return pause
+}
+
+// Helper function to get the stats of a habit on a day
+func (srv *testServer) habitStats(t *testing.T, id int, date string) habit.Stats {
+	t.Helper()
+
+	rr, _ := srv.makeRequest("GET", fmt.Sprintf("/api/habit-trackers/%d/stats?date=%s", id, date), nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	var stats habit.Stats
+	json.Unmarshal(rr.Body.Bytes(), &stats)
+	return stats
+}
+
+func TestPauseCRUD(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	h := srv.createDatedHabit(t, "Run", "2024-01-01", models.Due{Type: models.INTERVAL, IntervalType: "day", IntervalValue: 1})
+	habitType := models.HABIT
+	targetType := models.TARGET
+	unknownType := models.TrackerType("goal")
+
+	vacation := srv.createPause(t, models.CreatePauseRequest{StartDate: "2024-07-01", EndDate: "2024-07-14", Reason: "Vacation"})
+	if vacation.TrackerType != nil || vacation.Reason != "Vacation" || vacation.EndDate.Format("2006-01-02") != "2024-07-14" {
+		t.Errorf("Unexpected pause %+v", vacation)
+	}
+
+	// A single day by default
+	sick := srv.createPause(t, models.CreatePauseRequest{TrackerType: &habitType, TrackerID: &h.ID, StartDate: "2024-03-05"})
+	if sick.TrackerID == nil || *sick.TrackerID != h.ID || !sick.EndDate.Equal(sick.StartDate) {
+		t.Errorf("Unexpected pause %+v", sick)
+	}
+
+	missing := 9999
+	for _, tt := range []struct {
+		name string
+		req  models.CreatePauseRequest
+	}{
+		{"no start date", models.CreatePauseRequest{}},
+		{"invalid date", models.CreatePauseRequest{StartDate: "01-07-2024"}},
+		{"end before start", models.CreatePauseRequest{StartDate: "2024-07-14", EndDate: "2024-07-01"}},
+		{"type without ID", models.CreatePauseRequest{TrackerType: &habitType, StartDate: "2024-07-01"}},
+		{"unknown type", models.CreatePauseRequest{TrackerType: &unknownType, TrackerID: &h.ID, StartDate: "2024-07-01"}},
+		{"unknown tracker", models.CreatePauseRequest{TrackerType: &targetType, TrackerID: &missing, StartDate: "2024-07-01"}},
+	} {
+		rr, _ := srv.makeRequest("POST", "/api/pauses", tt.req)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status %d, got %d", tt.name, http.StatusBadRequest, rr.Code)
+		}
+	}
+
+	// Ordered by start date
+	rr, _ := srv.makeRequest("GET", "/api/pauses", nil)
+	var pauses []models.Pause
+	json.Unmarshal(rr.Body.Bytes(), &pauses)
+	if len(pauses) != 2 || pauses[0].ID != sick.ID || pauses[1].ID != vacation.ID {
+		t.Fatalf("Expected both pauses by start date, got %+v", pauses)
+	}
+
+	// End the vacation early
+	endDate := "2024-07-07"
+	rr, _ = srv.makeRequest("PUT", fmt.Sprintf("/api/pauses/%d", vacation.ID), models.UpdatePauseRequest{EndDate: &endDate})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	var updated models.Pause
+	json.Unmarshal(rr.Body.Bytes(), &updated)
+	if updated.EndDate.Format("2006-01-02") != endDate || updated.StartDate.Format("2006-01-02") != "2024-07-01" || updated.Reason != "Vacation" {
+		t.Errorf("Unexpected updated pause %+v", updated)
+	}
+
+	endDate = "2024-06-30"
+	rr, _ = srv.makeRequest("PUT", fmt.Sprintf("/api/pauses/%d", vacation.ID), models.UpdatePauseRequest{EndDate: &endDate})
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for an end before the start, got %d", http.StatusBadRequest, rr.Code)
+	}
+	rr, _ = srv.makeRequest("PUT", "/api/pauses/9999", models.UpdatePauseRequest{EndDate: &endDate})
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, rr.Code)
+	}
+
+	// Other users cannot see or delete the pauses
+	otherToken, err := srv.registerAndLogin("other", "password123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr, _ = srv.makeRequestWithToken(otherToken, "GET", "/api/pauses", nil)
+	json.Unmarshal(rr.Body.Bytes(), &pauses)
+	if len(pauses) != 0 {
+		t.Errorf("Expected no pauses of another user, got %d", len(pauses))
+	}
+	rr, _ = srv.makeRequestWithToken(otherToken, "DELETE", fmt.Sprintf("/api/pauses/%d", vacation.ID), nil)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, rr.Code)
+	}
+
+	rr, _ = srv.makeRequest("DELETE", fmt.Sprintf("/api/pauses/%d", vacation.ID), nil)
+	if rr.Code != http.StatusNoContent {
+		t.Errorf("Expected status %d, got %d", http.StatusNoContent, rr.Code)
+	}
+
+	// Deleting a tracker deletes its pauses
+	rr, _ = srv.makeRequest("DELETE", fmt.Sprintf("/api/habit-trackers/%d", h.ID), nil)
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("Expected status %d, got %d", http.StatusNoContent, rr.Code)
+	}
+	rr, _ = srv.makeRequest("GET", "/api/pauses", nil)
+	json.Unmarshal(rr.Body.Bytes(), &pauses)
+	if len(pauses) != 0 {
+		t.Errorf("Expected no pauses left, got %+v", pauses)
+	}
+}
+
+func TestPausedDaysAreNotDue(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	daily := models.Due{Type: models.INTERVAL, IntervalType: "day", IntervalValue: 1}
+	run := srv.createDatedHabit(t, "Run", "2024-01-01", daily)
+	srv.createDatedHabit(t, "Read", "2024-01-01", daily)
+	rr, _ := srv.makeRequest("POST", "/api/target-trackers", target.CreateTargetRequest{
+		TrackerName: "Weight",
+		StartValue:  80,
+		GoalValue:   70,
+		StartDate:   "2024-01-01",
+		GoalDate:    "2024-12-31",
+		Due:         daily,
+	})
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusCreated, rr.Code, rr.Body.String())
+	}
+
+	// Everything is paused on the 2nd, only running on the 3rd and 4th
+	habitType := models.HABIT
+	srv.createPause(t, models.CreatePauseRequest{StartDate: "2024-01-02", Reason: "Sick"})
+	srv.createPause(t, models.CreatePauseRequest{TrackerType: &habitType, TrackerID: &run.ID, StartDate: "2024-01-03", EndDate: "2024-01-04"})
+
+	// Reading is skipped on the 5th
+	skipped := true
+	rr, _ = srv.makeRequest("POST", "/api/habit-trackers/2/entries", models.AddEntryRequest{Skipped: &skipped, Date: "2024-01-05"})
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusCreated, rr.Code, rr.Body.String())
+	}
+	var entry models.Entry
+	json.Unmarshal(rr.Body.Bytes(), &entry)
+	if !entry.Skipped || entry.Done == nil || *entry.Done {
+		t.Errorf("Expected a skipped entry that is not done, got %+v", entry)
+	}
+
+	expected := []string{
+		"Read Run | Weight",
+		"|",
+		"Read | Weight",
+		"Read | Weight",
+		"Run | Weight",
+		"Read Run | Weight",
+	}
+	agenda := srv.agenda(t, "?from=2024-01-01&to=2024-01-06")
+	for i, day := range agenda.Days {
+		var names []string
+		for _, h := range day.HabitTrackers {
+			names = append(names, h.TrackerName)
+		}
+		names = append(names, "|")
+		for _, tg := range day.TargetTrackers {
+			names = append(names, tg.TrackerName)
+		}
+		got := strings.Join(names, " ")
+		if got != expected[i] && got != strings.Replace(expected[i], "Read Run", "Run Read", 1) {
+			t.Errorf("Expected %q on %s, got %q", expected[i], day.Date, got)
+		}
+
+		// The dashboard agrees with the agenda
+		rr, _ := srv.makeRequest("GET", "/api/dashboard?date="+day.Date, nil)
+		var dashboard trackers.DashboardResponse
+		json.Unmarshal(rr.Body.Bytes(), &dashboard)
+		if len(dashboard.HabitTrackers) != len(day.HabitTrackers) || len(dashboard.TargetTrackers) != len(day.TargetTrackers) {
+			t.Errorf("Dashboard of %s differs from the agenda", day.Date)
+		}
+	}
+
+	// Next due dates continue after the pauses
+	rr, _ = srv.makeRequest("GET", fmt.Sprintf("/api/habit-trackers/%d/next-due?date=2024-01-01&count=3", run.ID), nil)
+	var next trackers.NextDueResponse
+	json.Unmarshal(rr.Body.Bytes(), &next)
+	if got := strings.Join(next.Dates, " "); got != "2024-01-01 2024-01-05 2024-01-06" {
+		t.Errorf("Unexpected next due dates %q", got)
+	}
+}
+
+func TestPausesKeepStreaks(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	h := srv.createDatedHabit(t, "Run", "2024-01-01", models.Due{Type: models.INTERVAL, IntervalType: "day", IntervalValue: 1})
+
+	for _, date := range []string{"2024-01-01", "2024-01-02", "2024-01-05", "2024-01-06"} {
+		rr, _ := srv.makeRequest("POST", fmt.Sprintf("/api/habit-trackers/%d/entries", h.ID), models.AddEntryRequest{Date: date})
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusCreated, rr.Code, rr.Body.String())
+		}
+	}
+
+	// Two missed days break the streak
+	stats := srv.habitStats(t, h.ID, "2024-01-06")
+	if stats.CurrentStreak != 2 || stats.TotalPeriods != 6 || stats.CompletedPeriods != 4 {
+		t.Fatalf("Unexpected stats before pausing: current %d, %d of %d", stats.CurrentStreak, stats.CompletedPeriods, stats.TotalPeriods)
+	}
+
+	// A pause on the 3rd and a skipped 4th leave them out
+	srv.createPause(t, models.CreatePauseRequest{StartDate: "2024-01-03"})
+	skipped := true
+	rr, _ := srv.makeRequest("POST", fmt.Sprintf("/api/habit-trackers/%d/entries", h.ID), models.AddEntryRequest{Skipped: &skipped, Date: "2024-01-04"})
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusCreated, rr.Code, rr.Body.String())
+	}
+
+	stats = srv.habitStats(t, h.ID, "2024-01-06")
+	if stats.CurrentStreak != 4 || stats.BestStreak != 4 {
+		t.Errorf("Expected a streak of 4 across the days off, got current %d and best %d", stats.CurrentStreak, stats.BestStreak)
+	}
+	if stats.TotalPeriods != 4 || stats.CompletedPeriods != 4 || stats.SkippedPeriods != 2 || stats.CompletionRate != 1 {
+		t.Errorf("Expected 4 of 4 periods with 2 skipped, got %d of %d with %d skipped", stats.CompletedPeriods, stats.TotalPeriods, stats.SkippedPeriods)
+	}
+	if !stats.Periods[2].Skipped || stats.Periods[2].GoalMet || stats.Periods[2].DaysOff != 1 {
+		t.Errorf("Expected the paused period to be skipped, got %+v", stats.Periods[2])
+	}
+
+	// Doing it on a skipped day un-skips it
+	var entries []models.Entry
+	rr, _ = srv.makeRequest("GET", fmt.Sprintf("/api/habit-trackers/%d/entries", h.ID), nil)
+	json.Unmarshal(rr.Body.Bytes(), &entries)
+	var skippedID int
+	for _, e := range entries {
+		if e.Skipped {
+			skippedID = e.ID
+		}
+	}
+	done := true
+	rr, _ = srv.makeRequest("PUT", fmt.Sprintf("/api/entries/%d", skippedID), models.UpdateEntryRequest{Done: &done})
+	var entry models.Entry
+	json.Unmarshal(rr.Body.Bytes(), &entry)
+	if rr.Code != http.StatusOK || entry.Skipped || !*entry.Done {
+		t.Errorf("Expected a done entry, got %d %+v", rr.Code, entry)
+	}
+	if stats := srv.habitStats(t, h.ID, "2024-01-06"); stats.SkippedPeriods != 1 || stats.CurrentStreak != 5 {
+		t.Errorf("Expected one skipped period and a streak of 5, got %d and %d", stats.SkippedPeriods, stats.CurrentStreak)
+	}
+}
+
+func TestWeeklyGoalWithDaysOff(t *testing.T) {
+	t.Parallel()
+	tracker := habit.HabitTracker{ID: 1, Goal: 4, TimePeriod: models.PER_WEEK, StartDate: mustDate(t, "2024-01-01")}
+	entries := doneEntries("2024-01-01", "2024-01-02", "2024-01-08", "2024-01-09")
+	pauses := []models.Pause{{StartDate: mustDate(t, "2024-01-03"), EndDate: mustDate(t, "2024-01-07")}}
+	today := time.Date(2024, 1, 14, 12, 0, 0, 0, time.UTC)
+
+	// Five of seven days off leave a goal of two in the first week
+	periods := habit.CalculatePeriods(tracker, entries, models.NewDaysOff(pauses, nil, time.UTC), today)
+	if len(periods) != 2 || periods[0].DaysOff != 5 || periods[0].Skipped || !periods[0].GoalMet {
+		t.Errorf("Expected the first week to be met with 5 days off, got %+v", periods)
+	}
+	if periods[1].GoalMet {
+		t.Errorf("Expected the second week to miss the goal, got %+v", periods[1])
+	}
+}
+
+func TestSkippedEntryValidation(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	h := srv.createDatedHabit(t, "Run", "2024-01-01", models.Due{Type: models.INTERVAL, IntervalType: "day", IntervalValue: 1})
+	rr, _ := srv.makeRequest("POST", "/api/target-trackers", target.CreateTargetRequest{
+		TrackerName: "Weight", StartValue: 80, GoalValue: 70, StartDate: "2024-01-01", GoalDate: "2024-12-31",
+		Due: models.Due{Type: models.INTERVAL, IntervalType: "day", IntervalValue: 1},
+	})
+	var tg target.TargetTracker
+	json.Unmarshal(rr.Body.Bytes(), &tg)
+
+	skipped, done := true, true
+	rr, _ = srv.makeRequest("POST", fmt.Sprintf("/api/habit-trackers/%d/entries", h.ID), models.AddEntryRequest{Skipped: &skipped, Done: &done})
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for a done and skipped entry, got %d", http.StatusBadRequest, rr.Code)
+	}
+	rr, _ = srv.makeRequest("POST", fmt.Sprintf("/api/target-trackers/%d/entries", tg.ID), models.AddEntryRequest{Value: 79, Skipped: &skipped})
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for a skipped target entry, got %d", http.StatusBadRequest, rr.Code)
+	}
+
+	rr, _ = srv.makeRequest("POST", fmt.Sprintf("/api/target-trackers/%d/entries", tg.ID), models.AddEntryRequest{Value: 79})
+	var entry models.Entry
+	json.Unmarshal(rr.Body.Bytes(), &entry)
+	rr, _ = srv.makeRequest("PUT", fmt.Sprintf("/api/entries/%d", entry.ID), models.UpdateEntryRequest{Skipped: &skipped})
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d when skipping a target entry, got %d", http.StatusBadRequest, rr.Code)
+	}
+}
+
+func TestArchivePauses(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	daily := models.Due{Type: models.INTERVAL, IntervalType: "day", IntervalValue: 1}
+	srv.createDatedHabit(t, "Read", "2024-01-01", daily)
+	run := srv.createDatedHabit(t, "Run", "2024-01-01", daily)
+	habitType := models.HABIT
+	srv.createPause(t, models.CreatePauseRequest{StartDate: "2024-07-01", EndDate: "2024-07-14", Reason: "Vacation"})
+	srv.createPause(t, models.CreatePauseRequest{TrackerType: &habitType, TrackerID: &run.ID, StartDate: "2024-03-05"})
+	skipped := true
+	srv.makeRequest("POST", fmt.Sprintf("/api/habit-trackers/%d/entries", run.ID), models.AddEntryRequest{Skipped: &skipped, Date: "2024-03-06"})
+
+	archive := srv.exportArchive(t, srv.token)
+	if len(archive.Pauses) != 2 || len(archive.Entries) != 1 || !archive.Entries[0].Skipped {
+		t.Fatalf("Expected 2 pauses and a skipped entry in the export, got %+v and %+v", archive.Pauses, archive.Entries)
+	}
+
+	// Tracker IDs of pauses are remapped
+	destToken, err := srv.registerAndLogin("dest", "password123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr, _ := srv.makeRequestWithToken(destToken, "POST", "/api/habit-trackers", habit.CreateHabitRequest{
+		TrackerName: "Run", Goal: 1, TimePeriod: models.PER_DAY, StartDate: "2024-01-01", Due: daily,
+	})
+	var existing habit.HabitTracker
+	json.Unmarshal(rr.Body.Bytes(), &existing)
+
+	status, result := srv.importArchive(t, destToken, "?mode=merge", archive)
+	if status != http.StatusOK || result.PausesCreated != 2 {
+		t.Fatalf("Expected 2 pauses created, got %d %+v", status, result)
+	}
+	rr, _ = srv.makeRequestWithToken(destToken, "GET", "/api/pauses", nil)
+	var pauses []models.Pause
+	json.Unmarshal(rr.Body.Bytes(), &pauses)
+	if len(pauses) != 2 || pauses[0].TrackerID == nil || *pauses[0].TrackerID != existing.ID || pauses[1].Reason != "Vacation" {
+		t.Errorf("Unexpected imported pauses %+v", pauses)
+	}
+
+	// Importing again does not duplicate them
+	if _, result := srv.importArchive(t, destToken, "?mode=merge", archive); result.PausesCreated != 0 {
+		t.Errorf("Expected no new pauses, got %d", result.PausesCreated)
+	}
+
+	// Pauses of unknown trackers are rejected
+	missing := 9999
+	archive.Pauses = append(archive.Pauses, models.Pause{TrackerType: &habitType, TrackerID: &missing, StartDate: mustDate(t, "2024-01-01"), EndDate: mustDate(t, "2024-01-01")})
+	if status, _ := srv.importArchive(t, destToken, "?mode=merge", archive); status != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, status)
+	}
+}
@@ -0,0 +1,315 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+
+	"routine-tracker/analytics"
+	"routine-tracker/models"
+	"routine-tracker/trackers/target"
+)
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestLinearRegression(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name          string
+		points        []analytics.Point
+		weightType    analytics.WeightType
+		wantSlope     float64
+		wantIntercept float64
+	}{
+		{
+			name:       "not enough points",
+			points:     []analytics.Point{{X: 0, Y: 5}},
+			weightType: analytics.WEIGHT_NONE,
+		},
+		{
+			name:          "perfect line without weights",
+			points:        []analytics.Point{{X: 0, Y: 0}, {X: 1, Y: 2}, {X: 2, Y: 4}},
+			weightType:    analytics.WEIGHT_NONE,
+			wantSlope:     2,
+			wantIntercept: 0,
+		},
+		{
+			name:          "perfect line is not affected by weights",
+			points:        []analytics.Point{{X: 0, Y: 1}, {X: 1, Y: 3}, {X: 2, Y: 5}},
+			weightType:    analytics.WEIGHT_EXPONENTIAL_HIGH,
+			wantSlope:     2,
+			wantIntercept: 1,
+		},
+		{
+			name:          "unweighted fit",
+			points:        []analytics.Point{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 3}},
+			weightType:    analytics.WEIGHT_NONE,
+			wantSlope:     1.5,
+			wantIntercept: -0.5,
+		},
+		{
+			name:          "linear weights follow recent points",
+			points:        []analytics.Point{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 3}},
+			weightType:    analytics.WEIGHT_LINEAR,
+			wantSlope:     1.8,
+			wantIntercept: -0.9,
+		},
+		{
+			name:          "unknown weight type falls back to none",
+			points:        []analytics.Point{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 3}},
+			weightType:    "unknown",
+			wantSlope:     1.5,
+			wantIntercept: -0.5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			regression := analytics.LinearRegression(tt.points, tt.weightType)
+			if !almostEqual(regression.Slope, tt.wantSlope) {
+				t.Errorf("Expected slope %f, got %f", tt.wantSlope, regression.Slope)
+			}
+			if !almostEqual(regression.Intercept, tt.wantIntercept) {
+				t.Errorf("Expected intercept %f, got %f", tt.wantIntercept, regression.Intercept)
+			}
+		})
+	}
+}
+
+func TestProjectTarget(t *testing.T) {
+	t.Parallel()
+	date := func(s string) time.Time {
+		parsed, _ := time.Parse("2006-01-02", s)
+		return parsed
+	}
+	valueEntries := func(values ...float64) []models.Entry {
+		entries := make([]models.Entry, 0, len(values))
+		for i, value := range values {
+			entries = append(entries, models.Entry{Type: models.TARGET, Value: value, Date: date("2024-01-01").AddDate(0, 0, i)})
+		}
+		return entries
+	}
+
+	tests := []struct {
+		name              string
+		tracker           target.TargetTracker
+		entries           []models.Entry
+		now               time.Time
+		wantCurrent       float64
+		wantSlope         float64
+		wantIntercept     float64
+		wantCompletion    *time.Time
+		wantValueOnGoal   float64
+		wantOnPace        bool
+		wantRequiredDaily *float64
+		wantDaysUntilGoal int
+	}{
+		{
+			name: "additive target",
+			tracker: target.TargetTracker{
+				StartValue: 0, GoalValue: 100, AddToTotal: true,
+				StartDate: date("2024-01-01"), GoalDate: date("2024-04-10"),
+			},
+			entries:           valueEntries(10, 10, 10),
+			now:               date("2024-01-03"),
+			wantCurrent:       30,
+			wantSlope:         10,
+			wantIntercept:     10,
+			wantCompletion:    func() *time.Time { d := date("2024-01-10"); return &d }(),
+			wantValueOnGoal:   1010,
+			wantOnPace:        true,
+			wantRequiredDaily: func() *float64 { f := 70.0 / 98.0; return &f }(),
+			wantDaysUntilGoal: 98,
+		},
+		{
+			name: "decreasing replacement target",
+			tracker: target.TargetTracker{
+				StartValue: 80, GoalValue: 70,
+				StartDate: date("2024-01-01"), GoalDate: date("2024-02-01"),
+			},
+			entries:           valueEntries(80, 79, 78),
+			now:               date("2024-01-03"),
+			wantCurrent:       78,
+			wantSlope:         -1,
+			wantIntercept:     80,
+			wantCompletion:    func() *time.Time { d := date("2024-01-11"); return &d }(),
+			wantValueOnGoal:   49,
+			wantOnPace:        true,
+			wantRequiredDaily: func() *float64 { f := -8.0 / 29.0; return &f }(),
+			wantDaysUntilGoal: 29,
+		},
+		{
+			name: "trend moving away from the goal has no completion date",
+			tracker: target.TargetTracker{
+				StartValue: 50, GoalValue: 100,
+				StartDate: date("2024-01-01"), GoalDate: date("2024-01-11"),
+			},
+			entries:           valueEntries(50, 48, 46),
+			now:               date("2024-01-03"),
+			wantCurrent:       46,
+			wantSlope:         -2,
+			wantIntercept:     50,
+			wantValueOnGoal:   30,
+			wantOnPace:        false,
+			wantRequiredDaily: func() *float64 { f := 54.0 / 8.0; return &f }(),
+			wantDaysUntilGoal: 8,
+		},
+		{
+			name: "single entry falls back to average rate",
+			tracker: target.TargetTracker{
+				StartValue: 0, GoalValue: 100, AddToTotal: true,
+				StartDate: date("2024-01-01"), GoalDate: date("2024-01-21"),
+			},
+			entries:           valueEntries(20),
+			now:               date("2024-01-05"),
+			wantCurrent:       20,
+			wantSlope:         5,
+			wantIntercept:     0,
+			wantCompletion:    func() *time.Time { d := date("2024-01-21"); return &d }(),
+			wantValueOnGoal:   100,
+			wantOnPace:        true,
+			wantRequiredDaily: func() *float64 { f := 80.0 / 16.0; return &f }(),
+			wantDaysUntilGoal: 16,
+		},
+		{
+			name: "goal date passed has no required rate",
+			tracker: target.TargetTracker{
+				StartValue: 0, GoalValue: 10, AddToTotal: true,
+				StartDate: date("2024-01-01"), GoalDate: date("2024-01-03"),
+			},
+			entries:           valueEntries(1, 1, 1),
+			now:               date("2024-01-05"),
+			wantCurrent:       3,
+			wantSlope:         1,
+			wantIntercept:     1,
+			wantCompletion:    func() *time.Time { d := date("2024-01-10"); return &d }(),
+			wantValueOnGoal:   3,
+			wantOnPace:        false,
+			wantDaysUntilGoal: -2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projection := analytics.ProjectTarget(tt.tracker, tt.entries, tt.now)
+
+			if !almostEqual(projection.CurrentValue, tt.wantCurrent) {
+				t.Errorf("Expected current value %f, got %f", tt.wantCurrent, projection.CurrentValue)
+			}
+			if !almostEqual(projection.Slope, tt.wantSlope) {
+				t.Errorf("Expected slope %f, got %f", tt.wantSlope, projection.Slope)
+			}
+			if !almostEqual(projection.Intercept, tt.wantIntercept) {
+				t.Errorf("Expected intercept %f, got %f", tt.wantIntercept, projection.Intercept)
+			}
+			if !almostEqual(projection.ProjectedValueOnGoalDate, tt.wantValueOnGoal) {
+				t.Errorf("Expected projected value on goal date %f, got %f", tt.wantValueOnGoal, projection.ProjectedValueOnGoalDate)
+			}
+			if projection.OnPace != tt.wantOnPace {
+				t.Errorf("Expected on pace %v, got %v", tt.wantOnPace, projection.OnPace)
+			}
+			if projection.DaysUntilGoal != tt.wantDaysUntilGoal {
+				t.Errorf("Expected %d days until goal, got %d", tt.wantDaysUntilGoal, projection.DaysUntilGoal)
+			}
+
+			if tt.wantCompletion == nil {
+				if projection.ProjectedCompletionDate != nil {
+					t.Errorf("Expected no projected completion date, got %s", projection.ProjectedCompletionDate)
+				}
+			} else if projection.ProjectedCompletionDate == nil {
+				t.Errorf("Expected projected completion date %s, got none", tt.wantCompletion)
+			} else if projection.ProjectedCompletionDate.Sub(*tt.wantCompletion).Abs() > time.Second {
+				t.Errorf("Expected projected completion date %s, got %s", tt.wantCompletion, projection.ProjectedCompletionDate)
+			}
+
+			if tt.wantRequiredDaily == nil {
+				if projection.RequiredDailyRate != nil {
+					t.Errorf("Expected no required daily rate, got %f", *projection.RequiredDailyRate)
+				}
+			} else if projection.RequiredDailyRate == nil || !almostEqual(*projection.RequiredDailyRate, *tt.wantRequiredDaily) {
+				t.Errorf("Expected required daily rate %f, got %v", *tt.wantRequiredDaily, projection.RequiredDailyRate)
+			}
+		})
+	}
+}
+
+func TestGetTargetTrackerProjection(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	weightType := "linear"
+	targetRequest := target.CreateTargetRequest{
+		TrackerName:     "Projection Target",
+		StartValue:      0,
+		GoalValue:       100,
+		StartDate:       "2024-01-01",
+		GoalDate:        "2024-04-10",
+		AddToTotal:      true,
+		TrendWeightType: &weightType,
+		Due: models.Due{
+			Type:          models.INTERVAL,
+			IntervalType:  "day",
+			IntervalValue: 1,
+		},
+	}
+
+	createRr, err := srv.makeRequest("POST", "/api/target-trackers", targetRequest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var createdTarget target.TargetTracker
+	json.Unmarshal(createRr.Body.Bytes(), &createdTarget)
+
+	for _, date := range []string{"2024-01-01T00:00:00Z", "2024-01-02T00:00:00Z", "2024-01-03T00:00:00Z"} {
+		entryRequest := models.AddEntryRequest{Value: 10, Date: date}
+		srv.makeRequest("POST", fmt.Sprintf("/api/target-trackers/%d/entries", createdTarget.ID), entryRequest)
+	}
+
+	rr, err := srv.makeRequest("GET", fmt.Sprintf("/api/target-trackers/%d/projection?date=2024-01-03", createdTarget.ID), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	var projection analytics.Projection
+	if err := json.Unmarshal(rr.Body.Bytes(), &projection); err != nil {
+		t.Fatalf("Failed to parse projection response: %v", err)
+	}
+
+	if projection.TrendWeightType != analytics.WEIGHT_LINEAR {
+		t.Errorf("Expected trend weight type 'linear', got '%s'", projection.TrendWeightType)
+	}
+	if projection.DataPoints != 3 {
+		t.Errorf("Expected 3 data points, got %d", projection.DataPoints)
+	}
+	if !almostEqual(projection.CurrentValue, 30) {
+		t.Errorf("Expected current value 30, got %f", projection.CurrentValue)
+	}
+	if !almostEqual(projection.Slope, 10) {
+		t.Errorf("Expected slope 10, got %f", projection.Slope)
+	}
+	if projection.ProjectedCompletionDate == nil || projection.ProjectedCompletionDate.Format("2006-01-02") != "2024-01-10" {
+		t.Errorf("Expected projected completion date 2024-01-10, got %v", projection.ProjectedCompletionDate)
+	}
+}
+
+func TestGetTargetTrackerProjectionNotFound(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	rr, err := srv.makeRequest("GET", "/api/target-trackers/99999/projection", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, rr.Code)
+	}
+}
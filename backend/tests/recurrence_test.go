@@ -0,0 +1,296 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"routine-tracker/models"
+	"routine-tracker/recurrence"
+	"routine-tracker/trackers"
+	"routine-tracker/trackers/habit"
+)
+
+func mustDate(t *testing.T, value string) time.Time {
+	t.Helper()
+	d, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func formatDates(dates []time.Time) string {
+	s := make([]string, len(dates))
+	for i, d := range dates {
+		s[i] = d.Format("2006-01-02")
+	}
+	return strings.Join(s, " ")
+}
+
+func TestRRuleExpansion(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		rule     string
+		start    string
+		from     string
+		to       string
+		expected string
+	}{
+		{"first Monday of each month", "FREQ=MONTHLY;BYDAY=1MO", "2024-01-01", "2024-01-01", "2024-04-30",
+			"2024-01-01 2024-02-05 2024-03-04 2024-04-01"},
+		{"every 2nd Tuesday and Thursday", "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,TH", "2024-01-01", "2024-01-01", "2024-01-31",
+			"2024-01-02 2024-01-04 2024-01-16 2024-01-18 2024-01-30"},
+		{"last day of month", "FREQ=MONTHLY;BYMONTHDAY=-1", "2024-01-15", "2024-01-01", "2024-04-30",
+			"2024-01-31 2024-02-29 2024-03-31 2024-04-30"},
+		{"last weekday of month", "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1", "2024-01-01", "2024-01-01", "2024-03-31",
+			"2024-01-31 2024-02-29 2024-03-29"},
+		{"fourth Thursday of November", "FREQ=YEARLY;BYMONTH=11;BYDAY=4TH", "2024-01-01", "2024-01-01", "2025-12-31",
+			"2024-11-28 2025-11-27"},
+		{"count", "FREQ=DAILY;COUNT=3", "2024-01-10", "2024-01-01", "2024-01-31",
+			"2024-01-10 2024-01-11 2024-01-12"},
+		{"count before the range", "FREQ=MONTHLY;BYDAY=1MO;COUNT=2", "2024-01-01", "2024-02-01", "2024-12-31",
+			"2024-02-05"},
+		{"until", "FREQ=WEEKLY;BYDAY=MO;UNTIL=20240115T235959Z", "2024-01-01", "2024-01-01", "2024-02-29",
+			"2024-01-01 2024-01-08 2024-01-15"},
+		{"interval from a later date", "FREQ=DAILY;INTERVAL=10", "2024-01-01", "2024-03-01", "2024-03-31",
+			"2024-03-01 2024-03-11 2024-03-21 2024-03-31"},
+		{"monthly skips months without the day", "FREQ=MONTHLY", "2024-01-31", "2024-01-01", "2024-05-31",
+			"2024-01-31 2024-03-31 2024-05-31"},
+		{"leap day", "RRULE:FREQ=YEARLY", "2024-02-29", "2024-01-01", "2028-12-31",
+			"2024-02-29 2028-02-29"},
+		{"start date not matching the rule", "FREQ=WEEKLY;BYDAY=FR", "2024-01-01", "2024-01-01", "2024-01-14",
+			"2024-01-05 2024-01-12"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := recurrence.Parse(tt.rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+			dates := rule.Between(mustDate(t, tt.start), mustDate(t, tt.from), mustDate(t, tt.to))
+			if got := formatDates(dates); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRRuleNext(t *testing.T) {
+	t.Parallel()
+	rule, _ := recurrence.Parse("FREQ=MONTHLY;BYDAY=-1FR")
+	dates := rule.Next(mustDate(t, "2024-01-01"), mustDate(t, "2024-05-01"), 3)
+	if got := formatDates(dates); got != "2024-05-31 2024-06-28 2024-07-26" {
+		t.Errorf("Unexpected next occurrences %q", got)
+	}
+
+	// A rule without any occurrence gives up instead of looping forever
+	never, _ := recurrence.Parse("FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30")
+	if dates := never.Next(mustDate(t, "2024-01-01"), mustDate(t, "2024-01-01"), 1); len(dates) != 0 {
+		t.Errorf("Expected no occurrences, got %v", formatDates(dates))
+	}
+}
+
+func TestRRuleParsing(t *testing.T) {
+	t.Parallel()
+	rule, err := recurrence.Parse("rrule:freq=monthly;byday=+1mo;interval=1;wkst=MO")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rule.String() != "FREQ=MONTHLY;BYDAY=1MO" {
+		t.Errorf("Unexpected canonical rule %q", rule.String())
+	}
+
+	invalid := []string{
+		"",
+		"BYDAY=MO",
+		"FREQ=HOURLY",
+		"FREQ=FORTNIGHTLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=2;UNTIL=20240101",
+		"FREQ=DAILY;FREQ=WEEKLY",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=WEEKLY;BYMONTHDAY=15",
+		"FREQ=MONTHLY;BYDAY=6MO",
+		"FREQ=MONTHLY;BYDAY=XX",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=YEARLY;BYMONTH=13",
+		"FREQ=DAILY;BYSETPOS=1",
+		"FREQ=DAILY;BYHOUR=9",
+		"FREQ=DAILY;UNTIL=tomorrow",
+		"FREQ=DAILY;COLOR=RED",
+	}
+	for _, s := range invalid {
+		if _, err := recurrence.Parse(s); err == nil {
+			t.Errorf("Expected %q to be rejected", s)
+		}
+	}
+}
+
+func TestLegacyDueAsRRule(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		due      models.Due
+		expected string
+	}{
+		{models.Due{Type: models.SPECIFIC_DAYS, SpecificDays: []string{"Monday", "wednesday"}}, "FREQ=WEEKLY;BYDAY=MO,WE"},
+		{models.Due{Type: models.INTERVAL, IntervalType: "day", IntervalValue: 3}, "FREQ=DAILY;INTERVAL=3"},
+		{models.Due{Type: models.INTERVAL, IntervalType: "week", IntervalValue: 2}, "FREQ=WEEKLY;INTERVAL=2"},
+		{models.Due{Type: models.INTERVAL, IntervalType: "month", IntervalValue: 1}, "FREQ=MONTHLY"},
+		{models.Due{Type: models.INTERVAL, IntervalType: "year", IntervalValue: 1}, "FREQ=YEARLY"},
+	}
+
+	start := mustDate(t, "2024-01-15")
+	for _, tt := range tests {
+		rule, err := trackers.DueRule(tt.due)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rule.String() != tt.expected {
+			t.Errorf("Expected %+v to be %q, got %q", tt.due, tt.expected, rule.String())
+		}
+
+		// The RRULE is due on exactly the same days
+		equivalent := models.Due{Type: models.RRULE, RRule: rule.String()}
+		for day := start.AddDate(0, 0, -7); day.Year() < 2026; day = day.AddDate(0, 0, 1) {
+			if trackers.IsTrackerDueToday(tt.due, start, day) != trackers.IsTrackerDueToday(equivalent, start, day) {
+				t.Errorf("%q differs from %+v on %s", rule.String(), tt.due, day.Format("2006-01-02"))
+				break
+			}
+		}
+	}
+
+	// Schedules without any days are never due
+	for _, due := range []models.Due{
+		{Type: models.SPECIFIC_DAYS},
+		{Type: models.INTERVAL, IntervalType: "day"},
+		{Type: models.INTERVAL, IntervalType: "fortnight", IntervalValue: 1},
+	} {
+		if trackers.IsTrackerDueToday(due, start, start) {
+			t.Errorf("Expected %+v to never be due", due)
+		}
+	}
+}
+
+func TestMonthlyIntervalUsesDayOfMonth(t *testing.T) {
+	t.Parallel()
+	due := models.Due{Type: models.INTERVAL, IntervalType: "month", IntervalValue: 2}
+	start := mustDate(t, "2024-01-15")
+
+	for day, expected := range map[string]bool{
+		"2024-01-15": true,
+		"2024-02-15": false,
+		"2024-03-14": false,
+		"2024-03-15": true,
+		"2024-03-16": false,
+		"2025-01-15": true,
+	} {
+		if got := trackers.IsTrackerDueToday(due, start, mustDate(t, day)); got != expected {
+			t.Errorf("Expected due %v on %s, got %v", expected, day, got)
+		}
+	}
+}
+
+func TestWeeklyIntervalUsesStartWeekday(t *testing.T) {
+	t.Parallel()
+	due := models.Due{Type: models.INTERVAL, IntervalType: "week", IntervalValue: 2}
+	wholeWeek := models.Due{Type: models.RRULE, RRule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TU,WE,TH,FR,SA,SU"}
+	start := mustDate(t, "2024-01-15")
+
+	// Interval trackers are due on a single day, every day of the week takes a rule
+	for day, expected := range map[string][2]bool{
+		"2024-01-15": {true, true},
+		"2024-01-17": {false, true},
+		"2024-01-21": {false, true},
+		"2024-01-22": {false, false},
+		"2024-01-29": {true, true},
+		"2024-02-02": {false, true},
+	} {
+		date := mustDate(t, day)
+		if got := trackers.IsTrackerDueToday(due, start, date); got != expected[0] {
+			t.Errorf("Expected the interval to be due %v on %s, got %v", expected[0], day, got)
+		}
+		if got := trackers.IsTrackerDueToday(wholeWeek, start, date); got != expected[1] {
+			t.Errorf("Expected the whole week rule to be due %v on %s, got %v", expected[1], day, got)
+		}
+	}
+}
+
+func TestRRuleTracker(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+
+	due := models.Due{Type: models.RRULE, RRule: "FREQ=MONTHLY;BYDAY=1MO"}
+	h := srv.createDatedHabit(t, "Monthly Review", "2024-01-01", due)
+	if h.Due.Type != models.RRULE || h.Due.RRule != due.RRule {
+		t.Errorf("Expected due %+v, got %+v", due, h.Due)
+	}
+
+	for date, expected := range map[string]int{"2024-02-05": 1, "2024-02-06": 0, "2024-03-04": 1} {
+		rr, _ := srv.makeRequest("GET", "/api/dashboard?date="+date, nil)
+		var dashboard trackers.DashboardResponse
+		json.Unmarshal(rr.Body.Bytes(), &dashboard)
+		if len(dashboard.HabitTrackers) != expected {
+			t.Errorf("Expected %d trackers due on %s, got %d", expected, date, len(dashboard.HabitTrackers))
+		}
+	}
+
+	// Invalid rules are rejected when creating and updating
+	rr, _ := srv.makeRequest("POST", "/api/habit-trackers", habit.CreateHabitRequest{
+		TrackerName: "Broken",
+		Goal:        1,
+		TimePeriod:  models.PER_DAY,
+		StartDate:   "2024-01-01",
+		Due:         models.Due{Type: models.RRULE, RRule: "FREQ=MONTHLY;BYDAY=9MO"},
+	})
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for invalid rule, got %d", http.StatusBadRequest, rr.Code)
+	}
+
+	url := fmt.Sprintf("/api/habit-trackers/%d", h.ID)
+	rr, _ = srv.makeRequest("PUT", url, habit.UpdateHabitRequest{Due: &models.Due{Type: models.RRULE}})
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for empty rule, got %d", http.StatusBadRequest, rr.Code)
+	}
+
+	// Updating the rule is stored
+	rr, _ = srv.makeRequest("PUT", url, habit.UpdateHabitRequest{Due: &models.Due{Type: models.RRULE, RRule: "FREQ=MONTHLY;BYMONTHDAY=-1"}})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	var updated habit.HabitTracker
+	json.Unmarshal(rr.Body.Bytes(), &updated)
+	if updated.Due.RRule != "FREQ=MONTHLY;BYMONTHDAY=-1" {
+		t.Errorf("Expected updated rule, got %q", updated.Due.RRule)
+	}
+}
+
+func TestInvalidDuesAreRejected(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+
+	dues := map[string]models.Due{
+		"zero interval":   {Type: models.INTERVAL, IntervalType: "week", IntervalValue: 0},
+		"unknown period":  {Type: models.INTERVAL, IntervalType: "fortnight", IntervalValue: 1},
+		"unknown weekday": {Type: models.SPECIFIC_DAYS, SpecificDays: []string{"monday", "funday"}},
+		"no weekdays":     {Type: models.SPECIFIC_DAYS},
+		"unknown type":    {Type: "sometimes"},
+	}
+	for name, due := range dues {
+		rr, _ := srv.makeRequest("POST", "/api/habit-trackers", habit.CreateHabitRequest{
+			TrackerName: name,
+			Goal:        1,
+			TimePeriod:  models.PER_DAY,
+			StartDate:   "2024-01-01",
+			Due:         due,
+		})
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %s, got %d", http.StatusBadRequest, name, rr.Code)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"routine-tracker/database"
+	"routine-tracker/models"
+	"routine-tracker/notify"
+	"routine-tracker/reminders"
+	"routine-tracker/trackers/habit"
+	"routine-tracker/trackers/target"
+)
+
+// recordingNotifier remembers the messages sent to a single user
+type recordingNotifier struct {
+	mu       sync.Mutex
+	userID   int
+	fail     bool
+	messages []notify.Message
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, msg notify.Message) error {
+	if msg.UserID != n.userID {
+		return nil
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.fail {
+		return errors.New("delivery failed")
+	}
+	n.messages = append(n.messages, msg)
+	return nil
+}
+
+func (n *recordingNotifier) sent() []notify.Message {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]notify.Message(nil), n.messages...)
+}
+
+// scheduler returns a reminder scheduler of the server sending to notifier
+func (srv *testServer) scheduler(notifier notify.Notifier) *reminders.Scheduler {
+	scheduler := reminders.NewScheduler(srv.app)
+	scheduler.Notifier = notifier
+	return scheduler
+}
+
+// Helper function to register a user and return its token and ID
+func (srv *testServer) registerUser(t *testing.T, username string) (string, int) {
+	t.Helper()
+
+	token, err := srv.registerAndLogin(username, username+"-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr, _ := srv.makeRequestWithToken(token, "GET", "/api/auth/me", nil)
+	var user models.User
+	json.Unmarshal(rr.Body.Bytes(), &user)
+	return token, user.ID
+}
+
+func (srv *testServer) createReminderHabit(t *testing.T, token, name string, due models.Due, times ...string) habit.HabitTracker {
+	t.Helper()
+
+	request := habit.CreateHabitRequest{
+		TrackerName: name,
+		Goal:        1,
+		TimePeriod:  models.PER_DAY,
+		StartDate:   time.Now().AddDate(0, 0, -7).Format("2006-01-02"),
+		Due:         due,
+		Reminders:   models.Reminder{Enabled: len(times) > 0, Times: times},
+	}
+
+	rr, _ := srv.makeRequestWithToken(token, "POST", "/api/habit-trackers", request)
+	var created habit.HabitTracker
+	json.Unmarshal(rr.Body.Bytes(), &created)
+	return created
+}
+
+func TestReminderScheduler(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	token, userID := srv.registerUser(t, "reminder-user")
+
+	// Users are in UTC unless they choose another time zone
+	now := time.Now().UTC()
+	now = time.Date(now.Year(), now.Month(), now.Day(), 12, 0, 0, 0, time.UTC)
+	daily := models.Due{Type: models.INTERVAL, IntervalType: "day", IntervalValue: 1}
+	otherDay := models.Due{Type: models.SPECIFIC_DAYS, SpecificDays: []string{now.AddDate(0, 0, 1).Weekday().String()}}
+
+	due := srv.createReminderHabit(t, token, "Due Habit", daily, "11:30", "13:00", "09:00")
+	srv.createReminderHabit(t, token, "Not Due Habit", otherDay, "11:30")
+	srv.createReminderHabit(t, token, "Silent Habit", daily)
+
+	done := srv.createReminderHabit(t, token, "Done Habit", daily, "11:30")
+	srv.makeRequestWithToken(token, "POST", fmt.Sprintf("/api/habit-trackers/%d/entries", done.ID), models.AddEntryRequest{})
+
+	targetRequest := target.CreateTargetRequest{
+		TrackerName: "Due Target",
+		StartValue:  0,
+		GoalValue:   100,
+		StartDate:   time.Now().AddDate(0, 0, -7).Format("2006-01-02"),
+		GoalDate:    time.Now().AddDate(0, 1, 0).Format("2006-01-02"),
+		AddToTotal:  true,
+		Due:         daily,
+		Reminders:   models.Reminder{Enabled: true, Times: []string{"11:45"}},
+	}
+	rr, _ := srv.makeRequestWithToken(token, "POST", "/api/target-trackers", targetRequest)
+	var dueTarget target.TargetTracker
+	json.Unmarshal(rr.Body.Bytes(), &dueTarget)
+
+	notifier := &recordingNotifier{userID: userID}
+	scheduler := srv.scheduler(notifier)
+	if err := scheduler.Tick(context.Background(), now); err != nil {
+		t.Fatal(err)
+	}
+
+	sent := notifier.sent()
+	if len(sent) != 2 {
+		t.Fatalf("Expected 2 reminders, got %d: %+v", len(sent), sent)
+	}
+
+	expected := map[string]int{"Due Habit": due.ID, "Due Target": dueTarget.ID}
+	for _, msg := range sent {
+		id, ok := expected[msg.TrackerName]
+		if !ok || msg.TrackerID != id {
+			t.Errorf("Unexpected reminder for '%s' (ID %d)", msg.TrackerName, msg.TrackerID)
+		}
+	}
+
+	// Later ticks and a restarted scheduler do not send the same reminders again
+	scheduler.Tick(context.Background(), now.Add(time.Minute))
+	srv.scheduler(notifier).Tick(context.Background(), now.Add(2*time.Minute))
+	if len(notifier.sent()) != 2 {
+		t.Errorf("Expected reminders not to be resent, got %d", len(notifier.sent()))
+	}
+
+	// The next reminder time is sent once it has come
+	scheduler.Tick(context.Background(), now.Add(time.Hour))
+	sent = notifier.sent()
+	if len(sent) != 3 || !sent[2].ScheduledFor.Equal(now.Add(time.Hour)) {
+		t.Errorf("Expected the 13:00 reminder to be sent, got %+v", sent)
+	}
+}
+
+func TestReminderSchedulerRetriesFailedDelivery(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	token, userID := srv.registerUser(t, "reminder-retry-user")
+
+	// Users are in UTC unless they choose another time zone
+	now := time.Now().UTC()
+	now = time.Date(now.Year(), now.Month(), now.Day(), 12, 0, 0, 0, time.UTC)
+	srv.createReminderHabit(t, token, "Flaky Habit", models.Due{Type: models.INTERVAL, IntervalType: "day", IntervalValue: 1}, "12:00")
+
+	notifier := &recordingNotifier{userID: userID, fail: true}
+	scheduler := srv.scheduler(notifier)
+	scheduler.Tick(context.Background(), now)
+	if len(notifier.sent()) != 0 {
+		t.Fatal("Expected failed delivery not to be recorded")
+	}
+
+	notifier.fail = false
+	scheduler.Tick(context.Background(), now.Add(time.Minute))
+	if len(notifier.sent()) != 1 {
+		t.Errorf("Expected failed reminder to be retried, got %d messages", len(notifier.sent()))
+	}
+}
+
+// failingUserStore fails to look up every user other than userID
+type failingUserStore struct {
+	database.Store
+	userID int
+}
+
+func (s failingUserStore) GetUserByID(id int) (*models.User, error) {
+	if id != s.userID {
+		return nil, errors.New("lookup failed")
+	}
+	return s.Store.GetUserByID(id)
+}
+
+func TestReminderSchedulerSkipsFailingUsers(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	token, userID := srv.registerUser(t, "reminder-survivor")
+	srv.registerUser(t, "reminder-broken")
+
+	now := time.Now().UTC()
+	now = time.Date(now.Year(), now.Month(), now.Day(), 12, 0, 0, 0, time.UTC)
+	srv.createReminderHabit(t, token, "Survivor Habit", models.Due{Type: models.INTERVAL, IntervalType: "day", IntervalValue: 1}, "12:00")
+
+	notifier := &recordingNotifier{userID: userID}
+	scheduler := srv.scheduler(notifier)
+	scheduler.Store = failingUserStore{srv.app.Store, userID}
+	if err := scheduler.Tick(context.Background(), now); err != nil {
+		t.Fatalf("Expected failures of other users not to fail the tick, got %v", err)
+	}
+	if len(notifier.sent()) != 1 {
+		t.Errorf("Expected the reminder of the working user to be sent, got %+v", notifier.sent())
+	}
+}
+
+func TestReminderSchedulerInUserTimeZone(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	token, userID := srv.registerUser(t, "reminder-tokyo-user")
+
+	timeZone := "Asia/Tokyo"
+	srv.makeRequestWithToken(token, "PUT", "/api/auth/me", models.UpdateUserRequest{TimeZone: &timeZone})
+	daily := models.Due{Type: models.INTERVAL, IntervalType: "day", IntervalValue: 1}
+	srv.createReminderHabit(t, token, "Morning Habit", daily, "09:00")
+
+	// 00:30 in UTC is 09:30 in Tokyo
+	now := time.Now().UTC()
+	now = time.Date(now.Year(), now.Month(), now.Day(), 0, 30, 0, 0, time.UTC)
+	notifier := &recordingNotifier{userID: userID}
+	if err := srv.scheduler(notifier).Tick(context.Background(), now); err != nil {
+		t.Fatal(err)
+	}
+
+	sent := notifier.sent()
+	if len(sent) != 1 || !sent[0].ScheduledFor.Equal(now.Add(-30*time.Minute)) {
+		t.Errorf("Expected the 09:00 reminder in Tokyo time, got %+v", sent)
+	}
+}
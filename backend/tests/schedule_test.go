@@ -0,0 +1,185 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"routine-tracker/models"
+	"routine-tracker/trackers"
+	"routine-tracker/trackers/target"
+)
+
+// Helper function to request the agenda of a date range
+func (srv *testServer) agenda(t *testing.T, query string) trackers.AgendaResponse {
+	t.Helper()
+
+	rr, _ := srv.makeRequest("GET", "/api/agenda"+query, nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	var agenda trackers.AgendaResponse
+	json.Unmarshal(rr.Body.Bytes(), &agenda)
+	return agenda
+}
+
+func TestAgenda(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+
+	srv.createDatedHabit(t, "Gym", "2024-01-01", models.Due{Type: models.SPECIFIC_DAYS, SpecificDays: []string{"monday", "wednesday"}})
+	srv.createDatedHabit(t, "Monthly Review", "2024-01-01", models.Due{Type: models.RRULE, RRule: "FREQ=MONTHLY;BYDAY=1MO"})
+	rr, _ := srv.makeRequest("POST", "/api/target-trackers", target.CreateTargetRequest{
+		TrackerName: "Weight",
+		StartValue:  80,
+		GoalValue:   70,
+		StartDate:   "2024-01-01",
+		GoalDate:    "2024-12-31",
+		Due:         models.Due{Type: models.INTERVAL, IntervalType: "day", IntervalValue: 2},
+	})
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusCreated, rr.Code, rr.Body.String())
+	}
+
+	agenda := srv.agenda(t, "?from=2024-01-01&to=2024-01-07")
+	if agenda.From != "2024-01-01" || agenda.To != "2024-01-07" || len(agenda.Days) != 7 {
+		t.Fatalf("Expected 7 days from 2024-01-01 to 2024-01-07, got %+v", agenda)
+	}
+
+	expected := []string{
+		"Gym Monthly Review | Weight",
+		"|",
+		"Gym | Weight",
+		"|",
+		"| Weight",
+		"|",
+		"| Weight",
+	}
+	for i, day := range agenda.Days {
+		var names []string
+		for _, h := range day.HabitTrackers {
+			names = append(names, h.TrackerName)
+		}
+		sort.Strings(names)
+		names = append(names, "|")
+		for _, tg := range day.TargetTrackers {
+			if tg.CurrentValue == nil {
+				t.Errorf("Expected current value of %s on %s", tg.TrackerName, day.Date)
+			}
+			names = append(names, tg.TrackerName)
+		}
+		if got := strings.Join(names, " "); got != expected[i] {
+			t.Errorf("Expected %q on %s, got %q", expected[i], day.Date, got)
+		}
+	}
+
+	// The agenda agrees with the dashboard of each day
+	for _, day := range agenda.Days {
+		rr, _ := srv.makeRequest("GET", "/api/dashboard?date="+day.Date, nil)
+		var dashboard trackers.DashboardResponse
+		json.Unmarshal(rr.Body.Bytes(), &dashboard)
+		if len(dashboard.HabitTrackers) != len(day.HabitTrackers) || len(dashboard.TargetTrackers) != len(day.TargetTrackers) {
+			t.Errorf("Dashboard of %s differs from the agenda", day.Date)
+		}
+	}
+}
+
+func TestAgendaDefaultsAndValidation(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	srv.setTimeZone(t, "Pacific/Auckland")
+	srv.app.Clock = fixedClock{time.Date(2024, 3, 10, 20, 0, 0, 0, time.UTC)}
+
+	// A week starting today in the user's time zone
+	agenda := srv.agenda(t, "")
+	if agenda.From != "2024-03-11" || agenda.To != "2024-03-17" || len(agenda.Days) != 7 {
+		t.Errorf("Expected the week from 2024-03-11, got %s to %s with %d days", agenda.From, agenda.To, len(agenda.Days))
+	}
+	if agenda.Days[0].HabitTrackers == nil || agenda.Days[0].TargetTrackers == nil {
+		t.Error("Expected empty lists for days without trackers")
+	}
+
+	for _, query := range []string{
+		"?from=2024-03-10&to=2024-03-09",
+		"?from=10-03-2024",
+		"?to=tomorrow",
+		"?from=2024-01-01&to=2025-01-01",
+	} {
+		rr, _ := srv.makeRequest("GET", "/api/agenda"+query, nil)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %s, got %d", http.StatusBadRequest, query, rr.Code)
+		}
+	}
+
+	// A whole leap year is allowed
+	if agenda := srv.agenda(t, "?from=2024-01-01&to=2024-12-31"); len(agenda.Days) != 366 {
+		t.Errorf("Expected 366 days, got %d", len(agenda.Days))
+	}
+}
+
+func TestNextDue(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+
+	h := srv.createDatedHabit(t, "Last Friday", "2024-01-01", models.Due{Type: models.RRULE, RRule: "FREQ=MONTHLY;BYDAY=-1FR"})
+	url := fmt.Sprintf("/api/habit-trackers/%d/next-due", h.ID)
+
+	rr, _ := srv.makeRequest("GET", url+"?date=2024-05-01&count=3", nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	var next trackers.NextDueResponse
+	json.Unmarshal(rr.Body.Bytes(), &next)
+	if next.TrackerID != h.ID || next.Type != models.HABIT || next.From != "2024-05-01" {
+		t.Errorf("Unexpected response %+v", next)
+	}
+	if got := strings.Join(next.Dates, " "); got != "2024-05-31 2024-06-28 2024-07-26" {
+		t.Errorf("Unexpected next due dates %q", got)
+	}
+
+	// Five dates by default, and none before the start date
+	rr, _ = srv.makeRequest("GET", url+"?date=2023-06-01", nil)
+	json.Unmarshal(rr.Body.Bytes(), &next)
+	if len(next.Dates) != 5 || next.Dates[0] != "2024-01-26" {
+		t.Errorf("Expected 5 dates from 2024-01-26, got %v", next.Dates)
+	}
+
+	// Rules that end have fewer dates
+	rr, _ = srv.makeRequest("POST", "/api/target-trackers", target.CreateTargetRequest{
+		TrackerName: "Three Weigh-ins",
+		StartValue:  80,
+		GoalValue:   70,
+		StartDate:   "2024-01-01",
+		GoalDate:    "2024-12-31",
+		Due:         models.Due{Type: models.RRULE, RRule: "FREQ=WEEKLY;COUNT=3"},
+	})
+	var tg target.TargetTracker
+	json.Unmarshal(rr.Body.Bytes(), &tg)
+	rr, _ = srv.makeRequest("GET", fmt.Sprintf("/api/target-trackers/%d/next-due?date=2024-01-02&count=10", tg.ID), nil)
+	json.Unmarshal(rr.Body.Bytes(), &next)
+	if next.Type != models.TARGET || strings.Join(next.Dates, " ") != "2024-01-08 2024-01-15" {
+		t.Errorf("Expected the last two weigh-ins, got %+v", next)
+	}
+
+	for _, tt := range []struct {
+		url    string
+		status int
+	}{
+		{url + "?count=0", http.StatusBadRequest},
+		{url + "?count=101", http.StatusBadRequest},
+		{url + "?count=many", http.StatusBadRequest},
+		{url + "?date=someday", http.StatusBadRequest},
+		{"/api/habit-trackers/9999/next-due", http.StatusNotFound},
+		{"/api/target-trackers/9999/next-due", http.StatusNotFound},
+	} {
+		rr, _ := srv.makeRequest("GET", tt.url, nil)
+		if rr.Code != tt.status {
+			t.Errorf("Expected status %d for %s, got %d", tt.status, tt.url, rr.Code)
+		}
+	}
+}
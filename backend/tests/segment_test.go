@@ -0,0 +1,198 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"routine-tracker/analytics"
+	"routine-tracker/models"
+	"routine-tracker/trackers/target"
+)
+
+func (srv *testServer) targetSegments(t *testing.T, id int) []target.Segment {
+	t.Helper()
+
+	rr, _ := srv.makeRequest("GET", fmt.Sprintf("/api/target-trackers/%d/segments", id), nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	var segments []target.Segment
+	if err := json.Unmarshal(rr.Body.Bytes(), &segments); err != nil {
+		t.Fatal(err)
+	}
+	return segments
+}
+
+func TestTargetProgress(t *testing.T) {
+	t.Parallel()
+	date := func(s string) time.Time {
+		parsed, _ := time.Parse("2006-01-02", s)
+		return parsed
+	}
+	entry := func(day string, value float64) models.Entry {
+		return models.Entry{Type: models.TARGET, Value: value, Date: date(day)}
+	}
+
+	tracker := target.TargetTracker{
+		StartValue: 100, GoalValue: 50, AddToTotal: false, StartDate: date("2024-01-01"), GoalDate: date("2024-12-31"),
+		Segments: []target.Segment{
+			{EffectiveFrom: date("2024-01-01"), GoalValue: 1000, GoalDate: date("2024-12-31"), AddToTotal: true},
+			{EffectiveFrom: date("2024-03-01"), GoalValue: 50, GoalDate: date("2024-12-31"), AddToTotal: false},
+		},
+	}
+	entries := []models.Entry{
+		entry("2023-12-30", 5), // before the first segment, which still applies
+		entry("2024-02-01", 10),
+		entry("2024-03-01", 80),
+		entry("2024-03-02", 70),
+	}
+
+	want := []float64{105, 115, 80, 70}
+	got := tracker.Progress(entries)
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Expected progress %v, got %v", want, got)
+	}
+
+	if segment := tracker.SegmentAt(date("2024-02-29")); segment.GoalValue != 1000 {
+		t.Errorf("Expected the first segment before March, got %+v", segment)
+	}
+
+	projection := analytics.ProjectTarget(tracker, entries, date("2024-03-02"))
+	if projection.CurrentValue != 70 || projection.GoalValue != 50 {
+		t.Errorf("Expected current value 70 towards 50, got %f towards %f", projection.CurrentValue, projection.GoalValue)
+	}
+
+	// Trackers without segments use their own configuration for every entry
+	tracker.Segments = nil
+	want = []float64{5, 10, 80, 70}
+	if got := tracker.Progress(entries); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Expected progress %v without segments, got %v", want, got)
+	}
+}
+
+func TestTargetSegments(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	importer, _ := srv.registerUser(t, "segment-importer")
+	srv.app.Clock = fixedClock{time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)}
+
+	rr, _ := srv.makeRequest("POST", "/api/target-trackers", target.CreateTargetRequest{
+		TrackerName: "Pages read", StartValue: 0, GoalValue: 100, AddToTotal: true, StartDate: "2024-01-01", GoalDate: "2024-12-31",
+		Due: models.Due{Type: models.INTERVAL, IntervalType: "day", IntervalValue: 1},
+	})
+	var pages target.TargetTracker
+	json.Unmarshal(rr.Body.Bytes(), &pages)
+	srv.addEntry(t, models.TARGET, pages.ID, models.AddEntryRequest{Value: 10, Date: "2024-05-01"})
+	srv.addEntry(t, models.TARGET, pages.ID, models.AddEntryRequest{Value: 20, Date: "2024-05-02"})
+
+	segments := srv.targetSegments(t, pages.ID)
+	if len(segments) != 1 || !segments[0].AddToTotal || segments[0].GoalValue != 100 {
+		t.Fatalf("Expected the initial configuration, got %+v", segments)
+	}
+
+	// Switching to absolute values keeps the running total of earlier entries
+	addToTotal := false
+	goal := 500.0
+	rr, _ = srv.makeRequest("PUT", fmt.Sprintf("/api/target-trackers/%d", pages.ID), target.UpdateTargetRequest{AddToTotal: &addToTotal, GoalValue: &goal})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if value := srv.currentValue(t, pages.ID); value != 30 {
+		t.Errorf("Expected the earlier entries to still add up to 30, got %f", value)
+	}
+	srv.addEntry(t, models.TARGET, pages.ID, models.AddEntryRequest{Value: 250, Date: "2024-06-02"})
+	if value := srv.currentValue(t, pages.ID); value != 250 {
+		t.Errorf("Expected the new entry to replace the value, got %f", value)
+	}
+
+	segments = srv.targetSegments(t, pages.ID)
+	if len(segments) != 2 {
+		t.Fatalf("Expected 2 segments, got %+v", segments)
+	}
+	if segments[1].AddToTotal || segments[1].GoalValue != 500 || !segments[1].EffectiveFrom.Equal(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected a segment from today with the new goal, got %+v", segments[1])
+	}
+
+	// A change on the same day replaces the latest segment, other settings add none
+	goal = 400
+	name := "Pages"
+	srv.makeRequest("PUT", fmt.Sprintf("/api/target-trackers/%d", pages.ID), target.UpdateTargetRequest{GoalValue: &goal, TrackerName: &name})
+	segments = srv.targetSegments(t, pages.ID)
+	if len(segments) != 2 || segments[1].GoalValue != 400 {
+		t.Errorf("Expected the latest segment to be replaced, got %+v", segments)
+	}
+
+	// Changes cannot start before the latest one
+	effectiveFrom := "2024-05-15"
+	goal = 300
+	rr, _ = srv.makeRequest("PUT", fmt.Sprintf("/api/target-trackers/%d", pages.ID), target.UpdateTargetRequest{GoalValue: &goal, EffectiveFrom: &effectiveFrom})
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for an earlier change, got %d", http.StatusBadRequest, rr.Code)
+	}
+
+	// A change can be planned ahead, the tracker keeps its goal until then
+	effectiveFrom = "2024-07-01"
+	rr, _ = srv.makeRequest("PUT", fmt.Sprintf("/api/target-trackers/%d", pages.ID), target.UpdateTargetRequest{GoalValue: &goal, EffectiveFrom: &effectiveFrom})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d for a planned change, got %d. Body: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	var planned target.TargetTracker
+	json.Unmarshal(rr.Body.Bytes(), &planned)
+	if planned.GoalValue != 400 {
+		t.Errorf("Expected the goal to stay 400 until July, got %f", planned.GoalValue)
+	}
+	if segments := srv.targetSegments(t, pages.ID); len(segments) != 3 || segments[2].GoalValue != 300 {
+		t.Errorf("Expected a segment starting in July, got %+v", segments)
+	}
+
+	// Changes from today would start before the planned one
+	goal = 350
+	rr, _ = srv.makeRequest("PUT", fmt.Sprintf("/api/target-trackers/%d", pages.ID), target.UpdateTargetRequest{GoalValue: &goal})
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for a change before the planned one, got %d", http.StatusBadRequest, rr.Code)
+	}
+
+	srv.app.Clock = fixedClock{time.Date(2024, 7, 2, 10, 0, 0, 0, time.UTC)}
+	rr, _ = srv.makeRequest("GET", fmt.Sprintf("/api/target-trackers/%d", pages.ID), nil)
+	json.Unmarshal(rr.Body.Bytes(), &planned)
+	if planned.GoalValue != 300 {
+		t.Errorf("Expected the planned goal 300 to be in effect in July, got %f", planned.GoalValue)
+	}
+
+	// An export keeps the goal changes, so imported entries are read the same way
+	archive := srv.exportArchive(t, srv.token)
+	if len(archive.TargetSegments) != 3 {
+		t.Fatalf("Expected 3 exported segments, got %+v", archive.TargetSegments)
+	}
+	if code, result := srv.importArchive(t, importer, "", archive); code != http.StatusOK || result.TargetTrackersCreated != 1 {
+		t.Fatalf("Expected the tracker to be imported, got %d %+v", code, result)
+	}
+	rr, _ = srv.makeRequestWithToken(importer, "GET", "/api/target-trackers", nil)
+	var imported []target.TargetTracker
+	json.Unmarshal(rr.Body.Bytes(), &imported)
+	if len(imported) != 1 || imported[0].CurrentValue == nil || *imported[0].CurrentValue != 250 {
+		t.Fatalf("Expected the imported tracker to keep the value 250, got %s", rr.Body.String())
+	}
+	rr, _ = srv.makeRequestWithToken(importer, "GET", fmt.Sprintf("/api/target-trackers/%d/segments", imported[0].ID), nil)
+	var importedSegments []target.Segment
+	json.Unmarshal(rr.Body.Bytes(), &importedSegments)
+	if fmt.Sprint(goalValues(importedSegments)) != "[100 400 300]" || importedSegments[0].TrackerID != imported[0].ID || !importedSegments[0].AddToTotal {
+		t.Errorf("Expected the segments to be imported for the new tracker, got %+v", importedSegments)
+	}
+
+	rr, _ = srv.makeRequest("GET", "/api/target-trackers/999/segments", nil)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d for an unknown tracker, got %d", http.StatusNotFound, rr.Code)
+	}
+}
+
+func goalValues(segments []target.Segment) []float64 {
+	values := make([]float64, 0, len(segments))
+	for _, s := range segments {
+		values = append(values, s.GoalValue)
+	}
+	return values
+}
@@ -0,0 +1,301 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"routine-tracker/models"
+	"routine-tracker/trackers/habit"
+)
+
+func doneEntries(dates ...string) []models.Entry {
+	entries := make([]models.Entry, 0, len(dates))
+	for _, date := range dates {
+		parsed, _ := time.Parse("2006-01-02", date)
+		done := true
+		entries = append(entries, models.Entry{Type: models.HABIT, Done: &done, Date: parsed.Add(12 * time.Hour)})
+	}
+	return entries
+}
+
+func TestCalculateHabitStats(t *testing.T) {
+	t.Parallel()
+	intPtr := func(i int) *int { return &i }
+
+	tests := []struct {
+		name             string
+		timePeriod       models.TimePeriod
+		goal             float64
+		badHabit         bool
+		goalStreak       *int
+		startDate        string
+		today            string
+		entries          []models.Entry
+		wantPeriods      int
+		wantCurrent      int
+		wantBest         int
+		wantCompleted    int
+		wantTotal        int
+		wantGoalProgress *float64
+	}{
+		{
+			name:          "daily streak with current day still open",
+			timePeriod:    models.PER_DAY,
+			goal:          1,
+			startDate:     "2024-01-01",
+			today:         "2024-01-05",
+			entries:       doneEntries("2024-01-02", "2024-01-03", "2024-01-04"),
+			wantPeriods:   5,
+			wantCurrent:   3,
+			wantBest:      3,
+			wantCompleted: 3,
+			wantTotal:     4,
+		},
+		{
+			name:          "daily missed days give negative current streak",
+			timePeriod:    models.PER_DAY,
+			goal:          1,
+			startDate:     "2024-01-01",
+			today:         "2024-01-05",
+			entries:       doneEntries("2024-01-01", "2024-01-02"),
+			wantPeriods:   5,
+			wantCurrent:   -2,
+			wantBest:      2,
+			wantCompleted: 2,
+			wantTotal:     4,
+		},
+		{
+			name:          "no entries gives negative best streak",
+			timePeriod:    models.PER_DAY,
+			goal:          1,
+			startDate:     "2024-01-01",
+			today:         "2024-01-03",
+			entries:       doneEntries(),
+			wantPeriods:   3,
+			wantCurrent:   -2,
+			wantBest:      -2,
+			wantCompleted: 0,
+			wantTotal:     2,
+		},
+		{
+			name:          "weekly buckets start on monday",
+			timePeriod:    models.PER_WEEK,
+			goal:          2,
+			startDate:     "2024-01-03", // Wednesday
+			today:         "2024-01-17", // Wednesday, two weeks later
+			entries:       doneEntries("2024-01-01", "2024-01-04", "2024-01-08", "2024-01-10", "2024-01-15"),
+			wantPeriods:   3,
+			wantCurrent:   2,
+			wantBest:      2,
+			wantCompleted: 2,
+			wantTotal:     2,
+		},
+		{
+			name:          "monthly goal met in current period counts",
+			timePeriod:    models.PER_MONTH,
+			goal:          1,
+			startDate:     "2024-01-15",
+			today:         "2024-03-10",
+			entries:       doneEntries("2024-01-20", "2024-03-01"),
+			wantPeriods:   3,
+			wantCurrent:   1,
+			wantBest:      1,
+			wantCompleted: 2,
+			wantTotal:     3,
+		},
+		{
+			name:          "yearly buckets",
+			timePeriod:    models.PER_YEAR,
+			goal:          2,
+			startDate:     "2022-06-01",
+			today:         "2024-02-01",
+			entries:       doneEntries("2022-07-01", "2022-08-01", "2023-01-01", "2023-02-01"),
+			wantPeriods:   3,
+			wantCurrent:   2,
+			wantBest:      2,
+			wantCompleted: 2,
+			wantTotal:     2,
+		},
+		{
+			name:          "bad habit is met when staying under the goal",
+			timePeriod:    models.PER_DAY,
+			goal:          0,
+			badHabit:      true,
+			startDate:     "2024-01-01",
+			today:         "2024-01-04",
+			entries:       doneEntries("2024-01-02"),
+			wantPeriods:   4,
+			wantCurrent:   2,
+			wantBest:      2,
+			wantCompleted: 3,
+			wantTotal:     4,
+		},
+		{
+			name:             "goal streak progress",
+			timePeriod:       models.PER_DAY,
+			goal:             1,
+			goalStreak:       intPtr(4),
+			startDate:        "2024-01-01",
+			today:            "2024-01-03",
+			entries:          doneEntries("2024-01-01", "2024-01-02", "2024-01-03"),
+			wantPeriods:      3,
+			wantCurrent:      3,
+			wantBest:         3,
+			wantCompleted:    3,
+			wantTotal:        3,
+			wantGoalProgress: func() *float64 { f := 0.75; return &f }(),
+		},
+		{
+			name:          "start date in the future has no periods",
+			timePeriod:    models.PER_DAY,
+			goal:          1,
+			startDate:     "2024-02-01",
+			today:         "2024-01-01",
+			entries:       doneEntries(),
+			wantPeriods:   0,
+			wantCurrent:   0,
+			wantBest:      0,
+			wantCompleted: 0,
+			wantTotal:     0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			startDate, _ := time.Parse("2006-01-02", tt.startDate)
+			today, _ := time.Parse("2006-01-02", tt.today)
+
+			tracker := habit.HabitTracker{
+				ID:         1,
+				Goal:       tt.goal,
+				TimePeriod: tt.timePeriod,
+				StartDate:  startDate,
+				BadHabit:   tt.badHabit,
+				GoalStreak: tt.goalStreak,
+			}
+
+			stats := habit.CalculateStats(tracker, tt.entries, models.DaysOff{}, today)
+
+			if len(stats.Periods) != tt.wantPeriods {
+				t.Errorf("Expected %d periods, got %d", tt.wantPeriods, len(stats.Periods))
+			}
+			if stats.CurrentStreak != tt.wantCurrent {
+				t.Errorf("Expected current streak %d, got %d", tt.wantCurrent, stats.CurrentStreak)
+			}
+			if stats.BestStreak != tt.wantBest {
+				t.Errorf("Expected best streak %d, got %d", tt.wantBest, stats.BestStreak)
+			}
+			if stats.CompletedPeriods != tt.wantCompleted {
+				t.Errorf("Expected %d completed periods, got %d", tt.wantCompleted, stats.CompletedPeriods)
+			}
+			if stats.TotalPeriods != tt.wantTotal {
+				t.Errorf("Expected %d total periods, got %d", tt.wantTotal, stats.TotalPeriods)
+			}
+			if tt.wantTotal > 0 {
+				wantRate := float64(tt.wantCompleted) / float64(tt.wantTotal)
+				if stats.CompletionRate != wantRate {
+					t.Errorf("Expected completion rate %f, got %f", wantRate, stats.CompletionRate)
+				}
+			}
+			if tt.wantGoalProgress != nil {
+				if stats.GoalStreakProgress == nil || *stats.GoalStreakProgress != *tt.wantGoalProgress {
+					t.Errorf("Expected goal streak progress %f, got %v", *tt.wantGoalProgress, stats.GoalStreakProgress)
+				}
+			} else if stats.GoalStreakProgress != nil {
+				t.Errorf("Expected no goal streak progress, got %f", *stats.GoalStreakProgress)
+			}
+		})
+	}
+}
+
+func TestGetHabitTrackerStats(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	habitRequest := habit.CreateHabitRequest{
+		TrackerName: "Stats Habit",
+		Goal:        1,
+		TimePeriod:  models.PER_DAY,
+		StartDate:   "2024-01-01",
+		Due: models.Due{
+			Type:          models.INTERVAL,
+			IntervalType:  "day",
+			IntervalValue: 1,
+		},
+		GoalStreak: func() *int { i := 10; return &i }(),
+	}
+
+	createRr, err := srv.makeRequest("POST", "/api/habit-trackers", habitRequest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var createdHabit habit.HabitTracker
+	json.Unmarshal(createRr.Body.Bytes(), &createdHabit)
+
+	for _, date := range []string{"2024-01-01T10:00:00Z", "2024-01-02T10:00:00Z", "2024-01-03T10:00:00Z"} {
+		entryRequest := models.AddEntryRequest{
+			Done: func() *bool { b := true; return &b }(),
+			Date: date,
+		}
+		srv.makeRequest("POST", fmt.Sprintf("/api/habit-trackers/%d/entries", createdHabit.ID), entryRequest)
+	}
+
+	rr, err := srv.makeRequest("GET", fmt.Sprintf("/api/habit-trackers/%d/stats?date=2024-01-04", createdHabit.ID), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	var stats habit.Stats
+	if err := json.Unmarshal(rr.Body.Bytes(), &stats); err != nil {
+		t.Fatalf("Failed to parse stats response: %v", err)
+	}
+
+	if stats.TrackerID != createdHabit.ID {
+		t.Errorf("Expected tracker ID %d, got %d", createdHabit.ID, stats.TrackerID)
+	}
+	if len(stats.Periods) != 4 {
+		t.Errorf("Expected 4 periods, got %d", len(stats.Periods))
+	}
+	if stats.CurrentStreak != 3 {
+		t.Errorf("Expected current streak 3, got %d", stats.CurrentStreak)
+	}
+	if stats.BestStreak != 3 {
+		t.Errorf("Expected best streak 3, got %d", stats.BestStreak)
+	}
+	if stats.GoalStreakProgress == nil || *stats.GoalStreakProgress != 0.3 {
+		t.Errorf("Expected goal streak progress 0.3, got %v", stats.GoalStreakProgress)
+	}
+}
+
+func TestGetHabitTrackerStatsNotFound(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	rr, err := srv.makeRequest("GET", "/api/habit-trackers/99999/stats", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, rr.Code)
+	}
+}
+
+func TestGetHabitTrackerStatsInvalidDate(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	rr, err := srv.makeRequest("GET", "/api/habit-trackers/1/stats?date=not-a-date", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, rr.Code)
+	}
+}
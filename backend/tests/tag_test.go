@@ -0,0 +1,210 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"routine-tracker/models"
+	"routine-tracker/trackers"
+	"routine-tracker/trackers/habit"
+	"routine-tracker/trackers/target"
+)
+
+func (srv *testServer) createTag(t *testing.T, name string) models.Tag {
+	t.Helper()
+
+	rr, _ := srv.makeRequest("POST", "/api/tags", models.TagRequest{Name: name})
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusCreated, rr.Code, rr.Body.String())
+	}
+	var tag models.Tag
+	json.Unmarshal(rr.Body.Bytes(), &tag)
+	return tag
+}
+
+func (srv *testServer) createCategory(t *testing.T, name string) models.Category {
+	t.Helper()
+
+	rr, _ := srv.makeRequest("POST", "/api/categories", models.CreateCategoryRequest{Name: name, Color: "#4caf50", Icon: "heart"})
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusCreated, rr.Code, rr.Body.String())
+	}
+	var category models.Category
+	json.Unmarshal(rr.Body.Bytes(), &category)
+	return category
+}
+
+func (srv *testServer) trackers(t *testing.T, query string) trackers.TrackersResponse {
+	t.Helper()
+
+	rr, _ := srv.makeRequest("GET", "/api/trackers"+query, nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	var response trackers.TrackersResponse
+	json.Unmarshal(rr.Body.Bytes(), &response)
+	return response
+}
+
+// groupNames describes groups as "category: tracker names"
+func groupNames(groups []trackers.TrackerGroup) []string {
+	var names []string
+	for _, group := range groups {
+		name := "none:"
+		if group.Category != nil {
+			name = group.Category.Name + ":"
+		}
+		for _, h := range group.HabitTrackers {
+			name += " " + h.TrackerName
+		}
+		for _, t := range group.TargetTrackers {
+			name += " " + t.TrackerName
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+func TestTrackerCategories(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	daily := models.Due{Type: models.INTERVAL, IntervalType: "day", IntervalValue: 1}
+
+	work := srv.createCategory(t, "Work")
+	health := srv.createCategory(t, "Health")
+	morning := srv.createTag(t, "morning")
+
+	rr, _ := srv.makeRequest("POST", "/api/habit-trackers", habit.CreateHabitRequest{
+		TrackerName: "Stretch", Goal: 1, TimePeriod: models.PER_DAY, StartDate: "2024-01-01", Due: daily,
+		CategoryID: &health.ID, TagIDs: []int{morning.ID},
+	})
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusCreated, rr.Code, rr.Body.String())
+	}
+	var stretch habit.HabitTracker
+	json.Unmarshal(rr.Body.Bytes(), &stretch)
+	if stretch.CategoryID == nil || *stretch.CategoryID != health.ID || len(stretch.Tags) != 1 || stretch.Tags[0].Name != "morning" {
+		t.Errorf("Expected the Health category and the morning tag, got %+v and %+v", stretch.CategoryID, stretch.Tags)
+	}
+
+	srv.createDatedHabit(t, "Read", "2024-01-01", daily)
+	rr, _ = srv.makeRequest("POST", "/api/target-trackers", target.CreateTargetRequest{
+		TrackerName: "Tickets", StartValue: 0, GoalValue: 10, AddToTotal: true, StartDate: "2024-01-01", GoalDate: "2099-12-31",
+		Due: daily, CategoryID: &work.ID,
+	})
+	var tickets target.TargetTracker
+	json.Unmarshal(rr.Body.Bytes(), &tickets)
+
+	// Groups follow the category names, uncategorized trackers come last
+	want := "[Health: Stretch Work: Tickets none: Read]"
+	if got := fmt.Sprint(groupNames(srv.trackers(t, "").Groups)); got != want {
+		t.Errorf("Expected groups %s, got %s", want, got)
+	}
+	rr, _ = srv.makeRequest("GET", "/api/dashboard", nil)
+	var dashboard trackers.DashboardResponse
+	json.Unmarshal(rr.Body.Bytes(), &dashboard)
+	if got := fmt.Sprint(groupNames(dashboard.Groups)); got != want {
+		t.Errorf("Expected dashboard groups %s, got %s", want, got)
+	}
+
+	if got := fmt.Sprint(groupNames(srv.trackers(t, "?tag=Morning").Groups)); got != "[Health: Stretch]" {
+		t.Errorf("Expected only the morning tracker, got %s", got)
+	}
+	if response := srv.trackers(t, "?category=work"); len(response.HabitTrackers) != 0 || len(response.TargetTrackers) != 1 {
+		t.Errorf("Expected only the work tracker, got %+v", response)
+	}
+	rr, _ = srv.makeRequest("GET", "/api/habit-trackers?category=Health", nil)
+	var habits []habit.HabitTracker
+	json.Unmarshal(rr.Body.Bytes(), &habits)
+	if len(habits) != 1 || habits[0].ID != stretch.ID {
+		t.Errorf("Expected only Stretch in the Health category, got %+v", habits)
+	}
+
+	// Category 0 removes the category, tagIds replace the tags
+	none := 0
+	tags := []int{}
+	rr, _ = srv.makeRequest("PUT", fmt.Sprintf("/api/habit-trackers/%d", stretch.ID), habit.UpdateHabitRequest{CategoryID: &none, TagIDs: &tags})
+	json.Unmarshal(rr.Body.Bytes(), &stretch)
+	if stretch.CategoryID != nil || len(stretch.Tags) != 0 {
+		t.Errorf("Expected no category and no tags, got %+v and %+v", stretch.CategoryID, stretch.Tags)
+	}
+
+	// Deleting a category leaves its trackers uncategorized
+	srv.makeRequest("DELETE", fmt.Sprintf("/api/categories/%d", work.ID), nil)
+	groups := srv.trackers(t, "").Groups
+	if len(groups) != 1 || groups[0].Category != nil || len(groups[0].HabitTrackers) != 2 || len(groups[0].TargetTrackers) != 1 {
+		t.Errorf("Expected every tracker to be uncategorized, got %v", groupNames(groups))
+	}
+}
+
+func TestTags(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	otherToken, err := srv.registerAndLogin("other", "password123")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	morning := srv.createTag(t, "morning")
+	evening := srv.createTag(t, "evening")
+
+	rr, _ := srv.makeRequest("POST", "/api/tags", models.TagRequest{Name: "Morning"})
+	if rr.Code != http.StatusConflict {
+		t.Errorf("Expected status %d for a duplicate tag, got %d", http.StatusConflict, rr.Code)
+	}
+	rr, _ = srv.makeRequest("POST", "/api/tags", models.TagRequest{Name: " "})
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d without a name, got %d", http.StatusBadRequest, rr.Code)
+	}
+
+	rr, _ = srv.makeRequest("PUT", fmt.Sprintf("/api/tags/%d", morning.ID), models.TagRequest{Name: "early"})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	rr, _ = srv.makeRequest("GET", "/api/tags", nil)
+	var tags []models.Tag
+	json.Unmarshal(rr.Body.Bytes(), &tags)
+	if len(tags) != 2 || tags[0].Name != "early" || tags[1].Name != "evening" {
+		t.Errorf("Expected the tags ordered by name, got %+v", tags)
+	}
+
+	tracker := srv.createDatedHabit(t, "Read", "2024-01-01", models.Due{Type: models.INTERVAL, IntervalType: "day", IntervalValue: 1})
+	tagIDs := []int{morning.ID, evening.ID}
+	srv.makeRequest("PUT", fmt.Sprintf("/api/habit-trackers/%d", tracker.ID), habit.UpdateHabitRequest{TagIDs: &tagIDs})
+
+	// Deleting a tag removes it from the trackers
+	rr, _ = srv.makeRequest("DELETE", fmt.Sprintf("/api/tags/%d", evening.ID), nil)
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("Expected status %d, got %d", http.StatusNoContent, rr.Code)
+	}
+	rr, _ = srv.makeRequest("GET", fmt.Sprintf("/api/habit-trackers/%d", tracker.ID), nil)
+	json.Unmarshal(rr.Body.Bytes(), &tracker)
+	if len(tracker.Tags) != 1 || tracker.Tags[0].ID != morning.ID {
+		t.Errorf("Expected only the early tag to be left, got %+v", tracker.Tags)
+	}
+
+	events := srv.auditEvents(t, srv.token, fmt.Sprintf("/api/audit?entity=tag&id=%d", evening.ID))
+	if len(events) != 2 || events[0].Action != models.AUDIT_DELETE {
+		t.Errorf("Expected create and delete of the tag, got %+v", events)
+	}
+
+	// Tags and categories of other users cannot be used
+	rr, _ = srv.makeRequestWithToken(otherToken, "POST", "/api/habit-trackers", habit.CreateHabitRequest{
+		TrackerName: "Run", Goal: 1, TimePeriod: models.PER_DAY, StartDate: "2024-01-01", TagIDs: []int{morning.ID},
+		Due: models.Due{Type: models.INTERVAL, IntervalType: "day", IntervalValue: 1},
+	})
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for a tag of another user, got %d", http.StatusBadRequest, rr.Code)
+	}
+	rr, _ = srv.makeRequestWithToken(otherToken, "DELETE", fmt.Sprintf("/api/tags/%d", morning.ID), nil)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d for a tag of another user, got %d", http.StatusNotFound, rr.Code)
+	}
+	missing := 999
+	rr, _ = srv.makeRequest("PUT", fmt.Sprintf("/api/habit-trackers/%d", tracker.ID), habit.UpdateHabitRequest{CategoryID: &missing})
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for an unknown category, got %d", http.StatusBadRequest, rr.Code)
+	}
+}
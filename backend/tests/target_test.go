@@ -12,6 +12,8 @@ import (
 )
 
 func TestCreateTargetTracker(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
 	// Test data
 	targetRequest := target.CreateTargetRequest{
 		TrackerName: "Save Money",
@@ -27,7 +29,7 @@ func TestCreateTargetTracker(t *testing.T) {
 	}
 
 	// Make request
-	rr, err := makeRequest("POST", "/api/target-trackers", targetRequest)
+	rr, err := srv.makeRequest("POST", "/api/target-trackers", targetRequest)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -62,6 +64,8 @@ func TestCreateTargetTracker(t *testing.T) {
 }
 
 func TestGetTargetTrackers(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
 	// First create a target tracker
 	targetRequest := target.CreateTargetRequest{
 		TrackerName: "Learn Spanish",
@@ -78,13 +82,13 @@ func TestGetTargetTrackers(t *testing.T) {
 	}
 
 	// Create the target
-	_, err := makeRequest("POST", "/api/target-trackers", targetRequest)
+	_, err := srv.makeRequest("POST", "/api/target-trackers", targetRequest)
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	// Get all targets
-	rr, err := makeRequest("GET", "/api/target-trackers", nil)
+	rr, err := srv.makeRequest("GET", "/api/target-trackers", nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -104,6 +108,8 @@ func TestGetTargetTrackers(t *testing.T) {
 }
 
 func TestAddTargetEntry(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
 	// Create a target first
 	targetRequest := target.CreateTargetRequest{
 		TrackerName: "Read Pages",
@@ -118,7 +124,7 @@ func TestAddTargetEntry(t *testing.T) {
 		},
 	}
 
-	createRr, err := makeRequest("POST", "/api/target-trackers", targetRequest)
+	createRr, err := srv.makeRequest("POST", "/api/target-trackers", targetRequest)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -132,7 +138,7 @@ func TestAddTargetEntry(t *testing.T) {
 		Note:  "Great progress today",
 	}
 
-	entryRr, err := makeRequest("POST", fmt.Sprintf("/api/target-trackers/%d/entries", createdTarget.ID), entryRequest)
+	entryRr, err := srv.makeRequest("POST", fmt.Sprintf("/api/target-trackers/%d/entries", createdTarget.ID), entryRequest)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -158,6 +164,8 @@ func TestAddTargetEntry(t *testing.T) {
 }
 
 func TestTargetWithAddToTotalFalse(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
 	// Create a target with AddToTotal = false (replace mode)
 	targetRequest := target.CreateTargetRequest{
 		TrackerName: "Current Weight",
@@ -172,7 +180,7 @@ func TestTargetWithAddToTotalFalse(t *testing.T) {
 		},
 	}
 
-	createRr, err := makeRequest("POST", "/api/target-trackers", targetRequest)
+	createRr, err := srv.makeRequest("POST", "/api/target-trackers", targetRequest)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -189,7 +197,7 @@ func TestTargetWithAddToTotalFalse(t *testing.T) {
 	entry2 := models.AddEntryRequest{Value: 78.2, Note: "Week 2"}
 
 	for _, entryReq := range []models.AddEntryRequest{entry1, entry2} {
-		entryRr, err := makeRequest("POST", fmt.Sprintf("/api/target-trackers/%d/entries", createdTarget.ID), entryReq)
+		entryRr, err := srv.makeRequest("POST", fmt.Sprintf("/api/target-trackers/%d/entries", createdTarget.ID), entryReq)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -200,6 +208,8 @@ func TestTargetWithAddToTotalFalse(t *testing.T) {
 }
 
 func TestDeleteTargetTracker(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
 	// Create a target first
 	targetRequest := target.CreateTargetRequest{
 		TrackerName: "Test Delete Target",
@@ -214,7 +224,7 @@ func TestDeleteTargetTracker(t *testing.T) {
 		},
 	}
 
-	createRr, err := makeRequest("POST", "/api/target-trackers", targetRequest)
+	createRr, err := srv.makeRequest("POST", "/api/target-trackers", targetRequest)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -224,13 +234,13 @@ func TestDeleteTargetTracker(t *testing.T) {
 
 	// Add an entry to test cascade delete
 	entryRequest := models.AddEntryRequest{Value: 10, Note: "Test entry"}
-	_, err = makeRequest("POST", fmt.Sprintf("/api/target-trackers/%d/entries", createdTarget.ID), entryRequest)
+	_, err = srv.makeRequest("POST", fmt.Sprintf("/api/target-trackers/%d/entries", createdTarget.ID), entryRequest)
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	// Delete the target
-	deleteRr, err := makeRequest("DELETE", fmt.Sprintf("/api/target-trackers/%d", createdTarget.ID), nil)
+	deleteRr, err := srv.makeRequest("DELETE", fmt.Sprintf("/api/target-trackers/%d", createdTarget.ID), nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -240,7 +250,7 @@ func TestDeleteTargetTracker(t *testing.T) {
 	}
 
 	// Try to get the deleted target (should fail)
-	getRr, err := makeRequest("GET", fmt.Sprintf("/api/target-trackers/%d", createdTarget.ID), nil)
+	getRr, err := srv.makeRequest("GET", fmt.Sprintf("/api/target-trackers/%d", createdTarget.ID), nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -250,8 +260,9 @@ func TestDeleteTargetTracker(t *testing.T) {
 	}
 }
 
-
 func TestGetTargetTracker(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
 	// Create a target first
 	targetRequest := target.CreateTargetRequest{
 		TrackerName: "Test Get Target",
@@ -268,7 +279,7 @@ func TestGetTargetTracker(t *testing.T) {
 	}
 
 	// Create the target
-	createRr, err := makeRequest("POST", "/api/target-trackers", targetRequest)
+	createRr, err := srv.makeRequest("POST", "/api/target-trackers", targetRequest)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -277,7 +288,7 @@ func TestGetTargetTracker(t *testing.T) {
 	json.Unmarshal(createRr.Body.Bytes(), &createdTarget)
 
 	// Get the specific target
-	getRr, err := makeRequest("GET", fmt.Sprintf("/api/target-trackers/%d", createdTarget.ID), nil)
+	getRr, err := srv.makeRequest("GET", fmt.Sprintf("/api/target-trackers/%d", createdTarget.ID), nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -319,8 +330,10 @@ func TestGetTargetTracker(t *testing.T) {
 }
 
 func TestGetTargetTrackerNotFound(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
 	// Try to get a non-existent target tracker
-	getRr, err := makeRequest("GET", "/api/target-trackers/99999", nil)
+	getRr, err := srv.makeRequest("GET", "/api/target-trackers/99999", nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -331,8 +344,10 @@ func TestGetTargetTrackerNotFound(t *testing.T) {
 }
 
 func TestGetTargetTrackerInvalidID(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
 	// Try to get a target tracker with invalid ID
-	getRr, err := makeRequest("GET", "/api/target-trackers/invalid", nil)
+	getRr, err := srv.makeRequest("GET", "/api/target-trackers/invalid", nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -343,6 +358,8 @@ func TestGetTargetTrackerInvalidID(t *testing.T) {
 }
 
 func TestUpdateTargetTracker(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
 	// Create a target first
 	targetRequest := target.CreateTargetRequest{
 		TrackerName: "Original Target",
@@ -357,7 +374,7 @@ func TestUpdateTargetTracker(t *testing.T) {
 		},
 	}
 
-	createRr, err := makeRequest("POST", "/api/target-trackers", targetRequest)
+	createRr, err := srv.makeRequest("POST", "/api/target-trackers", targetRequest)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -378,7 +395,7 @@ func TestUpdateTargetTracker(t *testing.T) {
 		},
 	}
 
-	updateRr, err := makeRequest("PUT", fmt.Sprintf("/api/target-trackers/%d", createdTarget.ID), updateRequest)
+	updateRr, err := srv.makeRequest("PUT", fmt.Sprintf("/api/target-trackers/%d", createdTarget.ID), updateRequest)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -416,8 +433,38 @@ func TestUpdateTargetTracker(t *testing.T) {
 	}
 }
 
+func TestUpdateTargetTrackerInvalidDates(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	createRr, err := srv.makeRequest("POST", "/api/target-trackers", target.CreateTargetRequest{
+		TrackerName: "Pages",
+		StartValue:  0,
+		GoalValue:   100,
+		StartDate:   time.Now().Format("2006-01-02"),
+		GoalDate:    time.Now().AddDate(0, 2, 0).Format("2006-01-02"),
+		Due:         models.Due{Type: "specificDays", SpecificDays: []string{"monday"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var createdTarget target.TargetTracker
+	json.Unmarshal(createRr.Body.Bytes(), &createdTarget)
+
+	invalid := "2024-13-45"
+	for _, req := range []target.UpdateTargetRequest{{StartDate: &invalid}, {GoalDate: &invalid}} {
+		rr, err := srv.makeRequest("PUT", fmt.Sprintf("/api/target-trackers/%d", createdTarget.ID), req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d, got %d. Body: %s", http.StatusBadRequest, rr.Code, rr.Body.String())
+		}
+	}
+}
 
 func TestTargetTrackerDetailPageFlow(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
 	// Similar integration test for target trackers
 	targetRequest := target.CreateTargetRequest{
 		TrackerName: "Integration Test Target",
@@ -432,7 +479,7 @@ func TestTargetTrackerDetailPageFlow(t *testing.T) {
 		},
 	}
 
-	createRr, err := makeRequest("POST", "/api/target-trackers", targetRequest)
+	createRr, err := srv.makeRequest("POST", "/api/target-trackers", targetRequest)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -448,19 +495,19 @@ func TestTargetTrackerDetailPageFlow(t *testing.T) {
 	}
 
 	for _, entryReq := range entries {
-		_, err := makeRequest("POST", fmt.Sprintf("/api/target-trackers/%d/entries", createdTarget.ID), entryReq)
+		_, err := srv.makeRequest("POST", fmt.Sprintf("/api/target-trackers/%d/entries", createdTarget.ID), entryReq)
 		if err != nil {
 			t.Fatal(err)
 		}
 	}
 
 	// Get target details and entries
-	getRr, err := makeRequest("GET", fmt.Sprintf("/api/target-trackers/%d", createdTarget.ID), nil)
+	getRr, err := srv.makeRequest("GET", fmt.Sprintf("/api/target-trackers/%d", createdTarget.ID), nil)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	entriesRr, err := makeRequest("GET", fmt.Sprintf("/api/target-trackers/%d/entries", createdTarget.ID), nil)
+	entriesRr, err := srv.makeRequest("GET", fmt.Sprintf("/api/target-trackers/%d/entries", createdTarget.ID), nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -498,6 +545,8 @@ func TestTargetTrackerDetailPageFlow(t *testing.T) {
 }
 
 func TestTargetTrendWeightTypeDefault(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
 	// Create a target without specifying TrendWeightType
 	targetRequest := target.CreateTargetRequest{
 		TrackerName: "Test Default Trend Weight",
@@ -512,7 +561,7 @@ func TestTargetTrendWeightTypeDefault(t *testing.T) {
 		},
 	}
 
-	createRr, err := makeRequest("POST", "/api/target-trackers", targetRequest)
+	createRr, err := srv.makeRequest("POST", "/api/target-trackers", targetRequest)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -529,6 +578,8 @@ func TestTargetTrendWeightTypeDefault(t *testing.T) {
 }
 
 func TestTargetTrendWeightTypeCreate(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
 	// Create a target with specific TrendWeightType
 	weightType := "exponential_low"
 	targetRequest := target.CreateTargetRequest{
@@ -545,7 +596,7 @@ func TestTargetTrendWeightTypeCreate(t *testing.T) {
 		},
 	}
 
-	createRr, err := makeRequest("POST", "/api/target-trackers", targetRequest)
+	createRr, err := srv.makeRequest("POST", "/api/target-trackers", targetRequest)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -566,6 +617,8 @@ func TestTargetTrendWeightTypeCreate(t *testing.T) {
 }
 
 func TestTargetTrendWeightTypeUpdate(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
 	// Create a target with default trend weight type
 	targetRequest := target.CreateTargetRequest{
 		TrackerName: "Test Update Trend Weight",
@@ -580,7 +633,7 @@ func TestTargetTrendWeightTypeUpdate(t *testing.T) {
 		},
 	}
 
-	createRr, err := makeRequest("POST", "/api/target-trackers", targetRequest)
+	createRr, err := srv.makeRequest("POST", "/api/target-trackers", targetRequest)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -594,7 +647,7 @@ func TestTargetTrendWeightTypeUpdate(t *testing.T) {
 		TrendWeightType: &newWeightType,
 	}
 
-	updateRr, err := makeRequest("PUT", fmt.Sprintf("/api/target-trackers/%d", createdTarget.ID), updateRequest)
+	updateRr, err := srv.makeRequest("PUT", fmt.Sprintf("/api/target-trackers/%d", createdTarget.ID), updateRequest)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -615,6 +668,8 @@ func TestTargetTrendWeightTypeUpdate(t *testing.T) {
 }
 
 func TestTargetTrendWeightTypeAllOptions(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
 	// Test all valid trend weight type options
 	validOptions := []string{"none", "linear", "sqrt", "quadratic", "exponential_low", "exponential_high"}
 
@@ -634,7 +689,7 @@ func TestTargetTrendWeightTypeAllOptions(t *testing.T) {
 			},
 		}
 
-		createRr, err := makeRequest("POST", "/api/target-trackers", targetRequest)
+		createRr, err := srv.makeRequest("POST", "/api/target-trackers", targetRequest)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -0,0 +1,191 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"routine-tracker/models"
+	"routine-tracker/trackers/habit"
+)
+
+// Helper function to create a personal API token for the default test user
+func (srv *testServer) createAPIToken(t *testing.T, name string, scopes ...string) models.CreateAPITokenResponse {
+	t.Helper()
+
+	rr, err := srv.makeRequest("POST", "/api/tokens", models.CreateAPITokenRequest{Name: name, Scopes: scopes})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusCreated, rr.Code, rr.Body.String())
+	}
+
+	var created models.CreateAPITokenResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &created); err != nil {
+		t.Fatalf("Failed to parse token response: %v", err)
+	}
+	return created
+}
+
+func TestAPITokenScopes(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	habitRequest := habit.CreateHabitRequest{
+		TrackerName: "Scripted Habit",
+		Goal:        1,
+		TimePeriod:  models.PER_DAY,
+		StartDate:   time.Now().Format("2006-01-02"),
+		Due: models.Due{
+			Type:          models.INTERVAL,
+			IntervalType:  "day",
+			IntervalValue: 1,
+		},
+	}
+	createRr, _ := srv.makeRequest("POST", "/api/habit-trackers", habitRequest)
+	var createdHabit habit.HabitTracker
+	json.Unmarshal(createRr.Body.Bytes(), &createdHabit)
+
+	token := srv.createAPIToken(t, "Cron job", "entries:write")
+	if !strings.HasPrefix(token.Token, "pat_") {
+		t.Errorf("Expected token to start with 'pat_', got '%s'", token.Token)
+	}
+
+	timeZone := "Europe/Istanbul"
+	tests := []struct {
+		name       string
+		method     string
+		url        string
+		body       interface{}
+		wantStatus int
+	}{
+		{"add entry is allowed", "POST", fmt.Sprintf("/api/habit-trackers/%d/entries", createdHabit.ID), models.AddEntryRequest{}, http.StatusCreated},
+		{"reading entries is forbidden", "GET", fmt.Sprintf("/api/habit-trackers/%d/entries", createdHabit.ID), nil, http.StatusForbidden},
+		{"reading trackers is forbidden", "GET", "/api/habit-trackers", nil, http.StatusForbidden},
+		{"deleting trackers is forbidden", "DELETE", fmt.Sprintf("/api/habit-trackers/%d", createdHabit.ID), nil, http.StatusForbidden},
+		{"managing tokens is forbidden", "GET", "/api/tokens", nil, http.StatusForbidden},
+		{"changing the account is forbidden", "PUT", "/api/auth/me", models.UpdateUserRequest{TimeZone: &timeZone}, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr, err := srv.makeRequestWithToken(token.Token, tt.method, tt.url, tt.body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rr.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d. Body: %s", tt.wantStatus, rr.Code, rr.Body.String())
+			}
+		})
+	}
+
+	// The tracker survived the forbidden delete
+	rr, _ := srv.makeRequest("GET", fmt.Sprintf("/api/habit-trackers/%d", createdHabit.ID), nil)
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected tracker to still exist, got status %d", rr.Code)
+	}
+}
+
+func TestAPITokenAdminScope(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	admin := srv.createAPIToken(t, "Admin script", "admin")
+
+	rr, err := srv.makeRequestWithToken(admin.Token, "GET", "/api/tokens", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected admin token to list tokens, got status %d", rr.Code)
+	}
+
+	rr, err = srv.makeRequestWithToken(admin.Token, "GET", "/api/habit-trackers", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected admin token to read trackers, got status %d", rr.Code)
+	}
+}
+
+func TestAPITokenLastUsedAndRevoke(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	token := srv.createAPIToken(t, "Phone shortcut", "trackers:read")
+	if token.LastUsedAt != nil {
+		t.Error("Expected new token to have no last used time")
+	}
+
+	rr, _ := srv.makeRequestWithToken(token.Token, "GET", "/api/habit-trackers", nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+
+	rr, _ = srv.makeRequest("GET", "/api/tokens", nil)
+	var tokens []models.APIToken
+	json.Unmarshal(rr.Body.Bytes(), &tokens)
+
+	found := false
+	for _, listed := range tokens {
+		if listed.ID == token.ID {
+			found = true
+			if listed.LastUsedAt == nil {
+				t.Error("Expected last used time to be recorded")
+			}
+		}
+	}
+	if !found {
+		t.Fatal("Expected token to be listed")
+	}
+	if strings.Contains(rr.Body.String(), token.Token) {
+		t.Error("Expected token value not to be listed")
+	}
+
+	// Other users cannot revoke the token
+	otherToken, err := srv.registerAndLogin("token-thief", "token-thief-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr, _ = srv.makeRequestWithToken(otherToken, "DELETE", fmt.Sprintf("/api/tokens/%d", token.ID), nil)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d when revoking another user's token, got %d", http.StatusNotFound, rr.Code)
+	}
+
+	rr, _ = srv.makeRequest("DELETE", fmt.Sprintf("/api/tokens/%d", token.ID), nil)
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("Expected status %d, got %d", http.StatusNoContent, rr.Code)
+	}
+
+	rr, _ = srv.makeRequestWithToken(token.Token, "GET", "/api/habit-trackers", nil)
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("Expected revoked token to be rejected with %d, got %d", http.StatusUnauthorized, rr.Code)
+	}
+}
+
+func TestCreateAPITokenValidation(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	tests := []struct {
+		name    string
+		request models.CreateAPITokenRequest
+	}{
+		{"missing name", models.CreateAPITokenRequest{Scopes: []string{"trackers:read"}}},
+		{"missing scopes", models.CreateAPITokenRequest{Name: "No scopes"}},
+		{"unknown scope", models.CreateAPITokenRequest{Name: "Bad scope", Scopes: []string{"everything"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr, err := srv.makeRequest("POST", "/api/tokens", tt.request)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rr.Code != http.StatusBadRequest {
+				t.Errorf("Expected status %d, got %d", http.StatusBadRequest, rr.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,282 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"routine-tracker/models"
+	"routine-tracker/trackers"
+	"routine-tracker/trackers/habit"
+	"routine-tracker/trackers/target"
+	"routine-tracker/trash"
+)
+
+func (srv *testServer) trash(t *testing.T, token string) trackers.TrashResponse {
+	t.Helper()
+
+	rr, _ := srv.makeRequestWithToken(token, "GET", "/api/trash", nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	var response trackers.TrashResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	return response
+}
+
+func (srv *testServer) addEntry(t *testing.T, trackerType models.TrackerType, trackerID int, req models.AddEntryRequest) models.Entry {
+	t.Helper()
+
+	rr, _ := srv.makeRequest("POST", fmt.Sprintf("/api/%s-trackers/%d/entries", trackerType, trackerID), req)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusCreated, rr.Code, rr.Body.String())
+	}
+	var entry models.Entry
+	json.Unmarshal(rr.Body.Bytes(), &entry)
+	return entry
+}
+
+func (srv *testServer) currentValue(t *testing.T, id int) float64 {
+	t.Helper()
+
+	rr, _ := srv.makeRequest("GET", fmt.Sprintf("/api/target-trackers/%d", id), nil)
+	var tracker target.TargetTracker
+	json.Unmarshal(rr.Body.Bytes(), &tracker)
+	if tracker.CurrentValue == nil {
+		t.Fatalf("Expected a current value, got %s", rr.Body.String())
+	}
+	return *tracker.CurrentValue
+}
+
+func TestTrashEntries(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	srv.app.Clock = fixedClock{time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)}
+	rr, _ := srv.makeRequest("POST", "/api/target-trackers", target.CreateTargetRequest{
+		TrackerName: "Savings", StartValue: 0, GoalValue: 100, AddToTotal: true, StartDate: "2024-01-01", GoalDate: "2024-12-31",
+		Due: models.Due{Type: models.INTERVAL, IntervalType: "day", IntervalValue: 1},
+	})
+	var savings target.TargetTracker
+	json.Unmarshal(rr.Body.Bytes(), &savings)
+	first := srv.addEntry(t, models.TARGET, savings.ID, models.AddEntryRequest{Value: 10, Date: "2024-05-01"})
+	second := srv.addEntry(t, models.TARGET, savings.ID, models.AddEntryRequest{Value: 20, Date: "2024-05-02"})
+	third := srv.addEntry(t, models.TARGET, savings.ID, models.AddEntryRequest{Value: 30, Date: "2024-05-03"})
+
+	rr, _ = srv.makeRequest("DELETE", fmt.Sprintf("/api/entries/%d", third.ID), nil)
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("Expected status %d, got %d", http.StatusNoContent, rr.Code)
+	}
+	if value := srv.currentValue(t, savings.ID); value != 30 {
+		t.Errorf("Expected the deleted entry to be ignored, got current value %f", value)
+	}
+
+	// Deleted entries are hidden from reads and writes
+	rr, _ = srv.makeRequest("GET", "/api/entries", nil)
+	var entries []models.Entry
+	json.Unmarshal(rr.Body.Bytes(), &entries)
+	if len(entries) != 2 {
+		t.Errorf("Expected 2 entries, got %d", len(entries))
+	}
+	for _, req := range []struct{ method, url string }{
+		{"DELETE", fmt.Sprintf("/api/entries/%d", third.ID)},
+		{"PUT", fmt.Sprintf("/api/entries/%d", third.ID)},
+	} {
+		if rr, _ := srv.makeRequest(req.method, req.url, models.UpdateEntryRequest{}); rr.Code != http.StatusNotFound {
+			t.Errorf("Expected status %d for %s %s, got %d", http.StatusNotFound, req.method, req.url, rr.Code)
+		}
+	}
+
+	trashed := srv.trash(t, srv.token)
+	if len(trashed.Entries) != 1 || trashed.Entries[0].ID != third.ID || trashed.Entries[0].DeletedAt == nil || !trashed.Entries[0].DeletedAt.Equal(srv.app.Now()) {
+		t.Errorf("Expected the deleted entry in the trash, got %+v", trashed.Entries)
+	}
+	if trashed.RetentionDays != 30 || len(trashed.HabitTrackers) != 0 || len(trashed.TargetTrackers) != 0 {
+		t.Errorf("Unexpected trash %+v", trashed)
+	}
+
+	srv.makeRequest("DELETE", "/api/entries", []int{first.ID, second.ID})
+	if value := srv.currentValue(t, savings.ID); value != 0 {
+		t.Errorf("Expected the start value without entries, got %f", value)
+	}
+
+	// Undo the deletes
+	rr, _ = srv.makeRequest("POST", "/api/trash/entries/restore", []int{first.ID, second.ID, third.ID})
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusNoContent, rr.Code, rr.Body.String())
+	}
+	if value := srv.currentValue(t, savings.ID); value != 60 {
+		t.Errorf("Expected all entries to be restored, got current value %f", value)
+	}
+	if trashed := srv.trash(t, srv.token); len(trashed.Entries) != 0 {
+		t.Errorf("Expected an empty trash, got %+v", trashed.Entries)
+	}
+
+	if rr, _ := srv.makeRequest("POST", "/api/trash/entries/restore", []int{}); rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d without IDs, got %d", http.StatusBadRequest, rr.Code)
+	}
+}
+
+func TestTrashTrackers(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	srv.app.Clock = fixedClock{time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)}
+	daily := models.Due{Type: models.INTERVAL, IntervalType: "day", IntervalValue: 1}
+	reading := srv.createDatedHabit(t, "Reading", "2024-01-01", daily)
+	srv.createDatedHabit(t, "Running", "2024-01-01", daily)
+	early := srv.addEntry(t, models.HABIT, reading.ID, models.AddEntryRequest{Date: "2024-05-30"})
+	kept := srv.addEntry(t, models.HABIT, reading.ID, models.AddEntryRequest{Date: "2024-05-31"})
+	habitType := models.HABIT
+	srv.createPause(t, models.CreatePauseRequest{TrackerType: &habitType, TrackerID: &reading.ID, StartDate: "2024-06-10"})
+
+	// An entry deleted before its tracker stays in the trash when the tracker is restored
+	srv.makeRequest("DELETE", fmt.Sprintf("/api/entries/%d", early.ID), nil)
+	srv.app.Clock = fixedClock{time.Date(2024, 6, 1, 11, 0, 0, 0, time.UTC)}
+	rr, _ := srv.makeRequest("DELETE", fmt.Sprintf("/api/habit-trackers/%d", reading.ID), nil)
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("Expected status %d, got %d", http.StatusNoContent, rr.Code)
+	}
+
+	for _, url := range []string{
+		fmt.Sprintf("/api/habit-trackers/%d", reading.ID),
+		fmt.Sprintf("/api/habit-trackers/%d/entries", reading.ID),
+		fmt.Sprintf("/api/habit-trackers/%d/stats", reading.ID),
+	} {
+		if rr, _ := srv.makeRequest("GET", url, nil); rr.Code != http.StatusNotFound {
+			t.Errorf("Expected status %d for %s, got %d", http.StatusNotFound, url, rr.Code)
+		}
+	}
+	if rr, _ := srv.makeRequest("DELETE", fmt.Sprintf("/api/habit-trackers/%d", reading.ID), nil); rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d when deleting twice, got %d", http.StatusNotFound, rr.Code)
+	}
+	rr, _ = srv.makeRequest("GET", "/api/trackers?archived=all", nil)
+	var all trackers.TrackersResponse
+	json.Unmarshal(rr.Body.Bytes(), &all)
+	if len(all.HabitTrackers) != 1 || all.HabitTrackers[0].TrackerName != "Running" {
+		t.Errorf("Expected only the remaining habit, got %+v", all.HabitTrackers)
+	}
+	rr, _ = srv.makeRequest("GET", "/api/entries", nil)
+	var entries []models.Entry
+	json.Unmarshal(rr.Body.Bytes(), &entries)
+	if len(entries) != 0 {
+		t.Errorf("Expected the entries of the deleted habit to be hidden, got %d", len(entries))
+	}
+	rr, _ = srv.makeRequest("GET", "/api/pauses", nil)
+	var pauses []models.Pause
+	json.Unmarshal(rr.Body.Bytes(), &pauses)
+	if len(pauses) != 0 {
+		t.Errorf("Expected the pause of the deleted habit to be hidden, got %d", len(pauses))
+	}
+	if archive := srv.exportArchive(t, srv.token); len(archive.HabitTrackers) != 1 || len(archive.Entries) != 0 || len(archive.Pauses) != 0 {
+		t.Errorf("Expected the trash to be left out of the export, got %d habits, %d entries and %d pauses",
+			len(archive.HabitTrackers), len(archive.Entries), len(archive.Pauses))
+	}
+
+	// Entries of a deleted tracker are only listed and restored with it
+	trashed := srv.trash(t, srv.token)
+	if len(trashed.HabitTrackers) != 1 || trashed.HabitTrackers[0].DeletedAt == nil || len(trashed.Entries) != 0 {
+		t.Fatalf("Expected only the deleted habit in the trash, got %+v", trashed)
+	}
+	srv.makeRequest("POST", "/api/trash/entries/restore", []int{early.ID, kept.ID})
+	if trashed := srv.trash(t, srv.token); len(trashed.HabitTrackers) != 1 {
+		t.Errorf("Expected the habit to stay in the trash, got %+v", trashed)
+	}
+
+	rr, _ = srv.makeRequest("POST", fmt.Sprintf("/api/trash/habit-trackers/%d/restore", reading.ID), nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	var restored habit.HabitTracker
+	json.Unmarshal(rr.Body.Bytes(), &restored)
+	if restored.ID != reading.ID || restored.DeletedAt != nil {
+		t.Errorf("Expected the restored habit, got %+v", restored)
+	}
+	rr, _ = srv.makeRequest("GET", fmt.Sprintf("/api/habit-trackers/%d/entries", reading.ID), nil)
+	entries = nil
+	json.Unmarshal(rr.Body.Bytes(), &entries)
+	if len(entries) != 1 || entries[0].ID != kept.ID {
+		t.Errorf("Expected the entry deleted with the habit to be restored, got %+v", entries)
+	}
+	rr, _ = srv.makeRequest("GET", "/api/pauses", nil)
+	json.Unmarshal(rr.Body.Bytes(), &pauses)
+	if len(pauses) != 1 {
+		t.Errorf("Expected the pause to be back, got %d", len(pauses))
+	}
+	if trashed := srv.trash(t, srv.token); len(trashed.HabitTrackers) != 0 || len(trashed.Entries) != 1 || trashed.Entries[0].ID != early.ID {
+		t.Errorf("Expected only the early entry in the trash, got %+v", trashed)
+	}
+
+	for _, url := range []string{
+		fmt.Sprintf("/api/trash/habit-trackers/%d/restore", reading.ID),
+		"/api/trash/target-trackers/9999/restore",
+	} {
+		if rr, _ := srv.makeRequest("POST", url, nil); rr.Code != http.StatusNotFound {
+			t.Errorf("Expected status %d for %s, got %d", http.StatusNotFound, url, rr.Code)
+		}
+	}
+}
+
+func TestPurgeTrash(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+	otherToken, err := srv.registerAndLogin("other", "password123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	deletedAt := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	srv.app.Clock = fixedClock{deletedAt}
+	daily := models.Due{Type: models.INTERVAL, IntervalType: "day", IntervalValue: 1}
+	kept := srv.createDatedHabit(t, "Kept", "2024-01-01", daily)
+	deleted := srv.createDatedHabit(t, "Deleted", "2024-01-01", daily)
+	old := srv.addEntry(t, models.HABIT, kept.ID, models.AddEntryRequest{Date: "2024-05-30"})
+	recent := srv.addEntry(t, models.HABIT, kept.ID, models.AddEntryRequest{Date: "2024-05-31"})
+	srv.addEntry(t, models.HABIT, deleted.ID, models.AddEntryRequest{Date: "2024-05-31"})
+
+	srv.makeRequest("DELETE", fmt.Sprintf("/api/entries/%d", old.ID), nil)
+	srv.makeRequest("DELETE", fmt.Sprintf("/api/habit-trackers/%d", deleted.ID), nil)
+	srv.app.Clock = fixedClock{deletedAt.AddDate(0, 0, 20)}
+	srv.makeRequest("DELETE", fmt.Sprintf("/api/entries/%d", recent.ID), nil)
+
+	purger := trash.NewPurger(srv.app)
+	if purged, err := purger.Tick(deletedAt.AddDate(0, 0, 29)); err != nil || purged != 0 {
+		t.Fatalf("Expected nothing to be purged within the retention period, got %d and error %v", purged, err)
+	}
+	if trashed := srv.trash(t, srv.token); len(trashed.HabitTrackers) != 1 || len(trashed.Entries) != 2 {
+		t.Errorf("Expected the trash to be kept, got %+v", trashed)
+	}
+
+	// The old entry, the habit and its entry are purged
+	purged, err := purger.Tick(deletedAt.AddDate(0, 0, 31))
+	if err != nil || purged != 3 {
+		t.Fatalf("Expected 3 purged items, got %d and error %v", purged, err)
+	}
+	trashed := srv.trash(t, srv.token)
+	if len(trashed.HabitTrackers) != 0 || len(trashed.Entries) != 1 || trashed.Entries[0].ID != recent.ID {
+		t.Errorf("Expected only the recent entry in the trash, got %+v", trashed)
+	}
+	if rr, _ := srv.makeRequest("POST", fmt.Sprintf("/api/trash/habit-trackers/%d/restore", deleted.ID), nil); rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d for a purged habit, got %d", http.StatusNotFound, rr.Code)
+	}
+
+	// Emptying the trash leaves other users alone
+	rr, _ := srv.makeRequestWithToken(otherToken, "POST", "/api/habit-trackers", habit.CreateHabitRequest{
+		TrackerName: "Other", Goal: 1, TimePeriod: models.PER_DAY, StartDate: "2024-01-01", Due: daily,
+	})
+	var other habit.HabitTracker
+	json.Unmarshal(rr.Body.Bytes(), &other)
+	srv.makeRequestWithToken(otherToken, "DELETE", fmt.Sprintf("/api/habit-trackers/%d", other.ID), nil)
+
+	rr, _ = srv.makeRequest("DELETE", "/api/trash", nil)
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("Expected status %d, got %d", http.StatusNoContent, rr.Code)
+	}
+	if trashed := srv.trash(t, srv.token); len(trashed.Entries) != 0 {
+		t.Errorf("Expected an empty trash, got %+v", trashed)
+	}
+	if trashed := srv.trash(t, otherToken); len(trashed.HabitTrackers) != 1 {
+		t.Errorf("Expected the other user's trash to be kept, got %+v", trashed)
+	}
+}
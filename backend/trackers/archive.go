@@ -0,0 +1,179 @@
+package trackers
+
+import (
+	"fmt"
+	"routine-tracker/models"
+	"routine-tracker/trackers/duration"
+	"routine-tracker/trackers/habit"
+	"routine-tracker/trackers/target"
+	"time"
+)
+
+// ArchiveVersion is the version of the export format written by this server
+const ArchiveVersion = 1
+
+// Archive is a full export of a user's trackers, entries and pauses. Tracker,
+// category and tag IDs are only meaningful inside the archive, they are
+// remapped on import, and categories and tags are combined with existing ones
+// of the same name. Target segments keep the goal changes, so that entries
+// are read with the configuration of their date after an import too.
+type Archive struct {
+	Version          int                        `json:"version" example:"1"`
+	ExportedAt       time.Time                  `json:"exportedAt" example:"2024-01-01T10:00:00Z"`
+	HabitTrackers    []habit.HabitTracker       `json:"habitTrackers"`
+	TargetTrackers   []target.TargetTracker     `json:"targetTrackers"`
+	TargetSegments   []target.Segment           `json:"targetSegments,omitempty"`
+	DurationTrackers []duration.DurationTracker `json:"durationTrackers,omitempty"`
+	Entries          []models.Entry             `json:"entries"`
+	Pauses           []models.Pause             `json:"pauses,omitempty"`
+	Categories       []models.Category          `json:"categories,omitempty"`
+	Tags             []models.Tag               `json:"tags,omitempty"`
+}
+
+// ImportMode decides what happens to existing data on import
+type ImportMode string
+
+const (
+	IMPORT_MERGE   ImportMode = "merge"   // keep existing data, trackers with the same name and type are combined
+	IMPORT_REPLACE ImportMode = "replace" // delete all existing trackers, entries and pauses first
+)
+
+// ImportResult reports what an import changed, or would change in a dry run
+type ImportResult struct {
+	DryRun                  bool       `json:"dryRun" example:"false"`
+	Mode                    ImportMode `json:"mode" example:"merge"`
+	HabitTrackersCreated    int        `json:"habitTrackersCreated" example:"2"`
+	TargetTrackersCreated   int        `json:"targetTrackersCreated" example:"1"`
+	DurationTrackersCreated int        `json:"durationTrackersCreated" example:"1"`
+	TrackersMerged          int        `json:"trackersMerged" example:"1"` // imported into an existing tracker with the same name
+	EntriesCreated          int        `json:"entriesCreated" example:"120"`
+	EntriesSkipped          int        `json:"entriesSkipped" example:"3"` // identical entries that already existed
+	PausesCreated           int        `json:"pausesCreated" example:"1"`
+	CategoriesCreated       int        `json:"categoriesCreated" example:"1"` // categories and tags with a new name
+	TagsCreated             int        `json:"tagsCreated" example:"2"`
+	TrackersDeleted         int        `json:"trackersDeleted" example:"0"` // replace mode only
+	EntriesDeleted          int        `json:"entriesDeleted" example:"0"`  // replace mode only
+}
+
+// Validate checks that the archive can be imported as a whole
+func (a Archive) Validate() error {
+	if a.Version < 1 || a.Version > ArchiveVersion {
+		return fmt.Errorf("unsupported archive version %d, this server reads version %d", a.Version, ArchiveVersion)
+	}
+
+	habits := make(map[int]bool)
+	for i, h := range a.HabitTrackers {
+		if h.TrackerName == "" {
+			return fmt.Errorf("habit tracker %d has no name", i+1)
+		}
+		if err := ValidateDue(h.Due); err != nil {
+			return fmt.Errorf("habit tracker %d has an invalid due: %w", i+1, err)
+		}
+		if habits[h.ID] {
+			return fmt.Errorf("habit tracker ID %d appears more than once", h.ID)
+		}
+		habits[h.ID] = true
+	}
+
+	targets := make(map[int]bool)
+	for i, t := range a.TargetTrackers {
+		if t.TrackerName == "" {
+			return fmt.Errorf("target tracker %d has no name", i+1)
+		}
+		if err := ValidateDue(t.Due); err != nil {
+			return fmt.Errorf("target tracker %d has an invalid due: %w", i+1, err)
+		}
+		if targets[t.ID] {
+			return fmt.Errorf("target tracker ID %d appears more than once", t.ID)
+		}
+		targets[t.ID] = true
+	}
+
+	for i, s := range a.TargetSegments {
+		if !targets[s.TrackerID] {
+			return fmt.Errorf("target segment %d belongs to unknown target tracker %d", i+1, s.TrackerID)
+		}
+		if s.EffectiveFrom.IsZero() {
+			return fmt.Errorf("target segment %d has no effectiveFrom date", i+1)
+		}
+	}
+
+	durations := make(map[int]bool)
+	for i, d := range a.DurationTrackers {
+		if d.TrackerName == "" {
+			return fmt.Errorf("duration tracker %d has no name", i+1)
+		}
+		if err := ValidateDue(d.Due); err != nil {
+			return fmt.Errorf("duration tracker %d has an invalid due: %w", i+1, err)
+		}
+		if durations[d.ID] {
+			return fmt.Errorf("duration tracker ID %d appears more than once", d.ID)
+		}
+		durations[d.ID] = true
+	}
+
+	categories := make(map[int]bool)
+	for i, c := range a.Categories {
+		if c.Name == "" {
+			return fmt.Errorf("category %d has no name", i+1)
+		}
+		if categories[c.ID] {
+			return fmt.Errorf("category ID %d appears more than once", c.ID)
+		}
+		categories[c.ID] = true
+	}
+
+	tags := make(map[int]bool)
+	for i, t := range a.Tags {
+		if t.Name == "" {
+			return fmt.Errorf("tag %d has no name", i+1)
+		}
+		if tags[t.ID] {
+			return fmt.Errorf("tag ID %d appears more than once", t.ID)
+		}
+		tags[t.ID] = true
+	}
+
+	for i, e := range a.Entries {
+		switch e.Type {
+		case models.HABIT:
+			if !habits[e.TrackerID] {
+				return fmt.Errorf("entry %d belongs to unknown habit tracker %d", i+1, e.TrackerID)
+			}
+		case models.TARGET:
+			if !targets[e.TrackerID] {
+				return fmt.Errorf("entry %d belongs to unknown target tracker %d", i+1, e.TrackerID)
+			}
+		case models.DURATION:
+			if !durations[e.TrackerID] {
+				return fmt.Errorf("entry %d belongs to unknown duration tracker %d", i+1, e.TrackerID)
+			}
+		default:
+			return fmt.Errorf("entry %d has invalid type '%s'", i+1, e.Type)
+		}
+		if e.Date.IsZero() {
+			return fmt.Errorf("entry %d has no date", i+1)
+		}
+	}
+
+	for i, p := range a.Pauses {
+		if (p.TrackerType == nil) != (p.TrackerID == nil) {
+			return fmt.Errorf("pause %d needs both a tracker type and ID or neither", i+1)
+		}
+		if p.TrackerType != nil {
+			known := map[models.TrackerType]map[int]bool{models.HABIT: habits, models.TARGET: targets, models.DURATION: durations}
+			ids, ok := known[*p.TrackerType]
+			if !ok {
+				return fmt.Errorf("pause %d has invalid tracker type '%s'", i+1, *p.TrackerType)
+			}
+			if !ids[*p.TrackerID] {
+				return fmt.Errorf("pause %d belongs to unknown %s tracker %d", i+1, *p.TrackerType, *p.TrackerID)
+			}
+		}
+		if p.StartDate.IsZero() || p.EndDate.Before(p.StartDate) {
+			return fmt.Errorf("pause %d has an invalid date range", i+1)
+		}
+	}
+
+	return nil
+}
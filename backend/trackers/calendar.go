@@ -0,0 +1,87 @@
+package trackers
+
+import (
+	"fmt"
+	"routine-tracker/calendar"
+	"routine-tracker/models"
+	"routine-tracker/trackers/duration"
+	"routine-tracker/trackers/habit"
+	"routine-tracker/trackers/target"
+	"strconv"
+	"time"
+)
+
+// CalendarEvents turns the due schedules of trackers into repeating all-day
+// events with their reminders as alarms, and target goal dates into single
+// events. UIDs only depend on the tracker, so calendar apps update the events
+// in place when a tracker changes.
+func CalendarEvents(habits []habit.HabitTracker, targets []target.TargetTracker, durations []duration.DurationTracker) []calendar.Event {
+	events := make([]calendar.Event, 0)
+
+	for _, h := range habits {
+		description := fmt.Sprintf("Goal: %s %s", formatNumber(h.Goal), h.TimePeriod)
+		if event, ok := dueEvent(fmt.Sprintf("habit-%d", h.ID), h.TrackerName, description, h.Due, h.StartDate, h.Reminders); ok {
+			events = append(events, event)
+		}
+	}
+
+	for _, t := range targets {
+		description := fmt.Sprintf("From %s to %s by %s", formatNumber(t.StartValue), formatNumber(t.GoalValue), t.GoalDate.Format("2006-01-02"))
+		if event, ok := dueEvent(fmt.Sprintf("target-%d", t.ID), t.TrackerName, description, t.Due, t.StartDate, t.Reminders); ok {
+			events = append(events, event)
+		}
+
+		events = append(events, calendar.Event{
+			UID:         fmt.Sprintf("target-%d-goal@progress", t.ID),
+			Summary:     "Goal: " + t.TrackerName,
+			Description: fmt.Sprintf("Reach %s", formatNumber(t.GoalValue)),
+			Date:        t.GoalDate,
+		})
+	}
+
+	for _, d := range durations {
+		description := fmt.Sprintf("Goal: %s %s", time.Duration(d.Goal)*time.Second, d.TimePeriod)
+		if event, ok := dueEvent(fmt.Sprintf("duration-%d", d.ID), d.TrackerName, description, d.Due, d.StartDate, d.Reminders); ok {
+			events = append(events, event)
+		}
+	}
+
+	return events
+}
+
+// dueEvent returns the repeating event of a due schedule. It starts on the
+// first due day because iCalendar always counts the start as an occurrence.
+func dueEvent(id, name, description string, due models.Due, startDate time.Time, reminders models.Reminder) (calendar.Event, bool) {
+	rule, err := DueRule(due)
+	if err != nil {
+		return calendar.Event{}, false
+	}
+	first := rule.Next(startDate, startDate, 1)
+	if len(first) == 0 {
+		return calendar.Event{}, false
+	}
+
+	event := calendar.Event{
+		UID:         id + "@progress",
+		Summary:     name,
+		Description: description,
+		Date:        first[0],
+		RRule:       rule.String(),
+	}
+
+	if reminders.Enabled {
+		for _, at := range reminders.Times {
+			parsed, err := time.Parse("15:04", at)
+			if err != nil {
+				continue
+			}
+			event.Alarms = append(event.Alarms, time.Duration(parsed.Hour())*time.Hour+time.Duration(parsed.Minute())*time.Minute)
+		}
+	}
+
+	return event, true
+}
+
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,100 @@
+package trackers
+
+import (
+	"routine-tracker/models"
+	"routine-tracker/trackers/duration"
+	"routine-tracker/trackers/habit"
+	"routine-tracker/trackers/target"
+)
+
+// TrackerGroup holds the trackers of one category. Category is null for the
+// group of uncategorized trackers.
+type TrackerGroup struct {
+	Category         *models.Category           `json:"category"`
+	HabitTrackers    []habit.HabitTracker       `json:"habitTrackers"`
+	TargetTrackers   []target.TargetTracker     `json:"targetTrackers"`
+	DurationTrackers []duration.DurationTracker `json:"durationTrackers"`
+	Order            []TrackerRef               `json:"order"` // trackers of all types interleaved in the user-defined order
+}
+
+// GroupByCategory groups trackers in the order of the given categories,
+// uncategorized trackers come last. Groups without trackers are left out.
+func GroupByCategory(categories []models.Category, habits []habit.HabitTracker, targets []target.TargetTracker, durations []duration.DurationTracker) []TrackerGroup {
+	groups := make([]TrackerGroup, 0)
+	grouped := make(map[int]bool)
+
+	add := func(category *models.Category, matches func(categoryID *int) bool) {
+		group := TrackerGroup{
+			Category:         category,
+			HabitTrackers:    make([]habit.HabitTracker, 0),
+			TargetTrackers:   make([]target.TargetTracker, 0),
+			DurationTrackers: make([]duration.DurationTracker, 0),
+		}
+		for _, h := range habits {
+			if matches(h.CategoryID) {
+				group.HabitTrackers = append(group.HabitTrackers, h)
+			}
+		}
+		for _, t := range targets {
+			if matches(t.CategoryID) {
+				group.TargetTrackers = append(group.TargetTrackers, t)
+			}
+		}
+		for _, d := range durations {
+			if matches(d.CategoryID) {
+				group.DurationTrackers = append(group.DurationTrackers, d)
+			}
+		}
+		if len(group.HabitTrackers) > 0 || len(group.TargetTrackers) > 0 || len(group.DurationTrackers) > 0 {
+			group.Order = Order(group.HabitTrackers, group.TargetTrackers, group.DurationTrackers)
+			groups = append(groups, group)
+		}
+	}
+
+	for i := range categories {
+		category := categories[i]
+		grouped[category.ID] = true
+		add(&category, func(categoryID *int) bool {
+			return categoryID != nil && *categoryID == category.ID
+		})
+	}
+	// Trackers of unknown categories are treated as uncategorized
+	add(nil, func(categoryID *int) bool {
+		return categoryID == nil || !grouped[*categoryID]
+	})
+
+	return groups
+}
+
+// FilterHabits returns the habit trackers passing the filter
+func FilterHabits(habits []habit.HabitTracker, filter models.TrackerFilter, categories []models.Category) []habit.HabitTracker {
+	var filtered []habit.HabitTracker
+	for _, h := range habits {
+		if filter.Matches(categories, h.CategoryID, h.Tags) {
+			filtered = append(filtered, h)
+		}
+	}
+	return filtered
+}
+
+// FilterTargets returns the target trackers passing the filter
+func FilterTargets(targets []target.TargetTracker, filter models.TrackerFilter, categories []models.Category) []target.TargetTracker {
+	var filtered []target.TargetTracker
+	for _, t := range targets {
+		if filter.Matches(categories, t.CategoryID, t.Tags) {
+			filtered = append(filtered, t)
+		}
+	}
+	return filtered
+}
+
+// FilterDurations returns the duration trackers passing the filter
+func FilterDurations(durations []duration.DurationTracker, filter models.TrackerFilter, categories []models.Category) []duration.DurationTracker {
+	var filtered []duration.DurationTracker
+	for _, d := range durations {
+		if filter.Matches(categories, d.CategoryID, d.Tags) {
+			filtered = append(filtered, d)
+		}
+	}
+	return filtered
+}
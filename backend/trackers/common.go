@@ -1,16 +1,57 @@
 package trackers
 
+import "routine-tracker/models"
+import "routine-tracker/trackers/duration"
 import "routine-tracker/trackers/habit"
 import "routine-tracker/trackers/target"
 
 type TrackersResponse struct {
-	HabitTrackers  []habit.HabitTracker  `json:"habitTrackers"`
-	TargetTrackers []target.TargetTracker `json:"targetTrackers"`
+	HabitTrackers    []habit.HabitTracker       `json:"habitTrackers"`
+	TargetTrackers   []target.TargetTracker     `json:"targetTrackers"`
+	DurationTrackers []duration.DurationTracker `json:"durationTrackers"`
+	Groups           []TrackerGroup             `json:"groups"` // the same trackers grouped by category
+	Order            []TrackerRef               `json:"order"`  // trackers of all types interleaved in the user-defined order
 }
 
-
 type DashboardResponse struct {
-	Date           string          `json:"date" example:"2024-01-01"`
-	HabitTrackers  []habit.HabitTracker  `json:"habitTrackers"`
-	TargetTrackers []target.TargetTracker `json:"targetTrackers"`
+	Date             string                     `json:"date" example:"2024-01-01"`
+	HabitTrackers    []habit.HabitTracker       `json:"habitTrackers"`
+	TargetTrackers   []target.TargetTracker     `json:"targetTrackers"`
+	DurationTrackers []duration.DurationTracker `json:"durationTrackers"`
+	Groups           []TrackerGroup             `json:"groups"` // the same trackers grouped by category
+	Order            []TrackerRef               `json:"order"`  // trackers of all types interleaved in the user-defined order
+}
+
+// TrashResponse lists the deleted trackers and entries that can still be
+// restored. Entries of deleted trackers are restored with their tracker and
+// not listed on their own.
+type TrashResponse struct {
+	HabitTrackers    []habit.HabitTracker       `json:"habitTrackers"`
+	TargetTrackers   []target.TargetTracker     `json:"targetTrackers"`
+	DurationTrackers []duration.DurationTracker `json:"durationTrackers"`
+	Entries          []models.Entry             `json:"entries"`
+	RetentionDays    int                        `json:"retentionDays" example:"30"` // days before deleted items are purged
+}
+
+// AgendaDay lists the trackers due on one day of an agenda
+type AgendaDay struct {
+	Date             string                     `json:"date" example:"2024-01-01"`
+	HabitTrackers    []habit.HabitTracker       `json:"habitTrackers"`
+	TargetTrackers   []target.TargetTracker     `json:"targetTrackers"`
+	DurationTrackers []duration.DurationTracker `json:"durationTrackers"`
+}
+
+// AgendaResponse lists every day from From to To with its due trackers
+type AgendaResponse struct {
+	From string      `json:"from" example:"2024-01-01"`
+	To   string      `json:"to" example:"2024-01-07"`
+	Days []AgendaDay `json:"days"`
+}
+
+// NextDueResponse lists the next days a tracker is due on
+type NextDueResponse struct {
+	TrackerID int                `json:"trackerId" example:"1"`
+	Type      models.TrackerType `json:"type" example:"habit"`
+	From      string             `json:"from" example:"2024-01-01"`
+	Dates     []string           `json:"dates" example:"2024-01-01,2024-01-08"`
 }
@@ -0,0 +1,149 @@
+package duration
+
+import (
+	"math"
+	"routine-tracker/models"
+	"time"
+)
+
+// Period represents a single time period bucket of a duration tracker
+type Period struct {
+	Start     time.Time `json:"start" example:"2024-01-01T00:00:00Z"`
+	End       time.Time `json:"end" example:"2024-01-07T23:59:59Z"`
+	Total     int       `json:"total" example:"2700"` // seconds spent in the period
+	Goal      int       `json:"goal" example:"1800"`  // seconds needed, less in periods with days off
+	GoalMet   bool      `json:"goalMet" example:"true"`
+	IsCurrent bool      `json:"isCurrent" example:"false"`
+	DaysOff   int       `json:"daysOff" example:"0"`     // paused days in the period
+	Skipped   bool      `json:"skipped" example:"false"` // every day is off, the period does not count
+}
+
+// Stats represents the computed totals of a duration tracker
+type Stats struct {
+	TrackerID        int               `json:"trackerId" example:"1"`
+	TimePeriod       models.TimePeriod `json:"timePeriod" example:"perDay"`
+	Goal             int               `json:"goal" example:"1800"`
+	Date             string            `json:"date" example:"2024-01-15"`
+	CurrentTotal     int               `json:"currentTotal" example:"900"`    // seconds spent in the current period
+	CurrentProgress  float64           `json:"currentProgress" example:"0.5"` // current total / goal of the current period, can exceed 1
+	TotalDuration    int               `json:"totalDuration" example:"36000"` // seconds spent since the start date
+	CompletedPeriods int               `json:"completedPeriods" example:"10"`
+	TotalPeriods     int               `json:"totalPeriods" example:"14"`
+	SkippedPeriods   int               `json:"skippedPeriods" example:"2"`    // left out of the completion rate
+	CompletionRate   float64           `json:"completionRate" example:"0.71"` // between 0 and 1
+	Periods          []Period          `json:"periods"`
+}
+
+// CalculatePeriods splits the time between the tracker's start date and today
+// into period buckets and adds up the durations of the entries falling into
+// each of them. Entries count on the day they started in today's time zone.
+// Periods made of paused days only are skipped, periods with some days off
+// need proportionally less time.
+func CalculatePeriods(d DurationTracker, entries []models.Entry, daysOff models.DaysOff, today time.Time) []Period {
+	periods := make([]Period, 0)
+
+	todayDay := dayOf(today)
+	start := dayOf(d.StartDate)
+	if start.After(todayDay) || !d.TimePeriod.Valid() {
+		return periods
+	}
+
+	for periodStart := d.TimePeriod.Start(start); !periodStart.After(todayDay); periodStart = d.TimePeriod.Next(periodStart) {
+		next := d.TimePeriod.Next(periodStart)
+
+		days, off := 0, 0
+		for day := periodStart; day.Before(next); day = day.AddDate(0, 0, 1) {
+			days++
+			if daysOff.Contains(models.DURATION, d.ID, day) {
+				off++
+			}
+		}
+
+		total := totalBetween(entries, periodStart, next, today.Location())
+		goal := int(math.Ceil(float64(d.Goal) * float64(days-off) / float64(days)))
+		periods = append(periods, Period{
+			Start:     periodStart,
+			End:       next.Add(-time.Nanosecond),
+			Total:     total,
+			Goal:      goal,
+			GoalMet:   total >= goal && off < days,
+			IsCurrent: !todayDay.Before(periodStart) && todayDay.Before(next),
+			DaysOff:   off,
+			Skipped:   off == days,
+		})
+	}
+
+	return periods
+}
+
+// CalculateStats computes the periods, totals and completion rate. The current
+// period only counts once its goal is met.
+func CalculateStats(d DurationTracker, entries []models.Entry, daysOff models.DaysOff, today time.Time) Stats {
+	periods := CalculatePeriods(d, entries, daysOff, today)
+
+	stats := Stats{
+		TrackerID:  d.ID,
+		TimePeriod: d.TimePeriod,
+		Goal:       d.Goal,
+		Date:       today.Format("2006-01-02"),
+		Periods:    periods,
+	}
+
+	for _, period := range periods {
+		stats.TotalDuration += period.Total
+		if period.IsCurrent {
+			stats.CurrentTotal = period.Total
+			if period.Goal > 0 {
+				stats.CurrentProgress = float64(period.Total) / float64(period.Goal)
+			}
+		}
+
+		if period.Skipped {
+			stats.SkippedPeriods++
+			continue
+		}
+		if period.IsCurrent && !period.GoalMet {
+			continue
+		}
+		stats.TotalPeriods++
+		if period.GoalMet {
+			stats.CompletedPeriods++
+		}
+	}
+
+	if stats.TotalPeriods > 0 {
+		stats.CompletionRate = float64(stats.CompletedPeriods) / float64(stats.TotalPeriods)
+	}
+
+	return stats
+}
+
+// CurrentTotal returns the seconds spent in the period containing today
+func CurrentTotal(d DurationTracker, entries []models.Entry, today time.Time) int {
+	if !d.TimePeriod.Valid() {
+		return 0
+	}
+	start := d.TimePeriod.Start(dayOf(today))
+	return totalBetween(entries, start, d.TimePeriod.Next(start), today.Location())
+}
+
+// totalBetween adds up the durations of the entries on the days from start up
+// to but excluding end, entry dates are assigned to days in loc
+func totalBetween(entries []models.Entry, start, end time.Time, loc *time.Location) int {
+	total := 0
+	for _, entry := range entries {
+		if entry.Duration == nil {
+			continue
+		}
+		entryDay := dayOf(entry.Date.In(loc))
+		if !entryDay.Before(start) && entryDay.Before(end) {
+			total += *entry.Duration
+		}
+	}
+	return total
+}
+
+// dayOf strips the time of day, keeping the calendar date of t in its own location
+func dayOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,66 @@
+package duration
+
+import (
+	"routine-tracker/models"
+	"time"
+)
+
+// DurationTracker represents a time tracking configuration, e.g. practice
+// minutes or deep work. Time is logged with timers or as entries with a
+// duration and adds up per time period towards the goal.
+type DurationTracker struct {
+	ID           int               `json:"id" example:"1"`
+	UserID       int               `json:"-"`
+	TrackerName  string            `json:"trackerName" example:"Guitar Practice"`
+	Goal         int               `json:"goal" example:"1800"`         // seconds per time period
+	TimePeriod   models.TimePeriod `json:"timePeriod" example:"perDay"` // per day, week, month, year
+	StartDate    time.Time         `json:"startDate" example:"2024-01-01T00:00:00Z"`
+	Due          models.Due        `json:"due"`
+	Reminders    models.Reminder   `json:"reminders"`
+	CategoryID   *int              `json:"categoryId" example:"1"`
+	Tags         []models.Tag      `json:"tags"`
+	Position     int               `json:"position" example:"0"` // place in the user-defined order, see PUT /trackers/order
+	Pinned       bool              `json:"pinned" example:"false"`
+	Timer        *Timer            `json:"timer"`                                // the running timer, null when stopped
+	CurrentTotal *int              `json:"currentTotal,omitempty" example:"900"` // Calculated field, seconds spent in the current period
+	CreatedAt    time.Time         `json:"createdAt" example:"2024-01-01T10:00:00Z"`
+	ArchivedAt   *time.Time        `json:"archivedAt,omitempty" example:"2024-06-01T10:00:00Z"` // set while the tracker is archived
+	DeletedAt    *time.Time        `json:"deletedAt,omitempty" example:"2024-06-01T10:00:00Z"`  // set while the tracker is in the trash
+}
+
+// Timer is a running timer of a duration tracker. Stopping it turns it into
+// an entry dated at StartedAt.
+type Timer struct {
+	ID        int       `json:"id" example:"1"`
+	TrackerID int       `json:"trackerId" example:"1"`
+	StartedAt time.Time `json:"startedAt" example:"2024-01-01T10:00:00Z"`
+	Note      string    `json:"note,omitempty" example:"Scales"`
+}
+
+type CreateDurationRequest struct {
+	TrackerName string            `json:"trackerName" example:"Guitar Practice"`
+	Goal        int               `json:"goal" example:"1800"` // seconds per time period
+	TimePeriod  models.TimePeriod `json:"timePeriod" example:"perDay"`
+	StartDate   string            `json:"startDate" example:"2024-01-01"` // "2024-01-01" format
+	Due         models.Due        `json:"due"`
+	Reminders   models.Reminder   `json:"reminders,omitempty"`
+	CategoryID  *int              `json:"categoryId,omitempty" example:"1"`
+	TagIDs      []int             `json:"tagIds,omitempty"`
+	Pinned      bool              `json:"pinned" example:"false"`
+}
+
+type UpdateDurationRequest struct {
+	TrackerName *string            `json:"trackerName,omitempty"`
+	Goal        *int               `json:"goal,omitempty"`
+	TimePeriod  *models.TimePeriod `json:"timePeriod,omitempty"`
+	StartDate   *string            `json:"startDate,omitempty"`
+	Due         *models.Due        `json:"due,omitempty"`
+	Reminders   *models.Reminder   `json:"reminders,omitempty"`
+	CategoryID  *int               `json:"categoryId,omitempty"` // 0 removes the tracker from its category
+	TagIDs      *[]int             `json:"tagIds,omitempty"`     // replaces the tags of the tracker
+	Pinned      *bool              `json:"pinned,omitempty"`
+}
+
+type StartTimerRequest struct {
+	Note string `json:"note,omitempty" example:"Scales"` // becomes the note of the entry
+}
@@ -0,0 +1,211 @@
+package habit
+
+import (
+	"math"
+	"routine-tracker/models"
+	"time"
+)
+
+// Period represents a single time period bucket of a habit tracker
+type Period struct {
+	Start     time.Time `json:"start" example:"2024-01-01T00:00:00Z"`
+	End       time.Time `json:"end" example:"2024-01-07T23:59:59Z"`
+	Count     int       `json:"count" example:"3"` // number of done entries in the period
+	GoalMet   bool      `json:"goalMet" example:"true"`
+	IsCurrent bool      `json:"isCurrent" example:"false"`
+	DaysOff   int       `json:"daysOff" example:"0"`     // paused or skipped days in the period
+	Skipped   bool      `json:"skipped" example:"false"` // every day is off, the period does not count
+}
+
+// Stats represents the computed analytics of a habit tracker
+type Stats struct {
+	TrackerID          int               `json:"trackerId" example:"1"`
+	TimePeriod         models.TimePeriod `json:"timePeriod" example:"perDay"`
+	Goal               float64           `json:"goal" example:"1"`
+	BadHabit           bool              `json:"badHabit" example:"false"`
+	Date               string            `json:"date" example:"2024-01-15"`
+	CurrentStreak      int               `json:"currentStreak" example:"5"` // negative when the goal was missed in a row
+	BestStreak         int               `json:"bestStreak" example:"12"`   // negative when the goal was never met
+	CompletedPeriods   int               `json:"completedPeriods" example:"10"`
+	TotalPeriods       int               `json:"totalPeriods" example:"14"`
+	SkippedPeriods     int               `json:"skippedPeriods" example:"2"`    // left out of streaks and the completion rate
+	CompletionRate     float64           `json:"completionRate" example:"0.71"` // between 0 and 1
+	GoalStreak         *int              `json:"goalStreak" example:"30"`
+	GoalStreakProgress *float64          `json:"goalStreakProgress" example:"0.16"` // current streak / goal streak, capped at 1
+	Periods            []Period          `json:"periods"`
+}
+
+// CalculatePeriods splits the time between the tracker's start date and today
+// into period buckets and counts the done entries falling into each of them.
+// Entries are assigned to days in today's time zone. Periods made of days off
+// only are skipped, good habits need proportionally less in periods with some
+// days off.
+func CalculatePeriods(h HabitTracker, entries []models.Entry, daysOff models.DaysOff, today time.Time) []Period {
+	periods := make([]Period, 0)
+
+	todayDay := dayOf(today)
+	start := dayOf(h.StartDate)
+	if start.After(todayDay) {
+		return periods
+	}
+
+	if !h.TimePeriod.Valid() {
+		return periods
+	}
+	// Align the first period with the beginning of its calendar unit
+	start = h.TimePeriod.Start(start)
+
+	for periodStart := start; !periodStart.After(todayDay); periodStart = h.TimePeriod.Next(periodStart) {
+		next := h.TimePeriod.Next(periodStart)
+
+		count := 0
+		for _, entry := range entries {
+			if entry.Done == nil || !*entry.Done {
+				continue
+			}
+			entryDay := dayOf(entry.Date.In(today.Location()))
+			if !entryDay.Before(periodStart) && entryDay.Before(next) {
+				count++
+			}
+		}
+
+		days, off := 0, 0
+		for day := periodStart; day.Before(next); day = day.AddDate(0, 0, 1) {
+			days++
+			if daysOff.Contains(models.HABIT, h.ID, day) {
+				off++
+			}
+		}
+
+		goalMet := float64(count) >= math.Ceil(h.Goal*float64(days-off)/float64(days))
+		if h.BadHabit {
+			goalMet = float64(count) <= h.Goal
+		}
+
+		periods = append(periods, Period{
+			Start:     periodStart,
+			End:       next.Add(-time.Nanosecond),
+			Count:     count,
+			GoalMet:   goalMet && off < days,
+			IsCurrent: !todayDay.Before(periodStart) && todayDay.Before(next),
+			DaysOff:   off,
+			Skipped:   off == days,
+		})
+	}
+
+	return periods
+}
+
+// CalculateStreaks returns the current and best streak of the given periods.
+// A current period whose goal is not met yet does not break the streak.
+// Streaks of missed periods are reported as negative numbers. Skipped periods
+// neither continue nor break a streak.
+func CalculateStreaks(periods []Period) (current int, best int) {
+	var isPositive *bool
+	for i := len(periods) - 1; i >= 0; i-- {
+		period := periods[i]
+		// Skip current incomplete period if goal not met
+		if (period.IsCurrent && !period.GoalMet) || period.Skipped {
+			continue
+		}
+
+		if isPositive == nil {
+			goalMet := period.GoalMet
+			isPositive = &goalMet
+			current = 1
+		} else if period.GoalMet == *isPositive {
+			current++
+		} else {
+			break
+		}
+	}
+	if isPositive != nil && !*isPositive {
+		current = -current
+	}
+
+	maxPositive, maxNegative := 0, 0
+	runPositive, runNegative := 0, 0
+	for _, period := range periods {
+		if (period.IsCurrent && !period.GoalMet) || period.Skipped {
+			continue
+		}
+		if period.GoalMet {
+			runPositive++
+			runNegative = 0
+			if runPositive > maxPositive {
+				maxPositive = runPositive
+			}
+		} else {
+			runNegative++
+			runPositive = 0
+			if runNegative > maxNegative {
+				maxNegative = runNegative
+			}
+		}
+	}
+
+	if maxPositive > 0 {
+		return current, maxPositive
+	}
+	return current, -maxNegative
+}
+
+// CalculateStats computes periods, streaks, completion rate and goal streak progress
+func CalculateStats(h HabitTracker, entries []models.Entry, daysOff models.DaysOff, today time.Time) Stats {
+	periods := CalculatePeriods(h, entries, daysOff, today)
+	current, best := CalculateStreaks(periods)
+
+	// The current period only counts once it is decided, bad habits count it
+	// right away because every logged entry can only make it worse
+	completed, total, skipped := 0, 0, 0
+	for _, period := range periods {
+		if period.Skipped {
+			skipped++
+			continue
+		}
+		if period.IsCurrent && !period.GoalMet && !h.BadHabit {
+			continue
+		}
+		total++
+		if period.GoalMet {
+			completed++
+		}
+	}
+
+	stats := Stats{
+		TrackerID:        h.ID,
+		TimePeriod:       h.TimePeriod,
+		Goal:             h.Goal,
+		BadHabit:         h.BadHabit,
+		Date:             today.Format("2006-01-02"),
+		CurrentStreak:    current,
+		BestStreak:       best,
+		CompletedPeriods: completed,
+		TotalPeriods:     total,
+		SkippedPeriods:   skipped,
+		GoalStreak:       h.GoalStreak,
+		Periods:          periods,
+	}
+
+	if total > 0 {
+		stats.CompletionRate = float64(completed) / float64(total)
+	}
+
+	if h.GoalStreak != nil && *h.GoalStreak > 0 {
+		progress := 0.0
+		if current > 0 {
+			progress = float64(current) / float64(*h.GoalStreak)
+			if progress > 1 {
+				progress = 1
+			}
+		}
+		stats.GoalStreakProgress = &progress
+	}
+
+	return stats
+}
+
+// dayOf strips the time of day, keeping the calendar date of t in its own location
+func dayOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
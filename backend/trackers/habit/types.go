@@ -8,15 +8,22 @@ import (
 // HabitTracker represents a habit tracking configuration
 type HabitTracker struct {
 	ID          int               `json:"id" example:"1"`
+	UserID      int               `json:"-"`
 	TrackerName string            `json:"trackerName" example:"Drink Water"`
-	Goal        float64           `json:"goal" example:"8"`              // how many times
+	Goal        float64           `json:"goal" example:"8"`             // how many times
 	TimePeriod  models.TimePeriod `json:"timePeriod" example:"per_day"` // per day, week, month, year
 	StartDate   time.Time         `json:"startDate" example:"2024-01-01T00:00:00Z"`
 	Due         models.Due        `json:"due"`
 	Reminders   models.Reminder   `json:"reminders"`
 	BadHabit    bool              `json:"badHabit" example:"false"`
 	GoalStreak  *int              `json:"goalStreak" example:"30"` // null or int
+	CategoryID  *int              `json:"categoryId" example:"1"`
+	Tags        []models.Tag      `json:"tags"`
+	Position    int               `json:"position" example:"0"` // place in the user-defined order, see PUT /trackers/order
+	Pinned      bool              `json:"pinned" example:"false"`
 	CreatedAt   time.Time         `json:"createdAt" example:"2024-01-01T10:00:00Z"`
+	ArchivedAt  *time.Time        `json:"archivedAt,omitempty" example:"2024-06-01T10:00:00Z"` // set while the tracker is archived
+	DeletedAt   *time.Time        `json:"deletedAt,omitempty" example:"2024-06-01T10:00:00Z"`  // set while the tracker is in the trash
 }
 
 // API Request/Response structures
@@ -29,6 +36,9 @@ type CreateHabitRequest struct {
 	Reminders   models.Reminder   `json:"reminders,omitempty"`
 	BadHabit    bool              `json:"badHabit" example:"false"`
 	GoalStreak  *int              `json:"goalStreak" example:"30"`
+	CategoryID  *int              `json:"categoryId,omitempty" example:"1"`
+	TagIDs      []int             `json:"tagIds,omitempty"`
+	Pinned      bool              `json:"pinned" example:"false"`
 }
 
 type UpdateHabitRequest struct {
@@ -40,4 +50,7 @@ type UpdateHabitRequest struct {
 	Reminders   *models.Reminder   `json:"reminders,omitempty"`
 	BadHabit    *bool              `json:"badHabit,omitempty"`
 	GoalStreak  *int               `json:"goalStreak,omitempty"`
+	CategoryID  *int               `json:"categoryId,omitempty"` // 0 removes the tracker from its category
+	TagIDs      *[]int             `json:"tagIds,omitempty"`     // replaces the tags of the tracker
+	Pinned      *bool              `json:"pinned,omitempty"`
 }
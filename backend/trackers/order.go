@@ -0,0 +1,55 @@
+package trackers
+
+import (
+	"routine-tracker/models"
+	"routine-tracker/trackers/duration"
+	"routine-tracker/trackers/habit"
+	"routine-tracker/trackers/target"
+	"sort"
+	"time"
+)
+
+// TrackerRef points to a tracker in a list of trackers of all types
+type TrackerRef struct {
+	Type models.TrackerType `json:"type" example:"habit"`
+	ID   int                `json:"id" example:"1"`
+}
+
+// Order interleaves trackers of all types in the user-defined order: pinned
+// trackers first, then by position, newest first on equal positions
+func Order(habits []habit.HabitTracker, targets []target.TargetTracker, durations []duration.DurationTracker) []TrackerRef {
+	type ordered struct {
+		ref       TrackerRef
+		pinned    bool
+		position  int
+		createdAt time.Time
+	}
+
+	all := make([]ordered, 0, len(habits)+len(targets)+len(durations))
+	for _, h := range habits {
+		all = append(all, ordered{TrackerRef{models.HABIT, h.ID}, h.Pinned, h.Position, h.CreatedAt})
+	}
+	for _, t := range targets {
+		all = append(all, ordered{TrackerRef{models.TARGET, t.ID}, t.Pinned, t.Position, t.CreatedAt})
+	}
+	for _, d := range durations {
+		all = append(all, ordered{TrackerRef{models.DURATION, d.ID}, d.Pinned, d.Position, d.CreatedAt})
+	}
+
+	sort.SliceStable(all, func(i, j int) bool {
+		a, b := all[i], all[j]
+		if a.pinned != b.pinned {
+			return a.pinned
+		}
+		if a.position != b.position {
+			return a.position < b.position
+		}
+		return a.createdAt.After(b.createdAt)
+	})
+
+	refs := make([]TrackerRef, 0, len(all))
+	for _, o := range all {
+		refs = append(refs, o.ref)
+	}
+	return refs
+}
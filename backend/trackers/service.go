@@ -1,47 +1,120 @@
 package trackers
 
-
 import (
+	"fmt"
 	"routine-tracker/models"
+	"routine-tracker/recurrence"
+	"strings"
 	"time"
-  "strings"
 )
 
-// Helper function to determine if a tracker is due today
-func IsTrackerDueToday(due models.Due, startDate time.Time, today time.Time, todayWeekday string) bool {
-	// If start date is in the future, not due yet
-	if startDate.After(today) {
-		return false
-	}
+// RRULE frequencies of the interval types
+var intervalFrequencies = map[string]recurrence.Frequency{
+	"day":   recurrence.DAILY,
+	"week":  recurrence.WEEKLY,
+	"month": recurrence.MONTHLY,
+	"year":  recurrence.YEARLY,
+}
 
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// DueRule returns the recurrence rule of a due schedule. Specific days become
+// a weekly rule on those days and intervals repeat on the start date, e.g.
+// every 2 months on the day of month the tracker started.
+func DueRule(due models.Due) (*recurrence.Rule, error) {
 	switch due.Type {
 	case models.SPECIFIC_DAYS:
-		// Check if today's weekday is in the specific days list
+		rule := &recurrence.Rule{Freq: recurrence.WEEKLY, Interval: 1, WeekStart: time.Monday}
 		for _, day := range due.SpecificDays {
-			if strings.ToLower(day) == todayWeekday {
-				return true
+			weekday, ok := weekdays[strings.ToLower(day)]
+			if !ok {
+				return nil, fmt.Errorf("unknown day %q", day)
 			}
+			rule.ByDay = append(rule.ByDay, recurrence.WeekdayNum{Weekday: weekday})
+		}
+		if len(rule.ByDay) == 0 {
+			return nil, fmt.Errorf("no days given")
 		}
-		return false
+		return rule, nil
 
 	case models.INTERVAL:
-		daysSinceStart := int(today.Sub(startDate).Hours() / 24)
-
-		switch due.IntervalType {
-		case "day":
-			return daysSinceStart%due.IntervalValue == 0
-		case "week":
-			weeksSinceStart := daysSinceStart / 7
-			return weeksSinceStart%due.IntervalValue == 0
-		case "month":
-			// Simplified month calculation
-			monthsSinceStart := (today.Year()-startDate.Year())*12 + int(today.Month()-startDate.Month())
-			return monthsSinceStart%due.IntervalValue == 0
-		case "year":
-			yearsSinceStart := today.Year() - startDate.Year()
-			return yearsSinceStart%due.IntervalValue == 0
+		freq, ok := intervalFrequencies[due.IntervalType]
+		if !ok {
+			return nil, fmt.Errorf("unknown interval type %q", due.IntervalType)
+		}
+		if due.IntervalValue < 1 {
+			return nil, fmt.Errorf("interval value must be at least 1")
 		}
+		return &recurrence.Rule{Freq: freq, Interval: due.IntervalValue, WeekStart: time.Monday}, nil
+
+	case models.RRULE:
+		return recurrence.Parse(due.RRule)
 	}
 
-	return false
+	return nil, fmt.Errorf("unknown due type %q", due.Type)
+}
+
+// ValidateDue checks that a due schedule has a recurrence rule, trackers
+// with an invalid schedule would never be due
+func ValidateDue(due models.Due) error {
+	_, err := DueRule(due)
+	return err
+}
+
+// Helper function to determine if a tracker is due today. Only the calendar
+// dates matter, today is taken in its own time zone.
+func IsTrackerDueToday(due models.Due, startDate time.Time, today time.Time) bool {
+	return len(DueDates(due, startDate, today, today)) > 0
+}
+
+// DueDates returns the days from from to to, both inclusive, on which a
+// tracker starting on startDate is due
+func DueDates(due models.Due, startDate, from, to time.Time) []time.Time {
+	rule, err := DueRule(due)
+	if err != nil {
+		return nil
+	}
+	return rule.Between(startDate, from, to)
+}
+
+// NextDueDates returns up to count days on or after from on which a tracker
+// starting on startDate is due
+func NextDueDates(due models.Due, startDate, from time.Time, count int) []time.Time {
+	rule, err := DueRule(due)
+	if err != nil {
+		return nil
+	}
+	return rule.Next(startDate, from, count)
+}
+
+// nextDueHorizon is how far NextDueDatesExcept looks ahead for due days that
+// are not off
+const nextDueHorizon = 10
+
+// NextDueDatesExcept is NextDueDates leaving out the days for which off
+// returns true, e.g. paused days. It looks at most ten years ahead.
+func NextDueDatesExcept(due models.Due, startDate, from time.Time, count int, off func(time.Time) bool) []time.Time {
+	dates := make([]time.Time, 0, count)
+	horizon := from.AddDate(nextDueHorizon, 0, 0)
+	for len(dates) < count && from.Before(horizon) {
+		next := NextDueDates(due, startDate, from, count-len(dates))
+		if len(next) == 0 {
+			break
+		}
+		for _, d := range next {
+			if !off(d) {
+				dates = append(dates, d)
+			}
+		}
+		from = next[len(next)-1].AddDate(0, 0, 1)
+	}
+	return dates
 }
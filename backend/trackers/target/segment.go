@@ -0,0 +1,73 @@
+package target
+
+import (
+	"routine-tracker/models"
+	"time"
+)
+
+// Segment is the goal configuration of a target tracker from a point in time
+// on. It applies to the entries until the next segment starts, the first
+// segment also to the entries before it.
+type Segment struct {
+	ID            int       `json:"id" example:"1"`
+	TrackerID     int       `json:"trackerId" example:"1"`
+	EffectiveFrom time.Time `json:"effectiveFrom" example:"2024-01-01T00:00:00Z"`
+	GoalValue     float64   `json:"goalValue" example:"5000"`
+	GoalDate      time.Time `json:"goalDate" example:"2024-12-31T00:00:00Z"`
+	AddToTotal    bool      `json:"addToTotal" example:"false"`
+	CreatedAt     time.Time `json:"createdAt" example:"2024-01-01T10:00:00Z"`
+}
+
+// InitialSegment is the segment of a tracker that was never reconfigured
+func (t TargetTracker) InitialSegment() Segment {
+	return Segment{
+		TrackerID:     t.ID,
+		EffectiveFrom: t.StartDate,
+		GoalValue:     t.GoalValue,
+		GoalDate:      t.GoalDate,
+		AddToTotal:    t.AddToTotal,
+		CreatedAt:     t.CreatedAt,
+	}
+}
+
+// SegmentAt returns the configuration in effect at a point in time
+func (t TargetTracker) SegmentAt(date time.Time) Segment {
+	if len(t.Segments) == 0 {
+		return t.InitialSegment()
+	}
+
+	segment := t.Segments[0]
+	for _, s := range t.Segments[1:] {
+		if s.EffectiveFrom.After(date) {
+			break
+		}
+		segment = s
+	}
+	return segment
+}
+
+// At returns the tracker with the goal configuration in effect at a point in
+// time. Goal changes can be planned ahead, so the configuration stored with a
+// tracker is only the one in effect when it was last updated.
+func (t TargetTracker) At(date time.Time) TargetTracker {
+	segment := t.SegmentAt(date)
+	t.GoalValue, t.GoalDate, t.AddToTotal = segment.GoalValue, segment.GoalDate, segment.AddToTotal
+	return t
+}
+
+// Progress returns the value of the tracker after each of the entries, which
+// must be in chronological order. Entries of additive segments add to the
+// value, the others replace it.
+func (t TargetTracker) Progress(entries []models.Entry) []float64 {
+	values := make([]float64, 0, len(entries))
+	value := t.StartValue
+	for _, entry := range entries {
+		if t.SegmentAt(entry.Date).AddToTotal {
+			value += entry.Value
+		} else {
+			value = entry.Value
+		}
+		values = append(values, value)
+	}
+	return values
+}
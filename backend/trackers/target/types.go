@@ -7,20 +7,28 @@ import (
 
 // TargetTracker represents a target tracking configuration
 type TargetTracker struct {
-	ID                int             `json:"id" example:"1"`
-	TrackerName       string          `json:"trackerName" example:"Save Money"`
-	StartValue        float64         `json:"startValue" example:"0"` // Adjusted value when useActualBounds is true
-	OriginalStartValue float64        `json:"originalStartValue" example:"0"` // Always the original user-set value
-	GoalValue         float64         `json:"goalValue" example:"5000"`
-	CurrentValue      *float64        `json:"currentValue,omitempty" example:"1234.56"` // Calculated field, not stored in DB
-	StartDate         time.Time       `json:"startDate" example:"2024-01-01T00:00:00Z"`
-	GoalDate          time.Time       `json:"goalDate" example:"2024-12-31T00:00:00Z"`
-	AddToTotal        bool            `json:"addToTotal" example:"false"` // default false
-	UseActualBounds   bool            `json:"useActualBounds" example:"false"` // default false
-	TrendWeightType   *string         `json:"trendWeightType,omitempty" example:"none"` // Weighting algorithm for trend line
-	Due               models.Due      `json:"due"`
-	Reminders         models.Reminder `json:"reminders"`
-	CreatedAt         time.Time       `json:"createdAt" example:"2024-01-01T10:00:00Z"`
+	ID                 int             `json:"id" example:"1"`
+	UserID             int             `json:"-"`
+	TrackerName        string          `json:"trackerName" example:"Save Money"`
+	StartValue         float64         `json:"startValue" example:"0"`         // Adjusted value when useActualBounds is true
+	OriginalStartValue float64         `json:"originalStartValue" example:"0"` // Always the original user-set value
+	GoalValue          float64         `json:"goalValue" example:"5000"`
+	CurrentValue       *float64        `json:"currentValue,omitempty" example:"1234.56"` // Calculated field, not stored in DB
+	StartDate          time.Time       `json:"startDate" example:"2024-01-01T00:00:00Z"`
+	GoalDate           time.Time       `json:"goalDate" example:"2024-12-31T00:00:00Z"`
+	AddToTotal         bool            `json:"addToTotal" example:"false"`               // default false
+	UseActualBounds    bool            `json:"useActualBounds" example:"false"`          // default false
+	TrendWeightType    *string         `json:"trendWeightType,omitempty" example:"none"` // Weighting algorithm for trend line
+	Due                models.Due      `json:"due"`
+	Reminders          models.Reminder `json:"reminders"`
+	CategoryID         *int            `json:"categoryId" example:"1"`
+	Tags               []models.Tag    `json:"tags"`
+	Position           int             `json:"position" example:"0"` // place in the user-defined order, see PUT /trackers/order
+	Pinned             bool            `json:"pinned" example:"false"`
+	CreatedAt          time.Time       `json:"createdAt" example:"2024-01-01T10:00:00Z"`
+	ArchivedAt         *time.Time      `json:"archivedAt,omitempty" example:"2024-06-01T10:00:00Z"` // set while the tracker is archived
+	DeletedAt          *time.Time      `json:"deletedAt,omitempty" example:"2024-06-01T10:00:00Z"`  // set while the tracker is in the trash
+	Segments           []Segment       `json:"-"`                                                   // goal configurations ordered by effectiveFrom, the one in effect at the last update matches the fields above
 }
 
 type CreateTargetRequest struct {
@@ -33,6 +41,9 @@ type CreateTargetRequest struct {
 	TrendWeightType *string         `json:"trendWeightType,omitempty" example:"none"`
 	Due             models.Due      `json:"due"`
 	Reminders       models.Reminder `json:"reminders,omitempty"`
+	CategoryID      *int            `json:"categoryId,omitempty" example:"1"`
+	TagIDs          []int           `json:"tagIds,omitempty"`
+	Pinned          bool            `json:"pinned" example:"false"`
 }
 
 type UpdateTargetRequest struct {
@@ -46,4 +57,8 @@ type UpdateTargetRequest struct {
 	TrendWeightType *string          `json:"trendWeightType,omitempty"`
 	Due             *models.Due      `json:"due,omitempty"`
 	Reminders       *models.Reminder `json:"reminders,omitempty"`
+	EffectiveFrom   *string          `json:"effectiveFrom,omitempty" example:"2024-06-01"` // when a new goalValue, goalDate or addToTotal starts to apply, defaults to today and may be in the future
+	CategoryID      *int             `json:"categoryId,omitempty"`                         // 0 removes the tracker from its category
+	TagIDs          *[]int           `json:"tagIds,omitempty"`                             // replaces the tags of the tracker
+	Pinned          *bool            `json:"pinned,omitempty"`
 }
@@ -0,0 +1,64 @@
+package trash
+
+import (
+	"context"
+	"log/slog"
+	"routine-tracker/app"
+	"routine-tracker/database"
+	"time"
+)
+
+// Purger periodically deletes trackers and entries for good once they have
+// been in the trash longer than the retention period
+type Purger struct {
+	Store     database.Store
+	Clock     app.Clock
+	Logger    *slog.Logger
+	Retention time.Duration // how long deleted items can be restored
+	Interval  time.Duration // how often the trash is checked
+}
+
+// NewPurger creates a purger of the app checking the trash every hour
+func NewPurger(a *app.App) *Purger {
+	return &Purger{
+		Store:     a.Store,
+		Clock:     a.Clock,
+		Logger:    a.Logger,
+		Retention: a.Config.TrashRetention(),
+		Interval:  time.Hour,
+	}
+}
+
+// Run purges the trash until the context is cancelled
+func (p *Purger) Run(ctx context.Context) {
+	p.Logger.Info("🗑️  Trash purger started")
+
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := p.Tick(p.Clock.Now()); err != nil {
+			p.Logger.Error("failed to purge trash", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			p.Logger.Info("🗑️  Trash purger stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick deletes everything that was moved to the trash more than the retention
+// period before now and returns the number of deleted trackers and entries
+func (p *Purger) Tick(now time.Time) (int, error) {
+	purged, err := p.Store.PurgeTrash(now.Add(-p.Retention))
+	if err != nil {
+		return 0, err
+	}
+	if purged > 0 {
+		p.Logger.Info("🗑️  Purged trackers and entries from the trash", "count", purged)
+	}
+	return purged, nil
+}
@@ -1,18 +1,30 @@
 // src/App.tsx - Updated with routing
-import { BrowserRouter as Router, Routes, Route } from 'react-router-dom';
+import type { ReactElement } from 'react';
+import { BrowserRouter as Router, Routes, Route, Navigate } from 'react-router-dom';
 import Dashboard from './components/Dashboard';
 import HabitDetailPage from './components/detail/HabitDetailPage';
 import TargetDetailPage from './components/detail/TargetDetailPage';
+import LoginPage from './components/LoginPage';
+import { tokenStorage } from './services/api';
 import './index.css';
 
+// RequireLogin sends visitors without a session token to the login screen
+function RequireLogin({ children }: { children: ReactElement }) {
+  if (!tokenStorage.get()) {
+    return <Navigate to="/login" replace />;
+  }
+  return children;
+}
+
 function App() {
   return (
     <Router>
       <Routes>
-        <Route path="/" element={<Dashboard />} />
-        <Route path="/dashboard" element={<Dashboard />} />
-        <Route path="/habit/:id" element={<HabitDetailPage />} />
-        <Route path="/target/:id" element={<TargetDetailPage />} />
+        <Route path="/login" element={<LoginPage />} />
+        <Route path="/" element={<RequireLogin><Dashboard /></RequireLogin>} />
+        <Route path="/dashboard" element={<RequireLogin><Dashboard /></RequireLogin>} />
+        <Route path="/habit/:id" element={<RequireLogin><HabitDetailPage /></RequireLogin>} />
+        <Route path="/target/:id" element={<RequireLogin><TargetDetailPage /></RequireLogin>} />
       </Routes>
     </Router>
   );
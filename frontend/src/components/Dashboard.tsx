@@ -1,9 +1,10 @@
 // src/components/Dashboard.tsx - Mobile-style redesigned dashboard
 import { useState, useEffect } from 'react';
+import { useNavigate } from 'react-router-dom';
 import { Card, CardContent } from '@/components/ui/card';
 import { Button } from '@/components/ui/button';
-import { CheckCircle, Target, Calendar, Plus, Loader2, Settings, BarChart } from 'lucide-react';
-import { habitApi, targetApi } from '../services/api';
+import { CheckCircle, Target, Calendar, Plus, Loader2, Settings, BarChart, LogOut } from 'lucide-react';
+import { authApi, habitApi, targetApi } from '../services/api';
 import { useDashboard, useDueCounts } from '../hooks/useDashboard';
 import { useDashboardEntries } from '../hooks/useDashboardEntries';
 import WeeklyCalendar from './WeeklyCalendar';
@@ -13,6 +14,7 @@ import HabitCard from './dashboard/HabitCard';
 import TargetCard from './dashboard/TargetCard';
 
 export default function Dashboard() {
+  const navigate = useNavigate();
   const [showCreateHabit, setShowCreateHabit] = useState(false);
   const [showCreateTarget, setShowCreateTarget] = useState(false);
   
@@ -61,6 +63,15 @@ export default function Dashboard() {
     }
   };
 
+  const handleLogout = async () => {
+    try {
+      await authApi.logout();
+    } catch (error) {
+      console.error('Failed to logout:', error);
+    }
+    navigate('/login', { replace: true });
+  };
+
   const isToday = selectedDate.toDateString() === new Date().toDateString();
 
   if (loading) {
@@ -109,7 +120,12 @@ export default function Dashboard() {
               </button>
             )}
           </div>
-          <BarChart className="w-6 h-6" />
+          <div className="flex items-center gap-3">
+            <BarChart className="w-6 h-6" />
+            <button onClick={handleLogout} title="Logout" className="hover:text-blue-200 transition-colors">
+              <LogOut className="w-6 h-6" />
+            </button>
+          </div>
         </div>
         
         {/* Week Calendar */}
@@ -0,0 +1,122 @@
+// src/components/LoginPage.tsx - Login and registration screen
+import { useState, useEffect } from 'react';
+import { useNavigate } from 'react-router-dom';
+import { Card, CardContent, CardHeader, CardTitle } from '@/components/ui/card';
+import { Button } from '@/components/ui/button';
+import { Input } from '@/components/ui/input';
+import { Label } from '@/components/ui/label';
+import { Loader2 } from 'lucide-react';
+import { authApi, ApiError } from '../services/api';
+
+type Mode = 'login' | 'register';
+
+export default function LoginPage() {
+  const navigate = useNavigate();
+  const [mode, setMode] = useState<Mode>('login');
+  const [loading, setLoading] = useState(false);
+  const [username, setUsername] = useState('');
+  const [password, setPassword] = useState('');
+  const [error, setError] = useState<string | null>(null);
+
+  useEffect(() => {
+    document.title = mode === 'login' ? 'Login | Progress' : 'Register | Progress';
+    return () => {
+      document.title = 'Progress';
+    };
+  }, [mode]);
+
+  const handleSubmit = async (e: React.FormEvent) => {
+    e.preventDefault();
+
+    if (!username.trim()) {
+      setError('Username is required');
+      return;
+    }
+    if (mode === 'register' && password.length < 8) {
+      setError('Password must be at least 8 characters');
+      return;
+    }
+
+    setLoading(true);
+    setError(null);
+    try {
+      if (mode === 'register') {
+        await authApi.register({ username: username.trim(), password });
+      }
+      await authApi.login({ username: username.trim(), password });
+      navigate('/', { replace: true });
+    } catch (err) {
+      if (err instanceof ApiError && err.status === 401) {
+        setError('Invalid username or password');
+      } else if (err instanceof ApiError && err.status === 409) {
+        setError('Username already taken');
+      } else if (err instanceof ApiError && err.status === 403) {
+        setError('Registration is disabled');
+      } else {
+        setError(mode === 'login' ? 'Failed to login. Please try again.' : 'Failed to register. Please try again.');
+      }
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const toggleMode = () => {
+    setMode(prev => (prev === 'login' ? 'register' : 'login'));
+    setError(null);
+  };
+
+  return (
+    <div className="min-h-screen bg-gradient-to-b from-blue-600 to-blue-700 flex items-center justify-center p-4">
+      <Card className="w-full max-w-sm bg-white">
+        <CardHeader>
+          <CardTitle className="text-xl font-semibold">
+            {mode === 'login' ? 'Login' : 'Create Account'}
+          </CardTitle>
+        </CardHeader>
+        <CardContent>
+          <form onSubmit={handleSubmit} className="space-y-4">
+            <div className="space-y-2">
+              <Label htmlFor="username" className="text-sm font-medium">Username</Label>
+              <Input
+                id="username"
+                autoComplete="username"
+                value={username}
+                onChange={(e) => setUsername(e.target.value)}
+                className="w-full"
+              />
+            </div>
+
+            <div className="space-y-2">
+              <Label htmlFor="password" className="text-sm font-medium">Password</Label>
+              <Input
+                id="password"
+                type="password"
+                autoComplete={mode === 'login' ? 'current-password' : 'new-password'}
+                value={password}
+                onChange={(e) => setPassword(e.target.value)}
+                className="w-full"
+              />
+            </div>
+
+            {error && (
+              <p className="text-sm text-red-500">{error}</p>
+            )}
+
+            <Button type="submit" className="w-full" disabled={loading}>
+              {loading && <Loader2 className="w-4 h-4 mr-2 animate-spin" />}
+              {mode === 'login' ? 'Login' : 'Register'}
+            </Button>
+
+            <button
+              type="button"
+              onClick={toggleMode}
+              className="w-full text-sm text-blue-600 hover:underline"
+            >
+              {mode === 'login' ? "Don't have an account? Register" : 'Already have an account? Login'}
+            </button>
+          </form>
+        </CardContent>
+      </Card>
+    </div>
+  );
+}
@@ -6,12 +6,24 @@ import type {
   CreateHabitRequest,
   CreateTargetRequest,
   AddEntryRequest,
-  UpdateEntryRequest
+  UpdateEntryRequest,
+  User,
+  LoginRequest,
+  LoginResponse,
+  RegisterRequest
 } from '../types';
 
 const API_BASE = import.meta.env.VITE_API_URL || 'http://localhost:8080/api';
+const TOKEN_KEY = 'progress.token';
 
-class ApiError extends Error {
+// Session token storage, the API expects it as "Authorization: Bearer <token>"
+export const tokenStorage = {
+  get: (): string | null => localStorage.getItem(TOKEN_KEY),
+  set: (token: string) => localStorage.setItem(TOKEN_KEY, token),
+  clear: () => localStorage.removeItem(TOKEN_KEY),
+};
+
+export class ApiError extends Error {
   status: number;
   
   constructor(status: number, message: string) {
@@ -26,18 +38,28 @@ async function apiRequest<T>(
   options: RequestInit = {}
 ): Promise<T> {
   const url = `${API_BASE}${endpoint}`;
+  const token = tokenStorage.get();
   
   const config: RequestInit = {
+    ...options,
     headers: {
       'Content-Type': 'application/json',
+      ...(token ? { Authorization: `Bearer ${token}` } : {}),
       ...options.headers,
     },
-    ...options,
   };
 
   try {
     const response = await fetch(url, config);
     
+    // An expired or revoked session sends the user back to the login screen
+    if (response.status === 401 && !endpoint.startsWith('/auth/')) {
+      tokenStorage.clear();
+      if (window.location.pathname !== '/login') {
+        window.location.assign('/login');
+      }
+    }
+
     if (!response.ok) {
       throw new ApiError(
         response.status,
@@ -60,6 +82,35 @@ async function apiRequest<T>(
   }
 }
 
+// Auth API
+export const authApi = {
+  login: async (data: LoginRequest): Promise<LoginResponse> => {
+    const response = await apiRequest<LoginResponse>('/auth/login', {
+      method: 'POST',
+      body: JSON.stringify(data),
+    });
+    tokenStorage.set(response.token);
+    return response;
+  },
+
+  register: (data: RegisterRequest): Promise<User> =>
+    apiRequest<User>('/auth/register', {
+      method: 'POST',
+      body: JSON.stringify(data),
+    }),
+
+  logout: async (): Promise<void> => {
+    try {
+      await apiRequest<void>('/auth/logout', { method: 'POST' });
+    } finally {
+      tokenStorage.clear();
+    }
+  },
+
+  me: (): Promise<User> =>
+    apiRequest<User>('/auth/me'),
+};
+
 // Dashboard API
 export const dashboardApi = {
   getDashboard: (date?: string): Promise<DashboardResponse> => {
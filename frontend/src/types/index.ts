@@ -89,6 +89,29 @@ export interface UpdateEntryRequest {
   note?: string;
 }
 
+export interface User {
+  id: number;
+  username: string;
+  timeZone: string;
+  createdAt: string;
+}
+
+export interface LoginRequest {
+  username: string;
+  password: string;
+}
+
+export interface RegisterRequest {
+  username: string;
+  password: string;
+}
+
+export interface LoginResponse {
+  token: string;
+  expiresAt: string;
+  user: User;
+}
+
 // API Response wrapper
 export interface ApiResponse<T> {
   data: T;